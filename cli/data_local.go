@@ -0,0 +1,430 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// Supported formats for dumping tabular data.
+const (
+	DumpFormatCSV   = "csv"
+	DumpFormatJSONL = "jsonl"
+)
+
+// fileTimeFormat is how the time a reading was requested is written in file names. Unlike RFC 3339 it has no colons,
+// which are not allowed in file names on Windows.
+const fileTimeFormat = "20060102T150405.000000000Z"
+
+// TimeRange restricts readings to those requested in [Start, End). A zero Start or End is unbounded.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (r TimeRange) contains(t time.Time) bool {
+	return (r.Start.IsZero() || !t.Before(r.Start)) && (r.End.IsZero() || t.Before(r.End))
+}
+
+// captureFileContents is the metadata, readings and encoding of a data capture file.
+type captureFileContents struct {
+	metadata *v1.DataCaptureMetadata
+	readings []*v1.SensorData
+	encoding datacapture.EncodingOptions
+}
+
+// readCaptureFile reads all of the capture file at path. In progress files are read as-is.
+func readCaptureFile(path string) (*captureFileContents, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	// The underlying file is closed directly, since closing a datacapture.File renames in progress files.
+	defer utils.UncheckedErrorFunc(f.Close)
+	captureFile, err := datacapture.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	readings, err := datacapture.SensorDataFromFile(captureFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read readings from %s", path)
+	}
+	return &captureFileContents{metadata: captureFile.ReadMetadata(), readings: readings, encoding: captureFile.Encoding()}, nil
+}
+
+// findCaptureFiles returns the data capture files at path, which may be a file or a directory to search recursively.
+func findCaptureFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var paths []string
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(p)
+		if !info.IsDir() && (ext == datacapture.FileExt || ext == datacapture.InProgressFileExt) {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// ListCaptureFiles writes a summary of every data capture file under dir to w. Only the metadata at the start of
+// each file is read, so listing is quick however much data was captured. Files that cannot be read are left out of
+// the summary and reported in the returned error.
+func ListCaptureFiles(w io.Writer, dir string) error {
+	paths, err := findCaptureFiles(dir)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tCOMPONENT TYPE\tCOMPONENT NAME\tMETHOD\tDATA TYPE\tBYTES")
+	var errs error
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		md, err := datacapture.ReadFileMetadata(p)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", p, md.GetComponentType(), md.GetComponentName(),
+			md.GetMethodName(), md.GetType(), info.Size())
+	}
+	return multierr.Combine(tw.Flush(), errs)
+}
+
+// PrintCaptureMetadata writes the DataCaptureMetadata of the capture file at path to w as JSON.
+func PrintCaptureMetadata(w io.Writer, path string) error {
+	contents, err := readCaptureFile(path)
+	if err != nil {
+		return err
+	}
+	mdJSON, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(contents.metadata)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(mdJSON))
+	return err
+}
+
+// DumpTabularData writes the tabular readings of the capture files at path, which may be a directory, to w in
+// format. CSV columns are the union of the dot separated keys of every reading.
+func DumpTabularData(w io.Writer, path, format string, timeRange TimeRange) error {
+	paths, err := findCaptureFiles(path)
+	if err != nil {
+		return err
+	}
+	var readings []*v1.SensorData
+	for _, p := range paths {
+		contents, err := readCaptureFile(p)
+		if err != nil {
+			return err
+		}
+		if contents.metadata.GetType() != v1.DataType_DATA_TYPE_TABULAR_SENSOR {
+			continue
+		}
+		readings = append(readings, filterReadings(contents.readings, timeRange)...)
+	}
+	sortReadings(readings)
+
+	switch format {
+	case DumpFormatCSV:
+		return dumpCSV(w, readings)
+	case DumpFormatJSONL:
+		return dumpJSONL(w, readings)
+	default:
+		return errors.Errorf("format must be %s or %s, got %q", DumpFormatCSV, DumpFormatJSONL, format)
+	}
+}
+
+func dumpJSONL(w io.Writer, readings []*v1.SensorData) error {
+	enc := json.NewEncoder(w)
+	for _, r := range readings {
+		if err := enc.Encode(map[string]interface{}{
+			"time_requested": formatTimeRequested(r),
+			"time_received":  r.GetMetadata().GetTimeReceived().AsTime().Format(time.RFC3339Nano),
+			"data":           r.GetStruct().AsMap(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dumpCSV(w io.Writer, readings []*v1.SensorData) error {
+	rows := make([]map[string]string, 0, len(readings))
+	columns := map[string]bool{}
+	for _, r := range readings {
+		row := map[string]string{}
+		flattenReading("", r.GetStruct().AsMap(), row)
+		for k := range row {
+			columns[k] = true
+		}
+		rows = append(rows, row)
+	}
+	header := make([]string, 0, len(columns))
+	for k := range columns {
+		header = append(header, k)
+	}
+	sort.Strings(header)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"time_requested", "time_received"}, header...)); err != nil {
+		return err
+	}
+	for i, r := range readings {
+		record := []string{
+			formatTimeRequested(r),
+			r.GetMetadata().GetTimeReceived().AsTime().Format(time.RFC3339Nano),
+		}
+		for _, k := range header {
+			record = append(record, rows[i][k])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// flattenReading adds the values of reading to row, keyed by their dot separated path. Lists are encoded as JSON.
+func flattenReading(prefix string, reading map[string]interface{}, row map[string]string) {
+	for k, v := range reading {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]interface{}:
+			flattenReading(key, val, row)
+		case string:
+			row[key] = val
+		case float64:
+			row[key] = strconv.FormatFloat(val, 'g', -1, 64)
+		case bool:
+			row[key] = strconv.FormatBool(val)
+		case nil:
+			row[key] = ""
+		default:
+			encoded, err := json.Marshal(val)
+			if err != nil {
+				row[key] = fmt.Sprint(val)
+				continue
+			}
+			row[key] = string(encoded)
+		}
+	}
+}
+
+// ExtractBinaryData writes the payload of every binary reading in the capture files at path, which may be a
+// directory, to its own file in dst. Files are named after the resource, method and time the reading was requested,
+// with a numeric suffix added if a file of that name already exists.
+func ExtractBinaryData(w io.Writer, path, dst string, timeRange TimeRange) error {
+	paths, err := findCaptureFiles(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0o700); err != nil {
+		return err
+	}
+	var numExtracted int
+	for _, p := range paths {
+		contents, err := readCaptureFile(p)
+		if err != nil {
+			return err
+		}
+		if contents.metadata.GetType() != v1.DataType_DATA_TYPE_BINARY_SENSOR {
+			continue
+		}
+		for _, r := range filterReadings(contents.readings, timeRange) {
+			name := strings.Join([]string{
+				contents.metadata.GetComponentName(), contents.metadata.GetMethodName(), fileTimeRequested(r),
+			}, "_")
+			if err := writeNewFile(dst, name, contents.metadata.GetFileExtension(), r.GetBinary()); err != nil {
+				return err
+			}
+			numExtracted++
+		}
+	}
+	_, err = fmt.Fprintf(w, "extracted %d files to %s\n", numExtracted, dst)
+	return err
+}
+
+// MergeCaptureFiles writes the readings of every capture file in paths within timeRange to a single capture file in
+// dst, ordered by the time they were requested. All files must have been captured from the same method of the same
+// resource, and the new file is compressed and encrypted like the first of them. Its path is returned.
+func MergeCaptureFiles(paths []string, dst string, timeRange TimeRange) (string, error) {
+	if len(paths) == 0 {
+		return "", errors.New("no capture files to merge")
+	}
+	var md *v1.DataCaptureMetadata
+	var encoding datacapture.EncodingOptions
+	var readings []*v1.SensorData
+	for _, p := range paths {
+		contents, err := readCaptureFile(p)
+		if err != nil {
+			return "", err
+		}
+		if md == nil {
+			md = contents.metadata
+			encoding = contents.encoding
+		} else if !sameCaptureSource(md, contents.metadata) {
+			return "", errors.Errorf("cannot merge %s: captured from %s/%s/%s rather than %s/%s/%s", p,
+				contents.metadata.GetComponentType(), contents.metadata.GetComponentName(), contents.metadata.GetMethodName(),
+				md.GetComponentType(), md.GetComponentName(), md.GetMethodName())
+		}
+		readings = append(readings, filterReadings(contents.readings, timeRange)...)
+	}
+	if len(readings) == 0 {
+		return "", errors.New("no readings within the time range")
+	}
+	sortReadings(readings)
+	return writeCaptureFile(dst, md, encoding, readings)
+}
+
+// SplitCaptureFile splits the readings of the capture file at path within timeRange into capture files in dst,
+// each covering at most interval and compressed and encrypted like the original. The paths of the new files are
+// returned.
+func SplitCaptureFile(path, dst string, interval time.Duration, timeRange TimeRange) ([]string, error) {
+	if interval <= 0 {
+		return nil, errors.New("split interval must be positive")
+	}
+	contents, err := readCaptureFile(path)
+	if err != nil {
+		return nil, err
+	}
+	readings := filterReadings(contents.readings, timeRange)
+	sortReadings(readings)
+
+	var written []string
+	for len(readings) > 0 {
+		end := readings[0].GetMetadata().GetTimeRequested().AsTime().Truncate(interval).Add(interval)
+		n := sort.Search(len(readings), func(i int) bool {
+			return !readings[i].GetMetadata().GetTimeRequested().AsTime().Before(end)
+		})
+		p, err := writeCaptureFile(dst, contents.metadata, contents.encoding, readings[:n])
+		if err != nil {
+			return written, err
+		}
+		written = append(written, p)
+		readings = readings[n:]
+	}
+	return written, nil
+}
+
+// writeCaptureFile writes readings to a new capture file in dir, encoded as described by encoding and named after the
+// time the first reading was requested.
+func writeCaptureFile(
+	dir string,
+	md *v1.DataCaptureMetadata,
+	encoding datacapture.EncodingOptions,
+	readings []*v1.SensorData,
+) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	f, err := datacapture.NewEncodedFile(dir, md, encoding)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range readings {
+		if err := f.WriteNext(r); err != nil {
+			return "", multierr.Combine(err, f.Delete())
+		}
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	closedPath := strings.TrimSuffix(f.GetPath(), datacapture.InProgressFileExt) + datacapture.FileExt
+	dstPath := filepath.Join(dir, fileTimeRequested(readings[0])+datacapture.FileExt)
+	if _, err := os.Stat(dstPath); err == nil {
+		return "", multierr.Combine(errors.Errorf("%s already exists", dstPath), os.Remove(closedPath))
+	}
+	if err := os.Rename(closedPath, dstPath); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+func sameCaptureSource(a, b *v1.DataCaptureMetadata) bool {
+	return a.GetComponentType() == b.GetComponentType() &&
+		a.GetComponentName() == b.GetComponentName() &&
+		a.GetMethodName() == b.GetMethodName() &&
+		a.GetType() == b.GetType()
+}
+
+func filterReadings(readings []*v1.SensorData, timeRange TimeRange) []*v1.SensorData {
+	filtered := make([]*v1.SensorData, 0, len(readings))
+	for _, r := range readings {
+		if timeRange.contains(r.GetMetadata().GetTimeRequested().AsTime()) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+func sortReadings(readings []*v1.SensorData) {
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].GetMetadata().GetTimeRequested().AsTime().Before(
+			readings[j].GetMetadata().GetTimeRequested().AsTime())
+	})
+}
+
+func formatTimeRequested(r *v1.SensorData) string {
+	return r.GetMetadata().GetTimeRequested().AsTime().Format(time.RFC3339Nano)
+}
+
+func fileTimeRequested(r *v1.SensorData) string {
+	return r.GetMetadata().GetTimeRequested().AsTime().UTC().Format(fileTimeFormat)
+}
+
+// writeNewFile writes data to name+ext in dir, or to name_1+ext, name_2+ext and so on if that file already exists,
+// so that nothing is overwritten.
+func writeNewFile(dir, name, ext string, data []byte) error {
+	for i := 0; ; i++ {
+		p := filepath.Join(dir, name+ext)
+		if i > 0 {
+			p = filepath.Join(dir, name+"_"+strconv.Itoa(i)+ext)
+		}
+		//nolint:gosec
+		f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return multierr.Combine(err, f.Close())
+		}
+		return f.Close()
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+func readingAt(t time.Time, payload string) *v1.SensorData {
+	return &v1.SensorData{
+		Metadata: &v1.SensorMetadata{TimeRequested: timestamppb.New(t), TimeReceived: timestamppb.New(t)},
+		Data:     &v1.SensorData_Binary{Binary: []byte(payload)},
+	}
+}
+
+func TestFilterReadings(t *testing.T) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	var readings []*v1.SensorData
+	for i := 0; i < 4; i++ {
+		readings = append(readings, readingAt(start.Add(time.Duration(i)*time.Minute), ""))
+	}
+
+	test.That(t, filterReadings(readings, TimeRange{}), test.ShouldResemble, readings)
+	// the start is inclusive and the end exclusive
+	test.That(t, filterReadings(readings, TimeRange{Start: start.Add(time.Minute), End: start.Add(3 * time.Minute)}),
+		test.ShouldResemble, readings[1:3])
+	test.That(t, filterReadings(readings, TimeRange{Start: start.Add(2 * time.Minute)}), test.ShouldResemble, readings[2:])
+	test.That(t, filterReadings(readings, TimeRange{End: start.Add(time.Minute)}), test.ShouldResemble, readings[:1])
+	test.That(t, filterReadings(readings, TimeRange{Start: start.Add(time.Hour)}), test.ShouldBeEmpty)
+}
+
+func TestSortReadings(t *testing.T) {
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	first, second := readingAt(start, "first"), readingAt(start, "second")
+	last := readingAt(start.Add(time.Second), "last")
+	readings := []*v1.SensorData{last, first, second}
+	sortReadings(readings)
+	// readings requested at the same time keep their order
+	test.That(t, readings, test.ShouldResemble, []*v1.SensorData{first, second, last})
+}
+
+func TestFormatTimeRequested(t *testing.T) {
+	r := readingAt(time.Date(2023, 5, 1, 12, 30, 15, 123456789, time.UTC), "")
+	test.That(t, formatTimeRequested(r), test.ShouldEqual, "2023-05-01T12:30:15.123456789Z")
+	test.That(t, fileTimeRequested(r), test.ShouldEqual, "20230501T123015.123456789Z")
+	test.That(t, fileTimeRequested(readingAt(time.Date(2023, 5, 1, 12, 30, 15, 0, time.UTC), "")),
+		test.ShouldEqual, "20230501T123015.000000000Z")
+	test.That(t, strings.ContainsAny(fileTimeRequested(r), `<>:"/\|?*`), test.ShouldBeFalse)
+}
+
+func TestExtractBinaryDataNamesAreUnique(t *testing.T) {
+	dir := t.TempDir()
+	requested := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	md := &v1.DataCaptureMetadata{
+		ComponentName: "cam",
+		MethodName:    "ReadImage",
+		Type:          v1.DataType_DATA_TYPE_BINARY_SENSOR,
+		FileExtension: ".jpeg",
+	}
+	// two files holding readings requested at the same time, as happens when a capture directory is copied
+	for _, payload := range []string{"a", "b"} {
+		readings := []*v1.SensorData{readingAt(requested, payload)}
+		_, err := writeCaptureFile(filepath.Join(dir, payload), md, datacapture.EncodingOptions{}, readings)
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	dst := filepath.Join(t.TempDir(), "out")
+	var out bytes.Buffer
+	test.That(t, ExtractBinaryData(&out, dir, dst, TimeRange{}), test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldEqual, "extracted 2 files to "+dst+"\n")
+
+	entries, err := os.ReadDir(dst)
+	test.That(t, err, test.ShouldBeNil)
+	var names []string
+	payloads := map[string]bool{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+		//nolint:gosec
+		data, err := os.ReadFile(filepath.Join(dst, e.Name()))
+		test.That(t, err, test.ShouldBeNil)
+		payloads[string(data)] = true
+	}
+	test.That(t, names, test.ShouldResemble, []string{
+		"cam_ReadImage_20230501T120000.000000000Z.jpeg",
+		"cam_ReadImage_20230501T120000.000000000Z_1.jpeg",
+	})
+	test.That(t, payloads, test.ShouldResemble, map[string]bool{"a": true, "b": true})
+}
+
+func TestListCaptureFilesSkipsUnreadableFiles(t *testing.T) {
+	dir := t.TempDir()
+	md := &v1.DataCaptureMetadata{
+		ComponentType: "rdk:component:camera",
+		ComponentName: "cam",
+		MethodName:    "ReadImage",
+		Type:          v1.DataType_DATA_TYPE_BINARY_SENSOR,
+	}
+	good, err := writeCaptureFile(filepath.Join(dir, "good"), md, datacapture.EncodingOptions{}, []*v1.SensorData{readingAt(time.Now(), "a")})
+	test.That(t, err, test.ShouldBeNil)
+	bad := filepath.Join(dir, "bad.capture")
+	test.That(t, os.WriteFile(bad, []byte("not a capture file"), 0o600), test.ShouldBeNil)
+
+	var out bytes.Buffer
+	err = ListCaptureFiles(&out, dir)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, bad)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	test.That(t, len(lines), test.ShouldEqual, 2)
+	test.That(t, lines[1], test.ShouldStartWith, good)
+	test.That(t, lines[1], test.ShouldContainSubstring, "cam")
+}
+
+func TestSplitCaptureFileKeepsEncoding(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	test.That(t, err, test.ShouldBeNil)
+	keyFile := filepath.Join(t.TempDir(), "capture.key")
+	test.That(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0o600), test.ShouldBeNil)
+	t.Setenv(datacapture.KeyFileEnvVar, keyFile)
+	encoding := datacapture.EncodingOptions{Compression: datacapture.CompressionZstd, Key: key}
+	secret := "a very secret reading"
+	md := &v1.DataCaptureMetadata{ComponentName: "cam", MethodName: "ReadImage", Type: v1.DataType_DATA_TYPE_BINARY_SENSOR}
+	start := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	src, err := writeCaptureFile(t.TempDir(), md, encoding, []*v1.SensorData{
+		readingAt(start, secret), readingAt(start.Add(time.Hour), secret),
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	dst := t.TempDir()
+	written, err := SplitCaptureFile(src, dst, time.Hour, TimeRange{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(written), test.ShouldEqual, 2)
+	merged, err := MergeCaptureFiles(written, t.TempDir(), TimeRange{})
+	test.That(t, err, test.ShouldBeNil)
+	for _, p := range append(written, merged) {
+		//nolint:gosec
+		data, err := os.ReadFile(p)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, bytes.Contains(data, []byte(secret)), test.ShouldBeFalse)
+		//nolint:gosec
+		f, err := os.Open(p)
+		test.That(t, err, test.ShouldBeNil)
+		captureFile, err := datacapture.ReadFileWithKey(f, key)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, captureFile.Encoding(), test.ShouldResemble, encoding)
+		test.That(t, f.Close(), test.ShouldBeNil)
+	}
+}
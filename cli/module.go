@@ -0,0 +1,398 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+)
+
+// ModuleMetaFile is the default name of the file describing a module.
+const ModuleMetaFile = "meta.json"
+
+// Supported module visibilities.
+const (
+	ModuleVisibilityPrivate = "private"
+	ModuleVisibilityPublic  = "public"
+)
+
+// defaultModuleEntrypoint is the path, relative to the module directory, that scaffolded modules are built to.
+const defaultModuleEntrypoint = "bin/module"
+
+// serverStopTimeout is how long a locally run viam-server is given to shut down before it is killed.
+const serverStopTimeout = 10 * time.Second
+
+//go:embed module_template/*.tmpl
+var moduleTemplates embed.FS
+
+// ModuleMeta describes a module, and is stored in its meta.json.
+type ModuleMeta struct {
+	// ModuleID is formatted as namespace:name.
+	ModuleID    string        `json:"module_id"`
+	Visibility  string        `json:"visibility"`
+	URL         string        `json:"url"`
+	Description string        `json:"description"`
+	Models      []ModuleModel `json:"models"`
+	// Entrypoint is the path to the module's executable, relative to the directory containing meta.json.
+	Entrypoint string `json:"entrypoint"`
+}
+
+// ModuleModel is a model a module provides, and the API it implements.
+type ModuleModel struct {
+	API   string `json:"api"`
+	Model string `json:"model"`
+}
+
+// name returns the name part of the module's ID, which is the name the module is configured with on a robot.
+func (m *ModuleMeta) name() string {
+	return m.ModuleID[strings.LastIndex(m.ModuleID, ":")+1:]
+}
+
+// moduleConfig returns the config a robot would run the module in dir with.
+func (m *ModuleMeta) moduleConfig(dir string) config.Module {
+	return config.Module{Name: m.name(), ExePath: filepath.Join(dir, filepath.FromSlash(m.Entrypoint))}
+}
+
+// ReadModuleMeta reads the module meta file at path.
+func ReadModuleMeta(path string) (*ModuleMeta, error) {
+	//nolint:gosec
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var meta ModuleMeta
+	if err := json.Unmarshal(contents, &meta); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse module meta file %s", path)
+	}
+	return &meta, nil
+}
+
+// ValidateModule checks that the module meta file at metaPath is complete, and that a robot could run the module it
+// describes. The module's entrypoint must already be built.
+func ValidateModule(metaPath string) (*ModuleMeta, error) {
+	meta, err := ReadModuleMeta(metaPath)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(meta.ModuleID, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("module_id %q must be formatted as namespace:name", meta.ModuleID)
+	}
+	if meta.Visibility != ModuleVisibilityPrivate && meta.Visibility != ModuleVisibilityPublic {
+		return nil, errors.Errorf("visibility must be %s or %s, got %q",
+			ModuleVisibilityPrivate, ModuleVisibilityPublic, meta.Visibility)
+	}
+	if len(meta.Models) == 0 {
+		return nil, errors.New("models must list at least one model")
+	}
+	for idx, model := range meta.Models {
+		if _, err := resource.NewAPIFromString(model.API); err != nil {
+			return nil, errors.Wrapf(err, "models.%d.api", idx)
+		}
+		if _, err := resource.NewModelFromString(model.Model); err != nil {
+			return nil, errors.Wrapf(err, "models.%d.model", idx)
+		}
+	}
+	if meta.Entrypoint == "" {
+		return nil, errors.New("entrypoint is required")
+	}
+	if filepath.IsAbs(meta.Entrypoint) || strings.HasPrefix(filepath.Clean(filepath.FromSlash(meta.Entrypoint)), "..") {
+		return nil, errors.Errorf("entrypoint %q must be a path within the module directory", meta.Entrypoint)
+	}
+
+	modConf := meta.moduleConfig(filepath.Dir(metaPath))
+	if err := modConf.Validate(meta.ModuleID); err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(modConf.ExePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, errors.Errorf("entrypoint %s is a directory", modConf.ExePath)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0 {
+		return nil, errors.Errorf("entrypoint %s is not executable", modConf.ExePath)
+	}
+	return meta, nil
+}
+
+// moduleTemplateData is passed to the module templates.
+type moduleTemplateData struct {
+	Name       string
+	Model      resource.Model
+	Entrypoint string
+}
+
+// CreateModule scaffolds a new module with the given ID in dir, based on the simplemodule example in
+// examples/customresources. The module provides a single model of the rdk:component:generic API, which defaults to
+// namespace:name:counter.
+func CreateModule(w io.Writer, dir, moduleID, modelStr string) error {
+	parts := strings.Split(moduleID, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return errors.Errorf("module id %q must be formatted as namespace:name", moduleID)
+	}
+	namespace, name := parts[0], parts[1]
+	// Only the name is validated here, since the module has not been built yet.
+	if err := (&config.Module{Name: name, ExePath: "."}).Validate(moduleID); err != nil {
+		return err
+	}
+	if modelStr == "" {
+		modelStr = fmt.Sprintf("%s:%s:counter", namespace, name)
+	}
+	model, err := resource.NewModelFromString(modelStr)
+	if err != nil {
+		return err
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return errors.Errorf("%s already exists and is not empty", dir)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	data := moduleTemplateData{Name: name, Model: model, Entrypoint: defaultModuleEntrypoint}
+	templates, err := fs.Glob(moduleTemplates, "module_template/*.tmpl")
+	if err != nil {
+		return err
+	}
+	for _, tmplPath := range templates {
+		tmpl, err := template.ParseFS(moduleTemplates, tmplPath)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, strings.TrimSuffix(filepath.Base(tmplPath), ".tmpl"))
+		if err := writeTemplate(path, tmpl, data); err != nil {
+			return err
+		}
+	}
+
+	meta := ModuleMeta{
+		ModuleID:   moduleID,
+		Visibility: ModuleVisibilityPrivate,
+		Models:     []ModuleModel{{API: "rdk:component:generic", Model: model.String()}},
+		Entrypoint: defaultModuleEntrypoint,
+	}
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ModuleMetaFile), append(metaJSON, '\n'), 0o600); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Created module %s in %s\n", moduleID, dir)
+	fmt.Fprintf(w, "Run \"go mod tidy\" and \"make module\" in %s to build it, then \"make run\" to try it out\n", dir)
+	return nil
+}
+
+func writeTemplate(path string, tmpl *template.Template, data interface{}) (err error) {
+	//nolint:gosec
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, f.Close())
+	}()
+	return tmpl.Execute(f, data)
+}
+
+// RunModule smoke tests the module described by the meta file at metaPath by running it in a throwaway viam-server,
+// started from serverPath with the robot config at configPath. The module is added to the config's modules. The
+// server runs until ctx is cancelled or, if duration is non-zero, until duration has passed; it is an error for the
+// server to exit before then.
+func RunModule(
+	ctx context.Context,
+	w io.Writer,
+	metaPath, configPath, serverPath string,
+	duration time.Duration,
+) error {
+	meta, err := ValidateModule(metaPath)
+	if err != nil {
+		return errors.Wrap(err, "invalid module")
+	}
+	modConf := meta.moduleConfig(filepath.Dir(metaPath))
+	if modConf.ExePath, err = filepath.Abs(modConf.ExePath); err != nil {
+		return err
+	}
+
+	//nolint:gosec
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	var robotConf map[string]interface{}
+	if err := json.Unmarshal(contents, &robotConf); err != nil {
+		return errors.Wrapf(err, "failed to parse robot config %s", configPath)
+	}
+	// Replace any module of the same name, such as a previously installed version of this module.
+	modules := []interface{}{modConf}
+	existing, _ := robotConf["modules"].([]interface{})
+	for _, mod := range existing {
+		if modMap, ok := mod.(map[string]interface{}); !ok || modMap["name"] != modConf.Name {
+			modules = append(modules, mod)
+		}
+	}
+	robotConf["modules"] = modules
+
+	tmpDir, err := os.MkdirTemp("", "viam-module-run")
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(func() error { return os.RemoveAll(tmpDir) })
+	tmpConfig := filepath.Join(tmpDir, "robot.json")
+	robotJSON, err := json.MarshalIndent(robotConf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(tmpConfig, robotJSON, 0o600); err != nil {
+		return err
+	}
+
+	if serverPath, err = exec.LookPath(serverPath); err != nil {
+		return errors.Wrap(err, "could not find viam-server, set its path with --server-path")
+	}
+	//nolint:gosec
+	cmd := exec.Command(serverPath, "-config", tmpConfig)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	fmt.Fprintf(w, "Running module %s with %s\n", meta.ModuleID, serverPath)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	var timeout <-chan time.Time
+	if duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case err := <-exited:
+		if err == nil {
+			err = errors.New("exited with no error")
+		}
+		return errors.Wrap(err, "viam-server stopped before the module run finished")
+	case <-ctx.Done():
+	case <-timeout:
+	}
+	return stopServer(cmd, exited)
+}
+
+// stopServer asks the viam-server run by cmd to shut down, and kills it if it does not do so in time.
+func stopServer(cmd *exec.Cmd, exited <-chan error) error {
+	if runtime.GOOS == "windows" || cmd.Process.Signal(os.Interrupt) != nil {
+		return cmd.Process.Kill()
+	}
+	select {
+	case <-exited:
+		return nil
+	case <-time.After(serverStopTimeout):
+		return multierr.Combine(errors.New("viam-server did not shut down in time"), cmd.Process.Kill())
+	}
+}
+
+// PackageModule writes a gzipped tarball of the module described by the meta file at metaPath to output, for upload
+// to the module registry. The tarball contains the meta file, the module's entrypoint, and the files and directories
+// in include, which are relative to the module directory.
+func PackageModule(w io.Writer, metaPath, output string, include []string) (err error) {
+	meta, err := ValidateModule(metaPath)
+	if err != nil {
+		return errors.Wrap(err, "invalid module")
+	}
+	dir := filepath.Dir(metaPath)
+
+	//nolint:gosec
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+	defer func() {
+		err = multierr.Combine(err, tw.Close(), gzw.Close(), f.Close())
+		if err != nil {
+			utils.UncheckedError(os.Remove(output))
+		}
+	}()
+
+	outputAbs, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+	added := make(map[string]bool)
+	addFile := func(path string, info fs.FileInfo) error {
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if strings.HasPrefix(name, "../") {
+			return errors.Errorf("%s is not within the module directory %s", path, dir)
+		}
+		// Never include the tarball in itself.
+		if abs, err := filepath.Abs(path); err != nil || abs == outputAbs || added[name] {
+			return err
+		}
+		added[name] = true
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		//nolint:gosec
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer utils.UncheckedErrorFunc(src.Close)
+		_, err = io.Copy(tw, src)
+		return err
+	}
+
+	for _, p := range include {
+		if filepath.IsAbs(p) || strings.HasPrefix(filepath.Clean(p), "..") {
+			return errors.Errorf("%s is not within the module directory %s", p, dir)
+		}
+	}
+	paths := append([]string{filepath.Base(metaPath), filepath.FromSlash(meta.Entrypoint)}, include...)
+	for _, p := range paths {
+		if err := filepath.Walk(filepath.Join(dir, p), func(path string, info fs.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			return addFile(path, info)
+		}); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "Packaged %d files of module %s into %s\n", len(added), meta.ModuleID, output)
+	return nil
+}
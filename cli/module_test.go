@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+// writeModule writes a module with the given meta file and a built entrypoint to a new directory, and returns the
+// path of its meta file.
+func writeModule(t *testing.T, meta ModuleMeta) string {
+	t.Helper()
+	dir := t.TempDir()
+	metaJSON, err := json.Marshal(meta)
+	test.That(t, err, test.ShouldBeNil)
+	metaPath := filepath.Join(dir, ModuleMetaFile)
+	test.That(t, os.WriteFile(metaPath, metaJSON, 0o600), test.ShouldBeNil)
+	if meta.Entrypoint != "" && !filepath.IsAbs(meta.Entrypoint) {
+		exePath := filepath.Join(dir, filepath.FromSlash(meta.Entrypoint))
+		test.That(t, os.MkdirAll(filepath.Dir(exePath), 0o750), test.ShouldBeNil)
+		//nolint:gosec
+		test.That(t, os.WriteFile(exePath, []byte("#!/bin/sh\n"), 0o700), test.ShouldBeNil)
+	}
+	return metaPath
+}
+
+func validModuleMeta() ModuleMeta {
+	return ModuleMeta{
+		ModuleID:   "acme:counter",
+		Visibility: ModuleVisibilityPrivate,
+		Models:     []ModuleModel{{API: "rdk:component:generic", Model: "acme:counter:counter"}},
+		Entrypoint: "bin/module",
+	}
+}
+
+func TestValidateModule(t *testing.T) {
+	meta, err := ValidateModule(writeModule(t, validModuleMeta()))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, meta.name(), test.ShouldEqual, "counter")
+
+	for _, tc := range []struct {
+		name   string
+		modify func(meta *ModuleMeta)
+		err    string
+	}{
+		{"missing namespace", func(meta *ModuleMeta) { meta.ModuleID = "counter" }, "namespace:name"},
+		{"unknown visibility", func(meta *ModuleMeta) { meta.Visibility = "secret" }, "visibility"},
+		{"no models", func(meta *ModuleMeta) { meta.Models = nil }, "at least one model"},
+		{"invalid api", func(meta *ModuleMeta) { meta.Models[0].API = "generic" }, "models.0.api"},
+		{"invalid model", func(meta *ModuleMeta) { meta.Models[0].Model = "acme:counter" }, "models.0.model"},
+		{"no entrypoint", func(meta *ModuleMeta) { meta.Entrypoint = "" }, "entrypoint is required"},
+		{"entrypoint outside module", func(meta *ModuleMeta) { meta.Entrypoint = "../module" }, "within the module"},
+		{"absolute entrypoint", func(meta *ModuleMeta) { meta.Entrypoint = "/usr/bin/module" }, "within the module"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := validModuleMeta()
+			tc.modify(&meta)
+			_, err := ValidateModule(writeModule(t, meta))
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		})
+	}
+
+	t.Run("entrypoint not built", func(t *testing.T) {
+		metaPath := writeModule(t, validModuleMeta())
+		test.That(t, os.Remove(filepath.Join(filepath.Dir(metaPath), "bin", "module")), test.ShouldBeNil)
+		_, err := ValidateModule(metaPath)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+// tarballContents returns the names and contents of the files in the gzipped tarball at path.
+func tarballContents(t *testing.T, path string) map[string]string {
+	t.Helper()
+	//nolint:gosec
+	f, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, f.Close(), test.ShouldBeNil)
+	}()
+	gzr, err := gzip.NewReader(f)
+	test.That(t, err, test.ShouldBeNil)
+	tr := tar.NewReader(gzr)
+	contents := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		test.That(t, err, test.ShouldBeNil)
+		data, err := io.ReadAll(tr)
+		test.That(t, err, test.ShouldBeNil)
+		contents[header.Name] = string(data)
+	}
+	return contents
+}
+
+func TestPackageModule(t *testing.T) {
+	metaPath := writeModule(t, validModuleMeta())
+	dir := filepath.Dir(metaPath)
+	test.That(t, os.MkdirAll(filepath.Join(dir, "assets", "nested"), 0o750), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "assets", "nested", "model.bin"), []byte("weights"), 0o600), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0o600), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes"), 0o600), test.ShouldBeNil)
+
+	// The tarball is written within the module directory, and included directories are walked, but it is never
+	// packaged into itself.
+	output := filepath.Join(dir, "module.tar.gz")
+	var out bytes.Buffer
+	err := PackageModule(&out, metaPath, output, []string{"assets", "README.md", "."})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldContainSubstring, "acme:counter")
+
+	contents := tarballContents(t, output)
+	var names []string
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	test.That(t, names, test.ShouldResemble, []string{"README.md", "assets/nested/model.bin", "bin/module", "meta.json", "notes.txt"})
+	test.That(t, contents["assets/nested/model.bin"], test.ShouldEqual, "weights")
+
+	t.Run("invalid module", func(t *testing.T) {
+		meta := validModuleMeta()
+		meta.Visibility = ""
+		metaPath := writeModule(t, meta)
+		output := filepath.Join(t.TempDir(), "module.tar.gz")
+		err := PackageModule(io.Discard, metaPath, output, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid module")
+		_, err = os.Stat(output)
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	})
+
+	t.Run("include outside module", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "module.tar.gz")
+		err := PackageModule(io.Discard, metaPath, output, []string{".."})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "not within the module directory")
+		// A partially written tarball is removed.
+		_, err = os.Stat(output)
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	})
+}
+
+func TestCreateModule(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "counter")
+	var out bytes.Buffer
+	test.That(t, CreateModule(&out, dir, "acme:counter", ""), test.ShouldBeNil)
+
+	meta, err := ReadModuleMeta(filepath.Join(dir, ModuleMetaFile))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, meta.Models, test.ShouldResemble, []ModuleModel{{API: "rdk:component:generic", Model: "acme:counter:counter"}})
+	test.That(t, meta.Entrypoint, test.ShouldEqual, defaultModuleEntrypoint)
+
+	//nolint:gosec
+	mainGo, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	test.That(t, err, test.ShouldBeNil)
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", mainGo, parser.ImportsOnly)
+	test.That(t, err, test.ShouldBeNil)
+	var imports []string
+	for _, imp := range file.Imports {
+		imports = append(imports, strings.Trim(imp.Path.Value, `"`))
+	}
+	test.That(t, imports, test.ShouldContain, "github.com/edaniels/golog")
+	test.That(t, imports, test.ShouldNotContain, "go.uber.org/zap")
+	test.That(t, string(mainGo), test.ShouldContainSubstring, `resource.NewModel("acme", "counter", "counter")`)
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", mainGo, parser.AllErrors)
+	test.That(t, err, test.ShouldBeNil)
+
+	// Existing modules are not overwritten.
+	test.That(t, CreateModule(io.Discard, dir, "acme:counter", ""), test.ShouldNotBeNil)
+	// Module IDs must have a namespace.
+	test.That(t, CreateModule(io.Discard, t.TempDir(), "counter", ""), test.ShouldNotBeNil)
+}
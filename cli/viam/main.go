@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/edaniels/golog"
@@ -37,6 +40,18 @@ const (
 	dataFlagParallelDownloads = "parallel"
 	dataFlagTags              = "tags"
 	dataFlagBboxLabels        = "bbox_labels"
+	dataFlagFormat            = "format"
+	dataFlagInterval          = "interval"
+
+	moduleFlagMeta       = "module"
+	moduleFlagID         = "id"
+	moduleFlagModel      = "model"
+	moduleFlagDir        = "dir"
+	moduleFlagConfig     = "config"
+	moduleFlagServerPath = "server-path"
+	moduleFlagDuration   = "duration"
+	moduleFlagOutput     = "output"
+	moduleFlagInclude    = "include"
 
 	dataTypeBinary  = "binary"
 	dataTypeTabular = "tabular"
@@ -417,6 +432,246 @@ func main() {
 						},
 						Action: DeleteCommand,
 					},
+					{
+						Name:  "local",
+						Usage: "inspect and convert data capture files on this machine",
+						Subcommands: []*cli.Command{
+							{
+								Name:      "list",
+								Usage:     "list data capture files in a directory",
+								UsageText: "viam data local list <directory>",
+								Action: func(c *cli.Context) error {
+									dir, err := requiredArg(c, "directory")
+									if err != nil {
+										return err
+									}
+									return rdkcli.ListCaptureFiles(c.App.Writer, dir)
+								},
+							},
+							{
+								Name:      "metadata",
+								Usage:     "print the metadata of a data capture file",
+								UsageText: "viam data local metadata <file>",
+								Action: func(c *cli.Context) error {
+									path, err := requiredArg(c, "file")
+									if err != nil {
+										return err
+									}
+									return rdkcli.PrintCaptureMetadata(c.App.Writer, path)
+								},
+							},
+							{
+								Name:      "dump",
+								Usage:     "print the tabular readings of data capture files",
+								UsageText: fmt.Sprintf("viam data local dump [%s] [%s] [%s] <file or directory>", dataFlagFormat, dataFlagStart, dataFlagEnd),
+								Flags: append([]cli.Flag{
+									&cli.StringFlag{
+										Name:  dataFlagFormat,
+										Value: rdkcli.DumpFormatCSV,
+										Usage: "output format: either csv or jsonl",
+									},
+								}, localTimeRangeFlags()...),
+								Action: func(c *cli.Context) error {
+									path, err := requiredArg(c, "file or directory")
+									if err != nil {
+										return err
+									}
+									timeRange, err := localTimeRange(c)
+									if err != nil {
+										return err
+									}
+									return rdkcli.DumpTabularData(c.App.Writer, path, c.String(dataFlagFormat), timeRange)
+								},
+							},
+							{
+								Name:  "extract",
+								Usage: "write the binary payloads of data capture files, such as images and point clouds, to standalone files",
+								UsageText: fmt.Sprintf("viam data local extract <%s> [%s] [%s] <file or directory>",
+									dataFlagDestination, dataFlagStart, dataFlagEnd),
+								Flags: append([]cli.Flag{
+									&cli.PathFlag{
+										Name:     dataFlagDestination,
+										Required: true,
+										Usage:    "output directory for extracted files",
+									},
+								}, localTimeRangeFlags()...),
+								Action: func(c *cli.Context) error {
+									path, err := requiredArg(c, "file or directory")
+									if err != nil {
+										return err
+									}
+									timeRange, err := localTimeRange(c)
+									if err != nil {
+										return err
+									}
+									return rdkcli.ExtractBinaryData(c.App.Writer, path, c.Path(dataFlagDestination), timeRange)
+								},
+							},
+							{
+								Name:  "merge",
+								Usage: "merge data capture files from the same resource and method into one file",
+								UsageText: fmt.Sprintf("viam data local merge <%s> [%s] [%s] <file>...",
+									dataFlagDestination, dataFlagStart, dataFlagEnd),
+								Flags: append([]cli.Flag{
+									&cli.PathFlag{
+										Name:     dataFlagDestination,
+										Required: true,
+										Usage:    "output directory for the merged file",
+									},
+								}, localTimeRangeFlags()...),
+								Action: func(c *cli.Context) error {
+									if c.NArg() == 0 {
+										return errors.New("at least one file is required")
+									}
+									timeRange, err := localTimeRange(c)
+									if err != nil {
+										return err
+									}
+									merged, err := rdkcli.MergeCaptureFiles(c.Args().Slice(), c.Path(dataFlagDestination), timeRange)
+									if err != nil {
+										return err
+									}
+									fmt.Fprintf(c.App.Writer, "wrote %s\n", merged)
+									return nil
+								},
+							},
+							{
+								Name:  "split",
+								Usage: "split a data capture file into files covering fixed time intervals",
+								UsageText: fmt.Sprintf("viam data local split <%s> <%s> [%s] [%s] <file>",
+									dataFlagDestination, dataFlagInterval, dataFlagStart, dataFlagEnd),
+								Flags: append([]cli.Flag{
+									&cli.PathFlag{
+										Name:     dataFlagDestination,
+										Required: true,
+										Usage:    "output directory for the split files",
+									},
+									&cli.DurationFlag{
+										Name:     dataFlagInterval,
+										Required: true,
+										Usage:    "time interval covered by each file, e.g. 1h or 30m",
+									},
+								}, localTimeRangeFlags()...),
+								Action: func(c *cli.Context) error {
+									path, err := requiredArg(c, "file")
+									if err != nil {
+										return err
+									}
+									timeRange, err := localTimeRange(c)
+									if err != nil {
+										return err
+									}
+									written, err := rdkcli.SplitCaptureFile(path, c.Path(dataFlagDestination), c.Duration(dataFlagInterval), timeRange)
+									if err != nil {
+										return err
+									}
+									for _, p := range written {
+										fmt.Fprintf(c.App.Writer, "wrote %s\n", p)
+									}
+									return nil
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "module",
+				Usage: "create, test and package modules",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "create",
+						Usage:     "scaffold a new module based on the examples in examples/customresources",
+						UsageText: fmt.Sprintf("viam module create <%s> [%s] [%s]", moduleFlagID, moduleFlagModel, moduleFlagDir),
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     moduleFlagID,
+								Required: true,
+								Usage:    "ID of the module, formatted as namespace:name",
+							},
+							&cli.StringFlag{
+								Name:  moduleFlagModel,
+								Usage: "model the module provides, formatted as namespace:family:name (default namespace:name:counter)",
+							},
+							&cli.PathFlag{
+								Name:  moduleFlagDir,
+								Usage: "directory to create the module in (default the module name)",
+							},
+						},
+						Action: func(c *cli.Context) error {
+							dir := c.Path(moduleFlagDir)
+							if dir == "" {
+								id := c.String(moduleFlagID)
+								dir = id[strings.LastIndex(id, ":")+1:]
+							}
+							return rdkcli.CreateModule(c.App.Writer, dir, c.String(moduleFlagID), c.String(moduleFlagModel))
+						},
+					},
+					{
+						Name:      "validate",
+						Usage:     "check that a module's meta.json is valid and its entrypoint is built",
+						UsageText: fmt.Sprintf("viam module validate [%s]", moduleFlagMeta),
+						Flags:     []cli.Flag{moduleMetaFlag()},
+						Action: func(c *cli.Context) error {
+							meta, err := rdkcli.ValidateModule(c.Path(moduleFlagMeta))
+							if err != nil {
+								return err
+							}
+							fmt.Fprintf(c.App.Writer, "Module %s is valid\n", meta.ModuleID)
+							return nil
+						},
+					},
+					{
+						Name:  "run",
+						Usage: "run a module locally in a throwaway viam-server for smoke testing",
+						UsageText: fmt.Sprintf("viam module run <%s> [%s] [%s] [%s]",
+							moduleFlagConfig, moduleFlagMeta, moduleFlagServerPath, moduleFlagDuration),
+						Flags: []cli.Flag{
+							moduleMetaFlag(),
+							&cli.PathFlag{
+								Name:     moduleFlagConfig,
+								Required: true,
+								Usage:    "robot config to run the module with; the module is added to its modules",
+							},
+							&cli.StringFlag{
+								Name:  moduleFlagServerPath,
+								Value: "viam-server",
+								Usage: "path to the viam-server executable",
+							},
+							&cli.DurationFlag{
+								Name:  moduleFlagDuration,
+								Usage: "stop the server after this long; if unset, it runs until interrupted",
+							},
+						},
+						Action: func(c *cli.Context) error {
+							ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+							defer stop()
+							return rdkcli.RunModule(ctx, c.App.Writer, c.Path(moduleFlagMeta), c.Path(moduleFlagConfig),
+								c.String(moduleFlagServerPath), c.Duration(moduleFlagDuration))
+						},
+					},
+					{
+						Name:  "package",
+						Usage: "package a module into a tarball for upload",
+						UsageText: fmt.Sprintf("viam module package [%s] [%s] [%s]",
+							moduleFlagMeta, moduleFlagOutput, moduleFlagInclude),
+						Flags: []cli.Flag{
+							moduleMetaFlag(),
+							&cli.PathFlag{
+								Name:  moduleFlagOutput,
+								Value: "module.tar.gz",
+								Usage: "path of the tarball to write",
+							},
+							&cli.StringSliceFlag{
+								Name:  moduleFlagInclude,
+								Usage: "additional files or directories, relative to the module directory, to include",
+							},
+						},
+						Action: func(c *cli.Context) error {
+							return rdkcli.PackageModule(c.App.Writer, c.Path(moduleFlagMeta), c.Path(moduleFlagOutput),
+								c.StringSlice(moduleFlagInclude))
+						},
+					},
 				},
 			},
 			{
@@ -877,6 +1132,52 @@ func DeleteCommand(c *cli.Context) error {
 	return nil
 }
 
+func moduleMetaFlag() cli.Flag {
+	return &cli.PathFlag{
+		Name:  moduleFlagMeta,
+		Value: rdkcli.ModuleMetaFile,
+		Usage: "path to the module's meta.json",
+	}
+}
+
+func requiredArg(c *cli.Context, name string) (string, error) {
+	if c.Args().First() == "" {
+		return "", errors.Errorf("%s required", name)
+	}
+	return c.Args().First(), nil
+}
+
+func localTimeRangeFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     dataFlagStart,
+			Required: false,
+			Usage:    "ISO-8601 timestamp indicating the start of the interval filter",
+		},
+		&cli.StringFlag{
+			Name:     dataFlagEnd,
+			Required: false,
+			Usage:    "ISO-8601 timestamp indicating the end of the interval filter",
+		},
+	}
+}
+
+func localTimeRange(c *cli.Context) (rdkcli.TimeRange, error) {
+	var timeRange rdkcli.TimeRange
+	var err error
+	if c.String(dataFlagStart) != "" {
+		if timeRange.Start, err = time.Parse(time.RFC3339, c.String(dataFlagStart)); err != nil {
+			return rdkcli.TimeRange{}, errors.Wrap(err, "error parsing start flag")
+		}
+	}
+	if c.String(dataFlagEnd) != "" {
+		if timeRange.End, err = time.Parse(time.RFC3339, c.String(dataFlagEnd)); err != nil {
+			return rdkcli.TimeRange{}, errors.Wrap(err, "error parsing end flag")
+		}
+	}
+	return timeRange, nil
+}
+
 func createDataFilter(c *cli.Context) (*datapb.Filter, error) {
 	filter := &datapb.Filter{}
 
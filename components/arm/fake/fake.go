@@ -4,10 +4,12 @@ package fake
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/utils"
 
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/arm/eva"
@@ -209,6 +211,23 @@ func (a *Arm) GoToInputs(ctx context.Context, goal []referenceframe.Input) error
 	return a.MoveToJointPositions(ctx, positionDegs, nil)
 }
 
+// FollowTrajectory sets the joints to each point of the trajectory at the point's time, as an arm following a time
+// stamped joint trajectory would.
+func (a *Arm) FollowTrajectory(ctx context.Context, points []motionplan.TrajectoryPoint) error {
+	start := time.Now()
+	for _, point := range points {
+		if wait := point.Time - time.Since(start); wait > 0 {
+			if !utils.SelectContextOrWait(ctx, wait) {
+				return ctx.Err()
+			}
+		}
+		if err := a.GoToInputs(ctx, point.Inputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Close does nothing.
 func (a *Arm) Close(ctx context.Context) error {
 	a.mu.Lock()
@@ -3,11 +3,13 @@ package fake
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 )
@@ -88,3 +90,39 @@ func TestReconfigure(t *testing.T) {
 	test.That(t, fakeArm.joints.Values, test.ShouldResemble, modelJoints)
 	test.That(t, fakeArm.model, test.ShouldResemble, model)
 }
+
+func TestFollowTrajectory(t *testing.T) {
+	cfg := resource.Config{
+		Name:                "testArm",
+		ConvertedAttributes: &Config{ArmModel: "ur5e"},
+	}
+	a, err := NewArm(context.Background(), nil, cfg, golog.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	fakeArm, ok := a.(motionplan.TrajectoryFollower)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// The UR5e model declares joint velocity limits, so the trajectory is timed with them.
+	goal := referenceframe.FloatsToInputs([]float64{0.2, -0.1, 0.1, 0, 0.1, -0.2})
+	traj, err := motionplan.NewFrameTrajectory(a.ModelFrame(), [][]referenceframe.Input{
+		make([]referenceframe.Input, len(goal)),
+		goal,
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	start := time.Now()
+	components := map[string]referenceframe.InputEnabled{"testArm": a}
+	test.That(t, motionplan.ExecuteTrajectory(context.Background(), traj, components, 10*time.Millisecond), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, traj.Duration())
+
+	inputs, err := a.CurrentInputs(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	for i, input := range inputs {
+		test.That(t, input.Value, test.ShouldAlmostEqual, goal[i].Value)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	samples, err := traj.Sample(10 * time.Millisecond)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fakeArm.FollowTrajectory(ctx, samples["testArm"]), test.ShouldBeError, context.Canceled)
+}
@@ -468,6 +468,84 @@ func (ua *URArm) MoveToJointPositionRadians(ctx context.Context, radians []float
 	}
 }
 
+// Parameters of the servoj commands the arm follows trajectories with, and how long after a trajectory should end the
+// arm may take to settle on its last point.
+const (
+	servoLookaheadTime    = 0.1
+	servoGain             = 300
+	trajectorySettleDelay = 5 * time.Second
+)
+
+// FollowTrajectory sends the arm a program that servos its joints to each point of the trajectory at the point's time,
+// then waits for the arm to reach the last point.
+func (ua *URArm) FollowTrajectory(ctx context.Context, points []motionplan.TrajectoryPoint) error {
+	if !ua.inRemoteMode {
+		return errors.New("UR5 is in local mode; use the polyscope to switch it to remote control mode")
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	last := points[len(points)-1]
+	if err := arm.CheckDesiredJointPositions(ctx, ua, ua.model.ProtobufFromInput(last.Inputs)); err != nil {
+		return err
+	}
+	ctx, done := ua.opMgr.New(ctx)
+	defer done()
+
+	ua.muMove.Lock()
+	defer ua.muMove.Unlock()
+
+	var program strings.Builder
+	program.WriteString("def follow_trajectory():\r\n")
+	var prev time.Duration
+	for _, point := range points {
+		radians := referenceframe.InputsToFloats(point.Inputs)
+		if len(radians) != 6 {
+			return errors.New("need 6 joints")
+		}
+		// The first point is where the arm already is, and servoj takes how long to move to its point for.
+		dt := (point.Time - prev).Seconds()
+		prev = point.Time
+		if dt <= 0 {
+			continue
+		}
+		fmt.Fprintf(&program, "  servoj([%f,%f,%f,%f,%f,%f], t=%f, lookahead_time=%1.2f, gain=%d)\r\n",
+			radians[0], radians[1], radians[2], radians[3], radians[4], radians[5], dt, servoLookaheadTime, servoGain)
+	}
+	fmt.Fprintf(&program, "  stopj(%1.2f)\r\nend\r\n", 5.0*ua.speed)
+
+	if _, err := ua.connControl.Write([]byte(program.String())); err != nil {
+		return err
+	}
+
+	goal := referenceframe.InputsToFloats(last.Inputs)
+	deadline := time.Now().Add(last.Time + trajectorySettleDelay)
+	for {
+		state, err := ua.State()
+		if err != nil {
+			return err
+		}
+		reached := true
+		for idx, r := range goal {
+			if math.Round(r*100) != math.Round(state.Joints[idx].Qactual*100) {
+				reached = false
+			}
+		}
+		if reached {
+			return nil
+		}
+		if err := ua.getAndResetRuntimeError(); err != nil {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("did not reach the end of the trajectory within %v of it ending", trajectorySettleDelay)
+		}
+		if !goutils.SelectContextOrWait(ctx, 10*time.Millisecond) {
+			return ctx.Err()
+		}
+	}
+}
+
 // CurrentInputs TODO.
 func (ua *URArm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
 	res, err := ua.JointPositions(ctx, nil)
@@ -183,6 +183,17 @@ func CollisionGeometry(cfg *referenceframe.LinkConfig) ([]spatialmath.Geometry,
 		r += geoCfg.R
 	case spatialmath.CapsuleType:
 		r += geoCfg.L / 2
+	case spatialmath.CylinderType:
+		r += math.Hypot(geoCfg.R, geoCfg.L/2)
+	case spatialmath.MeshType:
+		// bound the mesh by the sphere around its bounding box
+		mesh, err := geoCfg.ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+		bounds := mesh.ToProtobuf()
+		dims := bounds.GetBox().GetDimsMm()
+		r = spatialmath.NewPoseFromProtobuf(bounds.Center).Point().Norm() + r3.Vector{X: dims.X, Y: dims.Y, Z: dims.Z}.Norm()/2
 	case spatialmath.UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		if norm := (r3.Vector{X: geoCfg.X, Y: geoCfg.Y, Z: geoCfg.Z}).Norm(); norm > 0 {
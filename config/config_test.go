@@ -601,6 +601,39 @@ func TestRemoteValidate(t *testing.T) {
 	})
 }
 
+func TestModuleHealthCheckConfig(t *testing.T) {
+	var mod config.Module
+	err := json.Unmarshal([]byte(`{
+		"name": "mod",
+		"executable_path": "/bin/true",
+		"health_check_interval": "30s",
+		"health_check_timeout": "2s",
+		"health_check_failures": 5
+	}`), &mod)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mod, test.ShouldResemble, config.Module{
+		Name:                "mod",
+		ExePath:             "/bin/true",
+		HealthCheckInterval: 30 * time.Second,
+		HealthCheckTimeout:  2 * time.Second,
+		HealthCheckFailures: 5,
+	})
+
+	md, err := json.Marshal(mod)
+	test.That(t, err, test.ShouldBeNil)
+	var roundTripped config.Module
+	test.That(t, json.Unmarshal(md, &roundTripped), test.ShouldBeNil)
+	test.That(t, roundTripped, test.ShouldResemble, mod)
+
+	mod.HealthCheckTimeout = -time.Second
+	err = mod.Validate("modules.0")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "health check settings cannot be negative")
+
+	err = json.Unmarshal([]byte(`{"name": "mod", "health_check_interval": "often"}`), &mod)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestCopyOnlyPublicFields(t *testing.T) {
 	t.Run("copy sample config", func(t *testing.T) {
 		content, err := os.ReadFile("data/robot.json")
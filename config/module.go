@@ -1,8 +1,10 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"regexp"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -25,6 +27,71 @@ type Module struct {
 	// value besides "" or "debug" is used for LogLevel ("log_level" in JSON). In other words, setting a LogLevel
 	// of something like "info" will ignore the debug setting on the server.
 	LogLevel string `json:"log_level"`
+	// HealthCheckInterval is how often the module is sent a gRPC health probe. If unset, the module manager's default
+	// is used.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout is how long the module has to respond to a health probe before the probe fails. If unset, the
+	// module manager's default is used.
+	HealthCheckTimeout time.Duration
+	// HealthCheckFailures is the number of consecutive health probes the module must fail to be considered hung and
+	// restarted. If unset, the module manager's default is used.
+	HealthCheckFailures int
+}
+
+// Note: keep this in sync with Module.
+type moduleData struct {
+	Name                string `json:"name"`
+	ExePath             string `json:"executable_path"`
+	LogLevel            string `json:"log_level"`
+	HealthCheckInterval string `json:"health_check_interval,omitempty"`
+	HealthCheckTimeout  string `json:"health_check_timeout,omitempty"`
+	HealthCheckFailures int    `json:"health_check_failures,omitempty"`
+}
+
+// UnmarshalJSON unmarshals JSON data into this config.
+func (m *Module) UnmarshalJSON(data []byte) error {
+	var temp moduleData
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	*m = Module{
+		Name:                temp.Name,
+		ExePath:             temp.ExePath,
+		LogLevel:            temp.LogLevel,
+		HealthCheckFailures: temp.HealthCheckFailures,
+	}
+	if temp.HealthCheckInterval != "" {
+		dur, err := time.ParseDuration(temp.HealthCheckInterval)
+		if err != nil {
+			return err
+		}
+		m.HealthCheckInterval = dur
+	}
+	if temp.HealthCheckTimeout != "" {
+		dur, err := time.ParseDuration(temp.HealthCheckTimeout)
+		if err != nil {
+			return err
+		}
+		m.HealthCheckTimeout = dur
+	}
+	return nil
+}
+
+// MarshalJSON marshals out this config.
+func (m Module) MarshalJSON() ([]byte, error) {
+	temp := moduleData{
+		Name:                m.Name,
+		ExePath:             m.ExePath,
+		LogLevel:            m.LogLevel,
+		HealthCheckFailures: m.HealthCheckFailures,
+	}
+	if m.HealthCheckInterval != 0 {
+		temp.HealthCheckInterval = m.HealthCheckInterval.String()
+	}
+	if m.HealthCheckTimeout != 0 {
+		temp.HealthCheckTimeout = m.HealthCheckTimeout.String()
+	}
+	return json.Marshal(temp)
 }
 
 // Validate checks if the config is valid.
@@ -43,5 +110,9 @@ func (m *Module) Validate(path string) error {
 		return errors.Errorf("module %s cannot use the reserved name of %s", path, reservedModuleName)
 	}
 
+	if m.HealthCheckInterval < 0 || m.HealthCheckTimeout < 0 || m.HealthCheckFailures < 0 {
+		return errors.Errorf("module %s health check settings cannot be negative", path)
+	}
+
 	return nil
 }
@@ -0,0 +1,218 @@
+package data
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	// Register the image formats cameras are captured in so that frames can be decoded.
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"math/bits"
+	"reflect"
+	"strings"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+)
+
+// CaptureFilter decides which captured readings are persisted. Readings it rejects are counted as suppressed in the
+// collector's CollectorStats and, if the collector's target is a datacapture.SuppressionRecorder, in the metadata of
+// the data capture files written.
+type CaptureFilter interface {
+	Keep(msg *v1.SensorData) bool
+}
+
+// Deadband is how much a numeric field must change by for a reading to be considered changed. If both thresholds
+// are set, a change must exceed both. If neither is set, any change is significant.
+type Deadband struct {
+	// Absolute is the change in the field's value.
+	Absolute float64
+	// Relative is the change in the field's value as a fraction of its last persisted value.
+	Relative float64
+}
+
+// ChangeFilterConfig configures a ChangeFilter.
+type ChangeFilterConfig struct {
+	// Deadbands are keyed by the dot separated path of a field in tabular readings, such as "readings.temp". A
+	// deadband applies to the field it names and every field nested within it.
+	Deadbands map[string]Deadband
+	// FrameHashDistance is the number of bits the perceptual hashes of two camera frames may differ by for the
+	// frames to be considered identical. Binary readings that are not images are only identical if equal.
+	FrameHashDistance int
+	// MaxSuppressed is the longest time readings are suppressed for. Once it has passed since a reading was last
+	// persisted, the next reading is persisted even if unchanged. If 0, readings are suppressed indefinitely.
+	MaxSuppressed time.Duration
+}
+
+// ChangeFilter only keeps readings that have changed significantly since the last reading it kept. Tabular readings
+// are compared field by field, and camera frames by their perceptual hash. It is not safe for concurrent use.
+type ChangeFilter struct {
+	conf ChangeFilterConfig
+
+	hasLast    bool
+	lastKept   time.Time
+	lastFields map[string]interface{}
+	lastImage  bool
+	lastHash   uint64
+	lastDigest [sha256.Size]byte
+}
+
+// NewChangeFilter returns a new ChangeFilter.
+func NewChangeFilter(conf ChangeFilterConfig) *ChangeFilter {
+	return &ChangeFilter{conf: conf}
+}
+
+// Keep returns whether msg has changed significantly since the last reading kept, and if so records it as the last
+// reading kept.
+func (f *ChangeFilter) Keep(msg *v1.SensorData) bool {
+	at := requestedAt(msg)
+	if binary := msg.GetBinary(); binary != nil {
+		return f.keepBinary(binary, at)
+	}
+	return f.keepTabular(msg.GetStruct().AsMap(), at)
+}
+
+func (f *ChangeFilter) expired(at time.Time) bool {
+	return f.conf.MaxSuppressed > 0 && at.Sub(f.lastKept) >= f.conf.MaxSuppressed
+}
+
+func (f *ChangeFilter) keepTabular(reading map[string]interface{}, at time.Time) bool {
+	fields := make(map[string]interface{})
+	flattenFields("", reading, fields)
+	if f.hasLast && !f.expired(at) && !f.fieldsChanged(fields) {
+		return false
+	}
+	f.hasLast, f.lastKept, f.lastFields = true, at, fields
+	return true
+}
+
+func (f *ChangeFilter) fieldsChanged(fields map[string]interface{}) bool {
+	if len(fields) != len(f.lastFields) {
+		return true
+	}
+	for key, value := range fields {
+		last, ok := f.lastFields[key]
+		if !ok {
+			return true
+		}
+		newNum, newIsNum := value.(float64)
+		lastNum, lastIsNum := last.(float64)
+		if !newIsNum || !lastIsNum {
+			if !reflect.DeepEqual(value, last) {
+				return true
+			}
+			continue
+		}
+		if exceedsDeadband(newNum, lastNum, f.deadband(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// deadband returns the deadband of the most specific configured path containing key.
+func (f *ChangeFilter) deadband(key string) Deadband {
+	for path := key; ; {
+		if deadband, ok := f.conf.Deadbands[path]; ok {
+			return deadband
+		}
+		idx := strings.LastIndex(path, ".")
+		if idx < 0 {
+			return Deadband{}
+		}
+		path = path[:idx]
+	}
+}
+
+func exceedsDeadband(value, last float64, deadband Deadband) bool {
+	change := math.Abs(value - last)
+	if deadband.Absolute == 0 && deadband.Relative == 0 {
+		return change != 0
+	}
+	if deadband.Absolute > 0 && change <= deadband.Absolute {
+		return false
+	}
+	if deadband.Relative > 0 && change <= deadband.Relative*math.Abs(last) {
+		return false
+	}
+	return true
+}
+
+// flattenFields stores the leaves of the nested map reading in fields, keyed by their dot separated path.
+func flattenFields(prefix string, reading map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range reading {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenFields(path, nested, fields)
+			continue
+		}
+		fields[path] = value
+	}
+}
+
+func (f *ChangeFilter) keepBinary(binary []byte, at time.Time) bool {
+	hash, isImage := perceptualHash(binary)
+	var digest [sha256.Size]byte
+	if !isImage {
+		digest = sha256.Sum256(binary)
+	}
+	if f.hasLast && !f.expired(at) && isImage == f.lastImage {
+		if isImage && bits.OnesCount64(hash^f.lastHash) <= f.conf.FrameHashDistance {
+			return false
+		}
+		if !isImage && digest == f.lastDigest {
+			return false
+		}
+	}
+	f.hasLast, f.lastKept, f.lastImage, f.lastHash, f.lastDigest = true, at, isImage, hash, digest
+	return true
+}
+
+// perceptualHash returns the difference hash of the image encoded in data, or false if data is not an image. The
+// hash compares the brightness of horizontally adjacent cells of a 9x8 grid over the image, so it is unaffected by
+// small changes such as compression artifacts and sensor noise.
+func perceptualHash(data []byte) (uint64, bool) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, false
+	}
+	const (
+		width  = 9
+		height = 8
+		// samples is the number of pixels sampled across each cell in each dimension.
+		samples = 4
+	)
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return 0, false
+	}
+	var cells [height][width]float64
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			var sum float64
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					x := bounds.Min.X + (cx*samples+sx)*bounds.Dx()/(width*samples)
+					y := bounds.Min.Y + (cy*samples+sy)*bounds.Dy()/(height*samples)
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+			cells[cy][cx] = sum
+		}
+	}
+	var hash uint64
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width-1; cx++ {
+			hash <<= 1
+			if cells[cy][cx] < cells[cy][cx+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, true
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func tabularReading(t *testing.T, at time.Time, fields map[string]interface{}) *v1.SensorData {
+	t.Helper()
+	pbStruct, err := structpb.NewStruct(fields)
+	test.That(t, err, test.ShouldBeNil)
+	return &v1.SensorData{
+		Metadata: &v1.SensorMetadata{TimeRequested: timestamppb.New(at)},
+		Data:     &v1.SensorData_Struct{Struct: pbStruct},
+	}
+}
+
+func binaryReading(at time.Time, binary []byte) *v1.SensorData {
+	return &v1.SensorData{
+		Metadata: &v1.SensorMetadata{TimeRequested: timestamppb.New(at)},
+		Data:     &v1.SensorData_Binary{Binary: binary},
+	}
+}
+
+func TestChangeFilterDeadbands(t *testing.T) {
+	start := time.Now()
+	filter := NewChangeFilter(ChangeFilterConfig{
+		Deadbands: map[string]Deadband{
+			"readings.temp":     {Absolute: 0.5},
+			"readings.pressure": {Relative: 0.1},
+		},
+		MaxSuppressed: time.Minute,
+	})
+	reading := func(offset time.Duration, temp, pressure float64, state string) *v1.SensorData {
+		return tabularReading(t, start.Add(offset), map[string]interface{}{
+			"readings": map[string]interface{}{"temp": temp, "pressure": pressure, "state": state},
+		})
+	}
+
+	test.That(t, filter.Keep(reading(0, 20, 100, "idle")), test.ShouldBeTrue)
+	// Changes within the deadbands are suppressed.
+	test.That(t, filter.Keep(reading(time.Second, 20.4, 105, "idle")), test.ShouldBeFalse)
+	// Changes are measured from the last reading kept, not the last reading seen.
+	test.That(t, filter.Keep(reading(2*time.Second, 20.6, 100, "idle")), test.ShouldBeTrue)
+	test.That(t, filter.Keep(reading(3*time.Second, 20.6, 111, "idle")), test.ShouldBeTrue)
+	// Fields without a deadband are kept on any change.
+	test.That(t, filter.Keep(reading(4*time.Second, 20.6, 111, "running")), test.ShouldBeTrue)
+	test.That(t, filter.Keep(reading(5*time.Second, 20.6, 111, "running")), test.ShouldBeFalse)
+	// Unchanged readings are still kept once MaxSuppressed has passed.
+	test.That(t, filter.Keep(reading(4*time.Second+time.Minute, 20.6, 111, "running")), test.ShouldBeTrue)
+}
+
+func testImage(t *testing.T, draw func(x, y int) uint8) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{Y: draw(x, y)})
+		}
+	}
+	var buf bytes.Buffer
+	test.That(t, png.Encode(&buf, img), test.ShouldBeNil)
+	return buf.Bytes()
+}
+
+func TestChangeFilterFrames(t *testing.T) {
+	start := time.Now()
+	filter := NewChangeFilter(ChangeFilterConfig{FrameHashDistance: 4})
+
+	gradient := testImage(t, func(x, y int) uint8 { return uint8(x * 4) })
+	// The same gradient with a little noise has the same perceptual hash.
+	noisyGradient := testImage(t, func(x, y int) uint8 { return uint8(x*4 + (x+y)%2) })
+	reversed := testImage(t, func(x, y int) uint8 { return uint8(255 - x*4) })
+
+	test.That(t, filter.Keep(binaryReading(start, gradient)), test.ShouldBeTrue)
+	test.That(t, filter.Keep(binaryReading(start.Add(time.Second), noisyGradient)), test.ShouldBeFalse)
+	test.That(t, filter.Keep(binaryReading(start.Add(2*time.Second), reversed)), test.ShouldBeTrue)
+
+	// Binary readings that are not images are compared exactly.
+	test.That(t, filter.Keep(binaryReading(start, []byte("point cloud"))), test.ShouldBeTrue)
+	test.That(t, filter.Keep(binaryReading(start, []byte("point cloud"))), test.ShouldBeFalse)
+	test.That(t, filter.Keep(binaryReading(start, []byte("point cloud 2"))), test.ShouldBeTrue)
+}
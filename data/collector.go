@@ -33,11 +33,19 @@ type Collector interface {
 	Close()
 	Collect()
 	Flush()
+	// Stats returns the recent health of the Collector.
+	Stats() CollectorStats
+}
+
+// capturedReading is a reading along with whether the Collector's Trigger had fired when it was captured.
+type capturedReading struct {
+	msg       *v1.SensorData
+	triggered bool
 }
 
 type collector struct {
 	clock          clock.Clock
-	captureResults chan *v1.SensorData
+	captureResults chan capturedReading
 	captureErrors  chan error
 	interval       time.Duration
 	params         map[string]*anypb.Any
@@ -50,6 +58,13 @@ type collector struct {
 	captureFunc    CaptureFunc
 	closed         bool
 	target         datacapture.BufferedWriter
+
+	trigger           Trigger
+	triggeredInterval time.Duration
+	preTrigger        preTriggerBuffer
+
+	filter CaptureFilter
+	stats  collectorStats
 }
 
 // Close closes the channels backing the Collector. It should always be called before disposing of a Collector to avoid
@@ -81,6 +96,13 @@ func (c *collector) Flush() {
 	}
 }
 
+func (c *collector) Stats() CollectorStats {
+	stats := c.stats.snapshot(c.clock.Now())
+	stats.QueueDepth = len(c.captureResults)
+	stats.QueueCapacity = cap(c.captureResults)
+	return stats
+}
+
 // Collect starts the Collector, causing it to run c.capturer.Capture every c.interval, and write the results to
 // c.target. It blocks until the underlying capture goroutine starts.
 func (c *collector) Collect() {
@@ -88,6 +110,9 @@ func (c *collector) Collect() {
 	defer span.End()
 
 	started := make(chan struct{})
+	c.stats.mu.Lock()
+	c.stats.started = c.clock.Now()
+	c.stats.mu.Unlock()
 	c.captureWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
 		defer c.captureWorkers.Done()
@@ -97,6 +122,7 @@ func (c *collector) Collect() {
 	utils.PanicCapturingGo(func() {
 		defer c.captureWorkers.Done()
 		if err := c.writeCaptureResults(); err != nil {
+			c.stats.failed(err, c.clock.Now())
 			c.captureErrors <- errors.Wrap(err, fmt.Sprintf("failed to write to collector %s", c.target.Path()))
 		}
 	})
@@ -120,8 +146,25 @@ func (c *collector) capture(started chan struct{}) {
 	}
 }
 
+// captureInterval returns the interval readings should currently be captured at, and whether they should be captured
+// at all. Without a trigger, readings are always captured at c.interval.
+func (c *collector) captureInterval() (time.Duration, bool) {
+	if c.trigger == nil {
+		return c.interval, true
+	}
+	if c.trigger.Triggered() {
+		if c.triggeredInterval > 0 {
+			return c.triggeredInterval, true
+		}
+		return c.interval, true
+	}
+	// While not triggered, only capture to fill the pre-trigger buffer.
+	return c.interval, c.preTrigger.window > 0
+}
+
 func (c *collector) sleepBasedCapture(started chan struct{}) {
-	next := c.clock.Now().Add(c.interval)
+	interval, _ := c.captureInterval()
+	next := c.clock.Now().Add(interval)
 	until := c.clock.Until(next)
 	var captureWorkers sync.WaitGroup
 
@@ -141,19 +184,23 @@ func (c *collector) sleepBasedCapture(started chan struct{}) {
 			close(c.captureResults)
 			return
 		default:
-			captureWorkers.Add(1)
-			utils.PanicCapturingGo(func() {
-				defer captureWorkers.Done()
-				c.getAndPushNextReading()
-			})
+			if _, active := c.captureInterval(); active {
+				captureWorkers.Add(1)
+				utils.PanicCapturingGo(func() {
+					defer captureWorkers.Done()
+					c.getAndPushNextReading()
+				})
+			}
 		}
-		next = next.Add(c.interval)
+		interval, _ = c.captureInterval()
+		next = next.Add(interval)
 		until = c.clock.Until(next)
 	}
 }
 
 func (c *collector) tickerBasedCapture(started chan struct{}) {
-	ticker := c.clock.Ticker(c.interval)
+	currInterval, _ := c.captureInterval()
+	ticker := c.clock.Ticker(currInterval)
 	defer ticker.Stop()
 	var captureWorkers sync.WaitGroup
 
@@ -172,6 +219,14 @@ func (c *collector) tickerBasedCapture(started chan struct{}) {
 			close(c.captureResults)
 			return
 		case <-ticker.C:
+			interval, active := c.captureInterval()
+			if interval != currInterval {
+				currInterval = interval
+				ticker.Reset(currInterval)
+			}
+			if !active {
+				continue
+			}
 			captureWorkers.Add(1)
 			utils.PanicCapturingGo(func() {
 				defer captureWorkers.Done()
@@ -182,13 +237,18 @@ func (c *collector) tickerBasedCapture(started chan struct{}) {
 }
 
 func (c *collector) getAndPushNextReading() {
+	// The trigger is checked as the reading is requested, so that whether it is persisted does not depend on how long
+	// it waits in c.captureResults.
+	triggered := c.trigger == nil || c.trigger.Triggered()
 	timeRequested := timestamppb.New(c.clock.Now().UTC())
 	reading, err := c.captureFunc(c.cancelCtx, c.params)
 	timeReceived := timestamppb.New(c.clock.Now().UTC())
 	if err != nil {
+		c.stats.failed(err, timeReceived.AsTime())
 		c.captureErrors <- errors.Wrap(err, "error while capturing data")
 		return
 	}
+	c.stats.captured(timeReceived.AsTime())
 
 	var msg v1.SensorData
 	switch v := reading.(type) {
@@ -206,6 +266,7 @@ func (c *collector) getAndPushNextReading() {
 		// If it's not bytes, it's a struct.
 		pbReading, err := protoutils.StructToStructPb(reading)
 		if err != nil {
+			c.stats.failed(err, timeReceived.AsTime())
 			c.captureErrors <- errors.Wrap(err, "error while converting reading to structpb.Struct")
 			return
 		}
@@ -225,7 +286,7 @@ func (c *collector) getAndPushNextReading() {
 	// If c.captureResults is full, c.captureResults <- a can block indefinitely. This additional select block allows cancel to
 	// still work when this happens.
 	case <-c.cancelCtx.Done():
-	case c.captureResults <- &msg:
+	case c.captureResults <- capturedReading{msg: &msg, triggered: triggered}:
 	}
 }
 
@@ -244,7 +305,7 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 		c = params.Clock
 	}
 	return &collector{
-		captureResults: make(chan *v1.SensorData, params.QueueSize),
+		captureResults: make(chan capturedReading, params.QueueSize),
 		captureErrors:  make(chan error, params.QueueSize),
 		interval:       params.Interval,
 		params:         params.MethodParams,
@@ -255,18 +316,50 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 		target:         params.Target,
 		clock:          c,
 		closed:         false,
+
+		trigger:           params.Trigger,
+		triggeredInterval: params.TriggeredInterval,
+		preTrigger:        preTriggerBuffer{window: params.PreTriggerWindow},
+
+		filter: params.Filter,
 	}, nil
 }
 
 func (c *collector) writeCaptureResults() error {
-	for msg := range c.captureResults {
-		if err := c.target.Write(msg); err != nil {
+	for captured := range c.captureResults {
+		msg := captured.msg
+		if c.trigger != nil {
+			if !captured.triggered {
+				c.preTrigger.push(msg)
+				continue
+			}
+			// The trigger has fired, so persist what was captured shortly before it did.
+			for _, buffered := range c.preTrigger.drain(requestedAt(msg)) {
+				if err := c.write(buffered); err != nil {
+					return err
+				}
+			}
+		}
+		if err := c.write(msg); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// write writes msg to c.target unless c.filter rejects it, in which case it is counted as suppressed in c.stats and
+// recorded as suppressed by c.target.
+func (c *collector) write(msg *v1.SensorData) error {
+	if c.filter != nil && !c.filter.Keep(msg) {
+		c.stats.suppressed()
+		if recorder, ok := c.target.(datacapture.SuppressionRecorder); ok {
+			recorder.RecordSuppressed(1)
+		}
+		return nil
+	}
+	return c.target.Write(msg)
+}
+
 func (c *collector) logCaptureErrs() {
 	for err := range c.captureErrors {
 		if c.closed {
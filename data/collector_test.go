@@ -6,15 +6,18 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	"go.uber.org/zap/zapcore"
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
+	"go.viam.com/utils/testutils"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -279,6 +282,239 @@ func TestCtxCancelledNotLoggedAfterClose(t *testing.T) {
 	test.That(t, logs.FilterLevelExact(zapcore.ErrorLevel).Len(), test.ShouldEqual, 0)
 }
 
+// TestTriggeredCapture verifies that readings are only written once the trigger fires, along with the readings
+// captured within the pre-trigger window before it.
+func TestTriggeredCapture(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tmpDir := t.TempDir()
+	wrote := make(chan struct{})
+	target := &signalingBuffer{
+		bw:    datacapture.NewBuffer(tmpDir, &v1.DataCaptureMetadata{}),
+		wrote: wrote,
+	}
+	captured := make(chan struct{}, 100)
+	signalingCapturer := CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		captured <- struct{}{}
+		return dummyStructReading, nil
+	})
+
+	mockClock := clock.NewMock()
+	interval := sleepCaptureCutoff + 1
+	trigger := &fakeTrigger{}
+	c, err := NewCollector(signalingCapturer, CollectorParams{
+		ComponentName:    "testComponent",
+		Interval:         interval,
+		MethodParams:     map[string]*anypb.Any{"name": fakeVal},
+		Target:           target,
+		QueueSize:        queueSize,
+		BufferSize:       bufferSize,
+		Logger:           golog.NewTestLogger(t),
+		Clock:            mockClock,
+		Trigger:          trigger,
+		PreTriggerWindow: 2 * interval,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	time.Sleep(time.Millisecond)
+
+	// Readings are captured into the pre-trigger buffer, but not written.
+	for i := 0; i < 5; i++ {
+		mockClock.Add(interval)
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for data to be captured")
+		case <-captured:
+		}
+	}
+
+	// Once triggered, the two readings within the pre-trigger window are written along with the new reading.
+	trigger.triggered.Store(true)
+	mockClock.Add(interval)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for data to be written")
+		case <-wrote:
+		}
+	}
+	close(wrote)
+	c.Close()
+
+	var actReadings []*v1.SensorData
+	for _, file := range getAllFiles(tmpDir) {
+		fileReadings, err := datacapture.SensorDataFromFilePath(filepath.Join(tmpDir, file.Name()))
+		test.That(t, err, test.ShouldBeNil)
+		actReadings = append(actReadings, fileReadings...)
+	}
+	test.That(t, len(actReadings), test.ShouldEqual, 3)
+	start := mockClock.Now().Add(-2 * interval)
+	for i, reading := range actReadings {
+		test.That(t, reading.GetMetadata().GetTimeRequested().AsTime(), test.ShouldEqual,
+			start.Add(time.Duration(i)*interval).UTC())
+	}
+}
+
+// TestTriggerCheckedAtCaptureTime verifies that whether a reading is persisted depends on the trigger when the reading
+// was requested, not when it is written.
+func TestTriggerCheckedAtCaptureTime(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tmpDir := t.TempDir()
+	wrote := make(chan struct{}, 1)
+	target := &signalingBuffer{
+		bw:    datacapture.NewBuffer(tmpDir, &v1.DataCaptureMetadata{}),
+		wrote: wrote,
+	}
+	trigger := &fakeTrigger{}
+	captured := make(chan struct{}, 100)
+	// The trigger fires while the first reading is being captured.
+	firingCapturer := CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		trigger.triggered.Store(true)
+		captured <- struct{}{}
+		return dummyStructReading, nil
+	})
+
+	mockClock := clock.NewMock()
+	interval := sleepCaptureCutoff + 1
+	c, err := NewCollector(firingCapturer, CollectorParams{
+		ComponentName: "testComponent",
+		Interval:      interval,
+		MethodParams:  map[string]*anypb.Any{"name": fakeVal},
+		Target:        target,
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        golog.NewTestLogger(t),
+		Clock:         mockClock,
+		Trigger:       trigger,
+		// too short to hold on to the first reading once the second is captured
+		PreTriggerWindow: interval / 2,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	time.Sleep(time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		mockClock.Add(interval)
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for data to be captured")
+		case <-captured:
+		}
+	}
+	select {
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for data to be written")
+	case <-wrote:
+	}
+	c.Close()
+
+	var actReadings []*v1.SensorData
+	for _, file := range getAllFiles(tmpDir) {
+		fileReadings, err := datacapture.SensorDataFromFilePath(filepath.Join(tmpDir, file.Name()))
+		test.That(t, err, test.ShouldBeNil)
+		actReadings = append(actReadings, fileReadings...)
+	}
+	test.That(t, len(actReadings), test.ShouldEqual, 1)
+	test.That(t, actReadings[0].GetMetadata().GetTimeRequested().AsTime(), test.ShouldEqual, mockClock.Now().UTC())
+}
+
+func TestCollectorStats(t *testing.T) {
+	var calls atomic.Int64
+	captureErr := errors.New("sensor unplugged")
+	// Every other capture fails.
+	flakyCapturer := CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		if calls.Add(1)%2 == 0 {
+			return nil, captureErr
+		}
+		return dummyStructReading, nil
+	})
+
+	mockClock := clock.NewMock()
+	c, err := NewCollector(flakyCapturer, CollectorParams{
+		ComponentName: "testComponent",
+		Interval:      time.Second,
+		Target:        datacapture.NewBuffer(t.TempDir(), &v1.DataCaptureMetadata{}),
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        golog.NewTestLogger(t),
+		Clock:         mockClock,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	defer c.Close()
+	time.Sleep(time.Millisecond)
+
+	for i := 0; i < 10; i++ {
+		mockClock.Add(time.Second)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			stats := c.Stats()
+			test.That(tb, stats.Captured+stats.Errors, test.ShouldEqual, i+1)
+		})
+	}
+
+	stats := c.Stats()
+	test.That(t, stats.Captured, test.ShouldEqual, 5)
+	test.That(t, stats.Errors, test.ShouldEqual, 5)
+	test.That(t, stats.LastError, test.ShouldBeError, captureErr)
+	test.That(t, stats.LastErrorTime, test.ShouldEqual, mockClock.Now().UTC())
+	test.That(t, stats.LastCapture, test.ShouldEqual, mockClock.Now().Add(-time.Second).UTC())
+	test.That(t, stats.CaptureRateHz, test.ShouldAlmostEqual, 0.5)
+	test.That(t, stats.QueueCapacity, test.ShouldEqual, queueSize)
+}
+
+// TestCollectorSuppressedReadings verifies that readings rejected by the collector's filter are counted in its stats
+// and in the metadata of the data capture files written.
+func TestCollectorSuppressedReadings(t *testing.T) {
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	mockClock := clock.NewMock()
+	c, err := NewCollector(CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		return dummyStructReading, nil
+	}), CollectorParams{
+		ComponentName: "testComponent",
+		Interval:      time.Second,
+		Target:        datacapture.NewBuffer(tmpDir, md),
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        golog.NewTestLogger(t),
+		Clock:         mockClock,
+		Filter:        &keepFirstFilter{},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	time.Sleep(time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		mockClock.Add(time.Second)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, c.Stats().Captured, test.ShouldEqual, i+1)
+		})
+	}
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, c.Stats().Suppressed, test.ShouldEqual, 3)
+	})
+	c.Close()
+
+	files := getAllFiles(tmpDir)
+	test.That(t, len(files), test.ShouldEqual, 1)
+	//nolint:gosec
+	f, err := os.Open(filepath.Join(tmpDir, files[0].Name()))
+	test.That(t, err, test.ShouldBeNil)
+	dcFile, err := datacapture.ReadFile(f)
+	test.That(t, err, test.ShouldBeNil)
+	readings, err := datacapture.SensorDataFromFile(dcFile)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(readings), test.ShouldEqual, 1)
+	suppressed, err := datacapture.SuppressedSamples(dcFile.ReadMetadata())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, suppressed, test.ShouldEqual, 3)
+	test.That(t, f.Close(), test.ShouldBeNil)
+}
+
 func validateReadings(t *testing.T, act []*v1.SensorData, n int) {
 	t.Helper()
 	for i := 0; i < n; i++ {
@@ -291,7 +527,7 @@ func validateReadings(t *testing.T, act []*v1.SensorData, n int) {
 	}
 }
 
-//nolint
+// nolint
 func getAllFiles(dir string) []os.FileInfo {
 	var files []os.FileInfo
 	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -325,3 +561,22 @@ func (b *signalingBuffer) Flush() error {
 func (b *signalingBuffer) Path() string {
 	return b.bw.Path()
 }
+
+type fakeTrigger struct {
+	triggered atomic.Bool
+}
+
+func (f *fakeTrigger) Triggered() bool {
+	return f.triggered.Load()
+}
+
+// keepFirstFilter keeps only the first reading.
+type keepFirstFilter struct {
+	kept bool
+}
+
+func (f *keepFirstFilter) Keep(msg *v1.SensorData) bool {
+	kept := f.kept
+	f.kept = true
+	return !kept
+}
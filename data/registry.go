@@ -26,6 +26,15 @@ type CollectorParams struct {
 	BufferSize    int
 	Logger        golog.Logger
 	Clock         clock.Clock
+
+	// Trigger, if set, gates which captured readings are persisted. While it is not triggered, readings are only
+	// captured if PreTriggerWindow is set, and are held in memory for that long in case the Trigger fires.
+	Trigger           Trigger
+	TriggeredInterval time.Duration
+	PreTriggerWindow  time.Duration
+
+	// Filter, if set, decides which captured readings are persisted.
+	Filter CaptureFilter
 }
 
 // Validate validates that p contains all required parameters.
@@ -0,0 +1,88 @@
+package data
+
+import (
+	"sync"
+	"time"
+)
+
+// rateWindowSecs is the number of seconds capture rates are averaged over.
+const rateWindowSecs = 60
+
+// CollectorStats describe the recent health of a Collector.
+type CollectorStats struct {
+	// CaptureRateHz is the average number of readings captured per second over the last minute.
+	CaptureRateHz float64
+	// LastCapture is when a reading was last captured, or the zero time if none has been.
+	LastCapture time.Time
+	// LastError is the last error encountered while capturing or writing readings.
+	LastError     error
+	LastErrorTime time.Time
+	// QueueDepth is the number of captured readings waiting to be written, out of at most QueueCapacity.
+	QueueDepth    int
+	QueueCapacity int
+	// Captured, Suppressed and Errors count readings captured, readings rejected by the collector's CaptureFilter
+	// and failed captures since the collector started.
+	Captured   int64
+	Suppressed int64
+	Errors     int64
+}
+
+// collectorStats accumulates the CollectorStats of a collector.
+type collectorStats struct {
+	mu      sync.Mutex
+	started time.Time
+	stats   CollectorStats
+	// buckets count the readings captured during each of the last rateWindowSecs seconds, which are recorded in
+	// seconds.
+	buckets [rateWindowSecs]int64
+	seconds [rateWindowSecs]int64
+}
+
+func (s *collectorStats) captured(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Captured++
+	s.stats.LastCapture = at
+	sec := at.Unix()
+	idx := sec % rateWindowSecs
+	if s.seconds[idx] != sec {
+		s.seconds[idx] = sec
+		s.buckets[idx] = 0
+	}
+	s.buckets[idx]++
+}
+
+func (s *collectorStats) suppressed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Suppressed++
+}
+
+func (s *collectorStats) failed(err error, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Errors++
+	s.stats.LastError = err
+	s.stats.LastErrorTime = at
+}
+
+// snapshot returns the stats as of now.
+func (s *collectorStats) snapshot(now time.Time) CollectorStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	var count int64
+	for idx, sec := range s.seconds {
+		if age := now.Unix() - sec; age >= 0 && age < rateWindowSecs {
+			count += s.buckets[idx]
+		}
+	}
+	window := now.Sub(s.started).Seconds()
+	if window > rateWindowSecs {
+		window = rateWindowSecs
+	}
+	if window > 0 {
+		stats.CaptureRateHz = float64(count) / window
+	}
+	return stats
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+)
+
+// Trigger controls when a Collector persists the data it captures. It is polled by the Collector, so Triggered
+// should return quickly; any expensive evaluation should happen in the background.
+type Trigger interface {
+	Triggered() bool
+}
+
+// preTriggerBuffer holds the most recent readings captured while a Collector's Trigger was not triggered, so that
+// the readings from shortly before the Trigger fires can also be persisted.
+type preTriggerBuffer struct {
+	window   time.Duration
+	readings []*v1.SensorData
+}
+
+// push adds msg to the buffer and drops any readings that fall outside the window ending at msg.
+func (b *preTriggerBuffer) push(msg *v1.SensorData) {
+	if b.window <= 0 {
+		return
+	}
+	b.readings = append(b.readings, msg)
+	b.trim(requestedAt(msg))
+}
+
+// drain returns the buffered readings requested within the window before triggeredAt, oldest first, and empties the
+// buffer.
+func (b *preTriggerBuffer) drain(triggeredAt time.Time) []*v1.SensorData {
+	b.trim(triggeredAt)
+	ret := b.readings
+	b.readings = nil
+	return ret
+}
+
+// trim drops readings requested more than b.window before end.
+func (b *preTriggerBuffer) trim(end time.Time) {
+	cutoff := end.Add(-b.window)
+	drop := 0
+	for drop < len(b.readings) && requestedAt(b.readings[drop]).Before(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		b.readings = append(b.readings[:0], b.readings[drop:]...)
+	}
+}
+
+func requestedAt(msg *v1.SensorData) time.Time {
+	return msg.GetMetadata().GetTimeRequested().AsTime()
+}
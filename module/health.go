@@ -0,0 +1,40 @@
+package module
+
+import (
+	"context"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckPollInterval is how often a health check tries to acquire the module's lock.
+const healthCheckPollInterval = 10 * time.Millisecond
+
+// healthServer implements the gRPC health checking protocol for a module, which the module manager uses to detect
+// modules that are running but hung.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	mod *Module
+}
+
+// Check reports the module as serving once it can acquire the module's lock. A module that is stuck while adding,
+// reconfiguring or removing a resource cannot service the parent's requests, so it does not respond before ctx is
+// done. The lock is polled rather than waited on, so that checks of a hung module do not pile up.
+//
+// Calls to the module's resources do not take the lock, so a resource method that never returns does not make the
+// module unhealthy. Only hangs in the module's own handling of the parent's requests are detected.
+func (h *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	ticker := time.NewTicker(healthCheckPollInterval)
+	defer ticker.Stop()
+	for {
+		if h.mod.mu.TryLock() {
+			h.mod.mu.Unlock()
+			return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
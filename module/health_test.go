@@ -0,0 +1,39 @@
+package module
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthCheck(t *testing.T) {
+	h := &healthServer{mod: &Module{}}
+	resp, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Status, test.ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+
+	// While the module is stuck holding its lock, checks time out without leaving anything behind.
+	h.mod.mu.Lock()
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		_, err := h.Check(ctx, &healthpb.HealthCheckRequest{})
+		cancel()
+		test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+	}
+	test.That(t, runtime.NumGoroutine(), test.ShouldBeLessThanOrEqualTo, goroutines)
+
+	// A check waiting on the lock passes once it is released.
+	checked := make(chan error, 1)
+	go func() {
+		_, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		checked <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	h.mod.mu.Unlock()
+	test.That(t, <-checked, test.ShouldBeNil)
+}
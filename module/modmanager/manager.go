@@ -22,6 +22,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/config"
@@ -43,6 +44,7 @@ var (
 
 // NewManager returns a Manager.
 func NewManager(parentAddr string, logger golog.Logger, options modmanageroptions.Options) modmaninterface.ModuleManager {
+	restartCtx, restartCancel := context.WithCancel(context.Background())
 	return &Manager{
 		logger:                  logger,
 		modules:                 map[string]*module{},
@@ -50,6 +52,8 @@ func NewManager(parentAddr string, logger golog.Logger, options modmanageroption
 		rMap:                    map[resource.Name]*module{},
 		untrustedEnv:            options.UntrustedEnv,
 		removeOrphanedResources: options.RemoveOrphanedResources,
+		restartCtx:              restartCtx,
+		restartCancel:           restartCancel,
 	}
 }
 
@@ -57,6 +61,7 @@ type module struct {
 	name      string
 	exe       string
 	logLevel  string
+	health    healthCheckConfig
 	process   pexec.ManagedProcess
 	handles   modlib.HandlerMap
 	conn      *grpc.ClientConn
@@ -76,6 +81,24 @@ type module struct {
 	// another OUE has finished.
 	inRecovery     atomic.Bool
 	inRecoveryLock sync.Mutex
+
+	// cancelHealthChecks stops the module's health check goroutine.
+	cancelHealthChecks context.CancelFunc
+
+	// statusMu guards the fields below, which track the module's state for
+	// Statuses and restart backoff.
+	statusMu            sync.Mutex
+	state               modmaninterface.ModuleState
+	restarts            int
+	lastRestart         time.Time
+	healthCheckFailures int
+}
+
+// healthCheckConfig is how a module is health checked, as configured.
+type healthCheckConfig struct {
+	interval time.Duration
+	timeout  time.Duration
+	failures int
 }
 
 type addedResource struct {
@@ -92,10 +115,15 @@ type Manager struct {
 	rMap                    map[resource.Name]*module
 	untrustedEnv            bool
 	removeOrphanedResources func(ctx context.Context, rNames []resource.Name)
+
+	// restartCtx is canceled on Close to stop waiting to restart modules.
+	restartCtx    context.Context
+	restartCancel context.CancelFunc
 }
 
 // Close terminates module connections and processes.
 func (mgr *Manager) Close(ctx context.Context) error {
+	mgr.restartCancel()
 	mgr.mu.Lock()
 	defer mgr.mu.Unlock()
 	var err error
@@ -123,10 +151,16 @@ func (mgr *Manager) add(ctx context.Context, conf config.Module, conn *grpc.Clie
 	}
 
 	mod := &module{
-		name:      conf.Name,
-		exe:       conf.ExePath,
-		logLevel:  conf.LogLevel,
+		name:     conf.Name,
+		exe:      conf.ExePath,
+		logLevel: conf.LogLevel,
+		health: healthCheckConfig{
+			interval: conf.HealthCheckInterval,
+			timeout:  conf.HealthCheckTimeout,
+			failures: conf.HealthCheckFailures,
+		},
 		resources: map[resource.Name]*addedResource{},
+		state:     modmaninterface.ModuleStateRunning,
 	}
 	mgr.modules[conf.Name] = mod
 
@@ -154,6 +188,7 @@ func (mgr *Manager) add(ctx context.Context, conf config.Module, conn *grpc.Clie
 	}
 
 	mod.registerResources(mgr, mgr.logger)
+	mgr.startHealthChecks(mod)
 
 	success = true
 	return nil
@@ -237,6 +272,12 @@ func (mgr *Manager) remove(mod *module, reconfigure bool) error {
 		}
 	}
 
+	// The health check goroutine may be waiting on mgr.mu to restart the
+	// module, so it is stopped but not waited on.
+	if mod.cancelHealthChecks != nil {
+		mod.cancelHealthChecks()
+	}
+
 	if err := mod.stopProcess(); err != nil {
 		return errors.WithMessage(err, "error while stopping module "+mod.name)
 	}
@@ -296,8 +337,9 @@ func (mgr *Manager) addResource(ctx context.Context, conf resource.Config, deps
 
 // ReconfigureResource updates/reconfigures a modular component with a new configuration.
 func (mgr *Manager) ReconfigureResource(ctx context.Context, conf resource.Config, deps []string) error {
-	mgr.mu.RLock()
-	defer mgr.mu.RUnlock()
+	// The write lock is held since mod.resources is updated below.
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
 	mod, ok := mgr.getModule(conf)
 	if !ok {
 		return errors.Errorf("no module registered to serve resource api %s and model %s", conf.API, conf.Model)
@@ -325,11 +367,28 @@ func (mgr *Manager) Configs() []config.Module {
 	for _, mod := range mgr.modules {
 		configs = append(configs, config.Module{
 			Name: mod.name, ExePath: mod.exe, LogLevel: mod.logLevel,
+			HealthCheckInterval: mod.health.interval, HealthCheckTimeout: mod.health.timeout,
+			HealthCheckFailures: mod.health.failures,
 		})
 	}
 	return configs
 }
 
+// Statuses returns the status of each managed module.
+func (mgr *Manager) Statuses() []modmaninterface.ModuleStatus {
+	mgr.mu.RLock()
+	defer mgr.mu.RUnlock()
+	statuses := make([]modmaninterface.ModuleStatus, 0, len(mgr.modules))
+	for _, mod := range mgr.modules {
+		status := mod.status(time.Now())
+		for name := range mod.resources {
+			status.Resources = append(status.Resources, name)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 // Provides returns true if a component/service config WOULD be handled by a module.
 func (mgr *Manager) Provides(conf resource.Config) bool {
 	mgr.mu.RLock()
@@ -433,62 +492,259 @@ var (
 	// function can attempt to restart the module process. Multiple restart
 	// attempts will use basic backoff.
 	oueRestartInterval = 5 * time.Second
+	// restartBackoffMax is the longest a module that keeps crashing waits
+	// before being restarted again.
+	restartBackoffMax = 5 * time.Minute
+	// moduleStableInterval is how long a module must run after a restart
+	// without crashing or hanging for its restart backoff to be reset.
+	moduleStableInterval = 5 * time.Minute
+	// crashLoopThreshold is the number of restarts within
+	// moduleStableInterval of each other after which a module is considered
+	// crash-looping.
+	crashLoopThreshold = 3
+
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+	defaultHealthCheckFailures = 3
 )
 
 // newOnUnexpectedExitHandler returns the appropriate OnUnexpectedExit function
 // for the passed-in module to include in the pexec.ProcessConfig.
 func (mgr *Manager) newOnUnexpectedExitHandler(mod *module) func(exitCode int) bool {
 	return func(exitCode int) bool {
-		mod.inRecoveryLock.Lock()
-		defer mod.inRecoveryLock.Unlock()
-		if mod.inRecovery.Load() {
-			return false
-		}
-		mod.inRecovery.Store(true)
-		defer mod.inRecovery.Store(false)
-
-		// Use oueTimeout for entire attempted module restart.
-		ctx, cancel := context.WithTimeout(context.Background(), oueTimeout)
-		defer cancel()
-
-		// Log error immediately, as this is unexpected behavior.
-		mgr.logger.Errorw(
-			"module has unexpectedly exited, attempting to restart it",
-			"module", mod.name,
-			"exit_code", exitCode,
-		)
+		// Log error immediately, as this is unexpected behavior. Since we handle
+		// process restarting ourselves, return false here so goutils knows not
+		// to attempt a process restart.
+		mgr.recoverModule(mod, "module has unexpectedly exited, attempting to restart it", "exit_code", exitCode)
+		return false
+	}
+}
 
-		// If attemptRestart returns any orphaned resource names, restart failed,
-		// and we should remove orphaned resources. Since we handle process
-		// restarting ourselves, return false here so goutils knows not to attempt
-		// a process restart.
-		if orphanedResourceNames := mgr.attemptRestart(ctx, mod); orphanedResourceNames != nil {
-			if mgr.removeOrphanedResources != nil {
-				mgr.removeOrphanedResources(ctx, orphanedResourceNames)
-			}
-			return false
+// recoverModule logs msg and restarts mod, whose process has exited or been
+// stopped, and re-adds its resources. Modules that have been restarted
+// recently are restarted with exponential backoff.
+func (mgr *Manager) recoverModule(mod *module, msg string, keysAndValues ...interface{}) {
+	mod.inRecoveryLock.Lock()
+	defer mod.inRecoveryLock.Unlock()
+	if mod.inRecovery.Load() {
+		return
+	}
+	mod.inRecovery.Store(true)
+	defer mod.inRecovery.Store(false)
+
+	mgr.logger.Errorw(msg, append([]interface{}{"module", mod.name}, keysAndValues...)...)
+
+	if delay := mod.recordRestart(time.Now()); delay > 0 {
+		mgr.logger.Warnw("module is crashing repeatedly, waiting before restarting it",
+			"module", mod.name, "delay", delay)
+		if !utils.SelectContextOrWait(mgr.restartCtx, delay) {
+			return
 		}
+	}
+	mgr.mu.RLock()
+	managed := mgr.modules[mod.name] == mod
+	mgr.mu.RUnlock()
+	if !managed {
+		return
+	}
 
-		// Otherwise, add old module process' resources to new module; warn if new
-		// module cannot handle old resource, deregister that resource and remove
-		// it from mod.resources. Finally, handle orphaned resources.
-		var orphanedResourceNames []resource.Name
-		for name, res := range mod.resources {
-			if _, err := mgr.addResource(ctx, res.conf, res.deps); err != nil {
-				mgr.logger.Warnw("error while re-adding resource to module",
-					"resource", name, "module", mod.name, "error", err)
-				resource.Deregister(res.conf.API, res.conf.Model)
-				delete(mod.resources, name)
-				orphanedResourceNames = append(orphanedResourceNames, name)
-			}
-		}
+	// Use oueTimeout for entire attempted module restart.
+	ctx, cancel := context.WithTimeout(context.Background(), oueTimeout)
+	defer cancel()
+
+	// If attemptRestart returns any orphaned resource names, restart failed,
+	// and we should remove orphaned resources.
+	if orphanedResourceNames := mgr.attemptRestart(ctx, mod); orphanedResourceNames != nil {
 		if mgr.removeOrphanedResources != nil {
 			mgr.removeOrphanedResources(ctx, orphanedResourceNames)
 		}
+		return
+	}
 
-		mgr.logger.Infow("module successfully restarted", "module", mod.name)
-		return false
+	// Otherwise, add old module process' resources to new module; warn if new
+	// module cannot handle old resource, deregister that resource and remove
+	// it from mod.resources. Finally, handle orphaned resources. mod.resources
+	// and mgr.rMap are only changed under mgr.mu, since Statuses and the health
+	// checks read them concurrently.
+	var orphanedResourceNames []resource.Name
+	mgr.mu.Lock()
+	for name, res := range mod.resources {
+		if _, err := mgr.addResource(ctx, res.conf, res.deps); err != nil {
+			mgr.logger.Warnw("error while re-adding resource to module",
+				"resource", name, "module", mod.name, "error", err)
+			resource.Deregister(res.conf.API, res.conf.Model)
+			delete(mod.resources, name)
+			orphanedResourceNames = append(orphanedResourceNames, name)
+		}
+	}
+	mgr.mu.Unlock()
+	if mgr.removeOrphanedResources != nil {
+		mgr.removeOrphanedResources(ctx, orphanedResourceNames)
+	}
+
+	mod.setRestarted()
+	mgr.logger.Infow("module successfully restarted", "module", mod.name)
+}
+
+// recordRestart records that the module is about to be restarted at now and
+// returns how long to wait before restarting it. The first restart after the
+// module has run stably is immediate, and later ones back off exponentially.
+func (m *module) recordRestart(now time.Time) time.Duration {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	if m.restarts > 0 && now.Sub(m.lastRestart) >= moduleStableInterval {
+		m.restarts = 0
+	}
+	var delay time.Duration
+	if m.restarts > 0 {
+		delay = restartBackoffMax
+		if m.restarts < 32 && oueRestartInterval<<(m.restarts-1) < restartBackoffMax {
+			delay = oueRestartInterval << (m.restarts - 1)
+		}
+	}
+	m.restarts++
+	m.lastRestart = now.Add(delay)
+	m.healthCheckFailures = 0
+	m.state = modmaninterface.ModuleStateRestarting
+	if m.restarts >= crashLoopThreshold {
+		m.state = modmaninterface.ModuleStateCrashLooping
+	}
+	return delay
+}
+
+// setRestarted records that the module was successfully restarted. A
+// crash-looping module stays crash-looping until it has run stably.
+func (m *module) setRestarted() {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	if m.state != modmaninterface.ModuleStateCrashLooping {
+		m.state = modmaninterface.ModuleStateRunning
+	}
+}
+
+// status returns the status of the module at now.
+func (m *module) status(now time.Time) modmaninterface.ModuleStatus {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	if m.state == modmaninterface.ModuleStateCrashLooping && !m.inRecovery.Load() &&
+		now.Sub(m.lastRestart) >= moduleStableInterval {
+		m.state = modmaninterface.ModuleStateRunning
+		m.restarts = 0
+	}
+	return modmaninterface.ModuleStatus{
+		Name:                m.name,
+		State:               m.state,
+		Restarts:            m.restarts,
+		LastRestart:         m.lastRestart,
+		HealthCheckFailures: m.healthCheckFailures,
+	}
+}
+
+// withDefaults returns c with the defaults applied to unset fields.
+func (c healthCheckConfig) withDefaults() healthCheckConfig {
+	if c.interval == 0 {
+		c.interval = defaultHealthCheckInterval
+	}
+	if c.timeout == 0 {
+		c.timeout = defaultHealthCheckTimeout
+	}
+	if c.failures == 0 {
+		c.failures = defaultHealthCheckFailures
+	}
+	return c
+}
+
+// startHealthChecks starts probing the module with gRPC health checks. Once
+// the module fails enough consecutive checks it is considered hung, and its
+// process is stopped and restarted like that of a module that crashed. Modules
+// that do not implement the health service are considered healthy as long as
+// they respond.
+func (mgr *Manager) startHealthChecks(mod *module) {
+	conf := mod.health.withDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	mod.cancelHealthChecks = cancel
+	utils.PanicCapturingGo(func() {
+		ticker := time.NewTicker(conf.interval)
+		defer ticker.Stop()
+		for {
+			if !utils.SelectContextOrWaitChan(ctx, ticker.C) {
+				return
+			}
+			// Skip checks while the module is being restarted.
+			if mod.inRecovery.Load() {
+				continue
+			}
+			mgr.mu.RLock()
+			conn := mod.conn
+			managed := mgr.modules[mod.name] == mod
+			mgr.mu.RUnlock()
+			if !managed {
+				return
+			}
+
+			err := checkHealth(ctx, conn, conf.timeout)
+			if ctx.Err() != nil {
+				return
+			}
+			if failures := mod.recordHealthCheck(err); failures < conf.failures {
+				if err != nil {
+					mgr.logger.Warnw("module failed health check", "module", mod.name, "error", err)
+				}
+				continue
+			}
+			// The process is stopped under mgr.mu so that it cannot race with the
+			// module being removed or reconfigured, which cancel ctx first.
+			mgr.mu.Lock()
+			if ctx.Err() != nil || mgr.modules[mod.name] != mod {
+				mgr.mu.Unlock()
+				return
+			}
+			if err := mod.stopProcess(); err != nil {
+				mgr.logger.Debugw("error while stopping hung module", "module", mod.name, "error", err)
+			}
+			mgr.mu.Unlock()
+			// Stopping the process does not trigger its OnUnexpectedExit handler,
+			// so restart it here.
+			mgr.recoverModule(mod, "module is not responding to health checks, attempting to restart it",
+				"failed_checks", conf.failures, "error", err)
+		}
+	})
+}
+
+// checkHealth sends a health probe over conn that must complete within timeout.
+func checkHealth(ctx context.Context, conn *grpc.ClientConn, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return errors.Errorf("module is %s", resp.Status)
+	}
+	return nil
+}
+
+// recordHealthCheck records the result of a health check and returns the
+// number of consecutive checks the module has failed.
+func (m *module) recordHealthCheck(err error) int {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	if err == nil {
+		m.healthCheckFailures = 0
+		if m.state == modmaninterface.ModuleStateUnhealthy {
+			m.state = modmaninterface.ModuleStateRunning
+		}
+		return 0
+	}
+	m.healthCheckFailures++
+	if m.state == modmaninterface.ModuleStateRunning {
+		m.state = modmaninterface.ModuleStateUnhealthy
 	}
+	return m.healthCheckFailures
 }
 
 // attemptRestart will attempt to restart the module up to three times and
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	"go.uber.org/zap/zaptest/observer"
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
@@ -17,6 +18,7 @@ import (
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/config"
 	modmanageroptions "go.viam.com/rdk/module/modmanager/options"
+	"go.viam.com/rdk/module/modmaninterface"
 	"go.viam.com/rdk/resource"
 	rtestutils "go.viam.com/rdk/testutils"
 )
@@ -461,6 +463,131 @@ func TestModuleReloading(t *testing.T) {
 	})
 }
 
+func TestModuleHealthChecks(t *testing.T) {
+	ctx := context.Background()
+	logger, logs := golog.NewObservedTestLogger(t)
+
+	myHelperModel := resource.NewModel("rdk", "test", "helper")
+	rNameMyHelper := generic.Named("myhelper")
+	cfgMyHelper := resource.Config{
+		Name:  "myhelper",
+		API:   generic.API,
+		Model: myHelperModel,
+	}
+	_, err := cfgMyHelper.Validate("test", resource.APITypeComponentName)
+	test.That(t, err, test.ShouldBeNil)
+
+	// Precompile module to avoid timeout issues when building takes too long.
+	modPath, err := rtestutils.BuildTempModule(t, "module/testmodule")
+	test.That(t, err, test.ShouldBeNil)
+
+	// This cannot use t.TempDir() as the path it gives on MacOS exceeds module.MaxSocketAddressLength.
+	parentAddr, err := os.MkdirTemp("", "viam-test-*")
+	test.That(t, err, test.ShouldBeNil)
+	defer os.RemoveAll(parentAddr)
+	parentAddr += "/parent.sock"
+
+	var removeOrphanedResourcesCallCount atomic.Uint64
+	mgr := NewManager(parentAddr, logger, modmanageroptions.Options{
+		UntrustedEnv: false,
+		RemoveOrphanedResources: func(context.Context, []resource.Name) {
+			removeOrphanedResourcesCallCount.Add(1)
+		},
+	})
+	err = mgr.Add(ctx, config.Module{
+		Name:                "test-module",
+		ExePath:             modPath,
+		HealthCheckInterval: 100 * time.Millisecond,
+		HealthCheckTimeout:  100 * time.Millisecond,
+		HealthCheckFailures: 2,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	h, err := mgr.AddResource(ctx, cfgMyHelper, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// A responsive module passes its health checks.
+	time.Sleep(500 * time.Millisecond)
+	statuses := mgr.Statuses()
+	test.That(t, statuses, test.ShouldHaveLength, 1)
+	test.That(t, statuses[0].Name, test.ShouldEqual, "test-module")
+	test.That(t, statuses[0].State, test.ShouldEqual, modmaninterface.ModuleStateRunning)
+	test.That(t, statuses[0].Resources, test.ShouldResemble, []resource.Name{rNameMyHelper})
+	test.That(t, logs.FilterMessageSnippet("module failed health check").Len(), test.ShouldEqual, 0)
+
+	// Deadlock the module. Assert that it is restarted and that the helper is
+	// functional again afterwards.
+	_, err = h.DoCommand(ctx, map[string]interface{}{"command": "deadlock"})
+	test.That(t, err, test.ShouldBeNil)
+
+	testutils.WaitForAssertionWithSleep(t, 100*time.Millisecond, 300, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, logs.FilterMessageSnippet("module successfully restarted").Len(),
+			test.ShouldEqual, 1)
+	})
+	test.That(t, logs.FilterMessageSnippet("module is not responding to health checks").Len(),
+		test.ShouldEqual, 1)
+	test.That(t, removeOrphanedResourcesCallCount.Load(), test.ShouldEqual, 1)
+
+	statuses = mgr.Statuses()
+	test.That(t, statuses, test.ShouldHaveLength, 1)
+	test.That(t, statuses[0].State, test.ShouldEqual, modmaninterface.ModuleStateRunning)
+	test.That(t, statuses[0].Restarts, test.ShouldEqual, 1)
+
+	resp, err := h.DoCommand(ctx, map[string]interface{}{"command": "echo"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["command"], test.ShouldEqual, "echo")
+
+	test.That(t, mgr.Close(ctx), test.ShouldBeNil)
+}
+
+func TestModuleRestartBackoff(t *testing.T) {
+	defer func(origInterval, origMax time.Duration) {
+		oueRestartInterval = origInterval
+		restartBackoffMax = origMax
+	}(oueRestartInterval, restartBackoffMax)
+	oueRestartInterval = time.Second
+	restartBackoffMax = 5 * time.Second
+
+	mod := &module{name: "test", state: modmaninterface.ModuleStateRunning}
+	now := time.Now()
+
+	// The first restart is immediate, and later ones back off exponentially
+	// up to the maximum.
+	test.That(t, mod.recordRestart(now), test.ShouldEqual, 0)
+	test.That(t, mod.status(now).State, test.ShouldEqual, modmaninterface.ModuleStateRestarting)
+	mod.setRestarted()
+	test.That(t, mod.status(now).State, test.ShouldEqual, modmaninterface.ModuleStateRunning)
+
+	test.That(t, mod.recordRestart(now), test.ShouldEqual, time.Second)
+	test.That(t, mod.recordRestart(now), test.ShouldEqual, 2*time.Second)
+	test.That(t, mod.status(now).State, test.ShouldEqual, modmaninterface.ModuleStateCrashLooping)
+	test.That(t, mod.recordRestart(now), test.ShouldEqual, 4*time.Second)
+	test.That(t, mod.recordRestart(now), test.ShouldEqual, 5*time.Second)
+	test.That(t, mod.recordRestart(now), test.ShouldEqual, 5*time.Second)
+
+	// A crash-looping module stays crash-looping after a successful restart
+	// until it has run stably.
+	mod.setRestarted()
+	status := mod.status(now)
+	test.That(t, status.State, test.ShouldEqual, modmaninterface.ModuleStateCrashLooping)
+	test.That(t, status.Restarts, test.ShouldEqual, 6)
+
+	later := status.LastRestart.Add(moduleStableInterval)
+	status = mod.status(later)
+	test.That(t, status.State, test.ShouldEqual, modmaninterface.ModuleStateRunning)
+	test.That(t, status.Restarts, test.ShouldEqual, 0)
+	test.That(t, mod.recordRestart(later), test.ShouldEqual, 0)
+
+	// Failed health checks make a running module unhealthy until one passes.
+	mod.setRestarted()
+	test.That(t, mod.recordHealthCheck(errors.New("timed out")), test.ShouldEqual, 1)
+	test.That(t, mod.recordHealthCheck(errors.New("timed out")), test.ShouldEqual, 2)
+	test.That(t, mod.status(later).State, test.ShouldEqual, modmaninterface.ModuleStateUnhealthy)
+	test.That(t, mod.recordHealthCheck(nil), test.ShouldEqual, 0)
+	test.That(t, mod.status(later).State, test.ShouldEqual, modmaninterface.ModuleStateRunning)
+}
+
 func TestDebugModule(t *testing.T) {
 	ctx := context.Background()
 
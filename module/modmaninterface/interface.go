@@ -3,6 +3,7 @@ package modmaninterface
 
 import (
 	"context"
+	"time"
 
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/resource"
@@ -22,6 +23,35 @@ type ModuleManager interface {
 
 	Configs() []config.Module
 	Provides(cfg resource.Config) bool
+	Statuses() []ModuleStatus
 
 	Close(ctx context.Context) error
 }
+
+// ModuleState is the state of a module's process as seen by the module manager.
+type ModuleState string
+
+const (
+	// ModuleStateRunning means the module is running and passing health checks.
+	ModuleStateRunning ModuleState = "running"
+	// ModuleStateUnhealthy means the module is running but failing health checks.
+	ModuleStateUnhealthy ModuleState = "unhealthy"
+	// ModuleStateRestarting means the module exited or hung and is being restarted.
+	ModuleStateRestarting ModuleState = "restarting"
+	// ModuleStateCrashLooping means the module keeps exiting or hanging shortly after being restarted, so it is being
+	// restarted with increasing delays.
+	ModuleStateCrashLooping ModuleState = "crash_looping"
+)
+
+// ModuleStatus is the status of a module.
+type ModuleStatus struct {
+	Name  string
+	State ModuleState
+	// Restarts is the number of times the module has been restarted since it last ran stably.
+	Restarts    int
+	LastRestart time.Time
+	// HealthCheckFailures is the number of consecutive health checks the module has failed.
+	HealthCheckFailures int
+	// Resources are the resources the module serves.
+	Resources []resource.Name
+}
@@ -21,6 +21,7 @@ import (
 	"go.viam.com/utils"
 	"go.viam.com/utils/rpc"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 
 	"go.viam.com/rdk/config"
@@ -146,6 +147,9 @@ func NewModule(ctx context.Context, address string, logger *zap.SugaredLogger) (
 	if err := m.server.RegisterServiceServer(ctx, &pb.ModuleService_ServiceDesc, m); err != nil {
 		return nil, err
 	}
+	if err := m.server.RegisterServiceServer(ctx, &healthpb.Health_ServiceDesc, &healthServer{mod: m}); err != nil {
+		return nil, err
+	}
 	return m, nil
 }
 
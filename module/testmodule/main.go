@@ -5,10 +5,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
+	pb "go.viam.com/api/module/v1"
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/components/generic"
@@ -60,8 +62,16 @@ func newHelper(ctx context.Context, deps resource.Dependencies, conf resource.Co
 type helper struct {
 	resource.Named
 	resource.TriviallyReconfigurable
-	resource.TriviallyCloseable
 	logger golog.Logger
+	hang   atomic.Bool
+}
+
+// Close blocks forever after the "deadlock" command.
+func (h *helper) Close(ctx context.Context) error {
+	if h.hang.Load() {
+		select {}
+	}
+	return nil
 }
 
 // DoCommand is the only method of this component. It looks up the "real" command from the map it's passed.
@@ -87,6 +97,15 @@ func (h *helper) DoCommand(ctx context.Context, req map[string]interface{}) (map
 		return map[string]interface{}{"ops": opsOut}, nil
 	case "echo":
 		return req, nil
+	case "deadlock":
+		// Remove this resource so that the module hangs in Close while holding its lock, as a deadlocked module would.
+		h.hang.Store(true)
+		utils.PanicCapturingGo(func() {
+			//nolint:errcheck
+			myMod.RemoveResource(context.Background(), &pb.RemoveResourceRequest{Name: h.Name().String()})
+		})
+		//nolint:nilnil
+		return nil, nil
 	case "kill_module":
 		os.Exit(1)
 		// unreachable return statement needed for compilation
@@ -0,0 +1,618 @@
+package motionplan
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// AnalyticIKSolver is the value of the "ik_solver" planning option which selects analytic inverse kinematics. No model
+// uses it unless its config or the planning options name it.
+const AnalyticIKSolver = "analytic"
+
+const (
+	// Joints whose axes are closer than this to being parallel or intersecting, in radians or mm, are treated as such.
+	analyticIKTolerance = 1e-6
+	// Solutions are checked against the forward kinematics of the model at a number of random configurations.
+	analyticIKValidationSamples = 10
+	// Arms with offset wrists are solved at a number of angles of the last joint, more finely within a few intervals of where
+	// a branch of the solution ends, and solutions are bisected for between them.
+	offsetWristSamples      = 360
+	offsetWristEndIntervals = 3
+	offsetWristEndSamples   = 16
+	offsetWristBisections   = 50
+)
+
+var errAnalyticIKUnsupported = errors.New(
+	"analytic IK requires 6 revolute joints with parallel second and third axes and intersecting fourth and fifth axes, " +
+		"or three parallel axes and intersecting last two axes",
+)
+
+// analyticKinematics identifies which analytic solution applies to a model.
+type analyticKinematics int
+
+const (
+	// sphericalWristKinematics have parallel second and third joint axes, and last three joint axes intersecting in a point.
+	sphericalWristKinematics analyticKinematics = iota
+	// urKinematics have parallel second, third and fourth joint axes, and intersecting fifth and sixth joint axes, as in UR arms.
+	urKinematics
+	// offsetWristKinematics have parallel second and third joint axes, and intersecting fourth and fifth joint axes, with the
+	// last joint axis offset from their intersection, as in xArm6 arms.
+	offsetWristKinematics
+)
+
+// screwAxis is the axis about which a revolute joint rotates when all joints are at zero.
+type screwAxis struct {
+	omega r3.Vector // unit direction
+	point r3.Vector // a point on the axis
+}
+
+// rotateVector rotates a direction about the axis by theta radians.
+func (s screwAxis) rotateVector(theta float64, v r3.Vector) r3.Vector {
+	cos, sin := math.Cos(theta), math.Sin(theta)
+	return v.Mul(cos).Add(s.omega.Cross(v).Mul(sin)).Add(s.omega.Mul(s.omega.Dot(v) * (1 - cos)))
+}
+
+// rotatePoint rotates a point about the axis by theta radians.
+func (s screwAxis) rotatePoint(theta float64, pt r3.Vector) r3.Vector {
+	return s.rotateVector(theta, pt.Sub(s.point)).Add(s.point)
+}
+
+// pose returns the transform which rotates about the axis by theta radians.
+func (s screwAxis) pose(theta float64) spatialmath.Pose {
+	return spatialmath.NewPose(
+		s.point.Sub(s.rotateVector(theta, s.point)),
+		&spatialmath.R4AA{Theta: theta, RX: s.omega.X, RY: s.omega.Y, RZ: s.omega.Z},
+	)
+}
+
+// AnalyticIK is an InverseKinematics solver which computes the solutions for a goal pose of 6 DOF arms with spherical
+// wrists or UR-style kinematics in closed form, and those of arms with xArm-style offset wrists by searching over the
+// angle of their last joint. The structure of the arm is identified from the forward kinematics of its frame, so it may
+// be used for any frame whose inputs are the joint angles of such an arm, including frames with fixed offsets before and
+// after the arm.
+type AnalyticIK struct {
+	model      referenceframe.Frame
+	logger     golog.Logger
+	kinematics analyticKinematics
+	axes       []screwAxis
+	home       spatialmath.Pose // pose of the frame when all joints are at zero
+	wrist      r3.Vector        // intersection of the wrist joint axes when all joints are at zero
+	goal       spatialmath.Pose
+	epsilon    float64
+}
+
+// CreateAnalyticIKSolver creates an AnalyticIK object which solves for the given goal pose of the given frame. An error is
+// returned if the kinematics of the frame are not supported.
+func CreateAnalyticIKSolver(model referenceframe.Frame, logger golog.Logger, goal spatialmath.Pose) (*AnalyticIK, error) {
+	ik := &AnalyticIK{model: model, logger: logger, goal: goal, epsilon: defaultEpsilon}
+	limits := model.DoF()
+	if len(limits) != 6 {
+		return nil, errAnalyticIKUnsupported
+	}
+
+	var err error
+	ik.home, err = transformAllowingOOB(model, make([]referenceframe.Input, 6))
+	if err != nil {
+		return nil, err
+	}
+	// Rotating a single joint of the frame moves it by that joint's screw motion, from which its axis can be recovered
+	for i, limit := range limits {
+		probe := math.Min(math.Pi/2, limit.Max)
+		if probe <= analyticIKTolerance {
+			probe = math.Max(-math.Pi/2, limit.Min)
+		}
+		inputs := make([]referenceframe.Input, 6)
+		inputs[i] = referenceframe.Input{Value: probe}
+		pose, err := transformAllowingOOB(model, inputs)
+		if err != nil {
+			return nil, err
+		}
+		motion := spatialmath.Compose(pose, spatialmath.PoseInverse(ik.home))
+		aa := motion.Orientation().AxisAngles()
+		if math.Abs(math.Abs(aa.Theta)-math.Abs(probe)) > analyticIKTolerance {
+			return nil, errAnalyticIKUnsupported
+		}
+		omega := r3.Vector{X: aa.RX, Y: aa.RY, Z: aa.RZ}.Normalize()
+		if aa.Theta*probe < 0 {
+			omega = omega.Mul(-1)
+		}
+		// a rotation of theta about an axis through r translates by t = (I - R)r, so the point on the axis nearest the
+		// origin is recovered from t
+		t := motion.Point()
+		point := t.Add(omega.Cross(t).Mul(1 / math.Tan(math.Abs(probe)/2))).Mul(0.5)
+		ik.axes = append(ik.axes, screwAxis{omega: omega, point: point})
+	}
+
+	if err := ik.validate(); err != nil {
+		return nil, err
+	}
+
+	parallel := func(i, j int) bool { return ik.axes[i].omega.Cross(ik.axes[j].omega).Norm() < analyticIKTolerance }
+	switch {
+	case parallel(1, 2) && parallel(2, 3) && !parallel(0, 1) && !parallel(3, 4) && !parallel(4, 5):
+		wrist, ok := axisIntersection(ik.axes[4], ik.axes[5])
+		if !ok {
+			return nil, errAnalyticIKUnsupported
+		}
+		ik.kinematics = urKinematics
+		ik.wrist = wrist
+	case parallel(1, 2) && !parallel(0, 1) && !parallel(3, 4) && !parallel(4, 5):
+		wrist, ok := axisIntersection(ik.axes[3], ik.axes[4])
+		if !ok {
+			return nil, errAnalyticIKUnsupported
+		}
+		ik.kinematics = sphericalWristKinematics
+		if ik.axes[5].omega.Cross(wrist.Sub(ik.axes[5].point)).Norm() > analyticIKTolerance {
+			ik.kinematics = offsetWristKinematics
+		}
+		ik.wrist = wrist
+	default:
+		return nil, errAnalyticIKUnsupported
+	}
+	return ik, nil
+}
+
+// validate checks that the forward kinematics of the frame are the product of the screw motions of its joints, which will
+// not be the case if any joints are coupled or are not revolute.
+func (ik *AnalyticIK) validate() error {
+	//nolint: gosec
+	randSeed := rand.New(rand.NewSource(1))
+	limits := ik.model.DoF()
+	for i := 0; i < analyticIKValidationSamples; i++ {
+		inputs := make([]referenceframe.Input, len(limits))
+		for j, limit := range limits {
+			lo, hi := math.Max(limit.Min, -math.Pi), math.Min(limit.Max, math.Pi)
+			inputs[j] = referenceframe.Input{Value: lo + randSeed.Float64()*(hi-lo)}
+		}
+		pose, err := transformAllowingOOB(ik.model, inputs)
+		if err != nil {
+			return err
+		}
+		if !spatialmath.PoseAlmostCoincidentEps(pose, ik.forwardKinematics(inputs), 1e-6) {
+			return errAnalyticIKUnsupported
+		}
+	}
+	return nil
+}
+
+// forwardKinematics computes the pose of the frame as the product of the screw motions of its joints.
+func (ik *AnalyticIK) forwardKinematics(inputs []referenceframe.Input) spatialmath.Pose {
+	pose := ik.home
+	for i := len(ik.axes) - 1; i >= 0; i-- {
+		pose = spatialmath.Compose(ik.axes[i].pose(inputs[i].Value), pose)
+	}
+	return pose
+}
+
+// Solve sends every solution for the goal pose of the solver which is within the limits of the frame and meets the metric,
+// ordered by their distance from the seed, to the given channel.
+func (ik *AnalyticIK) Solve(ctx context.Context,
+	c chan<- []referenceframe.Input,
+	seed []referenceframe.Input,
+	m StateMetric,
+	rseed int,
+) error {
+	if ik.goal == nil {
+		return errors.New("analytic IK requires a goal pose")
+	}
+	solutionsFound := 0
+	for _, solution := range ik.Solutions(ik.goal, seed) {
+		pose, err := ik.model.Transform(solution)
+		if err != nil {
+			continue
+		}
+		if m(&State{Position: pose, Configuration: solution, Frame: ik.model}) > ik.epsilon*ik.epsilon {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c <- solution:
+		}
+		solutionsFound++
+	}
+	if solutionsFound == 0 {
+		return errNoSolve
+	}
+	return nil
+}
+
+// Frame returns the associated referenceframe.
+func (ik *AnalyticIK) Frame() referenceframe.Frame {
+	return ik.model
+}
+
+// Solutions returns every set of joint positions within the limits of the frame which places it at the given goal pose,
+// ordered by their distance from the seed. Since joints may have ranges of more than a full turn, each solution
+// may appear several times with joint positions differing by full turns.
+func (ik *AnalyticIK) Solutions(goal spatialmath.Pose, seed []referenceframe.Input) [][]referenceframe.Input {
+	// the joints must move the frame from its home pose by this transform
+	motion := spatialmath.Compose(goal, spatialmath.PoseInverse(ik.home))
+	var raw [][]float64
+	switch ik.kinematics {
+	case urKinematics:
+		raw = ik.solveUR(motion, seed)
+	case sphericalWristKinematics:
+		raw = ik.solveSphericalWrist(motion, seed)
+	case offsetWristKinematics:
+		raw = ik.solveOffsetWrist(motion)
+	}
+
+	limits := ik.model.DoF()
+	seen := map[string]bool{}
+	var solutions [][]referenceframe.Input
+	for _, angles := range raw {
+		// reject branches which do not reach the goal, such as those found at singularities
+		inputs := referenceframe.FloatsToInputs(angles)
+		if !spatialmath.PoseAlmostCoincidentEps(ik.forwardKinematics(inputs), goal, 1e-3) {
+			continue
+		}
+		for _, expanded := range expandJointTurns(angles, limits) {
+			key := fmt.Sprintf("%.6f", referenceframe.InputsToFloats(expanded))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			solutions = append(solutions, expanded)
+		}
+	}
+	if len(seed) == len(limits) {
+		sort.SliceStable(solutions, func(i, j int) bool {
+			return referenceframe.InputsL2Distance(seed, solutions[i]) < referenceframe.InputsL2Distance(seed, solutions[j])
+		})
+	}
+	return solutions
+}
+
+// solveUR solves arms whose second, third and fourth joint axes are parallel, and whose last two joint axes intersect.
+func (ik *AnalyticIK) solveUR(motion spatialmath.Pose, seed []referenceframe.Input) [][]float64 {
+	a := ik.axes
+	n := a[1].omega
+	wristGoal := transformPoint(motion, ik.wrist)
+	zGoal := rotateByPose(motion, a[5].omega)
+
+	// Rotations about the parallel axes do not change the component of the wrist along them, which only the first joint can
+	var solutions [][]float64
+	for _, theta1 := range solveRotatedDot(a[0], n, wristGoal.Sub(a[0].point), n.Dot(ik.wrist.Sub(a[0].point))) {
+		// Likewise, only the fifth joint changes the component of the last joint's axis along the parallel axes
+		zLocal := a[0].rotateVector(-theta1, zGoal)
+		for _, theta5 := range solveRotatedDot(a[4], a[5].omega, n, n.Dot(zLocal)) {
+			// The sixth joint rotates the parallel axes, as seen from the end of the arm, onto their direction after the fifth
+			u := rotateByPose(spatialmath.PoseInverse(motion), a[0].rotateVector(theta1, n))
+			theta6, ok := subproblem1(a[5].omega, u, a[4].rotateVector(-theta5, n))
+			if !ok {
+				// the sixth joint is aligned with the parallel axes, so any angle may be used
+				theta6 = seedValue(seed, 5)
+			}
+
+			// the parallel joints rotate by the sum of their angles
+			perp := perpendicular(n)
+			rotated := a[0].rotateVector(-theta1, rotateByPose(motion, a[5].rotateVector(-theta6, a[4].rotateVector(-theta5, perp))))
+			phi, _ := subproblem1(n, perp, rotated)
+
+			// the position of the fourth joint's axis follows from the wrist position and the rotation of the parallel joints
+			wristLocal := a[0].rotatePoint(-theta1, wristGoal)
+			fourthLocal := wristLocal.Sub(screwAxis{omega: n}.rotateVector(phi, ik.wrist.Sub(a[3].point)))
+			for _, theta3 := range subproblem3(a[2], a[3].point, a[1].point, fourthLocal.Sub(a[1].point).Norm()) {
+				theta2, _ := subproblem1(a[1].omega, a[2].rotatePoint(theta3, a[3].point).Sub(a[1].point), fourthLocal.Sub(a[1].point))
+				// the axes may point in opposite directions, reversing the sense of their angles
+				theta4 := (phi - theta2*n.Dot(a[1].omega) - theta3*n.Dot(a[2].omega)) * n.Dot(a[3].omega)
+				solutions = append(solutions, []float64{theta1, theta2, theta3, theta4, theta5, theta6})
+			}
+		}
+	}
+	return solutions
+}
+
+// solveSphericalWrist solves arms whose second and third joint axes are parallel, and whose last three joint axes intersect.
+func (ik *AnalyticIK) solveSphericalWrist(motion spatialmath.Pose, seed []referenceframe.Input) [][]float64 {
+	a := ik.axes
+	n := a[1].omega
+	wristGoal := transformPoint(motion, ik.wrist)
+
+	var solutions [][]float64
+	for _, theta1 := range solveRotatedDot(a[0], n, wristGoal.Sub(a[0].point), n.Dot(ik.wrist.Sub(a[0].point))) {
+		wristLocal := a[0].rotatePoint(-theta1, wristGoal)
+		for _, theta3 := range subproblem3(a[2], ik.wrist, a[1].point, wristLocal.Sub(a[1].point).Norm()) {
+			theta2, _ := subproblem1(a[1].omega, a[2].rotatePoint(theta3, ik.wrist).Sub(a[1].point), wristLocal.Sub(a[1].point))
+			toWrist := func(v r3.Vector) r3.Vector {
+				return a[2].rotateVector(-theta3, a[1].rotateVector(-theta2, a[0].rotateVector(-theta1, rotateByPose(motion, v))))
+			}
+			// the wrist rotates the last joint's axis onto its goal direction, then the last joint rotates about it
+			for _, wrist := range subproblem2(a[3].omega, a[4].omega, a[5].omega, toWrist(a[5].omega)) {
+				theta4, theta5 := wrist[0], wrist[1]
+				perp := perpendicular(a[5].omega)
+				theta6, ok := subproblem1(a[5].omega, perp, a[4].rotateVector(-theta5, a[3].rotateVector(-theta4, toWrist(perp))))
+				if !ok {
+					theta6 = seedValue(seed, 5)
+				}
+				solutions = append(solutions, []float64{theta1, theta2, theta3, theta4, theta5, theta6})
+			}
+		}
+	}
+	return solutions
+}
+
+// offsetWristBranch is one branch of the solution of an arm with an offset wrist for a given angle of its last joint.
+type offsetWristBranch struct {
+	angles []float64
+	// the angle about the last joint's axis by which the branch misses the goal orientation
+	err float64
+}
+
+// solveOffsetWrist solves arms whose second and third joint axes are parallel, and whose fourth and fifth joint axes
+// intersect at a wrist point which the last joint axis does not pass through. Fixing the last joint's angle fixes the
+// position of the wrist, from which the first five joints are solved as for a spherical wrist, aligning the last joint's
+// axis with its goal direction. The angles of the last joint at which a branch also reaches the goal orientation are found
+// by sampling the last joint over a full turn, then bisecting between samples at which the error of the branch changes sign.
+// Branches end where the wrist leaves the reach of the arm, and their error changes quickly near there, so they are
+// sampled more finely near their ends. This is not a closed-form solution: two roots of a branch closer together than a
+// sampling interval are missed.
+func (ik *AnalyticIK) solveOffsetWrist(motion spatialmath.Pose) [][]float64 {
+	type sample struct {
+		theta6   float64
+		branches map[[3]int]offsetWristBranch
+	}
+	sampleAt := func(theta6 float64) sample {
+		return sample{theta6: theta6, branches: ik.offsetWristBranches(motion, theta6)}
+	}
+	step := 2 * math.Pi / offsetWristSamples
+	coarse := make([]sample, offsetWristSamples+1)
+	for i := range coarse {
+		coarse[i] = sampleAt(-math.Pi + float64(i)*step)
+	}
+	// intervals within a few samples of the end of a branch are sampled more finely
+	fine := make([]bool, offsetWristSamples)
+	for i := range fine {
+		if sameBranches(coarse[i].branches, coarse[i+1].branches) {
+			continue
+		}
+		for j := i - offsetWristEndIntervals; j <= i+offsetWristEndIntervals; j++ {
+			fine[(j+offsetWristSamples)%offsetWristSamples] = true
+		}
+	}
+	var samples []sample
+	for i := range fine {
+		samples = append(samples, coarse[i])
+		if fine[i] {
+			for j := 1; j < offsetWristEndSamples; j++ {
+				samples = append(samples, sampleAt(coarse[i].theta6+float64(j)*step/offsetWristEndSamples))
+			}
+		}
+	}
+	samples = append(samples, coarse[offsetWristSamples])
+
+	var solutions [][]float64
+	for i := 0; i < len(samples)-1; i++ {
+		for key, lo := range samples[i].branches {
+			hi, ok := samples[i+1].branches[key]
+			// the error wraps around at half a turn, which is not a solution
+			if !ok || lo.err*hi.err > 0 || math.Abs(lo.err-hi.err) > math.Pi {
+				continue
+			}
+			loTheta, hiTheta := samples[i].theta6, samples[i+1].theta6
+			solution := lo
+			for j := 0; j < offsetWristBisections && math.Abs(solution.err) > analyticIKTolerance*analyticIKTolerance; j++ {
+				midTheta := (loTheta + hiTheta) / 2
+				mid, ok := ik.offsetWristBranches(motion, midTheta)[key]
+				if !ok {
+					break
+				}
+				solution = mid
+				if (mid.err > 0) == (lo.err > 0) {
+					loTheta, lo = midTheta, mid
+				} else {
+					hiTheta = midTheta
+				}
+			}
+			solutions = append(solutions, solution.angles)
+		}
+	}
+	return solutions
+}
+
+// offsetWristBranches returns each branch of the solution of an arm with an offset wrist for the given angle of its last
+// joint, keyed by the choice made at each step of the solution.
+func (ik *AnalyticIK) offsetWristBranches(motion spatialmath.Pose, theta6 float64) map[[3]int]offsetWristBranch {
+	a := ik.axes
+	n := a[1].omega
+	wristGoal := transformPoint(motion, a[5].rotatePoint(-theta6, ik.wrist))
+
+	branches := map[[3]int]offsetWristBranch{}
+	for i1, theta1 := range solveRotatedDot(a[0], n, wristGoal.Sub(a[0].point), n.Dot(ik.wrist.Sub(a[0].point))) {
+		wristLocal := a[0].rotatePoint(-theta1, wristGoal)
+		for i3, theta3 := range subproblem3(a[2], ik.wrist, a[1].point, wristLocal.Sub(a[1].point).Norm()) {
+			theta2, _ := subproblem1(a[1].omega, a[2].rotatePoint(theta3, ik.wrist).Sub(a[1].point), wristLocal.Sub(a[1].point))
+			toWrist := func(v r3.Vector) r3.Vector {
+				return a[2].rotateVector(-theta3, a[1].rotateVector(-theta2, a[0].rotateVector(-theta1, rotateByPose(motion, v))))
+			}
+			zGoal := toWrist(a[5].omega)
+			for iw, wrist := range subproblem2(a[3].omega, a[4].omega, a[5].omega, zGoal) {
+				theta4, theta5 := wrist[0], wrist[1]
+				perp := perpendicular(a[5].omega)
+				reached := a[3].rotateVector(theta4, a[4].rotateVector(theta5, a[5].rotateVector(theta6, perp)))
+				err, _ := subproblem1(zGoal, reached, toWrist(perp))
+				branches[[3]int{i1, i3, iw}] = offsetWristBranch{
+					angles: []float64{theta1, theta2, theta3, theta4, theta5, theta6},
+					err:    err,
+				}
+			}
+		}
+	}
+	return branches
+}
+
+// sameBranches returns whether the same branches of a solution exist in both sets.
+func sameBranches(a, b map[[3]int]offsetWristBranch) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// solveRotatedDot returns the angles by which rotating v about the axis gives a vector whose dot product with w is target.
+func solveRotatedDot(axis screwAxis, v, w r3.Vector, target float64) []float64 {
+	parallel := axis.omega.Mul(axis.omega.Dot(v))
+	perp := v.Sub(parallel)
+	return solveCosSin(w.Dot(perp), w.Dot(axis.omega.Cross(v)), target-w.Dot(parallel))
+}
+
+// solveCosSin returns the angles theta for which a*cos(theta) + b*sin(theta) = c.
+func solveCosSin(a, b, c float64) []float64 {
+	r := math.Hypot(a, b)
+	if r < analyticIKTolerance || math.Abs(c) > r*(1+analyticIKTolerance) {
+		return nil
+	}
+	base := math.Atan2(b, a)
+	offset := math.Acos(math.Max(-1, math.Min(1, c/r)))
+	if offset < analyticIKTolerance {
+		return []float64{base}
+	}
+	return []float64{base + offset, base - offset}
+}
+
+// subproblem1 returns the angle by which rotating u about omega aligns it with v, and false if u is parallel to omega.
+func subproblem1(omega, u, v r3.Vector) (float64, bool) {
+	uPerp := u.Sub(omega.Mul(omega.Dot(u)))
+	vPerp := v.Sub(omega.Mul(omega.Dot(v)))
+	if uPerp.Norm() < analyticIKTolerance || vPerp.Norm() < analyticIKTolerance {
+		return 0, false
+	}
+	return math.Atan2(omega.Dot(uPerp.Cross(vPerp)), uPerp.Dot(vPerp)), true
+}
+
+// subproblem2 returns the pairs of angles by which rotating p about omega2, then about omega1, gives q.
+func subproblem2(omega1, omega2, p, q r3.Vector) [][2]float64 {
+	dot := omega1.Dot(omega2)
+	denom := dot*dot - 1
+	alpha := (dot*omega2.Dot(p) - omega1.Dot(q)) / denom
+	beta := (dot*omega1.Dot(q) - omega2.Dot(p)) / denom
+	cross := omega1.Cross(omega2)
+	gammaSq := (p.Norm2() - alpha*alpha - beta*beta - 2*alpha*beta*dot) / cross.Norm2()
+	if gammaSq < -analyticIKTolerance {
+		return nil
+	}
+	gammas := []float64{math.Sqrt(math.Max(gammaSq, 0))}
+	if gammas[0] > analyticIKTolerance {
+		gammas = append(gammas, -gammas[0])
+	}
+	var solutions [][2]float64
+	for _, gamma := range gammas {
+		z := omega1.Mul(alpha).Add(omega2.Mul(beta)).Add(cross.Mul(gamma))
+		theta2, ok2 := subproblem1(omega2, p, z)
+		theta1, ok1 := subproblem1(omega1, z, q)
+		if ok1 && ok2 {
+			solutions = append(solutions, [2]float64{theta1, theta2})
+		}
+	}
+	return solutions
+}
+
+// subproblem3 returns the angles by which rotating p about the axis places it a distance delta from q.
+func subproblem3(axis screwAxis, p, q r3.Vector, delta float64) []float64 {
+	u := p.Sub(axis.point)
+	v := q.Sub(axis.point)
+	uPerp := u.Sub(axis.omega.Mul(axis.omega.Dot(u)))
+	vPerp := v.Sub(axis.omega.Mul(axis.omega.Dot(v)))
+	along := axis.omega.Dot(u.Sub(v))
+	deltaPerpSq := delta*delta - along*along
+	uNorm, vNorm := uPerp.Norm(), vPerp.Norm()
+	if uNorm < analyticIKTolerance || vNorm < analyticIKTolerance {
+		return nil
+	}
+	base := math.Atan2(axis.omega.Dot(uPerp.Cross(vPerp)), uPerp.Dot(vPerp))
+	cos := (uNorm*uNorm + vNorm*vNorm - deltaPerpSq) / (2 * uNorm * vNorm)
+	if math.Abs(cos) > 1+analyticIKTolerance {
+		return nil
+	}
+	offset := math.Acos(math.Max(-1, math.Min(1, cos)))
+	if offset < analyticIKTolerance {
+		return []float64{base}
+	}
+	return []float64{base + offset, base - offset}
+}
+
+// axisIntersection returns the point at which two axes intersect, and false if they do not.
+func axisIntersection(a, b screwAxis) (r3.Vector, bool) {
+	cross := a.omega.Cross(b.omega)
+	if cross.Norm() < analyticIKTolerance {
+		return r3.Vector{}, false
+	}
+	delta := b.point.Sub(a.point)
+	if math.Abs(delta.Dot(cross))/cross.Norm() > analyticIKTolerance {
+		return r3.Vector{}, false
+	}
+	// the distance along a to the intersection
+	t := delta.Cross(b.omega).Dot(cross) / cross.Norm2()
+	return a.point.Add(a.omega.Mul(t)), true
+}
+
+// expandJointTurns returns every combination of the given joint angles, offset by whole turns, that is within the limits.
+func expandJointTurns(angles []float64, limits []referenceframe.Limit) [][]referenceframe.Input {
+	expanded := [][]referenceframe.Input{{}}
+	for i, angle := range angles {
+		angle = math.Remainder(angle, 2*math.Pi)
+		var options []float64
+		for turn := -2.; turn <= 2; turn++ {
+			value := angle + turn*2*math.Pi
+			if value >= limits[i].Min-analyticIKTolerance && value <= limits[i].Max+analyticIKTolerance {
+				options = append(options, math.Max(limits[i].Min, math.Min(limits[i].Max, value)))
+			}
+		}
+		next := make([][]referenceframe.Input, 0, len(expanded)*len(options))
+		for _, partial := range expanded {
+			for _, value := range options {
+				solution := make([]referenceframe.Input, len(partial), len(partial)+1)
+				copy(solution, partial)
+				next = append(next, append(solution, referenceframe.Input{Value: value}))
+			}
+		}
+		expanded = next
+	}
+	return expanded
+}
+
+// transformAllowingOOB returns the pose of the frame at the given inputs, even if they are out of bounds.
+func transformAllowingOOB(model referenceframe.Frame, inputs []referenceframe.Input) (spatialmath.Pose, error) {
+	pose, err := model.Transform(inputs)
+	if pose == nil || (err != nil && !strings.Contains(err.Error(), referenceframe.OOBErrString)) {
+		return nil, err
+	}
+	return pose, nil
+}
+
+func transformPoint(pose spatialmath.Pose, pt r3.Vector) r3.Vector {
+	return spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(pt)).Point()
+}
+
+func rotateByPose(pose spatialmath.Pose, v r3.Vector) r3.Vector {
+	return transformPoint(pose, v).Sub(pose.Point())
+}
+
+// perpendicular returns a unit vector perpendicular to v.
+func perpendicular(v r3.Vector) r3.Vector {
+	return v.Ortho()
+}
+
+func seedValue(seed []referenceframe.Input, i int) float64 {
+	if i < len(seed) {
+		return seed[i].Value
+	}
+	return 0
+}
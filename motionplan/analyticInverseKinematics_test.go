@@ -0,0 +1,181 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// makePumaModel builds an arm with a spherical wrist and an offset shoulder.
+func makePumaModel(t *testing.T) referenceframe.Model {
+	t.Helper()
+	model := referenceframe.NewSimpleModel("puma")
+	limit := referenceframe.Limit{Min: -2 * math.Pi, Max: 2 * math.Pi}
+	addJoint := func(name string, axis r3.Vector) {
+		joint, err := referenceframe.NewRotationalFrame(name, spatialmath.R4AA{RX: axis.X, RY: axis.Y, RZ: axis.Z}, limit)
+		test.That(t, err, test.ShouldBeNil)
+		model.OrdTransforms = append(model.OrdTransforms, joint)
+	}
+	addLink := func(name string, pt r3.Vector) {
+		link, err := referenceframe.NewStaticFrame(name, spatialmath.NewPoseFromPoint(pt))
+		test.That(t, err, test.ShouldBeNil)
+		model.OrdTransforms = append(model.OrdTransforms, link)
+	}
+	addLink("base", r3.Vector{Z: 300})
+	addJoint("waist", r3.Vector{Z: 1})
+	addLink("shoulder_offset", r3.Vector{Y: 150})
+	addJoint("shoulder", r3.Vector{Y: 1})
+	addLink("upper_arm", r3.Vector{X: 430})
+	addJoint("elbow", r3.Vector{Y: 1})
+	addLink("forearm", r3.Vector{X: 20, Z: 430})
+	addJoint("wrist_1", r3.Vector{Z: 1})
+	addJoint("wrist_2", r3.Vector{Y: 1})
+	addJoint("wrist_3", r3.Vector{Z: 1})
+	addLink("tool", r3.Vector{Z: 60})
+	return model
+}
+
+func TestAnalyticIKSolutions(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	ur5e, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	xarm6, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, m := range []referenceframe.Model{ur5e, xarm6, makePumaModel(t)} {
+		t.Run(m.Name(), func(t *testing.T) {
+			ik, err := CreateAnalyticIKSolver(m, logger, nil)
+			test.That(t, err, test.ShouldBeNil)
+
+			//nolint: gosec
+			randSeed := rand.New(rand.NewSource(1))
+			for i := 0; i < 20; i++ {
+				inputs := referenceframe.FloatsToInputs(referenceframe.GenerateRandomConfiguration(m, randSeed))
+				goal, err := m.Transform(inputs)
+				test.That(t, err, test.ShouldBeNil)
+
+				solutions := ik.Solutions(goal, inputs)
+				test.That(t, len(solutions), test.ShouldBeGreaterThan, 0)
+				// the configuration the goal came from is found, and is the closest to itself as the seed
+				test.That(t, referenceframe.InputsL2Distance(inputs, solutions[0]), test.ShouldBeLessThan, 1e-4)
+				for _, solution := range solutions {
+					pose, err := m.Transform(solution)
+					test.That(t, err, test.ShouldBeNil)
+					test.That(t, spatialmath.PoseAlmostCoincidentEps(pose, goal, 1e-3), test.ShouldBeTrue)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyticIKSolve(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	m, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	goal := spatialmath.NewPose(r3.Vector{X: -400, Y: -200, Z: 300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+	ik, err := CreateAnalyticIKSolver(m, logger, goal)
+	test.That(t, err, test.ShouldBeNil)
+
+	seed := referenceframe.FloatsToInputs([]float64{0, -1, 1, -1, -1, 0})
+	solutions := make(chan []referenceframe.Input, len(ik.Solutions(goal, seed)))
+	test.That(t, ik.Solve(context.Background(), solutions, seed, NewSquaredNormMetric(goal), 1), test.ShouldBeNil)
+	// at most eight branches, each with joints that may be offset by whole turns
+	test.That(t, len(solutions), test.ShouldBeGreaterThanOrEqualTo, 8)
+	closest := <-solutions
+	for solution := range solutions {
+		test.That(t, referenceframe.InputsL2Distance(seed, solution), test.ShouldBeGreaterThanOrEqualTo,
+			referenceframe.InputsL2Distance(seed, closest))
+		if len(solutions) == 0 {
+			break
+		}
+	}
+
+	// unreachable goals have no solutions
+	ik.goal = spatialmath.NewPoseFromPoint(r3.Vector{X: 5000})
+	test.That(t, ik.Solve(context.Background(), solutions, seed, NewSquaredNormMetric(ik.goal), 1), test.ShouldBeError, errNoSolve)
+}
+
+func TestAnalyticIKUnsupported(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	for _, f := range []string{"components/arm/xarm/xarm7_kinematics.json", "components/arm/yahboom/dofbot.json"} {
+		m, err := referenceframe.ParseModelJSONFile(utils.ResolveFile(f), "")
+		test.That(t, err, test.ShouldBeNil)
+		_, err = CreateAnalyticIKSolver(m, logger, nil)
+		test.That(t, err, test.ShouldBeError, errAnalyticIKUnsupported)
+	}
+}
+
+func TestPlanWithAnalyticIK(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	m, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	goal := spatialmath.NewPose(r3.Vector{X: -400, Y: -200, Z: 300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+	seed := referenceframe.FloatsToInputs([]float64{0, -1, 1, -1, -1, 0})
+
+	steps, err := PlanFrameMotion(context.Background(), logger, goal, m, seed, nil, map[string]interface{}{"ik_solver": AnalyticIKSolver})
+	test.That(t, err, test.ShouldBeNil)
+	pose, err := m.Transform(steps[len(steps)-1])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostCoincidentEps(pose, goal, 1e-2), test.ShouldBeTrue)
+
+	_, err = PlanFrameMotion(context.Background(), logger, goal, m, seed, nil, map[string]interface{}{"ik_solver": "unknown"})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestModelIKSolver(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	shipped, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, referenceframe.ModelIKSolver(shipped), test.ShouldEqual, "")
+
+	// models opt in to analytic IK through their config
+	cfg := *shipped.ModelConfig()
+	cfg.IKSolver = AnalyticIKSolver
+	ur5e, err := cfg.ParseConfig(shipped.Name())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, referenceframe.ModelIKSolver(ur5e), test.ShouldEqual, AnalyticIKSolver)
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, ur5e.Name(), referenceframe.World, referenceframe.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+
+	opt := newBasicPlannerOptions()
+	opt.goal = spatialmath.NewPose(r3.Vector{X: -400, Y: -200, Z: 300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+	ik, err := newIKSolver(sf, logger, opt)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok := ik.(*AnalyticIK)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// the planning option overrides the solver named by the model
+	opt.IKSolver = NumericalIKSolver
+	ik, err = newIKSolver(sf, logger, opt)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok = ik.(*AnalyticIK)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// numerical IK is used if the model's solver cannot solve for the frame, as when the arm is on a gantry
+	gantry, err := referenceframe.NewTranslationalFrame("gantry", r3.Vector{X: 1}, referenceframe.Limit{Min: -500, Max: 500})
+	test.That(t, err, test.ShouldBeNil)
+	fs = referenceframe.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(gantry, fs.World()), test.ShouldBeNil)
+	test.That(t, fs.AddFrame(ur5e, gantry), test.ShouldBeNil)
+	sf, err = newSolverFrame(fs, ur5e.Name(), referenceframe.World, referenceframe.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+	opt.IKSolver = ""
+	ik, err = newIKSolver(sf, logger, opt)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok = ik.(*AnalyticIK)
+	test.That(t, ok, test.ShouldBeFalse)
+	opt.IKSolver = AnalyticIKSolver
+	_, err = newIKSolver(sf, logger, opt)
+	test.That(t, err, test.ShouldBeError, errAnalyticIKUnsupported)
+}
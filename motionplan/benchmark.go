@@ -0,0 +1,317 @@
+package motionplan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/service/motion/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// DefaultBenchmarkPlanner is the name used in benchmarks for planning without choosing a planning algorithm, which tries
+// RRT* first and falls back to cBiRRT.
+const DefaultBenchmarkPlanner = "default"
+
+// BenchmarkPlanners are the planners that benchmarks compare by default, named as they are chosen with the planning_alg
+// planning option. The TP-space planner only plans for frames that provide PTGs, which scenarios cannot describe, so it is
+// not benchmarked.
+var BenchmarkPlanners = []string{DefaultBenchmarkPlanner, "rrtstar", "cbirrt"}
+
+// BenchmarkScenario is a fixed motion planning problem: moving a model from a start configuration to a goal pose among
+// obstacles, while satisfying constraints. Scenarios are read from JSON files.
+type BenchmarkScenario struct {
+	Name string `json:"name"`
+	// Model is the path to a model JSON or URDF file, relative to the scenario file or to the root of this repository.
+	Model string `json:"model"`
+	// Obstacles are geometries in the frame of World.
+	Obstacles []spatialmath.GeometryConfig `json:"obstacles,omitempty"`
+	Start     []float64                    `json:"start"`
+	Goal      BenchmarkPose                `json:"goal"`
+	// Constraints are motion service constraints in their protobuf JSON form.
+	Constraints json.RawMessage `json:"constraints,omitempty"`
+	// Options are the planning options used for every planner.
+	Options map[string]interface{} `json:"options,omitempty"`
+	// Seeds are the random seeds each planner is run with, defaulting to a single run with the seed 0.
+	Seeds []int `json:"seeds,omitempty"`
+
+	model       frame.Model
+	constraints *pb.Constraints
+	worldState  *frame.WorldState
+}
+
+// BenchmarkPose is a pose in the frame of World.
+type BenchmarkPose struct {
+	Translation r3.Vector                      `json:"translation"`
+	Orientation *spatialmath.OrientationConfig `json:"orientation,omitempty"`
+}
+
+// BenchmarkResult is the result of running one planner on one scenario with one seed.
+type BenchmarkResult struct {
+	Scenario string
+	Planner  string
+	Seed     int
+	Err      error
+	Duration time.Duration
+	Steps    int
+	// JointDistance is the sum of the L2 distances between the inputs of consecutive steps of the plan.
+	JointDistance float64
+	// CartesianDistance is the distance in mm travelled by the end of the model between consecutive steps of the plan.
+	CartesianDistance float64
+}
+
+// ReadBenchmarkScenarios reads the scenarios in the given JSON files, or in every JSON file in the given directories.
+func ReadBenchmarkScenarios(paths ...string) ([]*BenchmarkScenario, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	scenarios := make([]*BenchmarkScenario, 0, len(files))
+	for _, file := range files {
+		scenario, err := readBenchmarkScenario(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read benchmark scenario %s", file)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+func readBenchmarkScenario(file string) (*BenchmarkScenario, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	scenario := &BenchmarkScenario{}
+	if err := json.Unmarshal(data, scenario); err != nil {
+		return nil, err
+	}
+	if scenario.Name == "" {
+		scenario.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	modelFile := filepath.Join(filepath.Dir(file), scenario.Model)
+	if _, err := os.Stat(modelFile); err != nil {
+		modelFile = utils.ResolveFile(scenario.Model)
+	}
+	if strings.EqualFold(filepath.Ext(modelFile), ".urdf") {
+		scenario.model, err = frame.ParseURDFFile(modelFile, "")
+	} else {
+		scenario.model, err = frame.ParseModelJSONFile(modelFile, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(scenario.Start) != len(scenario.model.DoF()) {
+		return nil, errors.Errorf(
+			"start has %d inputs but model %s has %d",
+			len(scenario.Start),
+			scenario.model.Name(),
+			len(scenario.model.DoF()),
+		)
+	}
+
+	geometries := make([]spatialmath.Geometry, 0, len(scenario.Obstacles))
+	for i := range scenario.Obstacles {
+		geometry, err := scenario.Obstacles[i].ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+		geometries = append(geometries, geometry)
+	}
+	scenario.worldState, err = frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, geometries)},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scenario.constraints = &pb.Constraints{}
+	if len(scenario.Constraints) > 0 {
+		if err := protojson.Unmarshal(scenario.Constraints, scenario.constraints); err != nil {
+			return nil, err
+		}
+	}
+	if len(scenario.Seeds) == 0 {
+		scenario.Seeds = []int{0}
+	}
+	return scenario, nil
+}
+
+// goal returns the goal pose of the scenario.
+func (s *BenchmarkScenario) goal() (spatialmath.Pose, error) {
+	if s.Goal.Orientation == nil {
+		return spatialmath.NewPoseFromPoint(s.Goal.Translation), nil
+	}
+	orientation, err := s.Goal.Orientation.ParseConfig()
+	if err != nil {
+		return nil, err
+	}
+	return spatialmath.NewPose(s.Goal.Translation, orientation), nil
+}
+
+// RunBenchmark plans every scenario with each of the given planners and each seed of the scenario, and returns the result of
+// every run. Failing to plan is recorded in the result rather than returned.
+func RunBenchmark(
+	ctx context.Context,
+	logger golog.Logger,
+	scenarios []*BenchmarkScenario,
+	planners []string,
+) ([]*BenchmarkResult, error) {
+	var results []*BenchmarkResult
+	for _, scenario := range scenarios {
+		goal, err := scenario.goal()
+		if err != nil {
+			return nil, errors.Wrapf(err, "scenario %s", scenario.Name)
+		}
+		fs := frame.NewEmptyFrameSystem(scenario.Name)
+		if err := fs.AddFrame(scenario.model, fs.World()); err != nil {
+			return nil, err
+		}
+		seedMap := frame.StartPositions(fs)
+		seedMap[scenario.model.Name()] = frame.FloatsToInputs(scenario.Start)
+
+		for _, planner := range planners {
+			for _, seed := range scenario.Seeds {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				opts := deepAtomicCopyMap(scenario.Options)
+				opts["rseed"] = seed
+				if planner != DefaultBenchmarkPlanner {
+					opts["planning_alg"] = planner
+				}
+
+				start := time.Now()
+				plan, err := PlanMotion(
+					ctx,
+					logger,
+					frame.NewPoseInFrame(frame.World, goal),
+					scenario.model,
+					seedMap,
+					fs,
+					scenario.worldState,
+					scenario.constraints,
+					opts,
+				)
+				result := &BenchmarkResult{
+					Scenario: scenario.Name,
+					Planner:  planner,
+					Seed:     seed,
+					Err:      err,
+					Duration: time.Since(start),
+				}
+				if err == nil {
+					if err := result.measurePlan(scenario.model, plan); err != nil {
+						return nil, err
+					}
+				}
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+// measurePlan records the length of the plan in the result.
+func (r *BenchmarkResult) measurePlan(model frame.Model, plan []map[string][]frame.Input) error {
+	steps, err := FrameStepsFromRobotPath(model.Name(), plan)
+	if err != nil {
+		return err
+	}
+	r.Steps = len(steps)
+	var last spatialmath.Pose
+	for i, step := range steps {
+		pose, err := model.Transform(step)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			r.JointDistance += frame.InputsL2Distance(steps[i-1], step)
+			r.CartesianDistance += pose.Point().Distance(last.Point())
+		}
+		last = pose
+	}
+	return nil
+}
+
+// WriteBenchmarkTable writes a table comparing the planners on each scenario: how many of their runs succeeded, how long
+// they took on average, and the average steps and lengths of the plans they made.
+func WriteBenchmarkTable(w io.Writer, results []*BenchmarkResult) {
+	type summary struct {
+		scenario, planner                string
+		runs, successes, steps           int
+		duration                         time.Duration
+		jointDistance, cartesianDistance float64
+	}
+	var summaries []*summary
+	byKey := map[[2]string]*summary{}
+	for _, result := range results {
+		key := [2]string{result.Scenario, result.Planner}
+		s, ok := byKey[key]
+		if !ok {
+			s = &summary{scenario: result.Scenario, planner: result.Planner}
+			byKey[key] = s
+			summaries = append(summaries, s)
+		}
+		s.runs++
+		s.duration += result.Duration
+		if result.Err == nil {
+			s.successes++
+			s.steps += result.Steps
+			s.jointDistance += result.JointDistance
+			s.cartesianDistance += result.CartesianDistance
+		}
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Scenario", "Planner", "Success", "Mean time", "Mean steps", "Mean joint distance", "Mean cartesian mm"})
+	for _, s := range summaries {
+		row := table.Row{
+			s.scenario,
+			s.planner,
+			fmt.Sprintf("%d/%d", s.successes, s.runs),
+			(s.duration / time.Duration(s.runs)).Round(time.Millisecond).String(),
+			"-", "-", "-",
+		}
+		if s.successes > 0 {
+			n := float64(s.successes)
+			row[4] = fmt.Sprintf("%.1f", float64(s.steps)/n)
+			row[5] = fmt.Sprintf("%.3f", s.jointDistance/n)
+			row[6] = fmt.Sprintf("%.1f", s.cartesianDistance/n)
+		}
+		t.AppendRow(row)
+	}
+	t.Render()
+}
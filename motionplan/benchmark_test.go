@@ -0,0 +1,48 @@
+package motionplan
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/utils"
+)
+
+func TestBenchmark(t *testing.T) {
+	scenarios, err := ReadBenchmarkScenarios(utils.ResolveFile("motionplan/data/benchmark/ur5e_free.json"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(scenarios), test.ShouldEqual, 1)
+	test.That(t, scenarios[0].Name, test.ShouldEqual, "ur5e_free")
+	scenarios[0].Seeds = []int{0}
+
+	results, err := RunBenchmark(context.Background(), golog.NewTestLogger(t), scenarios, []string{"cbirrt"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(results), test.ShouldEqual, 1)
+	test.That(t, results[0].Err, test.ShouldBeNil)
+	test.That(t, results[0].Steps, test.ShouldBeGreaterThan, 1)
+	test.That(t, results[0].JointDistance, test.ShouldBeGreaterThan, 0)
+	test.That(t, results[0].CartesianDistance, test.ShouldBeGreaterThan, 0)
+
+	var table bytes.Buffer
+	WriteBenchmarkTable(&table, results)
+	test.That(t, table.String(), test.ShouldContainSubstring, "ur5e_free")
+	test.That(t, table.String(), test.ShouldContainSubstring, "1/1")
+
+	// every scenario in a directory is read
+	scenarios, err = ReadBenchmarkScenarios(utils.ResolveFile("motionplan/data/benchmark"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(scenarios), test.ShouldEqual, 3)
+
+	// the start must fit the model
+	dir := t.TempDir()
+	badScenario := filepath.Join(dir, "bad.json")
+	err = os.WriteFile(badScenario, []byte(`{"model": "components/arm/universalrobots/ur5e.json", "start": [0]}`), 0o600)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = ReadBenchmarkScenarios(dir)
+	test.That(t, err, test.ShouldNotBeNil)
+}
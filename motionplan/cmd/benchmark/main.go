@@ -0,0 +1,64 @@
+// Plan motion benchmark scenarios with each planner and print a table comparing them
+// $./benchmark -planners=rrtstar,cbirrt -seeds=0,1,2 motionplan/data/benchmark
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"go.viam.com/rdk/motionplan"
+)
+
+var logger = golog.NewLogger("benchmark")
+
+func main() {
+	plannersPtr := flag.String("planners", strings.Join(motionplan.BenchmarkPlanners, ","), "comma separated planners to compare")
+	seedsPtr := flag.String("seeds", "", "comma separated random seeds, overriding those of each scenario")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		logger.Fatal(errors.New("need at least one scenario file or directory"))
+	}
+
+	scenarios, err := motionplan.ReadBenchmarkScenarios(flag.Args()...)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if *seedsPtr != "" {
+		var seeds []int
+		for _, s := range strings.Split(*seedsPtr, ",") {
+			seed, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				logger.Fatal(errors.Wrapf(err, "invalid seed %q", s))
+			}
+			seeds = append(seeds, seed)
+		}
+		for _, scenario := range scenarios {
+			scenario.Seeds = seeds
+		}
+	}
+
+	// the planners log every plan they make, so only their warnings are shown
+	plannerLoggerConfig := golog.NewDevelopmentLoggerConfig()
+	plannerLoggerConfig.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+	plannerLogger, err := plannerLoggerConfig.Build()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	results, err := motionplan.RunBenchmark(
+		context.Background(),
+		plannerLogger.Sugar().Named("motionplan"),
+		scenarios,
+		strings.Split(*plannersPtr, ","),
+	)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	motionplan.WriteBenchmarkTable(os.Stdout, results)
+}
@@ -3,6 +3,7 @@ package motionplan
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 
 	pb "go.viam.com/api/service/motion/v1"
@@ -137,6 +138,85 @@ func collisionSpecificationsFromProto(
 	return allowedCollisions, nil
 }
 
+// frameSystemAllowedCollisions returns the collisions between each moving geometry and any other geometry that are allowed
+// by the given pairs of the frame system.
+func frameSystemAllowedCollisions(
+	allowed []referenceframe.AllowedCollision,
+	moving []spatial.Geometry,
+	others ...[]spatial.Geometry,
+) []*Collision {
+	if len(allowed) == 0 {
+		return nil
+	}
+	var collisions []*Collision
+	for _, x := range moving {
+		for _, geometries := range append([][]spatial.Geometry{moving}, others...) {
+			for _, y := range geometries {
+				if x == y || x.Label() == "" || y.Label() == "" {
+					continue
+				}
+				for _, pair := range allowed {
+					if pair.Allows(x.Label(), y.Label()) {
+						collisions = append(collisions, &Collision{name1: x.Label(), name2: y.Label()})
+						break
+					}
+				}
+			}
+		}
+	}
+	return collisions
+}
+
+// CollisionPairs returns the names of the pairs of geometries that are checked for collisions when planning a motion of
+// the frame from the given inputs. Each moving geometry is checked against every obstacle, stationary geometry of the
+// robot and other moving geometry, except for pairs allowed to be in collision by the frame system or the collision
+// specifications of the constraints, and unless strict is true, pairs that are already in collision at the given inputs.
+func CollisionPairs(
+	f referenceframe.Frame,
+	fs referenceframe.FrameSystem,
+	worldState *referenceframe.WorldState,
+	inputs map[string][]referenceframe.Input,
+	constraints *pb.Constraints,
+	strict bool,
+) ([][2]string, error) {
+	sf, err := newSolverFrame(fs, f.Name(), referenceframe.World, inputs)
+	if err != nil {
+		return nil, err
+	}
+	geometries, err := newCollisionGeometries(sf, fs, worldState, inputs, constraints.GetCollisionSpecification())
+	if err != nil {
+		return nil, err
+	}
+	pairs := [][2]string{}
+	seen := map[[2]string]bool{}
+	for _, others := range [][]spatial.Geometry{geometries.obstacles, geometries.static, nil} {
+		cg, err := newReferenceCollisionGraph(geometries.moving, others, geometries.allowed, strict)
+		if err != nil {
+			return nil, err
+		}
+		for xName := range cg.x {
+			for yName := range cg.y {
+				if xName == yName || cg.collisionBetween(xName, yName) {
+					continue
+				}
+				pair := [2]string{xName, yName}
+				sort.Strings(pair[:])
+				if !seen[pair] {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs, nil
+}
+
 // geometryGraph is a struct that stores distance relationships between sets of geometries.
 type geometryGraph struct {
 	// x and y are the two sets of geometries, each of which will be compared to the geometries in the other set
@@ -239,6 +319,37 @@ func newCollisionGraph(x, y []spatial.Geometry, reference *collisionGraph, repor
 	return cg, nil
 }
 
+// newReferenceCollisionGraph instantiates the collisionGraph between the x and y sets of geometries that is used as the reference of
+// other collisionGraphs, keeping the collisions it holds from ever being checked. It holds the given allowed collisions and, unless
+// strict is true, the collisions present between the geometries as given. If the set y is nil, the graph will be instantiated with
+// y = x.
+func newReferenceCollisionGraph(x, y []spatial.Geometry, allowed []*Collision, strict bool) (*collisionGraph, error) {
+	var cg *collisionGraph
+	if strict {
+		if y == nil {
+			y = x
+		}
+		xMap, err := createUniqueCollisionMap(x)
+		if err != nil {
+			return nil, err
+		}
+		yMap, err := createUniqueCollisionMap(y)
+		if err != nil {
+			return nil, err
+		}
+		cg = &collisionGraph{geometryGraph: newGeometryGraph(xMap, yMap)}
+	} else {
+		var err error
+		if cg, err = newCollisionGraph(x, y, nil, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, specification := range allowed {
+		cg.addCollisionSpecification(specification)
+	}
+	return cg, nil
+}
+
 // checkCollision takes a pair of geometries and returns the distance between them.
 // If this number is less than the CollisionBuffer they can be considered to be in collision.
 func (cg *collisionGraph) checkCollision(x, y spatial.Geometry) (float64, error) {
@@ -277,9 +388,18 @@ func (cg *collisionGraph) collisions() []Collision {
 	return collisions
 }
 
-// addCollisionSpecification marks the two objects specified as colliding.
+// addCollisionSpecification marks the two objects specified as colliding, if the collisionGraph compares them.
 func (cg *collisionGraph) addCollisionSpecification(specification *Collision) {
-	cg.setDistance(specification.name1, specification.name2, math.Inf(-1))
+	_, x1 := cg.x[specification.name1]
+	_, y1 := cg.y[specification.name1]
+	_, x2 := cg.x[specification.name2]
+	_, y2 := cg.y[specification.name2]
+	switch {
+	case x1 && y2:
+		cg.setDistance(specification.name1, specification.name2, math.Inf(-1))
+	case x2 && y1:
+		cg.setDistance(specification.name2, specification.name1, math.Inf(-1))
+	}
 }
 
 func createUniqueCollisionMap(geoms []spatial.Geometry) (map[string]spatial.Geometry, error) {
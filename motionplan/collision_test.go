@@ -1,6 +1,7 @@
 package motionplan
 
 import (
+	"context"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -117,3 +118,84 @@ func TestUniqueCollisions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, collisionListsAlmostEqual(cg.collisions(), expectedCollisions[:1]), test.ShouldBeTrue)
 }
+
+func TestCollisionPairs(t *testing.T) {
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "ur")
+	test.That(t, err, test.ShouldBeNil)
+	newFS := func(allowed ...frame.AllowedCollision) frame.FrameSystem {
+		fs, err := frame.NewFrameSystem("test", []*frame.FrameSystemPart{{
+			FrameConfig:       frame.NewLinkInFrame(frame.World, spatial.NewZeroPose(), "ur", nil),
+			ModelFrame:        ur5e,
+			AllowedCollisions: allowed,
+		}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		return fs
+	}
+	goal := spatial.NewPose(r3.Vector{X: -400, Y: -200, Z: 300}, &spatial.OrientationVectorDegrees{OZ: -1})
+	box, err := spatial.NewBox(spatial.NewPoseFromPoint(goal.Point()), r3.Vector{100, 100, 100}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatial.Geometry{box})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	hasPair := func(pairs [][2]string, name1, name2 string) bool {
+		for _, pair := range pairs {
+			if pair == [2]string{name1, name2} {
+				return true
+			}
+		}
+		return false
+	}
+	fs := newFS()
+	pairs, err := CollisionPairs(ur5e, fs, worldState, frame.StartPositions(fs), nil, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, hasPair(pairs, "box", "ur:wrist_2_link"), test.ShouldBeTrue)
+	test.That(t, hasPair(pairs, "ur:base_link", "ur:wrist_2_link"), test.ShouldBeTrue)
+	// links allowed to touch by the model are never checked
+	test.That(t, hasPair(pairs, "ur:base_link", "ur:upper_arm_link"), test.ShouldBeFalse)
+
+	// the goal is blocked by the box unless the arm is allowed to collide with it
+	_, err = PlanMotion(context.Background(), logger.Sugar(), frame.NewPoseInFrame(frame.World, goal), fs.Frame("ur"),
+		frame.StartPositions(fs), fs, worldState, nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	fs = newFS(frame.AllowedCollision{Frame1: "ur", Frame2: "box"}, frame.AllowedCollision{Frame1: "ur:base_link", Frame2: "ur:wrist_*"})
+	pairs, err = CollisionPairs(ur5e, fs, worldState, frame.StartPositions(fs), nil, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, hasPair(pairs, "box", "ur:wrist_2_link"), test.ShouldBeFalse)
+	test.That(t, hasPair(pairs, "ur:base_link", "ur:wrist_2_link"), test.ShouldBeFalse)
+	test.That(t, hasPair(pairs, "ur:base_link", "ur:forearm_link"), test.ShouldBeTrue)
+
+	_, err = PlanMotion(context.Background(), logger.Sugar(), frame.NewPoseInFrame(frame.World, goal), fs.Frame("ur"),
+		frame.StartPositions(fs), fs, worldState, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// a mount the arm starts in collision with is only checked when planning strictly, unless it is allowed
+	mount, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{200, 200, 20}, "mount")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err = frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatial.Geometry{mount})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	strict := map[string]interface{}{StrictCollisionsKey: true}
+	pairs, err = CollisionPairs(ur5e, fs, worldState, frame.StartPositions(fs), nil, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, hasPair(pairs, "mount", "ur:base_link"), test.ShouldBeFalse)
+	pairs, err = CollisionPairs(ur5e, fs, worldState, frame.StartPositions(fs), nil, true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, hasPair(pairs, "mount", "ur:base_link"), test.ShouldBeTrue)
+	_, err = PlanMotion(context.Background(), logger.Sugar(), frame.NewPoseInFrame(frame.World, goal), fs.Frame("ur"),
+		frame.StartPositions(fs), fs, worldState, nil, strict)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, frame.AllowCollisions(fs, frame.AllowedCollision{Frame1: "ur:base_link", Frame2: "mount"}), test.ShouldBeNil)
+	pairs, err = CollisionPairs(ur5e, fs, worldState, frame.StartPositions(fs), nil, true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, hasPair(pairs, "mount", "ur:base_link"), test.ShouldBeFalse)
+	_, err = PlanMotion(context.Background(), logger.Sugar(), frame.NewPoseInFrame(frame.World, goal), fs.Frame("ur"),
+		frame.StartPositions(fs), fs, worldState, nil, strict)
+	test.That(t, err, test.ShouldBeNil)
+}
@@ -234,15 +234,20 @@ func (c *ConstraintHandler) SegmentConstraints() []string {
 	return names
 }
 
-func createAllCollisionConstraints(
+// collisionGeometries holds the geometries that are compared when checking a frame for collisions, and the collisions
+// allowed between them.
+type collisionGeometries struct {
+	moving, static, obstacles []spatial.Geometry
+	allowed                   []*Collision
+}
+
+func newCollisionGeometries(
 	frame *solverFrame,
 	fs referenceframe.FrameSystem,
 	worldState *referenceframe.WorldState,
 	inputs map[string][]referenceframe.Input,
 	pbConstraint []*pb.CollisionSpecification,
-) (map[string]StateConstraint, error) {
-	constraintMap := map[string]StateConstraint{}
-
+) (*collisionGeometries, error) {
 	// extract inputs corresponding to the frame
 	frameInputs, err := frame.mapToSlice(inputs)
 	if err != nil {
@@ -281,25 +286,54 @@ func createAllCollisionConstraints(
 	if err != nil {
 		return nil, err
 	}
+	allowedCollisions = append(allowedCollisions, frameSystemAllowedCollisions(
+		referenceframe.AllowedCollisions(fs),
+		movingGeometries.Geometries(),
+		staticGeometries,
+		obstacles.Geometries(),
+	)...)
+
+	return &collisionGeometries{
+		moving:    movingGeometries.Geometries(),
+		static:    staticGeometries,
+		obstacles: obstacles.Geometries(),
+		allowed:   allowedCollisions,
+	}, nil
+}
+
+// StrictCollisionsKey is the planning option which, if true, has every pair of geometries checked for collisions unless the
+// frame system or the collision specifications of the constraints allow it. Otherwise pairs of geometries already in
+// collision where the motion starts are not checked either.
+const StrictCollisionsKey = "strict_collisions"
+
+func createAllCollisionConstraints(
+	frame *solverFrame,
+	fs referenceframe.FrameSystem,
+	worldState *referenceframe.WorldState,
+	inputs map[string][]referenceframe.Input,
+	pbConstraint []*pb.CollisionSpecification,
+	strict bool,
+) (map[string]StateConstraint, error) {
+	constraintMap := map[string]StateConstraint{}
+
+	geometries, err := newCollisionGeometries(frame, fs, worldState, inputs, pbConstraint)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(obstacles.Geometries()) > 0 {
+	if len(geometries.obstacles) > 0 {
 		// create constraint to keep moving geometries from hitting world state obstacles
 		// can use zeroth element of worldState.Obstacles because ToWorldFrame returns only one GeometriesInFrame
-		obstacleConstraint, err := newCollisionConstraint(
-			movingGeometries.Geometries(),
-			obstacles.Geometries(),
-			allowedCollisions,
-			false,
-		)
+		obstacleConstraint, err := newCollisionConstraint(geometries.moving, geometries.obstacles, geometries.allowed, strict, false)
 		if err != nil {
 			return nil, err
 		}
 		constraintMap[defaultObstacleConstraintDesc] = obstacleConstraint
 	}
 
-	if len(staticGeometries) > 0 {
+	if len(geometries.static) > 0 {
 		// create constraint to keep moving geometries from hitting other geometries on robot that are not moving
-		robotConstraint, err := newCollisionConstraint(movingGeometries.Geometries(), staticGeometries, allowedCollisions, false)
+		robotConstraint, err := newCollisionConstraint(geometries.moving, geometries.static, geometries.allowed, strict, false)
 		if err != nil {
 			return nil, err
 		}
@@ -307,8 +341,8 @@ func createAllCollisionConstraints(
 	}
 
 	// create constraint to keep moving geometries from hitting themselves
-	if len(movingGeometries.Geometries()) > 1 {
-		selfCollisionConstraint, err := newCollisionConstraint(movingGeometries.Geometries(), nil, allowedCollisions, false)
+	if len(geometries.moving) > 1 {
+		selfCollisionConstraint, err := newCollisionConstraint(geometries.moving, nil, geometries.allowed, strict, false)
 		if err != nil {
 			return nil, err
 		}
@@ -319,21 +353,19 @@ func createAllCollisionConstraints(
 
 // newCollisionConstraint is the most general method to create a collision constraint, which will be violated if geometries constituting
 // the given frame ever come into collision with obstacle geometries outside of the collisions present for the observationInput.
-// Collisions specified as collisionSpecifications will also be ignored
+// Collisions specified as collisionSpecifications will also be ignored. If strict is true, only those are.
 // if reportDistances is false, this check will be done as fast as possible, if true maximum information will be available for debugging.
 func newCollisionConstraint(
 	moving, static []spatial.Geometry,
 	collisionSpecifications []*Collision,
+	strict bool,
 	reportDistances bool,
 ) (StateConstraint, error) {
 	// create the reference collisionGraph
-	zeroCG, err := newCollisionGraph(moving, static, nil, true)
+	zeroCG, err := newReferenceCollisionGraph(moving, static, collisionSpecifications, strict)
 	if err != nil {
 		return nil, err
 	}
-	for _, specification := range collisionSpecifications {
-		zeroCG.addCollisionSpecification(specification)
-	}
 
 	// create constraint from reference collision graph
 	constraint := func(state *State) bool {
@@ -217,7 +217,7 @@ func TestCollisionConstraints(t *testing.T) {
 	sf, err := newSolverFrame(fs, model.Name(), frame.World, frame.StartPositions(fs))
 	test.That(t, err, test.ShouldBeNil)
 	handler := &ConstraintHandler{}
-	collisionConstraints, err := createAllCollisionConstraints(sf, fs, worldState, frame.StartPositions(fs), nil)
+	collisionConstraints, err := createAllCollisionConstraints(sf, fs, worldState, frame.StartPositions(fs), nil, false)
 	test.That(t, err, test.ShouldBeNil)
 	for name, constraint := range collisionConstraints {
 		handler.AddStateConstraint(name, constraint)
@@ -254,7 +254,7 @@ func BenchmarkCollisionConstraints(b *testing.B) {
 	sf, err := newSolverFrame(fs, model.Name(), frame.World, frame.StartPositions(fs))
 	test.That(b, err, test.ShouldBeNil)
 	handler := &ConstraintHandler{}
-	collisionConstraints, err := createAllCollisionConstraints(sf, fs, worldState, frame.StartPositions(fs), nil)
+	collisionConstraints, err := createAllCollisionConstraints(sf, fs, worldState, frame.StartPositions(fs), nil, false)
 	test.That(b, err, test.ShouldBeNil)
 	for name, constraint := range collisionConstraints {
 		handler.AddStateConstraint(name, constraint)
@@ -128,7 +128,25 @@ func motionPlanInternal(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	var recordingDir string
+	if name, ok := motionConfig[DebugRecordingKey].(string); ok && name != "" {
+		if recordingDir, err = DebugRecordingPath(name); err != nil {
+			return nil, err
+		}
+		sfPlanner.recording, err = newPlanRecording(sf, seed, goal, seedMap, worldState)
+		if err != nil {
+			return nil, err
+		}
+	}
 	resultSlices, err := sfPlanner.PlanSingleWaypoint(ctx, seedMap, goal.Pose(), worldState, constraintSpec, motionConfig)
+	if sfPlanner.recording != nil {
+		sfPlanner.recording.finish(resultSlices, err)
+		if path, writeErr := sfPlanner.recording.WriteFiles(recordingDir); writeErr != nil {
+			logger.Warnf("failed to write motion plan recording: %v", writeErr)
+		} else {
+			logger.Infof("wrote motion plan recording to %s", path)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +170,7 @@ type planner struct {
 }
 
 func newPlanner(frame frame.Frame, seed *rand.Rand, logger golog.Logger, opt *plannerOptions) (*planner, error) {
-	ik, err := CreateCombinedIKSolver(frame, logger, opt.NumThreads, opt.GoalThreshold)
+	ik, err := newIKSolver(frame, logger, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +184,41 @@ func newPlanner(frame frame.Frame, seed *rand.Rand, logger golog.Logger, opt *pl
 	return mp, nil
 }
 
+// NumericalIKSolver is the value of the "ik_solver" planning option which selects the default numerical inverse kinematics,
+// even for models which name another solver.
+const NumericalIKSolver = "numerical"
+
+// newIKSolver creates the IK solver named by the ik_solver planning option, or if that is unset, by the model being planned
+// for. The default numerical solvers are used if neither names a solver, or if the model's solver cannot solve for the frame.
+func newIKSolver(f frame.Frame, logger golog.Logger, opt *plannerOptions) (InverseKinematics, error) {
+	if opt.IKSolver != "" {
+		switch opt.IKSolver {
+		case AnalyticIKSolver:
+			return CreateAnalyticIKSolver(f, logger, opt.goal)
+		case NumericalIKSolver:
+			return CreateCombinedIKSolver(f, logger, opt.NumThreads, opt.GoalThreshold)
+		default:
+			return nil, errors.Errorf("unknown ik_solver %q", opt.IKSolver)
+		}
+	}
+
+	// the solvers a model may name only solve for a known goal
+	if sf, ok := f.(*solverFrame); ok && opt.goal != nil {
+		switch solver := sf.modelIKSolver(); solver {
+		case AnalyticIKSolver:
+			ik, err := CreateAnalyticIKSolver(f, logger, opt.goal)
+			if err == nil {
+				return ik, nil
+			}
+			logger.Debugf("cannot use the analytic IK solver of the model, using numerical IK: %v", err)
+		case "":
+		default:
+			logger.Warnf("unknown ik_solver %q named by the model, using numerical IK", solver)
+		}
+	}
+	return CreateCombinedIKSolver(f, logger, opt.NumThreads, opt.GoalThreshold)
+}
+
 func (mp *planner) checkInputs(inputs []frame.Input) bool {
 	ok, _ := mp.planOpts.CheckStateConstraints(&State{
 		Configuration: inputs,
@@ -353,5 +406,6 @@ IK:
 	for _, key := range keys {
 		orderedSolutions = append(orderedSolutions, &basicNode{q: solutions[key], cost: key})
 	}
+	mp.planOpts.recording.recordIKSolutions(orderedSolutions)
 	return orderedSolutions, nil
 }
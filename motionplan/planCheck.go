@@ -0,0 +1,88 @@
+package motionplan
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+// ObservedObstaclesKey is the planning option holding *ObservedObstacles that a plan must also avoid. Unlike the obstacles of
+// the world state, a robot is never allowed to start in collision with observed obstacles.
+const ObservedObstaclesKey = "observed_obstacles"
+
+// ObservedObstacles are obstacles seen around a robot, such as by its cameras, stored as points in the frame of World. A
+// geometry collides with an observed obstacle when it comes within Buffer mm of one of the points.
+type ObservedObstacles struct {
+	Points *pointcloud.BasicOctree
+	Buffer float64
+}
+
+// constraint returns a constraint that is met by states whose geometries do not collide with any of the observed obstacles.
+func (o *ObservedObstacles) constraint() StateConstraint {
+	// every point is an obstacle, whatever its data
+	return NewOctreeCollisionConstraint(o.Points, math.MinInt, o.Buffer)
+}
+
+// CheckPlan checks whether a frame can move from the current inputs through each step of a plan in turn without colliding with
+// the obstacles of the world state, the observed obstacles, the rest of the robot or itself. The motion between steps is checked
+// at the given resolution in mm. Collisions with the world state present at the current inputs are allowed, as they are when
+// planning, but collisions with observed obstacles never are. If the plan is predicted to collide, the returned error names the
+// step being moved to and the constraint that fails.
+func CheckPlan(
+	f frame.Frame,
+	currentInputs map[string][]frame.Input,
+	plan []map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	observed *ObservedObstacles,
+	resolution float64,
+) error {
+	if resolution <= 0 {
+		return errors.New("resolution of a plan check must be positive")
+	}
+	sf, err := newSolverFrame(fs, f.Name(), frame.World, currentInputs)
+	if err != nil {
+		return err
+	}
+	collisionConstraints, err := createAllCollisionConstraints(sf, fs, worldState, currentInputs, nil, false)
+	if err != nil {
+		return err
+	}
+	handler := &ConstraintHandler{}
+	for name, constraint := range collisionConstraints {
+		handler.AddStateConstraint(name, constraint)
+	}
+	if observed != nil && observed.Points != nil {
+		handler.AddStateConstraint(defaultObservedConstraintDesc, observed.constraint())
+	}
+
+	from, err := sf.mapToSlice(currentInputs)
+	if err != nil {
+		return err
+	}
+	for i, step := range plan {
+		to, err := sf.mapToSlice(step)
+		if err != nil {
+			return err
+		}
+		segment := &Segment{StartConfiguration: from, EndConfiguration: to, Frame: sf}
+		if err := resolveSegmentsToPositions(segment); err != nil {
+			return err
+		}
+		interpSteps := PathStepCount(segment.StartPosition, segment.EndPosition, resolution)
+		for j := 0; j <= interpSteps; j++ {
+			state := &State{
+				Frame:         sf,
+				Configuration: frame.InterpolateInputs(from, to, float64(j)/float64(interpSteps)),
+			}
+			if ok, name := handler.CheckStateConstraints(state); !ok {
+				return errors.Errorf("moving to step %d of the plan fails a constraint: %s", i, name)
+			}
+		}
+		from = to
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package motionplan
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/pointcloud"
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestCheckPlan(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("")
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "ur")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+	current := frame.StartPositions(fs)
+	current[ur5e.Name()] = frame.FloatsToInputs([]float64{0, -1, 1, -1, -1, 0})
+	plan := []map[string][]frame.Input{
+		{ur5e.Name(): frame.FloatsToInputs([]float64{1, -1, 1, -1, -1, 0})},
+		{ur5e.Name(): frame.FloatsToInputs([]float64{1.5, -1, 1, -1, -1, 0})},
+	}
+	test.That(t, CheckPlan(ur5e, current, plan, fs, nil, nil, defaultResolution), test.ShouldBeNil)
+
+	// an obstacle swept through while moving to the second step
+	between, err := ur5e.Transform(frame.FloatsToInputs([]float64{1.25, -1, 1, -1, -1, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(between.Point()), r3.Vector{20, 20, 20}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatialmath.Geometry{box})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	err = CheckPlan(ur5e, current, plan, fs, worldState, nil, defaultResolution)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldEqual, "moving to step 1 of the plan fails a constraint: "+defaultObstacleConstraintDesc)
+
+	// only the rest of the plan is checked once the obstacle has been passed
+	test.That(t, CheckPlan(ur5e, plan[1], plan[2:], fs, worldState, nil, defaultResolution), test.ShouldBeNil)
+
+	test.That(t, CheckPlan(ur5e, current, plan, fs, nil, nil, 0), test.ShouldNotBeNil)
+
+	// the same obstacle observed as a point is avoided too
+	points, err := pointcloud.NewBasicOctree(between.Point(), 100)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, points.Set(between.Point(), pointcloud.NewBasicData()), test.ShouldBeNil)
+	observed := &ObservedObstacles{Points: points, Buffer: 10}
+	err = CheckPlan(ur5e, current, plan, fs, nil, observed, defaultResolution)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldEqual, "moving to step 1 of the plan fails a constraint: "+defaultObservedConstraintDesc)
+
+	// starting in collision with an obstacle of the world state is allowed, but not with an observed one
+	start := map[string][]frame.Input{ur5e.Name(): frame.FloatsToInputs([]float64{1.25, -1, 1, -1, -1, 0})}
+	test.That(t, CheckPlan(ur5e, start, plan[1:], fs, worldState, nil, defaultResolution), test.ShouldBeNil)
+	err = CheckPlan(ur5e, start, plan[1:], fs, nil, observed, defaultResolution)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldEqual, "moving to step 0 of the plan fails a constraint: "+defaultObservedConstraintDesc)
+}
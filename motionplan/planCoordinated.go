@@ -0,0 +1,251 @@
+package motionplan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/service/motion/v1"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// sweptVolumeResolution is how far in mm a frame moves between the geometries sampled along its plan to make up the volume
+// it sweeps through.
+const sweptVolumeResolution = 20.
+
+// FrameGoal is the goal of one of the frames moved by a coordinated motion.
+type FrameGoal struct {
+	Frame frame.Frame
+	Goal  *frame.PoseInFrame
+}
+
+// coordinatedPlan is the plan of one frame of a coordinated motion, holding only the inputs of the frames it moves.
+type coordinatedPlan struct {
+	sf    *solverFrame
+	steps []map[string][]frame.Input
+}
+
+// PlanCoordinatedMotion plans motions of several frames of a frame system to their own goals, and returns them as a single
+// synchronized plan in which the frames move simultaneously. Each step of the plan holds the inputs of every frame moved by
+// any of the motions.
+//
+// Frames are planned in the order given. While a frame is planned the frames yet to be planned are at their starting
+// inputs, and the volumes swept by the frames already planned are obstacles, so that every frame can move at the same time
+// without colliding with the others. A frame that cannot avoid those volumes is instead planned to start moving once the
+// frames before it have reached their goals. No two of the frames may move the same input, for instance by sharing a gantry.
+func PlanCoordinatedMotion(ctx context.Context,
+	logger golog.Logger,
+	goals []FrameGoal,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	constraintSpec *pb.Constraints,
+	planningOpts map[string]interface{},
+) ([]map[string][]frame.Input, error) {
+	if len(goals) == 0 {
+		return nil, errors.New("no goals passed to PlanCoordinatedMotion")
+	}
+
+	// goals are planned to in the frame of World, so that each plan only moves the frames between its frame and World
+	worldGoals := make([]*frame.PoseInFrame, 0, len(goals))
+	movedBy := map[string]string{}
+	for _, goal := range goals {
+		if goal.Frame == nil || goal.Goal == nil {
+			return nil, errors.New("coordinated motion goals must have a frame and a destination")
+		}
+		tf, err := fs.Transform(seedMap, goal.Goal, frame.World)
+		if err != nil {
+			return nil, err
+		}
+		worldGoals = append(worldGoals, tf.(*frame.PoseInFrame))
+
+		sf, err := newSolverFrame(fs, goal.Frame.Name(), frame.World, seedMap)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range sf.frames {
+			if len(f.DoF()) == 0 {
+				continue
+			}
+			if other, ok := movedBy[f.Name()]; ok {
+				return nil, errors.Errorf("cannot plan %q and %q together as both move %q", other, goal.Frame.Name(), f.Name())
+			}
+			movedBy[f.Name()] = goal.Frame.Name()
+		}
+	}
+
+	// current holds the inputs of every frame at the start of the phase being planned, in which frames move simultaneously
+	current := copyInputMap(seedMap)
+	var steps []map[string][]frame.Input
+	var phase []*coordinatedPlan
+	var swept []spatialmath.Geometry
+	for i, goal := range goals {
+		plan, err := planCoordinatedFrame(ctx, logger, goal.Frame, worldGoals[i], current, fs, worldState, swept, constraintSpec, planningOpts)
+		if err != nil && len(phase) > 0 {
+			logger.Debugf("planning %s to move after the frames before it, as it cannot avoid them: %v", goal.Frame.Name(), err)
+			steps = append(steps, synchronizePlans(phase, current, movedBy)...)
+			for _, p := range phase {
+				for name, inputs := range p.steps[len(p.steps)-1] {
+					current[name] = inputs
+				}
+			}
+			phase = nil
+			swept = nil
+			plan, err = planCoordinatedFrame(ctx, logger, goal.Frame, worldGoals[i], current, fs, worldState, nil, constraintSpec, planningOpts)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to plan coordinated motion of %s", goal.Frame.Name())
+		}
+		phase = append(phase, plan)
+		if i < len(goals)-1 {
+			planSwept, err := plan.sweptVolume(len(swept))
+			if err != nil {
+				return nil, err
+			}
+			swept = append(swept, planSwept...)
+		}
+	}
+	return append(steps, synchronizePlans(phase, current, movedBy)...), nil
+}
+
+// planCoordinatedFrame plans the motion of one frame of a coordinated motion from the given inputs, avoiding the obstacles
+// of the world state and the swept geometries.
+func planCoordinatedFrame(ctx context.Context,
+	logger golog.Logger,
+	f frame.Frame,
+	goal *frame.PoseInFrame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	swept []spatialmath.Geometry,
+	constraintSpec *pb.Constraints,
+	planningOpts map[string]interface{},
+) (*coordinatedPlan, error) {
+	if len(swept) > 0 {
+		obstacles, err := worldState.ObstaclesInWorldFrame(fs, seedMap)
+		if err != nil {
+			return nil, err
+		}
+		worldState, err = frame.NewWorldState(
+			[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, append(obstacles.Geometries(), swept...))},
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sf, err := newSolverFrame(fs, f.Name(), frame.World, seedMap)
+	if err != nil {
+		return nil, err
+	}
+	planSteps, err := motionPlanInternal(ctx, logger, goal, f, seedMap, fs, worldState, constraintSpec, planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	if len(planSteps) == 0 {
+		planSteps = append(planSteps, seedMap)
+	}
+
+	// only the inputs of the frames moved by this plan are kept
+	plan := &coordinatedPlan{sf: sf}
+	for _, step := range planSteps {
+		planStep := map[string][]frame.Input{}
+		for _, moved := range sf.frames {
+			if len(moved.DoF()) > 0 {
+				planStep[moved.Name()] = step[moved.Name()]
+			}
+		}
+		plan.steps = append(plan.steps, planStep)
+	}
+	return plan, nil
+}
+
+// sweptVolume returns the geometries of the plan's frames sampled along the plan. Each geometry is labelled with its own
+// label and the number of geometries sampled before it, starting from the given count, which keeps the labels of the
+// geometries of different plans unique.
+func (p *coordinatedPlan) sweptVolume(count int) ([]spatialmath.Geometry, error) {
+	var swept []spatialmath.Geometry
+	addGeometries := func(inputs []frame.Input) error {
+		geometries, err := p.sf.Geometries(inputs)
+		if err != nil {
+			return err
+		}
+		for _, geometry := range geometries.Geometries() {
+			sample := geometry.Transform(spatialmath.NewZeroPose())
+			sample.SetLabel(fmt.Sprintf("%s@%d", geometry.Label(), count+len(swept)))
+			swept = append(swept, sample)
+		}
+		return nil
+	}
+
+	from, err := p.sf.mapToSlice(p.steps[0])
+	if err != nil {
+		return nil, err
+	}
+	if err := addGeometries(from); err != nil {
+		return nil, err
+	}
+	for _, step := range p.steps[1:] {
+		to, err := p.sf.mapToSlice(step)
+		if err != nil {
+			return nil, err
+		}
+		segment := &Segment{StartConfiguration: from, EndConfiguration: to, Frame: p.sf}
+		if err := resolveSegmentsToPositions(segment); err != nil {
+			return nil, err
+		}
+		interpSteps := PathStepCount(segment.StartPosition, segment.EndPosition, sweptVolumeResolution)
+		for j := 1; j <= interpSteps; j++ {
+			if err := addGeometries(frame.InterpolateInputs(from, to, float64(j)/float64(interpSteps))); err != nil {
+				return nil, err
+			}
+		}
+		from = to
+	}
+	return swept, nil
+}
+
+// synchronizePlans merges the plans of a phase of a coordinated motion into steps in which they all move simultaneously.
+// Plans with fewer steps than the others stay at their last step, and the other frames moved by the coordinated motion
+// stay at their inputs at the start of the phase.
+func synchronizePlans(
+	phase []*coordinatedPlan,
+	current map[string][]frame.Input,
+	movedBy map[string]string,
+) []map[string][]frame.Input {
+	length := 0
+	for _, p := range phase {
+		if len(p.steps) > length {
+			length = len(p.steps)
+		}
+	}
+	steps := make([]map[string][]frame.Input, 0, length)
+	for i := 0; i < length; i++ {
+		step := make(map[string][]frame.Input, len(movedBy))
+		for name := range movedBy {
+			step[name] = current[name]
+		}
+		for _, p := range phase {
+			planStep := p.steps[len(p.steps)-1]
+			if i < len(p.steps) {
+				planStep = p.steps[i]
+			}
+			for name, inputs := range planStep {
+				step[name] = inputs
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func copyInputMap(inputs map[string][]frame.Input) map[string][]frame.Input {
+	inputsCopy := make(map[string][]frame.Input, len(inputs))
+	for name, value := range inputs {
+		inputsCopy[name] = value
+	}
+	return inputsCopy
+}
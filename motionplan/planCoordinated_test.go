@@ -0,0 +1,111 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// dualArmFrameSystem returns a frame system of two UR5e arms, "a" at the origin and "b" at the given position and rotated
+// half a turn about Z to face "a".
+func dualArmFrameSystem(t *testing.T, bPosition r3.Vector) (frame.FrameSystem, frame.Model, frame.Model) {
+	t.Helper()
+	fs := frame.NewEmptyFrameSystem("")
+	a, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "a")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(a, fs.World()), test.ShouldBeNil)
+	bOffset, err := frame.NewStaticFrame("b_offset", spatialmath.NewPose(bPosition, &spatialmath.OrientationVector{OZ: 1, Theta: math.Pi}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(bOffset, fs.World()), test.ShouldBeNil)
+	b, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "b")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(b, bOffset), test.ShouldBeNil)
+	return fs, a, b
+}
+
+// poseAt returns the pose in World of a frame at the given inputs, with every other frame at its starting inputs.
+func poseAt(t *testing.T, fs frame.FrameSystem, f frame.Frame, inputs []float64) *frame.PoseInFrame {
+	t.Helper()
+	fsInputs := frame.StartPositions(fs)
+	fsInputs[f.Name()] = frame.FloatsToInputs(inputs)
+	tf, err := fs.Transform(fsInputs, frame.NewPoseInFrame(f.Name(), spatialmath.NewZeroPose()), frame.World)
+	test.That(t, err, test.ShouldBeNil)
+	return tf.(*frame.PoseInFrame)
+}
+
+func TestPlanCoordinatedMotion(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	start := []float64{0, -1, 1, -1, -1, 0}
+	goal := []float64{1.2, -1, 1, -1, -1, 0}
+
+	t.Run("simultaneous", func(t *testing.T) {
+		fs, a, b := dualArmFrameSystem(t, r3.Vector{X: 1500})
+		seedMap := frame.StartPositions(fs)
+		seedMap[a.Name()] = frame.FloatsToInputs(start)
+		seedMap[b.Name()] = frame.FloatsToInputs(start)
+		goals := []FrameGoal{
+			{Frame: a, Goal: poseAt(t, fs, a, goal)},
+			{Frame: b, Goal: poseAt(t, fs, b, goal)},
+		}
+		plan, err := PlanCoordinatedMotion(ctx, logger, goals, seedMap, fs, nil, nil, map[string]interface{}{"rseed": 1})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(plan), test.ShouldBeGreaterThan, 1)
+
+		// both arms start moving in the first step, and every step holds the inputs of both
+		for _, step := range plan {
+			test.That(t, len(step), test.ShouldEqual, 2)
+		}
+		test.That(t, plan[1][a.Name()], test.ShouldNotResemble, seedMap[a.Name()])
+		test.That(t, plan[1][b.Name()], test.ShouldNotResemble, seedMap[b.Name()])
+		for _, goal := range goals {
+			last := plan[len(plan)-1]
+			pose := poseAt(t, fs, goal.Frame, frame.InputsToFloats(last[goal.Frame.Name()]))
+			test.That(t, spatialmath.PoseAlmostCoincidentEps(pose.Pose(), goal.Goal.Pose(), 1), test.ShouldBeTrue)
+		}
+	})
+
+	t.Run("sequential when the swept volume is in the way", func(t *testing.T) {
+		// "b" reaches to where the end of "a" starts, so it can only move once "a" has moved away
+		fs, a, b := dualArmFrameSystem(t, r3.Vector{X: -1320, Y: -374})
+		seedMap := frame.StartPositions(fs)
+		seedMap[a.Name()] = frame.FloatsToInputs(start)
+		seedMap[b.Name()] = frame.FloatsToInputs(goal)
+		aGoal := poseAt(t, fs, a, goal)
+		bGoal := poseAt(t, fs, b, start)
+		goals := []FrameGoal{{Frame: a, Goal: aGoal}, {Frame: b, Goal: bGoal}}
+		plan, err := PlanCoordinatedMotion(ctx, logger, goals, seedMap, fs, nil, nil, map[string]interface{}{"rseed": 1})
+		test.That(t, err, test.ShouldBeNil)
+
+		// "b" stays still until "a" has reached its goal
+		aArrives := 0
+		for i, step := range plan {
+			pose := poseAt(t, fs, a, frame.InputsToFloats(step[a.Name()]))
+			if spatialmath.PoseAlmostCoincidentEps(pose.Pose(), aGoal.Pose(), 1) {
+				aArrives = i
+				break
+			}
+			test.That(t, step[b.Name()], test.ShouldResemble, seedMap[b.Name()])
+		}
+		test.That(t, aArrives, test.ShouldBeGreaterThan, 0)
+		last := plan[len(plan)-1]
+		pose := poseAt(t, fs, b, frame.InputsToFloats(last[b.Name()]))
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(pose.Pose(), bGoal.Pose(), 1), test.ShouldBeTrue)
+	})
+
+	t.Run("frames moving the same inputs", func(t *testing.T) {
+		fs, a, _ := dualArmFrameSystem(t, r3.Vector{X: 1500})
+		goals := []FrameGoal{{Frame: a, Goal: poseAt(t, fs, a, goal)}, {Frame: a, Goal: poseAt(t, fs, a, start)}}
+		_, err := PlanCoordinatedMotion(ctx, logger, goals, frame.StartPositions(fs), fs, nil, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "both move")
+	})
+}
@@ -27,8 +27,9 @@ const (
 // motionplan.PlanMotion() -> SolvableFrameSystem.SolveWaypointsWithOptions() -> planManager.planSingleWaypoint().
 type planManager struct {
 	*planner
-	frame *solverFrame
-	fs    referenceframe.FrameSystem
+	frame     *solverFrame
+	fs        referenceframe.FrameSystem
+	recording *PlanRecording
 }
 
 func newPlanManager(
@@ -42,7 +43,7 @@ func newPlanManager(
 	if err != nil {
 		return nil, err
 	}
-	return &planManager{p, frame, fs}, nil
+	return &planManager{planner: p, frame: frame, fs: fs}, nil
 }
 
 // PlanSingleWaypoint will solve the solver frame to one individual pose. If you have multiple waypoints to hit, call this multiple times.
@@ -316,6 +317,11 @@ func (pm *planManager) planParallelRRTMotion(
 		// We didn't get a solution preview (possible error), so we get and process the full step set and error.
 
 		mapSeed := finalSteps.maps
+		// the trees must be recorded before a fallback can continue growing them
+		pathPlanner.opt().recording.recordTrees(mapSeed)
+		if finalSteps.err() == nil {
+			pathPlanner.opt().recording.recordPath("unsmoothed", finalSteps.toInputs())
+		}
 
 		// Create fallback planner
 		var fallbackPlanner motionPlanner
@@ -372,6 +378,9 @@ func (pm *planManager) planParallelRRTMotion(
 
 		// Receive the newly smoothed path from our original solve, and score it
 		finalSteps.steps = <-smoothChan
+		if finalSteps.err() == nil {
+			pathPlanner.opt().recording.recordPath("smoothed", finalSteps.toInputs())
+		}
 		_, score := goodPlan(finalSteps, pathPlanner.opt())
 
 		// If we ran a fallback, retrieve the result and compare to the smoothed path
@@ -414,22 +423,29 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 	// Start with normal options
 	opt := newBasicPlannerOptions()
 	opt.SetGoalMetric(NewSquaredNormMetric(to))
+	opt.goal = to
+	opt.recording = pm.recording
 
 	opt.extra = planningOpts
 
 	// add collision constraints
+	strict, _ := planningOpts[StrictCollisionsKey].(bool)
 	collisionConstraints, err := createAllCollisionConstraints(
 		pm.frame,
 		pm.fs,
 		worldState,
 		seedMap,
 		constraints.GetCollisionSpecification(),
+		strict,
 	)
 	if err != nil {
 		return nil, err
 	}
 	for name, constraint := range collisionConstraints {
-		opt.AddStateConstraint(name, constraint)
+		opt.AddStateConstraint(name, pm.recording.watchConstraint(name, constraint))
+	}
+	if observed, ok := planningOpts[ObservedObstaclesKey].(*ObservedObstacles); ok && observed != nil && observed.Points != nil {
+		opt.AddStateConstraint(defaultObservedConstraintDesc, pm.recording.watchConstraint(defaultObservedConstraintDesc, observed.constraint()))
 	}
 
 	hasTopoConstraint := opt.addPbTopoConstraints(from, to, constraints)
@@ -0,0 +1,69 @@
+package motionplan
+
+import (
+	"context"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/service/motion/v1"
+
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+// PathSegment is one segment of a cartesian path, ending at Goal. While moving along the segment the frame stays within
+// LinearToleranceMm of the straight line from the end of the previous segment, and within OrientationToleranceDegs of the
+// orientation interpolated between them. Tolerances of zero use the defaults of the linear constraint.
+type PathSegment struct {
+	Goal                     *frame.PoseInFrame
+	LinearToleranceMm        float64
+	OrientationToleranceDegs float64
+}
+
+// PlanMotionAlongPath plans a motion of the given frame through the goal of each path segment in turn, staying within the
+// tolerances of each segment, and returns the whole path as a single plan. The first segment starts from the seed.
+func PlanMotionAlongPath(ctx context.Context,
+	logger golog.Logger,
+	path []PathSegment,
+	f frame.Frame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	planningOpts map[string]interface{},
+) ([]map[string][]frame.Input, error) {
+	if len(path) == 0 {
+		return nil, errors.New("no path segments passed to PlanMotionAlongPath")
+	}
+
+	var steps []map[string][]frame.Input
+	for i, segment := range path {
+		lineTolerance := float32(segment.LinearToleranceMm)
+		orientationTolerance := float32(segment.OrientationToleranceDegs)
+		constraints := &pb.Constraints{LinearConstraint: []*pb.LinearConstraint{{
+			LineToleranceMm:          &lineTolerance,
+			OrientationToleranceDegs: &orientationTolerance,
+		}}}
+		segmentSteps, err := motionPlanInternal(ctx, logger, segment.Goal, f, seedMap, fs, worldState, constraints, planningOpts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to plan path segment %d", i)
+		}
+		if len(segmentSteps) == 0 {
+			continue
+		}
+		// each segment begins where the previous one ended, so that step is not repeated
+		if len(steps) > 0 {
+			segmentSteps = segmentSteps[1:]
+		}
+		steps = append(steps, segmentSteps...)
+
+		// the next segment starts from where this one ended
+		nextSeed := make(map[string][]frame.Input, len(seedMap))
+		for name, inputs := range seedMap {
+			nextSeed[name] = inputs
+		}
+		for name, inputs := range steps[len(steps)-1] {
+			nextSeed[name] = inputs
+		}
+		seedMap = nextSeed
+	}
+	return steps, nil
+}
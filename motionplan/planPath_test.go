@@ -0,0 +1,58 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestPlanMotionAlongPath(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("")
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "ur")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+	seedMap := frame.StartPositions(fs)
+	seedMap[ur5e.Name()] = frame.FloatsToInputs([]float64{0, -1, 1, -1, -1, 0})
+	start, err := ur5e.Transform(seedMap[ur5e.Name()])
+	test.That(t, err, test.ShouldBeNil)
+
+	// trace three sides of a square
+	corners := []r3.Vector{start.Point()}
+	var path []PathSegment
+	for _, offset := range []r3.Vector{{X: 100}, {X: 100, Y: 100}, {Y: 100}} {
+		corner := start.Point().Add(offset)
+		corners = append(corners, corner)
+		path = append(path, PathSegment{
+			Goal:              frame.NewPoseInFrame(frame.World, spatialmath.NewPose(corner, start.Orientation())),
+			LinearToleranceMm: 1,
+		})
+	}
+	steps, err := PlanMotionAlongPath(context.Background(), logger.Sugar(), path, ur5e, seedMap, fs, nil, map[string]interface{}{"max_ik_solutions": 1})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, steps[0][ur5e.Name()], test.ShouldResemble, seedMap[ur5e.Name()])
+
+	// every step is close to one of the sides, and every corner is visited in order
+	corner := 1
+	for _, step := range steps {
+		pose, err := ur5e.Transform(step[ur5e.Name()])
+		test.That(t, err, test.ShouldBeNil)
+		distance := spatialmath.DistToLineSegment(corners[corner-1], corners[corner], pose.Point())
+		test.That(t, distance, test.ShouldBeLessThan, 2)
+		if pose.Point().Sub(corners[corner]).Norm() < 1e-2 && corner < len(corners)-1 {
+			corner++
+		}
+	}
+	test.That(t, corner, test.ShouldEqual, len(corners)-1)
+	end, err := ur5e.Transform(steps[len(steps)-1][ur5e.Name()])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostCoincidentEps(end, path[len(path)-1].Goal.Pose(), 1e-2), test.ShouldBeTrue)
+
+	_, err = PlanMotionAlongPath(context.Background(), logger.Sugar(), nil, ur5e, seedMap, fs, nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
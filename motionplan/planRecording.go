@@ -0,0 +1,396 @@
+package motionplan
+
+import (
+	// embed is needed for the viewer template.
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// DebugRecordingKey is the planning option which, when set to the name of a directory within DebugRecordingDir, records
+// what the planner did while solving and writes it to that directory as ".json" and ".html" files with a generated name
+// once planning finishes, whether or not it succeeded. Since it writes to the robot's disk it is meant to be enabled by a
+// robot's own config, and the motion service does not accept it from requests.
+const DebugRecordingKey = "debug_recording"
+
+// DebugRecordingDir is the directory in the robot's data directory under which plan recordings are written.
+var DebugRecordingDir = filepath.Join(os.Getenv("HOME"), ".viam", "debug", "motionplan")
+
+const (
+	// Collisions beyond this many are counted but not recorded, to bound the size of a recording.
+	maxRecordedCollisions = 1000
+	// Number of segments used to draw circles of round geometries.
+	wireframeCircleSegments = 24
+)
+
+//go:embed planRecordingViewer.html
+var planRecordingViewer string
+
+var planRecordingTemplate = template.Must(template.New("viewer").Parse(planRecordingViewer))
+
+// RecordedState is a configuration of the planned frame, with the position it places the frame at in the world.
+type RecordedState struct {
+	Configuration []float64 `json:"configuration"`
+	Position      r3.Vector `json:"position"`
+}
+
+// RecordedEdge connects a node of a planner's tree to its parent.
+type RecordedEdge struct {
+	From RecordedState `json:"from"`
+	To   RecordedState `json:"to"`
+}
+
+// RecordedTree is a tree explored by an RRT-based planner, grown from either the start or the goal configurations.
+type RecordedTree struct {
+	Root  string         `json:"root"`
+	Edges []RecordedEdge `json:"edges"`
+}
+
+// RecordedCollision is a configuration which a collision constraint rejected.
+type RecordedCollision struct {
+	Constraint string        `json:"constraint"`
+	State      RecordedState `json:"state"`
+}
+
+// RecordedPath is a sequence of configurations produced by some stage of planning.
+type RecordedPath struct {
+	Name   string          `json:"name"`
+	States []RecordedState `json:"states"`
+}
+
+// RecordedGeometry is a geometry in the world, drawn as a set of line segments.
+type RecordedGeometry struct {
+	Label string         `json:"label"`
+	Type  string         `json:"type"`
+	Lines [][2]r3.Vector `json:"lines"`
+}
+
+// PlanRecording holds what the planner explored while solving a motion, so that it can be inspected after planning. All
+// positions are in the world frame.
+type PlanRecording struct {
+	Frame             string              `json:"frame"`
+	Start             RecordedState       `json:"start"`
+	GoalPosition      r3.Vector           `json:"goal_position"`
+	Obstacles         []RecordedGeometry  `json:"obstacles"`
+	StartGeometries   []RecordedGeometry  `json:"start_geometries"`
+	FinalGeometries   []RecordedGeometry  `json:"final_geometries"`
+	IKSolutions       []RecordedState     `json:"ik_solutions"`
+	Trees             []RecordedTree      `json:"trees"`
+	Collisions        []RecordedCollision `json:"collisions"`
+	CollisionsDropped int                 `json:"collisions_dropped"`
+	Paths             []RecordedPath      `json:"paths"`
+	Error             string              `json:"error,omitempty"`
+
+	mu    sync.Mutex
+	frame *solverFrame
+}
+
+// newPlanRecording creates a recording of planning for the given frame from the seed, in a world with the given obstacles.
+func newPlanRecording(
+	sf *solverFrame,
+	seed []referenceframe.Input,
+	goal *referenceframe.PoseInFrame,
+	seedMap map[string][]referenceframe.Input,
+	worldState *referenceframe.WorldState,
+) (*PlanRecording, error) {
+	r := &PlanRecording{Frame: sf.Name(), frame: sf}
+	r.Start = r.state(seed)
+	goalInWorld, err := sf.fss.Transform(seedMap, goal, referenceframe.World)
+	if err != nil {
+		return nil, err
+	}
+	r.GoalPosition = goalInWorld.(*referenceframe.PoseInFrame).Pose().Point()
+	obstacles, err := worldState.ObstaclesInWorldFrame(sf.fss, seedMap)
+	if err != nil {
+		return nil, err
+	}
+	r.Obstacles = recordGeometries(obstacles.Geometries())
+	r.StartGeometries = r.geometries(seed)
+	return r, nil
+}
+
+// state returns the recorded form of a configuration.
+func (r *PlanRecording) state(q []referenceframe.Input) RecordedState {
+	state := RecordedState{Configuration: referenceframe.InputsToFloats(q)}
+	tf, err := r.frame.fss.Transform(
+		r.frame.sliceToMap(q),
+		referenceframe.NewPoseInFrame(r.frame.solveFrame.Name(), spatialmath.NewZeroPose()),
+		referenceframe.World,
+	)
+	if err == nil {
+		state.Position = tf.(*referenceframe.PoseInFrame).Pose().Point()
+	}
+	return state
+}
+
+func (r *PlanRecording) geometries(q []referenceframe.Input) []RecordedGeometry {
+	// geometries which could not be placed are left out rather than failing the recording
+	geometries, _ := r.frame.Geometries(q)
+	if geometries == nil {
+		return nil
+	}
+	return recordGeometries(geometries.Geometries())
+}
+
+// recordIKSolutions records the IK solutions which met all constraints.
+func (r *PlanRecording) recordIKSolutions(solutions []node) {
+	if r == nil {
+		return
+	}
+	states := make([]RecordedState, 0, len(solutions))
+	for _, solution := range solutions {
+		states = append(states, r.state(solution.Q()))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.IKSolutions = append(r.IKSolutions, states...)
+}
+
+// recordTrees records the trees of an RRT-based planner. The maps must not be modified while they are recorded.
+func (r *PlanRecording) recordTrees(maps *rrtMaps) {
+	if r == nil || maps == nil {
+		return
+	}
+	trees := []RecordedTree{r.tree("start", maps.startMap), r.tree("goal", maps.goalMap)}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Trees = append(r.Trees, trees...)
+}
+
+func (r *PlanRecording) tree(root string, rrtMap rrtMap) RecordedTree {
+	tree := RecordedTree{Root: root, Edges: []RecordedEdge{}}
+	for child, parent := range rrtMap {
+		if parent == nil {
+			continue
+		}
+		tree.Edges = append(tree.Edges, RecordedEdge{From: r.state(parent.Q()), To: r.state(child.Q())})
+	}
+	return tree
+}
+
+// recordPath records a path produced by some stage of planning.
+func (r *PlanRecording) recordPath(name string, steps [][]referenceframe.Input) {
+	if r == nil {
+		return
+	}
+	path := RecordedPath{Name: name, States: make([]RecordedState, 0, len(steps))}
+	for _, step := range steps {
+		path.States = append(path.States, r.state(step))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Paths = append(r.Paths, path)
+}
+
+// watchConstraint wraps a collision constraint so that the configurations it rejects are recorded.
+func (r *PlanRecording) watchConstraint(name string, constraint StateConstraint) StateConstraint {
+	if r == nil {
+		return constraint
+	}
+	return func(state *State) bool {
+		if constraint(state) {
+			return true
+		}
+		r.mu.Lock()
+		full := len(r.Collisions) >= maxRecordedCollisions
+		r.mu.Unlock()
+		var collision RecordedCollision
+		if !full {
+			collision = RecordedCollision{Constraint: name, State: r.state(state.Configuration)}
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if len(r.Collisions) >= maxRecordedCollisions {
+			r.CollisionsDropped++
+		} else {
+			r.Collisions = append(r.Collisions, collision)
+		}
+		return false
+	}
+}
+
+// finish records the outcome of planning.
+func (r *PlanRecording) finish(steps [][]referenceframe.Input, planErr error) {
+	if planErr != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.Error = planErr.Error()
+		return
+	}
+	r.recordPath("final", steps)
+	if len(steps) > 0 {
+		geometries := r.geometries(steps[len(steps)-1])
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.FinalGeometries = geometries
+	}
+}
+
+// WriteJSON writes the recording as JSON.
+func (r *PlanRecording) WriteJSON(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return json.NewEncoder(w).Encode(r)
+}
+
+// WriteHTML writes a self-contained HTML page which draws the recording, and can be opened offline in a browser.
+func (r *PlanRecording) WriteHTML(w io.Writer) error {
+	r.mu.Lock()
+	data, err := json.Marshal(r)
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	//nolint:gosec
+	return planRecordingTemplate.Execute(w, template.JS(data))
+}
+
+// DebugRecordingPath returns the directory within DebugRecordingDir with the given name, which is an error if the name is
+// an absolute path or leads out of DebugRecordingDir.
+func DebugRecordingPath(name string) (string, error) {
+	cleaned := filepath.Clean(name)
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("%s %q must be a relative path within %s", DebugRecordingKey, name, DebugRecordingDir)
+	}
+	return filepath.Join(DebugRecordingDir, cleaned), nil
+}
+
+// WriteFiles writes the recording to the given directory, creating it if needed, as ".json" and ".html" files named for
+// the time they are written. It returns the path of the files without their extensions.
+func (r *PlanRecording) WriteFiles(dir string) (path string, err error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+	path = filepath.Join(dir, "plan-"+time.Now().UTC().Format("20060102T150405.000000000"))
+	for ext, write := range map[string]func(io.Writer) error{".json": r.WriteJSON, ".html": r.WriteHTML} {
+		//nolint:gosec
+		f, createErr := os.OpenFile(path+ext, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if createErr != nil {
+			return "", createErr
+		}
+		multierr.AppendInto(&err, write(f))
+		multierr.AppendInto(&err, f.Close())
+	}
+	return path, err
+}
+
+// recordGeometries converts geometries into line segments which outline them.
+func recordGeometries(geometries []spatialmath.Geometry) []RecordedGeometry {
+	recorded := make([]RecordedGeometry, 0, len(geometries))
+	for _, g := range geometries {
+		recorded = append(recorded, geometryWireframe(g))
+	}
+	return recorded
+}
+
+func geometryWireframe(g spatialmath.Geometry) RecordedGeometry {
+	recorded := RecordedGeometry{Label: g.Label(), Type: string(spatialmath.MeshType)}
+	pose := g.Pose()
+	transform := func(pt r3.Vector) r3.Vector {
+		return spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(pt)).Point()
+	}
+	addLine := func(a, b r3.Vector) {
+		recorded.Lines = append(recorded.Lines, [2]r3.Vector{transform(a), transform(b)})
+	}
+	// circle draws a circle about the z axis, offset to the given point and optionally rotated onto the x or y axis
+	circle := func(center r3.Vector, radius float64, axis int) {
+		onCircle := func(i int) r3.Vector {
+			theta := 2 * math.Pi * float64(i) / wireframeCircleSegments
+			a, b := radius*math.Cos(theta), radius*math.Sin(theta)
+			switch axis {
+			case 0:
+				return center.Add(r3.Vector{Y: a, Z: b})
+			case 1:
+				return center.Add(r3.Vector{X: a, Z: b})
+			default:
+				return center.Add(r3.Vector{X: a, Y: b})
+			}
+		}
+		for i := 0; i < wireframeCircleSegments; i++ {
+			addLine(onCircle(i), onCircle(i+1))
+		}
+	}
+	// tube draws the circular ends and four sides of a tube along the z axis
+	tube := func(radius, halfLength float64) {
+		circle(r3.Vector{Z: -halfLength}, radius, 2)
+		circle(r3.Vector{Z: halfLength}, radius, 2)
+		for _, side := range []r3.Vector{{X: radius}, {X: -radius}, {Y: radius}, {Y: -radius}} {
+			addLine(side.Add(r3.Vector{Z: -halfLength}), side.Add(r3.Vector{Z: halfLength}))
+		}
+	}
+
+	cfg, err := spatialmath.NewGeometryConfig(g)
+	if err != nil {
+		// meshes which were not loaded from files have no config, but their points begin with the vertices of each triangle,
+		// and a low enough resolution adds no other points
+		cfg = &spatialmath.GeometryConfig{Type: spatialmath.MeshType}
+	}
+	recorded.Type = string(cfg.Type)
+	switch cfg.Type {
+	case spatialmath.BoxType:
+		half := r3.Vector{X: cfg.X / 2, Y: cfg.Y / 2, Z: cfg.Z / 2}
+		corner := func(i int) r3.Vector {
+			c := half
+			if i&1 != 0 {
+				c.X = -c.X
+			}
+			if i&2 != 0 {
+				c.Y = -c.Y
+			}
+			if i&4 != 0 {
+				c.Z = -c.Z
+			}
+			return c
+		}
+		// corners which differ in exactly one coordinate share an edge
+		for i := 0; i < 8; i++ {
+			for _, bit := range []int{1, 2, 4} {
+				if i&bit == 0 {
+					addLine(corner(i), corner(i|bit))
+				}
+			}
+		}
+	case spatialmath.SphereType:
+		for axis := 0; axis < 3; axis++ {
+			circle(r3.Vector{}, cfg.R, axis)
+		}
+	case spatialmath.CapsuleType:
+		tube(cfg.R, cfg.L/2-cfg.R)
+		circle(r3.Vector{Z: -cfg.L/2 + cfg.R}, cfg.R, 0)
+		circle(r3.Vector{Z: cfg.L/2 - cfg.R}, cfg.R, 0)
+		circle(r3.Vector{Z: -cfg.L/2 + cfg.R}, cfg.R, 1)
+		circle(r3.Vector{Z: cfg.L/2 - cfg.R}, cfg.R, 1)
+	case spatialmath.CylinderType:
+		tube(cfg.R, cfg.L/2)
+	case spatialmath.MeshType:
+		// mesh points are already in the world, so are recorded without the pose
+		points := g.ToPoints(1e-9)
+		for i := 0; i+2 < len(points); i += 3 {
+			recorded.Lines = append(recorded.Lines,
+				[2]r3.Vector{points[i], points[i+1]},
+				[2]r3.Vector{points[i+1], points[i+2]},
+				[2]r3.Vector{points[i+2], points[i]},
+			)
+		}
+	default:
+		const size = 5.
+		addLine(r3.Vector{X: -size}, r3.Vector{X: size})
+		addLine(r3.Vector{Y: -size}, r3.Vector{Y: size})
+		addLine(r3.Vector{Z: -size}, r3.Vector{Z: size})
+	}
+	return recorded
+}
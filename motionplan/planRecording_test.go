@@ -0,0 +1,191 @@
+package motionplan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// useDebugRecordingDir makes plan recordings be written within a new temporary directory for the rest of the test.
+func useDebugRecordingDir(t *testing.T) string {
+	t.Helper()
+	dir := DebugRecordingDir
+	t.Cleanup(func() { DebugRecordingDir = dir })
+	DebugRecordingDir = t.TempDir()
+	return DebugRecordingDir
+}
+
+// readPlanRecording reads the only recording written to dir, removing it.
+func readPlanRecording(t *testing.T, dir string) *PlanRecording {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "plan-*.json"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(matches), test.ShouldEqual, 1)
+	path := strings.TrimSuffix(matches[0], ".json")
+	defer func() {
+		test.That(t, os.Remove(path+".json"), test.ShouldBeNil)
+		test.That(t, os.Remove(path+".html"), test.ShouldBeNil)
+	}()
+
+	//nolint:gosec
+	data, err := os.ReadFile(path + ".json")
+	test.That(t, err, test.ShouldBeNil)
+	recording := &PlanRecording{}
+	test.That(t, json.Unmarshal(data, recording), test.ShouldBeNil)
+
+	//nolint:gosec
+	html, err := os.ReadFile(path + ".html")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bytes.Contains(html, []byte("const recording = {\"frame\":")), test.ShouldBeTrue)
+	return recording
+}
+
+func TestPlanRecording(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("")
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "ur")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 300, Y: 300, Z: 300}), r3.Vector{100, 100, 100}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatialmath.Geometry{obstacle})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	dir := filepath.Join(useDebugRecordingDir(t), "ur")
+
+	goal := spatialmath.NewPose(r3.Vector{X: -400, Y: -200, Z: 300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+	plan, err := PlanMotion(
+		context.Background(),
+		logger.Sugar(),
+		frame.NewPoseInFrame(frame.World, goal),
+		ur5e,
+		frame.StartPositions(fs),
+		fs,
+		worldState,
+		nil,
+		map[string]interface{}{DebugRecordingKey: "ur"},
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	recording := readPlanRecording(t, dir)
+	test.That(t, recording.Error, test.ShouldBeEmpty)
+	test.That(t, len(recording.Obstacles), test.ShouldEqual, 1)
+	test.That(t, len(recording.Obstacles[0].Lines), test.ShouldEqual, 12)
+	test.That(t, len(recording.StartGeometries), test.ShouldBeGreaterThan, 0)
+	test.That(t, len(recording.FinalGeometries), test.ShouldBeGreaterThan, 0)
+	test.That(t, len(recording.IKSolutions), test.ShouldBeGreaterThan, 0)
+	final := recording.Paths[len(recording.Paths)-1]
+	test.That(t, final.Name, test.ShouldEqual, "final")
+	test.That(t, len(final.States), test.ShouldEqual, len(plan))
+	test.That(t, spatialmath.R3VectorAlmostEqual(final.States[len(final.States)-1].Position, goal.Point(), 1e-2), test.ShouldBeTrue)
+
+	// failed plans are recorded along with the collisions that prevented them
+	blocked, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(goal.Point()), r3.Vector{100, 100, 100}, "blocked")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err = frame.NewWorldState(
+		[]*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, []spatialmath.Geometry{blocked})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = PlanMotion(
+		context.Background(),
+		logger.Sugar(),
+		frame.NewPoseInFrame(frame.World, goal),
+		ur5e,
+		frame.StartPositions(fs),
+		fs,
+		worldState,
+		nil,
+		map[string]interface{}{DebugRecordingKey: "ur"},
+	)
+	test.That(t, err, test.ShouldNotBeNil)
+	recording = readPlanRecording(t, dir)
+	test.That(t, recording.Error, test.ShouldEqual, err.Error())
+	test.That(t, len(recording.Collisions), test.ShouldBeGreaterThan, 0)
+	test.That(t, len(recording.IKSolutions), test.ShouldEqual, 0)
+}
+
+func TestDebugRecordingPath(t *testing.T) {
+	dir := useDebugRecordingDir(t)
+	for _, name := range []string{"ur", "arm/ur", "arm/../ur", "."} {
+		path, err := DebugRecordingPath(name)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, path, test.ShouldEqual, filepath.Join(dir, filepath.Clean(name)))
+	}
+	for _, name := range []string{"..", "../ur", "arm/../../ur", filepath.Join(t.TempDir(), "ur")} {
+		_, err := DebugRecordingPath(name)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	// planning does not start if the recording would be written outside of the debug directory
+	fs := frame.NewEmptyFrameSystem("")
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "ur")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+	outside := t.TempDir()
+	goal := spatialmath.NewPose(r3.Vector{X: -400, Y: -200, Z: 300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+	for _, name := range []string{"../ur", outside} {
+		_, err = PlanMotion(
+			context.Background(),
+			logger.Sugar(),
+			frame.NewPoseInFrame(frame.World, goal),
+			ur5e,
+			frame.StartPositions(fs),
+			fs,
+			nil,
+			nil,
+			map[string]interface{}{DebugRecordingKey: name},
+		)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "must be a relative path")
+	}
+	// the test's temporary directories share a parent
+	for _, d := range []string{filepath.Dir(dir), outside} {
+		matches, err := filepath.Glob(filepath.Join(d, "*", "plan-*"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, matches, test.ShouldBeEmpty)
+	}
+}
+
+func TestRecordTrees(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("")
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "ur")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(ur5e, fs.World()), test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, ur5e.Name(), frame.World, frame.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+	recording := &PlanRecording{frame: sf}
+
+	root := &basicNode{q: home6}
+	child := &basicNode{q: frame.FloatsToInputs([]float64{1, 0, 0, 0, 0, 0})}
+	goal := &basicNode{q: frame.FloatsToInputs([]float64{1, 1, 0, 0, 0, 0})}
+	recording.recordTrees(&rrtMaps{startMap: rrtMap{root: nil, child: root}, goalMap: rrtMap{goal: nil}})
+	test.That(t, len(recording.Trees), test.ShouldEqual, 2)
+	test.That(t, recording.Trees[0].Root, test.ShouldEqual, "start")
+	test.That(t, len(recording.Trees[0].Edges), test.ShouldEqual, 1)
+	edge := recording.Trees[0].Edges[0]
+	test.That(t, edge.From.Configuration, test.ShouldResemble, frame.InputsToFloats(home6))
+	homePose, err := ur5e.Transform(home6)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.R3VectorAlmostEqual(edge.From.Position, homePose.Point(), 1e-6), test.ShouldBeTrue)
+	test.That(t, len(recording.Trees[1].Edges), test.ShouldEqual, 0)
+
+	// nothing is recorded when recording is disabled
+	var disabled *PlanRecording
+	disabled.recordTrees(&rrtMaps{startMap: rrtMap{root: nil, child: root}})
+	disabled.recordPath("final", [][]frame.Input{home6})
+	constraint := func(*State) bool { return false }
+	test.That(t, disabled.watchConstraint("collision", constraint)(&State{}), test.ShouldBeFalse)
+}
@@ -49,6 +49,7 @@ const (
 	defaultObstacleConstraintDesc       = "Collision between the robot and an obstacle"
 	defaultSelfCollisionConstraintDesc  = "Collision between two robot components that are moving"
 	defaultRobotCollisionConstraintDesc = "Collision between a robot component that is moving and one that is stationary"
+	defaultObservedConstraintDesc       = "Collision between the robot and an observed obstacle"
 
 	// When breaking down a path into smaller waypoints, add a waypoint every this many mm of movement.
 	defaultPathStepSize = 10
@@ -100,7 +101,9 @@ type plannerOptions struct {
 	ConstraintHandler
 	goalMetric   StateMetric // Distance function which converges to the final goal position
 	goalArcScore SegmentMetric
-	pathMetric   StateMetric // Distance function which converges on the valid manifold of intermediate path states
+	pathMetric   StateMetric      // Distance function which converges on the valid manifold of intermediate path states
+	goal         spatialmath.Pose // The pose being planned to, used by solvers which compute it directly
+	recording    *PlanRecording   // Records what the planner explored, if debug recording is enabled
 
 	extra map[string]interface{}
 
@@ -129,6 +132,10 @@ type plannerOptions struct {
 	// How close to get to the goal
 	GoalThreshold float64 `json:"goal_threshold"`
 
+	// Which IK solver to use to generate goal configurations. If left empty the solver named by the config of the model
+	// being planned for is used, if any, or else the default numerical solvers.
+	IKSolver string `json:"ik_solver"`
+
 	// DistanceFunc is the function that the planner will use to measure the degree of "closeness" between two states of the robot
 	DistanceFunc SegmentMetric
 
@@ -238,6 +238,21 @@ func (sf *solverFrame) Geometries(inputs []frame.Input) (*frame.GeometriesInFram
 	return frame.NewGeometriesInFrame(frame.World, sfGeometries), errAll
 }
 
+// modelIKSolver returns the IK solver named by the model of the only frame between the two solver frames with inputs, if
+// there is exactly one.
+func (sf *solverFrame) modelIKSolver() string {
+	var moving []frame.Frame
+	for _, f := range sf.frames {
+		if len(f.DoF()) > 0 {
+			moving = append(moving, f)
+		}
+	}
+	if len(moving) != 1 {
+		return ""
+	}
+	return frame.ModelIKSolver(moving[0])
+}
+
 // DoF returns the summed DoF of all frames between the two solver frames.
 func (sf *solverFrame) DoF() []frame.Limit {
 	var limits []frame.Limit
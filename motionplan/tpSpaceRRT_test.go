@@ -72,7 +72,7 @@ func TestPtgWithObstacle(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	sf, err := newSolverFrame(fs, "ackframe", referenceframe.World, nil)
 	test.That(t, err, test.ShouldBeNil)
-	collisionConstraints, err := createAllCollisionConstraints(sf, fs, worldState, referenceframe.StartPositions(fs), nil)
+	collisionConstraints, err := createAllCollisionConstraints(sf, fs, worldState, referenceframe.StartPositions(fs), nil, false)
 	test.That(t, err, test.ShouldBeNil)
 
 	for name, constraint := range collisionConstraints {
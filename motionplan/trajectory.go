@@ -0,0 +1,500 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+// TrajectoryProfile is the shape of the velocity changes along a trajectory.
+type TrajectoryProfile string
+
+const (
+	// TrapezoidalProfile changes velocities at constant acceleration. Velocities are continuous, but accelerations
+	// change instantaneously, so jerk limits are not respected.
+	TrapezoidalProfile TrajectoryProfile = "trapezoidal"
+	// SCurveProfile changes velocities with continuously varying acceleration, respecting jerk limits.
+	SCurveProfile TrajectoryProfile = "s_curve"
+)
+
+// TrajectoryOptions configures how a plan is time parameterized.
+type TrajectoryOptions struct {
+	// Profile is the shape of velocity changes. Defaults to TrapezoidalProfile.
+	Profile TrajectoryProfile
+	// DefaultLimit supplies the velocity, acceleration and jerk limits of inputs whose frames have no known dynamic
+	// limits, as returned by referenceframe.DynamicLimits.
+	DefaultLimit frame.DynamicLimit
+	// VelocityScale and AccelerationScale are the fractions of the velocity and of the acceleration and jerk limits that
+	// the trajectory may use, in (0, 1]. Zero means 1.
+	VelocityScale     float64
+	AccelerationScale float64
+	// MaxBlendDeviation is how far, in the units of each input, the trajectory may stray from the straight lines between
+	// waypoints while changing velocity around them. Plans are checked along those lines, so segments are slowed down
+	// until every blend stays this close to them. Zero means defaultMaxBlendDeviation.
+	MaxBlendDeviation float64
+}
+
+// defaultMaxBlendDeviation keeps blends within a millimeter of the checked path at a meter from a revolute joint.
+const defaultMaxBlendDeviation = 1e-3
+
+// TrajectoryPoint is the state of a frame's inputs at a time along a trajectory.
+type TrajectoryPoint struct {
+	Time          time.Duration
+	Inputs        []frame.Input
+	Velocities    []float64
+	Accelerations []float64
+}
+
+// Trajectory is a motion plan parameterized by time. Each frame's inputs move along straight lines between the plan's
+// waypoints at velocities within their limits, with all frames arriving at each waypoint together. Velocities change
+// over blends centered on each waypoint, so the trajectory passes within TrajectoryOptions.MaxBlendDeviation of the
+// intermediate waypoints rather than through them, and starts and ends at rest exactly on the first and last waypoints.
+type Trajectory struct {
+	frames  []string
+	dofs    []int
+	profile TrajectoryProfile
+
+	// waypoints are the flattened inputs of all frames, and times are when the straight-line path between them passes
+	// each waypoint, in seconds.
+	waypoints [][]float64
+	times     []float64
+	// blends are the durations of the velocity changes centered on each waypoint, and velocities are the velocities
+	// along each segment between waypoints.
+	blends     []float64
+	velocities [][]float64
+	duration   float64
+}
+
+// NewTrajectory time parameterizes plan, as returned by PlanMotion, using the dynamic limits of the frames in fs.
+func NewTrajectory(plan []map[string][]frame.Input, fs frame.FrameSystem, opts *TrajectoryOptions) (*Trajectory, error) {
+	if len(plan) == 0 {
+		return nil, errors.New("cannot create a trajectory from an empty plan")
+	}
+	var frames []string
+	for name, inputs := range plan[0] {
+		if len(inputs) > 0 {
+			frames = append(frames, name)
+		}
+	}
+	sort.Strings(frames)
+
+	var limits []frame.DynamicLimit
+	dofs := make([]int, 0, len(frames))
+	for _, name := range frames {
+		f := fs.Frame(name)
+		if f == nil {
+			return nil, frame.NewFrameMissingError(name)
+		}
+		limits = append(limits, frame.DynamicLimits(f)...)
+		dofs = append(dofs, len(f.DoF()))
+	}
+
+	waypoints := make([][]float64, 0, len(plan))
+	for idx, step := range plan {
+		waypoint := make([]float64, 0, len(limits))
+		for i, name := range frames {
+			inputs := step[name]
+			if len(inputs) != dofs[i] {
+				return nil, errors.Errorf("step %d of plan has %d inputs for frame %q, expected %d", idx, len(inputs), name, dofs[i])
+			}
+			for _, input := range inputs {
+				waypoint = append(waypoint, input.Value)
+			}
+		}
+		waypoints = append(waypoints, waypoint)
+	}
+	return newTrajectory(frames, dofs, waypoints, limits, opts)
+}
+
+// NewFrameTrajectory time parameterizes steps of f's inputs, as returned by PlanFrameMotion, using f's dynamic limits.
+func NewFrameTrajectory(f frame.Frame, steps [][]frame.Input, opts *TrajectoryOptions) (*Trajectory, error) {
+	if len(steps) == 0 {
+		return nil, errors.New("cannot create a trajectory from an empty plan")
+	}
+	waypoints := make([][]float64, 0, len(steps))
+	for idx, step := range steps {
+		if len(step) != len(f.DoF()) {
+			return nil, errors.Errorf("step %d of plan has %d inputs, expected %d", idx, len(step), len(f.DoF()))
+		}
+		waypoints = append(waypoints, frame.InputsToFloats(step))
+	}
+	return newTrajectory([]string{f.Name()}, []int{len(f.DoF())}, waypoints, frame.DynamicLimits(f), opts)
+}
+
+// maxTimingIterations bounds how many times segments are slowed down to make room for the blends between them.
+const maxTimingIterations = 1000
+
+func newTrajectory(
+	frames []string,
+	dofs []int,
+	waypoints [][]float64,
+	limits []frame.DynamicLimit,
+	opts *TrajectoryOptions,
+) (*Trajectory, error) {
+	if opts == nil {
+		opts = &TrajectoryOptions{}
+	}
+	profile := opts.Profile
+	switch profile {
+	case "":
+		profile = TrapezoidalProfile
+	case TrapezoidalProfile, SCurveProfile:
+	default:
+		return nil, errors.Errorf("unsupported trajectory profile %q", profile)
+	}
+	maxVel, maxAcc, maxJerk, err := rateLimits(limits, opts, profile)
+	if err != nil {
+		return nil, err
+	}
+	maxDeviation := opts.MaxBlendDeviation
+	switch {
+	case maxDeviation == 0:
+		maxDeviation = defaultMaxBlendDeviation
+	case maxDeviation < 0:
+		return nil, errors.New("trajectory max blend deviation must not be negative")
+	}
+	// Each input strays furthest from the straight lines halfway through a blend, by this fraction of its velocity
+	// change times the blend's duration.
+	_, _, midBlendDeviation := blendShape(profile, 0.5)
+
+	// Drop repeated waypoints, as they would make zero length segments.
+	deduped := waypoints[:1]
+	for _, waypoint := range waypoints[1:] {
+		if !floatsAlmostEqual(waypoint, deduped[len(deduped)-1]) {
+			deduped = append(deduped, waypoint)
+		}
+	}
+	waypoints = deduped
+
+	traj := &Trajectory{
+		frames:     frames,
+		dofs:       dofs,
+		profile:    profile,
+		waypoints:  waypoints,
+		times:      make([]float64, len(waypoints)),
+		blends:     make([]float64, len(waypoints)),
+		velocities: make([][]float64, len(waypoints)-1),
+	}
+	if len(waypoints) == 1 {
+		return traj, nil
+	}
+
+	// Each segment initially takes as long as its slowest input needs at its maximum velocity.
+	durations := make([]float64, len(waypoints)-1)
+	for k := range durations {
+		for j := range limits {
+			durations[k] = math.Max(durations[k], math.Abs(waypoints[k+1][j]-waypoints[k][j])/maxVel[j])
+		}
+	}
+
+	// Slow down segments until the blends on either end of each fit within it, and the blends stay close enough to the
+	// straight lines between waypoints.
+	for iter := 0; ; iter++ {
+		if iter == maxTimingIterations {
+			return nil, errors.New("could not fit velocity changes between waypoints within the acceleration limits")
+		}
+		for k := range durations {
+			traj.velocities[k] = make([]float64, len(limits))
+			for j := range limits {
+				traj.velocities[k][j] = (waypoints[k+1][j] - waypoints[k][j]) / durations[k]
+			}
+		}
+		for i := range waypoints {
+			traj.blends[i] = 0
+			prev, next := traj.segmentVelocity(i-1), traj.segmentVelocity(i)
+			for j := range limits {
+				traj.blends[i] = math.Max(traj.blends[i], blendDuration(profile, math.Abs(next[j]-prev[j]), maxAcc[j], maxJerk[j]))
+			}
+		}
+		scales := make([]float64, len(durations))
+		for k := range durations {
+			scales[k] = 1
+			if need := (traj.blends[k] + traj.blends[k+1]) / 2; need > durations[k]*(1+1e-9) {
+				scales[k] = math.Max(1.01, math.Sqrt(need/durations[k]))
+			}
+		}
+		// The velocity change, and with it the deviation, shrinks as the segments on either side of a waypoint slow down.
+		for i := 1; i < len(waypoints)-1; i++ {
+			for j := range limits {
+				dv := math.Abs(traj.velocities[i][j] - traj.velocities[i-1][j])
+				if deviation := dv * traj.blends[i] * midBlendDeviation; deviation > maxDeviation*(1+1e-9) {
+					scale := math.Max(1.01, math.Sqrt(deviation/maxDeviation))
+					scales[i-1] = math.Max(scales[i-1], scale)
+					scales[i] = math.Max(scales[i], scale)
+				}
+			}
+		}
+		fits := true
+		for k, scale := range scales {
+			if scale > 1 {
+				fits = false
+				durations[k] *= scale
+			}
+		}
+		if fits {
+			break
+		}
+	}
+
+	traj.times[0] = traj.blends[0] / 2
+	for k, dur := range durations {
+		traj.times[k+1] = traj.times[k] + dur
+	}
+	traj.duration = traj.times[len(traj.times)-1] + traj.blends[len(traj.blends)-1]/2
+	return traj, nil
+}
+
+// rateLimits returns the velocity, acceleration and jerk limits of each input, scaled by opts.
+func rateLimits(limits []frame.DynamicLimit, opts *TrajectoryOptions, profile TrajectoryProfile) ([]float64, []float64, []float64, error) {
+	velScale, accScale := opts.VelocityScale, opts.AccelerationScale
+	if velScale == 0 {
+		velScale = 1
+	}
+	if accScale == 0 {
+		accScale = 1
+	}
+	if velScale < 0 || velScale > 1 || accScale < 0 || accScale > 1 {
+		return nil, nil, nil, errors.New("trajectory velocity and acceleration scales must be in (0, 1]")
+	}
+	maxVel := make([]float64, len(limits))
+	maxAcc := make([]float64, len(limits))
+	maxJerk := make([]float64, len(limits))
+	for j, limit := range limits {
+		maxVel[j], maxAcc[j], maxJerk[j] = limit.MaxVelocity, limit.MaxAcceleration, limit.MaxJerk
+		if maxVel[j] == 0 {
+			maxVel[j] = opts.DefaultLimit.MaxVelocity
+		}
+		if maxAcc[j] == 0 {
+			maxAcc[j] = opts.DefaultLimit.MaxAcceleration
+		}
+		if maxJerk[j] == 0 {
+			maxJerk[j] = opts.DefaultLimit.MaxJerk
+		}
+		if maxVel[j] <= 0 || maxAcc[j] <= 0 {
+			return nil, nil, nil, errors.Errorf("input %d has no velocity or acceleration limit", j)
+		}
+		if profile == SCurveProfile && maxJerk[j] <= 0 {
+			return nil, nil, nil, errors.Errorf("input %d has no jerk limit, which an s-curve profile requires", j)
+		}
+		maxVel[j] *= velScale
+		maxAcc[j] *= accScale
+		maxJerk[j] *= accScale
+	}
+	return maxVel, maxAcc, maxJerk, nil
+}
+
+// blendDuration returns how long an input takes to change velocity by dv under profile.
+func blendDuration(profile TrajectoryProfile, dv, maxAcc, maxJerk float64) float64 {
+	if profile == SCurveProfile {
+		// The acceleration of an s-curve blend peaks at 1.5dv/t and its jerk at 6dv/t^2.
+		return math.Max(1.5*dv/maxAcc, math.Sqrt(6*dv/maxJerk))
+	}
+	return dv / maxAcc
+}
+
+// blendShape returns the fraction of a blend's velocity change made by fraction u of the way through it, that
+// fraction's derivative, and its integral.
+func blendShape(profile TrajectoryProfile, u float64) (float64, float64, float64) {
+	if profile == SCurveProfile {
+		return 3*u*u - 2*u*u*u, 6*u - 6*u*u, u*u*u - u*u*u*u/2
+	}
+	return u, 1, u * u / 2
+}
+
+// segmentVelocity returns the velocity along segment k, which is zero before the first and after the last waypoint.
+func (t *Trajectory) segmentVelocity(k int) []float64 {
+	if k < 0 || k >= len(t.velocities) {
+		return make([]float64, len(t.waypoints[0]))
+	}
+	return t.velocities[k]
+}
+
+// Duration returns how long the trajectory takes.
+func (t *Trajectory) Duration() time.Duration {
+	return time.Duration(t.duration * float64(time.Second))
+}
+
+// Frames returns the names of the frames the trajectory moves.
+func (t *Trajectory) Frames() []string {
+	return t.frames
+}
+
+// At returns the state of each frame's inputs at time at along the trajectory, which is clamped to the trajectory's
+// duration.
+func (t *Trajectory) At(at time.Duration) map[string]TrajectoryPoint {
+	pos, vel, acc := t.evaluate(math.Min(math.Max(at.Seconds(), 0), t.duration))
+	points := make(map[string]TrajectoryPoint, len(t.frames))
+	offset := 0
+	for i, name := range t.frames {
+		end := offset + t.dofs[i]
+		points[name] = TrajectoryPoint{
+			Time:          at,
+			Inputs:        frame.FloatsToInputs(pos[offset:end]),
+			Velocities:    vel[offset:end],
+			Accelerations: acc[offset:end],
+		}
+		offset = end
+	}
+	return points
+}
+
+// Sample returns the state of each frame's inputs every period along the trajectory, including at its end.
+func (t *Trajectory) Sample(period time.Duration) (map[string][]TrajectoryPoint, error) {
+	if period <= 0 {
+		return nil, errors.New("trajectory sample period must be positive")
+	}
+	samples := make(map[string][]TrajectoryPoint, len(t.frames))
+	duration := t.Duration()
+	for at := time.Duration(0); ; at += period {
+		if at > duration {
+			at = duration
+		}
+		for name, point := range t.At(at) {
+			samples[name] = append(samples[name], point)
+		}
+		if at == duration {
+			return samples, nil
+		}
+	}
+}
+
+// evaluate returns the position, velocity and acceleration of every input at time at, in seconds.
+func (t *Trajectory) evaluate(at float64) ([]float64, []float64, []float64) {
+	pos := make([]float64, len(t.waypoints[0]))
+	vel := make([]float64, len(pos))
+	acc := make([]float64, len(pos))
+	if len(t.waypoints) == 1 {
+		copy(pos, t.waypoints[0])
+		return pos, vel, acc
+	}
+
+	// k is the segment whose straight-line portion at falls in, unless it is within the blend around waypoint k or k+1.
+	k := sort.SearchFloat64s(t.times, at) - 1
+	if k < 0 {
+		k = 0
+	}
+	if k > len(t.velocities)-1 {
+		k = len(t.velocities) - 1
+	}
+	for _, i := range []int{k, k + 1} {
+		tb := t.blends[i]
+		start := t.times[i] - tb/2
+		if tb == 0 || at < start || at > start+tb {
+			continue
+		}
+		prev, next := t.segmentVelocity(i-1), t.segmentVelocity(i)
+		elapsed := at - start
+		frac, dfrac, ifrac := blendShape(t.profile, elapsed/tb)
+		for j := range pos {
+			dv := next[j] - prev[j]
+			pos[j] = t.waypoints[i][j] - prev[j]*tb/2 + prev[j]*elapsed + dv*tb*ifrac
+			vel[j] = prev[j] + dv*frac
+			acc[j] = dv / tb * dfrac
+		}
+		return pos, vel, acc
+	}
+	for j := range pos {
+		vel[j] = t.velocities[k][j]
+		pos[j] = t.waypoints[k][j] + vel[j]*(at-t.times[k])
+	}
+	return pos, vel, acc
+}
+
+func floatsAlmostEqual(a, b []float64) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+// TrajectoryFollower is implemented by components that can follow a trajectory of their inputs themselves, such as
+// arms whose controllers accept time stamped joint trajectories.
+type TrajectoryFollower interface {
+	FollowTrajectory(ctx context.Context, points []TrajectoryPoint) error
+}
+
+// ExecuteTrajectory moves each frame of traj along it in real time. Frames whose components are TrajectoryFollowers
+// are sent their sampled trajectory. The others are sent the inputs they should have at the time each call to
+// GoToInputs returns, so components whose GoToInputs blocks until they arrive only approximate the trajectory's timing.
+func ExecuteTrajectory(ctx context.Context, traj *Trajectory, components map[string]frame.InputEnabled, period time.Duration) error {
+	samples, err := traj.Sample(period)
+	if err != nil {
+		return err
+	}
+	var streamed []string
+	var followers []func() error
+	for _, name := range traj.Frames() {
+		component, ok := components[name]
+		if !ok {
+			return errors.Errorf("no component to move frame %q", name)
+		}
+		if follower, ok := component.(TrajectoryFollower); ok {
+			points := samples[name]
+			followers = append(followers, func() error { return follower.FollowTrajectory(ctx, points) })
+			continue
+		}
+		streamed = append(streamed, name)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var followErr error
+	for _, follow := range followers {
+		follow := follow
+		wg.Add(1)
+		utils.PanicCapturingGo(func() {
+			defer wg.Done()
+			if err := follow(); err != nil {
+				mu.Lock()
+				followErr = multierr.Combine(followErr, err)
+				mu.Unlock()
+			}
+		})
+	}
+
+	streamErr := streamTrajectory(ctx, traj, components, streamed, period)
+	wg.Wait()
+	return multierr.Combine(streamErr, followErr)
+}
+
+// streamTrajectory sends each of frames the inputs it should have along traj every period, or as soon as the previous
+// inputs have been reached if that takes longer, until the end of traj.
+func streamTrajectory(
+	ctx context.Context,
+	traj *Trajectory,
+	components map[string]frame.InputEnabled,
+	frames []string,
+	period time.Duration,
+) error {
+	if len(frames) == 0 {
+		return nil
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	start := time.Now()
+	for {
+		elapsed := time.Since(start)
+		points := traj.At(elapsed)
+		for _, name := range frames {
+			if err := components[name].GoToInputs(ctx, points[name].Inputs); err != nil {
+				return err
+			}
+		}
+		if elapsed >= traj.Duration() {
+			return nil
+		}
+		if !utils.SelectContextOrWaitChan(ctx, ticker.C) {
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// jointModel returns a model of a single joint whose limits are declared in its config, in mm or degrees.
+func jointModel(t *testing.T, name, jointType string, limit frame.Limit, dynamic frame.DynamicLimit) frame.Model {
+	t.Helper()
+	cfg := &frame.ModelConfig{Name: name, Joints: []frame.JointConfig{{
+		ID:     name + "_joint",
+		Type:   jointType,
+		Parent: frame.World,
+		Axis:   spatialmath.AxisConfig{X: 1},
+		Min:    limit.Min,
+		Max:    limit.Max,
+
+		MaxVelocity:     dynamic.MaxVelocity,
+		MaxAcceleration: dynamic.MaxAcceleration,
+		MaxJerk:         dynamic.MaxJerk,
+	}}}
+	m, err := cfg.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+	return m
+}
+
+// unknownFrame hides the type of the frame it wraps, so that its dynamic limits are unknown.
+type unknownFrame struct {
+	frame.Frame
+}
+
+func TestTrapezoidalTrajectory(t *testing.T) {
+	f := jointModel(t, "gantry", frame.PrismaticJoint, frame.Limit{Min: -100, Max: 100},
+		frame.DynamicLimit{MaxVelocity: 2, MaxAcceleration: 1})
+
+	t.Run("cruises at max velocity", func(t *testing.T) {
+		traj, err := NewFrameTrajectory(f, [][]frame.Input{{{0}}, {{10}}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		// 10mm at 2mm/s, plus 1s lost to each of accelerating and decelerating.
+		test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 7)
+
+		start := traj.At(0)["gantry"]
+		test.That(t, start.Inputs[0].Value, test.ShouldAlmostEqual, 0)
+		test.That(t, start.Velocities[0], test.ShouldAlmostEqual, 0)
+		test.That(t, start.Accelerations[0], test.ShouldAlmostEqual, 1)
+
+		mid := traj.At(3500 * time.Millisecond)["gantry"]
+		test.That(t, mid.Inputs[0].Value, test.ShouldAlmostEqual, 5)
+		test.That(t, mid.Velocities[0], test.ShouldAlmostEqual, 2)
+		test.That(t, mid.Accelerations[0], test.ShouldAlmostEqual, 0)
+
+		end := traj.At(time.Hour)["gantry"]
+		test.That(t, end.Inputs[0].Value, test.ShouldAlmostEqual, 10)
+		test.That(t, end.Velocities[0], test.ShouldAlmostEqual, 0)
+	})
+
+	t.Run("short moves never reach max velocity", func(t *testing.T) {
+		traj, err := NewFrameTrajectory(f, [][]frame.Input{{{0}}, {{1}}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, traj.Duration().Seconds(), test.ShouldBeGreaterThanOrEqualTo, 2)
+		checkTrajectory(t, traj, frame.DynamicLimits(f), TrapezoidalProfile)
+		test.That(t, traj.At(traj.Duration())["gantry"].Inputs[0].Value, test.ShouldAlmostEqual, 1)
+	})
+
+	t.Run("repeated waypoints are skipped", func(t *testing.T) {
+		traj, err := NewFrameTrajectory(f, [][]frame.Input{{{0}}, {{0}}, {{10}}, {{10}}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 7)
+
+		traj, err = NewFrameTrajectory(f, [][]frame.Input{{{3}}, {{3}}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, traj.Duration(), test.ShouldEqual, 0)
+		test.That(t, traj.At(time.Second)["gantry"].Inputs[0].Value, test.ShouldEqual, 3)
+	})
+}
+
+func TestMultiJointTrajectory(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("test")
+	x := jointModel(t, "x", frame.PrismaticJoint, frame.Limit{Min: -1000, Max: 1000},
+		frame.DynamicLimit{MaxVelocity: 100, MaxAcceleration: 200, MaxJerk: 1000})
+	test.That(t, fs.AddFrame(x, fs.World()), test.ShouldBeNil)
+	// theta does not declare a jerk limit, so it is given the default for revolute joints.
+	theta := jointModel(t, "theta", frame.RevoluteJoint, frame.Limit{Min: -180, Max: 180},
+		frame.DynamicLimit{MaxVelocity: utils.RadToDeg(1), MaxAcceleration: utils.RadToDeg(2)})
+	test.That(t, fs.AddFrame(theta, x), test.ShouldBeNil)
+	limits := append(frame.DynamicLimits(theta), frame.DynamicLimits(x)...)
+	test.That(t, limits[0].MaxVelocity, test.ShouldAlmostEqual, 1)
+	test.That(t, limits[0].MaxJerk, test.ShouldBeGreaterThan, 0)
+
+	plan := []map[string][]frame.Input{
+		{"x": {{0}}, "theta": {{0}}, "world": {}},
+		{"x": {{100}}, "theta": {{0.2}}, "world": {}},
+		{"x": {{150}}, "theta": {{1.5}}, "world": {}},
+		{"x": {{-50}}, "theta": {{1.4}}, "world": {}},
+	}
+
+	for _, profile := range []TrajectoryProfile{TrapezoidalProfile, SCurveProfile} {
+		t.Run(string(profile), func(t *testing.T) {
+			opts := &TrajectoryOptions{Profile: profile}
+			traj, err := NewTrajectory(plan, fs, opts)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, traj.Frames(), test.ShouldResemble, []string{"theta", "x"})
+
+			start := traj.At(0)
+			test.That(t, start["x"].Inputs[0].Value, test.ShouldAlmostEqual, 0)
+			test.That(t, start["theta"].Inputs[0].Value, test.ShouldAlmostEqual, 0)
+			end := traj.At(traj.Duration())
+			test.That(t, end["x"].Inputs[0].Value, test.ShouldAlmostEqual, -50)
+			test.That(t, end["theta"].Inputs[0].Value, test.ShouldAlmostEqual, 1.4)
+			checkTrajectory(t, traj, limits, profile)
+		})
+	}
+
+	t.Run("scaling slows the trajectory", func(t *testing.T) {
+		traj, err := NewTrajectory(plan, fs, nil)
+		test.That(t, err, test.ShouldBeNil)
+		slow, err := NewTrajectory(plan, fs, &TrajectoryOptions{VelocityScale: 0.5, AccelerationScale: 0.5})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, slow.Duration(), test.ShouldBeGreaterThan, traj.Duration())
+	})
+
+	t.Run("blends stay near the waypoints", func(t *testing.T) {
+		for _, profile := range []TrajectoryProfile{TrapezoidalProfile, SCurveProfile} {
+			for _, maxDeviation := range []float64{0, 0.01, 1} {
+				traj, err := NewTrajectory(plan, fs, &TrajectoryOptions{Profile: profile, MaxBlendDeviation: maxDeviation})
+				test.That(t, err, test.ShouldBeNil)
+				if maxDeviation == 0 {
+					maxDeviation = defaultMaxBlendDeviation
+				}
+				// Halfway through its blend is where the trajectory strays furthest from the waypoint's corner.
+				for i := 1; i < len(traj.waypoints)-1; i++ {
+					positions, _, _ := traj.evaluate(traj.times[i])
+					for j, position := range positions {
+						test.That(t, math.Abs(position-traj.waypoints[i][j]), test.ShouldBeLessThanOrEqualTo, maxDeviation*(1+1e-6))
+					}
+				}
+				checkTrajectory(t, traj, limits, profile)
+			}
+		}
+
+		tight, err := NewTrajectory(plan, fs, nil)
+		test.That(t, err, test.ShouldBeNil)
+		loose, err := NewTrajectory(plan, fs, &TrajectoryOptions{MaxBlendDeviation: 1})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, tight.Duration(), test.ShouldBeGreaterThan, loose.Duration())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewTrajectory(plan, fs, &TrajectoryOptions{Profile: "bang_bang"})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		_, err = NewTrajectory(plan, fs, &TrajectoryOptions{MaxBlendDeviation: -1})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		_, err = NewTrajectory([]map[string][]frame.Input{{"x": {{0}}}, {"x": {{0}, {1}}}}, fs, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		y, err := frame.NewTranslationalFrame("y", r3.Vector{Y: 1}, frame.Limit{Min: -10, Max: 10})
+		test.That(t, err, test.ShouldBeNil)
+		unknown := &unknownFrame{y}
+		_, err = NewFrameTrajectory(unknown, [][]frame.Input{{{0}}, {{1}}}, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "no velocity or acceleration limit")
+		opts := &TrajectoryOptions{DefaultLimit: frame.DynamicLimit{MaxVelocity: 1, MaxAcceleration: 1}}
+		_, err = NewFrameTrajectory(unknown, [][]frame.Input{{{0}}, {{1}}}, opts)
+		test.That(t, err, test.ShouldBeNil)
+		opts.Profile = SCurveProfile
+		_, err = NewFrameTrajectory(unknown, [][]frame.Input{{{0}}, {{1}}}, opts)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "no jerk limit")
+
+		// Frames of known types without declared limits are given conservative defaults.
+		_, err = NewFrameTrajectory(y, [][]frame.Input{{{0}}, {{1}}}, &TrajectoryOptions{Profile: SCurveProfile})
+		test.That(t, err, test.ShouldBeNil)
+	})
+}
+
+func TestArmModelTrajectory(t *testing.T) {
+	// The UR5e declares the maximum velocity of its joints, and the xArm6 relies on the default limits.
+	for _, file := range []string{"components/arm/universalrobots/ur5e.json", "components/arm/xarm/xarm6_kinematics.json"} {
+		t.Run(file, func(t *testing.T) {
+			m, err := frame.ParseModelJSONFile(utils.ResolveFile(file), "")
+			test.That(t, err, test.ShouldBeNil)
+			limits := frame.DynamicLimits(m)
+			test.That(t, limits, test.ShouldHaveLength, len(m.DoF()))
+
+			steps := [][]frame.Input{
+				frame.FloatsToInputs([]float64{0, 0, 0, 0, 0, 0}),
+				frame.FloatsToInputs([]float64{0.5, -0.3, 0.4, 0.2, -0.6, 1}),
+				frame.FloatsToInputs([]float64{0.7, -0.5, 0.1, 0.2, -0.2, 1.5}),
+			}
+			for _, profile := range []TrajectoryProfile{TrapezoidalProfile, SCurveProfile} {
+				traj, err := NewFrameTrajectory(m, steps, &TrajectoryOptions{Profile: profile})
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, traj.Duration(), test.ShouldBeGreaterThan, 0)
+				checkTrajectory(t, traj, limits, profile)
+			}
+		})
+	}
+	ur5e, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frame.DynamicLimits(ur5e)[0].MaxVelocity, test.ShouldAlmostEqual, math.Pi)
+}
+
+// checkTrajectory samples traj finely and checks that it is continuous and within limits.
+func checkTrajectory(t *testing.T, traj *Trajectory, limits []frame.DynamicLimit, profile TrajectoryProfile) {
+	t.Helper()
+	const period = time.Millisecond
+	samples, err := traj.Sample(period)
+	test.That(t, err, test.ShouldBeNil)
+	const tolerance = 1e-6
+	offset := 0
+	for _, name := range traj.Frames() {
+		points := samples[name]
+		for j := range points[0].Inputs {
+			limit := limits[offset+j]
+			for i, point := range points {
+				test.That(t, math.Abs(point.Velocities[j]), test.ShouldBeLessThanOrEqualTo, limit.MaxVelocity+tolerance)
+				test.That(t, math.Abs(point.Accelerations[j]), test.ShouldBeLessThanOrEqualTo, limit.MaxAcceleration+tolerance)
+				if i == 0 {
+					continue
+				}
+				prev := points[i-1]
+				dt := (point.Time - prev.Time).Seconds()
+				// Positions and velocities change no faster than the velocity and acceleration limits allow.
+				test.That(t, math.Abs(point.Inputs[j].Value-prev.Inputs[j].Value), test.ShouldBeLessThanOrEqualTo,
+					limit.MaxVelocity*dt+tolerance)
+				test.That(t, math.Abs(point.Velocities[j]-prev.Velocities[j]), test.ShouldBeLessThanOrEqualTo,
+					limit.MaxAcceleration*dt+tolerance)
+				if profile == SCurveProfile && limit.MaxJerk > 0 {
+					test.That(t, math.Abs(point.Accelerations[j]-prev.Accelerations[j]), test.ShouldBeLessThanOrEqualTo,
+						limit.MaxJerk*dt+tolerance)
+				}
+			}
+		}
+		offset += len(points[0].Inputs)
+	}
+}
+
+type fakeInputEnabled struct {
+	mu     sync.Mutex
+	inputs [][]frame.Input
+}
+
+func (f *fakeInputEnabled) CurrentInputs(ctx context.Context) ([]frame.Input, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.inputs[len(f.inputs)-1], nil
+}
+
+func (f *fakeInputEnabled) GoToInputs(ctx context.Context, goal []frame.Input) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inputs = append(f.inputs, goal)
+	return nil
+}
+
+type fakeFollower struct {
+	fakeInputEnabled
+	points []TrajectoryPoint
+}
+
+func (f *fakeFollower) FollowTrajectory(ctx context.Context, points []TrajectoryPoint) error {
+	f.points = points
+	return nil
+}
+
+func TestExecuteTrajectory(t *testing.T) {
+	fs := frame.NewEmptyFrameSystem("test")
+	for _, name := range []string{"streamed", "followed"} {
+		f := jointModel(t, name, frame.PrismaticJoint, frame.Limit{Min: -100, Max: 100},
+			frame.DynamicLimit{MaxVelocity: 100, MaxAcceleration: 1000})
+		test.That(t, fs.AddFrame(f, fs.World()), test.ShouldBeNil)
+	}
+	traj, err := NewTrajectory([]map[string][]frame.Input{
+		{"streamed": {{0}}, "followed": {{0}}},
+		{"streamed": {{10}}, "followed": {{-10}}},
+	}, fs, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	streamed := &fakeInputEnabled{}
+	followed := &fakeFollower{}
+	components := map[string]frame.InputEnabled{"streamed": streamed, "followed": followed}
+	err = ExecuteTrajectory(context.Background(), traj, components, 10*time.Millisecond)
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, len(streamed.inputs), test.ShouldBeGreaterThan, 1)
+	test.That(t, streamed.inputs[len(streamed.inputs)-1][0].Value, test.ShouldAlmostEqual, 10)
+	test.That(t, followed.inputs, test.ShouldBeEmpty)
+	test.That(t, len(followed.points), test.ShouldBeGreaterThan, 1)
+	test.That(t, followed.points[len(followed.points)-1].Inputs[0].Value, test.ShouldAlmostEqual, -10)
+	test.That(t, followed.points[len(followed.points)-1].Time, test.ShouldEqual, traj.Duration())
+
+	err = ExecuteTrajectory(context.Background(), traj, map[string]frame.InputEnabled{"streamed": streamed}, 10*time.Millisecond)
+	test.That(t, err, test.ShouldNotBeNil)
+}
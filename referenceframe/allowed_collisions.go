@@ -0,0 +1,94 @@
+package referenceframe
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AllowedCollision is a pair of names of frames or geometries that are allowed to be in collision with each other, such
+// as adjacent links of an arm that touch by design. Collisions between them are never checked when planning. Either name
+// may contain the wildcards of path.Match, so that "*" matches every name, and the name of a frame matches every geometry
+// of that frame.
+type AllowedCollision struct {
+	Frame1 string `json:"frame1"`
+	Frame2 string `json:"frame2"`
+}
+
+// Validate returns an error if either name of the pair is empty or is not a valid pattern.
+func (ac AllowedCollision) Validate() error {
+	for _, name := range []string{ac.Frame1, ac.Frame2} {
+		if name == "" {
+			return errors.New("allowed collisions must name two frames or geometries")
+		}
+		if _, err := path.Match(name, ""); err != nil {
+			return errors.Wrapf(err, "invalid allowed collision name %q", name)
+		}
+	}
+	return nil
+}
+
+// Allows returns whether the pair allows a collision between the geometries with the given labels, in either order.
+func (ac AllowedCollision) Allows(label1, label2 string) bool {
+	return (nameMatches(ac.Frame1, label1) && nameMatches(ac.Frame2, label2)) ||
+		(nameMatches(ac.Frame1, label2) && nameMatches(ac.Frame2, label1))
+}
+
+// nameMatches returns whether the pattern matches the label of a geometry, or the name of the model it belongs to. The
+// geometries of models are labelled with the name of the model, a colon and the name of the link.
+func nameMatches(pattern, label string) bool {
+	names := []string{label}
+	if i := strings.LastIndex(label, ":"); i >= 0 {
+		names = append(names, label[:i])
+	}
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedCollisions returns every pair of frames or geometries allowed to be in collision in the frame system. These are
+// the pairs configured for each part of the frame system, and the pairs of links configured for each model in it, which
+// are prefixed with the name of the model so that they only match geometries of that model.
+func AllowedCollisions(fs FrameSystem) []AllowedCollision {
+	var allowed []AllowedCollision
+	if sfs, ok := fs.(*simpleFrameSystem); ok {
+		allowed = append(allowed, sfs.allowedCollisions...)
+	}
+	for _, name := range fs.FrameNames() {
+		frame := fs.Frame(name)
+		if named, ok := frame.(*namedFrame); ok {
+			frame = named.Frame
+		}
+		model, ok := frame.(Model)
+		if !ok || model.ModelConfig() == nil {
+			continue
+		}
+		for _, pair := range model.ModelConfig().AllowedCollisions {
+			allowed = append(allowed, AllowedCollision{
+				Frame1: model.Name() + ":" + pair.Frame1,
+				Frame2: model.Name() + ":" + pair.Frame2,
+			})
+		}
+	}
+	return allowed
+}
+
+// AllowCollisions adds pairs of frames or geometries allowed to be in collision to a frame system, as if they had been
+// configured for one of its parts.
+func AllowCollisions(fs FrameSystem, allowed ...AllowedCollision) error {
+	sfs, ok := fs.(*simpleFrameSystem)
+	if !ok {
+		return errors.Errorf("cannot allow collisions in frame system of type %T", fs)
+	}
+	for _, pair := range allowed {
+		if err := pair.Validate(); err != nil {
+			return err
+		}
+	}
+	sfs.allowedCollisions = append(sfs.allowedCollisions, allowed...)
+	return nil
+}
@@ -0,0 +1,83 @@
+package referenceframe
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+func TestAllowedCollisionAllows(t *testing.T) {
+	allowed := AllowedCollision{Frame1: "arm:wrist_*", Frame2: "gripper"}
+	test.That(t, allowed.Validate(), test.ShouldBeNil)
+	test.That(t, allowed.Allows("arm:wrist_1_link", "gripper"), test.ShouldBeTrue)
+	test.That(t, allowed.Allows("gripper", "arm:wrist_2_link"), test.ShouldBeTrue)
+	test.That(t, allowed.Allows("arm:forearm_link", "gripper"), test.ShouldBeFalse)
+	test.That(t, allowed.Allows("arm:wrist_1_link", "camera"), test.ShouldBeFalse)
+
+	// the name of a model matches all of its geometries
+	allowed = AllowedCollision{Frame1: "arm", Frame2: "*"}
+	test.That(t, allowed.Allows("arm:wrist_1_link", "box"), test.ShouldBeTrue)
+	test.That(t, allowed.Allows("gripper", "box"), test.ShouldBeFalse)
+
+	test.That(t, AllowedCollision{Frame1: "arm"}.Validate(), test.ShouldNotBeNil)
+	test.That(t, AllowedCollision{Frame1: "arm", Frame2: "[gripper"}.Validate(), test.ShouldNotBeNil)
+}
+
+func TestFrameSystemAllowedCollisions(t *testing.T) {
+	jsonData, err := os.ReadFile(rdkutils.ResolveFile("components/arm/universalrobots/ur5e.json"))
+	test.That(t, err, test.ShouldBeNil)
+	modelCfg := &ModelConfig{}
+	test.That(t, json.Unmarshal(jsonData, modelCfg), test.ShouldBeNil)
+	modelCfg.AllowedCollisions = []AllowedCollision{{Frame1: "wrist_1_link", Frame2: "wrist_2_link"}}
+	model, err := modelCfg.ParseConfig("arm")
+	test.That(t, err, test.ShouldBeNil)
+
+	box, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "gripper")
+	test.That(t, err, test.ShouldBeNil)
+	parts := []*FrameSystemPart{
+		{FrameConfig: NewLinkInFrame(World, spatial.NewZeroPose(), "arm", nil), ModelFrame: model},
+		{
+			FrameConfig:       NewLinkInFrame("arm", spatial.NewZeroPose(), "gripper", box),
+			AllowedCollisions: []AllowedCollision{{Frame1: "gripper", Frame2: "arm"}},
+		},
+	}
+	fs, err := NewFrameSystem("test", parts, nil)
+	test.That(t, err, test.ShouldBeNil)
+	expected := []AllowedCollision{
+		{Frame1: "gripper", Frame2: "arm"},
+		{Frame1: "arm:wrist_1_link", Frame2: "arm:wrist_2_link"},
+	}
+	test.That(t, AllowedCollisions(fs), test.ShouldResemble, expected)
+
+	// the pairs are kept when dividing and merging frame systems
+	subset, err := fs.FrameSystemSubset(fs.Frame("arm_origin"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, AllowedCollisions(subset), test.ShouldResemble, expected)
+	merged := NewEmptyFrameSystem("merged")
+	test.That(t, merged.MergeFrameSystem(fs, merged.World()), test.ShouldBeNil)
+	test.That(t, AllowedCollisions(merged), test.ShouldResemble, expected)
+
+	// exported model configs keep their pairs
+	exported, err := NewModelConfig(model)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, exported.AllowedCollisions, test.ShouldResemble, modelCfg.AllowedCollisions)
+
+	// pairs can be added to a frame system once it is built
+	added := AllowedCollision{Frame1: "gripper", Frame2: "table"}
+	test.That(t, AllowCollisions(fs, added), test.ShouldBeNil)
+	test.That(t, AllowedCollisions(fs), test.ShouldResemble, append([]AllowedCollision{expected[0], added}, expected[1:]...))
+	test.That(t, AllowCollisions(fs, AllowedCollision{Frame1: "gripper"}), test.ShouldNotBeNil)
+
+	parts[1].AllowedCollisions = []AllowedCollision{{Frame1: "gripper"}}
+	_, err = NewFrameSystem("test", parts, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	modelCfg.AllowedCollisions = []AllowedCollision{{Frame1: "[", Frame2: "wrist_2_link"}}
+	_, err = modelCfg.ParseConfig("arm")
+	test.That(t, err, test.ShouldNotBeNil)
+}
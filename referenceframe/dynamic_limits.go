@@ -0,0 +1,100 @@
+package referenceframe
+
+import (
+	"go.viam.com/rdk/utils"
+)
+
+// DynamicLimit bounds how quickly a single input of a frame may change, in the input's units (mm or radians) per
+// second, per second squared and per second cubed. A zero field means the rate is unknown.
+type DynamicLimit struct {
+	MaxVelocity     float64
+	MaxAcceleration float64
+	MaxJerk         float64
+}
+
+// Conservative limits for joints whose model does not declare their own. They are well below what arms and gantries
+// are typically capable of, so that motion timed with them is slow but safe.
+var (
+	defaultRevoluteDynamicLimit = DynamicLimit{
+		MaxVelocity:     utils.DegToRad(30),
+		MaxAcceleration: utils.DegToRad(60),
+		MaxJerk:         utils.DegToRad(300),
+	}
+	defaultPrismaticDynamicLimit = DynamicLimit{
+		MaxVelocity:     50,
+		MaxAcceleration: 100,
+		MaxJerk:         500,
+	}
+)
+
+// DynamicLimits returns the dynamic limits of each of f's inputs, in the order of f.DoF(). The limits of a model's
+// joints are those declared in its config, with any it leaves out filled in by conservative defaults for the joint's
+// type. Inputs of frames whose joint types are unknown have zero limits.
+func DynamicLimits(f Frame) []DynamicLimit {
+	switch f := f.(type) {
+	case *namedFrame:
+		return DynamicLimits(f.Frame)
+	case *SimpleModel:
+		return f.dynamicLimits()
+	case *rotationalFrame:
+		return []DynamicLimit{defaultRevoluteDynamicLimit}
+	case *translationalFrame:
+		return []DynamicLimit{defaultPrismaticDynamicLimit}
+	default:
+		return make([]DynamicLimit, len(f.DoF()))
+	}
+}
+
+func (m *SimpleModel) dynamicLimits() []DynamicLimit {
+	declared := map[string]DynamicLimit{}
+	if m.modelConfig != nil {
+		for _, joint := range m.modelConfig.Joints {
+			declared[joint.ID] = joint.dynamicLimit()
+		}
+		for _, dh := range m.modelConfig.DHParams {
+			declared[dh.ID+"_j"] = dh.dynamicLimit()
+		}
+	}
+
+	limits := make([]DynamicLimit, 0, len(m.DoF()))
+	for _, transform := range m.OrdTransforms {
+		if len(transform.DoF()) == 0 {
+			continue
+		}
+		transformLimits := DynamicLimits(transform)
+		if limit, ok := declared[transform.Name()]; ok && len(transformLimits) == 1 {
+			if limit.MaxVelocity > 0 {
+				transformLimits[0].MaxVelocity = limit.MaxVelocity
+			}
+			if limit.MaxAcceleration > 0 {
+				transformLimits[0].MaxAcceleration = limit.MaxAcceleration
+			}
+			if limit.MaxJerk > 0 {
+				transformLimits[0].MaxJerk = limit.MaxJerk
+			}
+		}
+		limits = append(limits, transformLimits...)
+	}
+	return limits
+}
+
+// dynamicLimit returns the limits declared by the joint, converted to radians for revolute joints.
+func (cfg *JointConfig) dynamicLimit() DynamicLimit {
+	if cfg.Type == RevoluteJoint {
+		return DynamicLimit{
+			MaxVelocity:     utils.DegToRad(cfg.MaxVelocity),
+			MaxAcceleration: utils.DegToRad(cfg.MaxAcceleration),
+			MaxJerk:         utils.DegToRad(cfg.MaxJerk),
+		}
+	}
+	return DynamicLimit{MaxVelocity: cfg.MaxVelocity, MaxAcceleration: cfg.MaxAcceleration, MaxJerk: cfg.MaxJerk}
+}
+
+// dynamicLimit returns the limits declared by the parameters' revolute joint, in radians.
+func (cfg *DHParamConfig) dynamicLimit() DynamicLimit {
+	return DynamicLimit{
+		MaxVelocity:     utils.DegToRad(cfg.MaxVelocity),
+		MaxAcceleration: utils.DegToRad(cfg.MaxAcceleration),
+		MaxJerk:         utils.DegToRad(cfg.MaxJerk),
+	}
+}
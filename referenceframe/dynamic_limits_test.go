@@ -0,0 +1,49 @@
+package referenceframe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestDynamicLimits(t *testing.T) {
+	cfg := &ModelConfig{
+		Name: "arm",
+		Joints: []JointConfig{
+			{ID: "shoulder", Type: RevoluteJoint, Parent: World, Axis: spatial.AxisConfig{Z: 1}, Min: -180, Max: 180, MaxVelocity: 90},
+			{ID: "slider", Type: PrismaticJoint, Parent: "shoulder", Axis: spatial.AxisConfig{X: 1}, Min: 0, Max: 500, MaxJerk: 1000},
+		},
+	}
+	model, err := cfg.ParseConfig("")
+	test.That(t, err, test.ShouldBeNil)
+
+	// Declared limits are converted to radians for revolute joints, and the rest are filled in by defaults.
+	expected := []DynamicLimit{
+		{MaxVelocity: math.Pi / 2, MaxAcceleration: defaultRevoluteDynamicLimit.MaxAcceleration, MaxJerk: defaultRevoluteDynamicLimit.MaxJerk},
+		{MaxVelocity: defaultPrismaticDynamicLimit.MaxVelocity, MaxAcceleration: defaultPrismaticDynamicLimit.MaxAcceleration, MaxJerk: 1000},
+	}
+	test.That(t, DynamicLimits(model), test.ShouldResemble, expected)
+	test.That(t, DynamicLimits(NewNamedFrame(model, "renamed")), test.ShouldResemble, expected)
+
+	// Declared limits survive describing the model by its frames.
+	cfg2, err := NewModelConfig(model)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg2.Joints[0].MaxVelocity, test.ShouldAlmostEqual, 90)
+	test.That(t, cfg2.Joints[1].MaxJerk, test.ShouldAlmostEqual, 1000)
+
+	dh, err := ParseModelJSONFile(utils.ResolveFile("referenceframe/testjson/ur5eDH.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	for _, limit := range DynamicLimits(dh) {
+		test.That(t, limit, test.ShouldResemble, defaultRevoluteDynamicLimit)
+	}
+
+	slider, err := NewTranslationalFrame("slider", r3.Vector{X: 1}, Limit{Min: 0, Max: 10})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, DynamicLimits(slider), test.ShouldResemble, []DynamicLimit{defaultPrismaticDynamicLimit})
+	test.That(t, DynamicLimits(NewZeroStaticFrame("static")), test.ShouldBeEmpty)
+}
@@ -22,6 +22,9 @@ type LinkConfig struct {
 	Orientation *spatial.OrientationConfig `json:"orientation"`
 	Geometry    *spatial.GeometryConfig    `json:"geometry,omitempty"`
 	Parent      string                     `json:"parent,omitempty"`
+	// AllowedCollisions names the frames or geometries, which may contain wildcards, that the geometries of a component
+	// are allowed to be in collision with. It is only used in the frame config of a component.
+	AllowedCollisions []string `json:"allowed_collisions,omitempty"`
 }
 
 // JointConfig is a frame with nonzero DOF. Supports rotational or translational.
@@ -33,6 +36,10 @@ type JointConfig struct {
 	Max      float64                 `json:"max"`                // in mm or degs
 	Min      float64                 `json:"min"`                // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"` // only valid for prismatic/translational joints
+
+	MaxVelocity     float64 `json:"max_velocity,omitempty"`     // in mm/s or degs/s
+	MaxAcceleration float64 `json:"max_acceleration,omitempty"` // in mm/s^2 or degs/s^2
+	MaxJerk         float64 `json:"max_jerk,omitempty"`         // in mm/s^3 or degs/s^3
 }
 
 // DHParamConfig is a revolute and static frame combined in a set of Denavit Hartenberg parameters.
@@ -45,6 +52,10 @@ type DHParamConfig struct {
 	Max      float64                 `json:"max"` // in mm or degs
 	Min      float64                 `json:"min"` // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"`
+
+	MaxVelocity     float64 `json:"max_velocity,omitempty"`     // in degs/s
+	MaxAcceleration float64 `json:"max_acceleration,omitempty"` // in degs/s^2
+	MaxJerk         float64 `json:"max_jerk,omitempty"`         // in degs/s^3
 }
 
 // NewLinkConfig constructs a config from a Frame.
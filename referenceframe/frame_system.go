@@ -70,20 +70,25 @@ type FrameSystem interface {
 type FrameSystemPart struct {
 	FrameConfig *LinkInFrame
 	ModelFrame  Model
+	// AllowedCollisions are the pairs of frames or geometries allowed to be in collision with each other, as configured
+	// by the allowed_collisions of the part's frame config. The API has no field for them, so they are not sent with the
+	// part in its protobuf form.
+	AllowedCollisions []AllowedCollision
 }
 
 // simpleFrameSystem implements FrameSystem. It is a simple tree graph.
 type simpleFrameSystem struct {
-	name    string
-	world   Frame // separate from the map of frames so it can be detached easily
-	frames  map[string]Frame
-	parents map[Frame]Frame
+	name              string
+	world             Frame // separate from the map of frames so it can be detached easily
+	frames            map[string]Frame
+	parents           map[Frame]Frame
+	allowedCollisions []AllowedCollision
 }
 
 // NewEmptyFrameSystem creates a graph of Frames that have.
 func NewEmptyFrameSystem(name string) FrameSystem {
 	worldFrame := NewZeroStaticFrame(World)
-	return &simpleFrameSystem{name, worldFrame, map[string]Frame{}, map[Frame]Frame{}}
+	return &simpleFrameSystem{name, worldFrame, map[string]Frame{}, map[Frame]Frame{}, nil}
 }
 
 // NewFrameSystem assembles a frame system from a set of parts and additional transforms.
@@ -123,7 +128,7 @@ func NewFrameSystem(name string, parts []*FrameSystemPart, additionalTransforms
 			getPartNames(allParts),
 		)
 	}
-	fs := NewEmptyFrameSystem(name)
+	fs := &simpleFrameSystem{name, NewZeroStaticFrame(World), map[string]Frame{}, map[Frame]Frame{}, nil}
 	for _, part := range sortedParts {
 		// make the frames from the configs
 		modelFrame, staticOffsetFrame, err := createFramesFromPart(part)
@@ -137,6 +142,12 @@ func NewFrameSystem(name string, parts []*FrameSystemPart, additionalTransforms
 		if err = fs.AddFrame(modelFrame, staticOffsetFrame); err != nil {
 			return nil, err
 		}
+		for _, allowed := range part.AllowedCollisions {
+			if err := allowed.Validate(); err != nil {
+				return nil, errors.Wrapf(err, "frame system part %q", part.FrameConfig.Name())
+			}
+		}
+		fs.allowedCollisions = append(fs.allowedCollisions, part.AllowedCollisions...)
 	}
 	return fs, nil
 }
@@ -322,6 +333,9 @@ func (sfs *simpleFrameSystem) MergeFrameSystem(systemToMerge FrameSystem, attach
 			}
 		}
 	}
+	if merged, ok := systemToMerge.(*simpleFrameSystem); ok {
+		sfs.allowedCollisions = append(sfs.allowedCollisions, merged.allowedCollisions...)
+	}
 	return nil
 }
 
@@ -329,7 +343,7 @@ func (sfs *simpleFrameSystem) MergeFrameSystem(systemToMerge FrameSystem, attach
 // at the given frame and containing all descendents of it. The original frame system is unchanged.
 func (sfs *simpleFrameSystem) FrameSystemSubset(newRoot Frame) (FrameSystem, error) {
 	newWorld := NewZeroStaticFrame(World)
-	newFS := &simpleFrameSystem{newRoot.Name() + "_FS", newWorld, map[string]Frame{}, map[Frame]Frame{}}
+	newFS := &simpleFrameSystem{newRoot.Name() + "_FS", newWorld, map[string]Frame{}, map[Frame]Frame{}, sfs.allowedCollisions}
 
 	rootFrame := sfs.Frame(newRoot.Name())
 	if rootFrame == nil {
@@ -67,6 +67,20 @@ func (m *SimpleModel) ModelConfig() *ModelConfig {
 	return m.modelConfig
 }
 
+// ModelIKSolver returns the inverse kinematics solver named by the config of the model f was created from, or an empty
+// string if f was not created from a config or its config does not name one.
+func ModelIKSolver(f Frame) string {
+	switch f := f.(type) {
+	case *namedFrame:
+		return ModelIKSolver(f.Frame)
+	case *SimpleModel:
+		if f.modelConfig != nil {
+			return f.modelConfig.IKSolver
+		}
+	}
+	return ""
+}
+
 // Transform takes a model and a list of joint angles in radians and computes the dual quaternion representing the
 // cartesian position of the end effector. This is useful for when conversions between quaternions and OV are not needed.
 func (m *SimpleModel) Transform(inputs []Input) (spatialmath.Pose, error) {
@@ -167,9 +181,17 @@ func (m *SimpleModel) DoF() []Limit {
 	return limits
 }
 
-// MarshalJSON serializes a Model.
+// MarshalJSON serializes a Model. Models which were not created from a config are serialized as the config describing
+// their frames.
 func (m *SimpleModel) MarshalJSON() ([]byte, error) {
-	return json.Marshal(m.modelConfig)
+	if m.modelConfig != nil {
+		return json.Marshal(m.modelConfig)
+	}
+	cfg, err := NewModelConfig(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cfg)
 }
 
 // AlmostEquals returns true if the only difference between this model and another is floating point inprecision.
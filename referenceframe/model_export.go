@@ -0,0 +1,240 @@
+package referenceframe
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// NewModelConfig builds a ModelConfig that describes the frames of the given model as links and joints, regardless of
+// how the model was created. Models defined by DH parameters are described by their equivalent links and joints.
+func NewModelConfig(m Model) (*ModelConfig, error) {
+	simple, ok := m.(*SimpleModel)
+	if !ok {
+		if cfg := m.ModelConfig(); cfg != nil {
+			return cfg, nil
+		}
+		return nil, errors.Errorf("cannot build a model config for model %s of type %T", m.Name(), m)
+	}
+	cfg := &ModelConfig{Name: m.Name(), KinParamType: "SVA"}
+	parent := World
+	for _, frame := range simple.OrdTransforms {
+		if err := cfg.addFrame(frame, parent); err != nil {
+			return nil, err
+		}
+		parent = frame.Name()
+	}
+	if simple.modelConfig != nil {
+		cfg.AllowedCollisions = simple.modelConfig.AllowedCollisions
+		cfg.IKSolver = simple.modelConfig.IKSolver
+		cfg.copyDynamicLimits(simple.modelConfig)
+	}
+	return cfg, nil
+}
+
+// copyDynamicLimits copies the velocity, acceleration and jerk limits declared by the joints of from, which may be
+// defined by DH parameters, onto the joints of cfg describing the same frames.
+func (cfg *ModelConfig) copyDynamicLimits(from *ModelConfig) {
+	declared := map[string]JointConfig{}
+	for _, joint := range from.Joints {
+		declared[joint.ID] = joint
+	}
+	for _, dh := range from.DHParams {
+		declared[dh.ID+"_j"] = JointConfig{MaxVelocity: dh.MaxVelocity, MaxAcceleration: dh.MaxAcceleration, MaxJerk: dh.MaxJerk}
+	}
+	for i := range cfg.Joints {
+		if joint, ok := declared[cfg.Joints[i].ID]; ok {
+			cfg.Joints[i].MaxVelocity = joint.MaxVelocity
+			cfg.Joints[i].MaxAcceleration = joint.MaxAcceleration
+			cfg.Joints[i].MaxJerk = joint.MaxJerk
+		}
+	}
+}
+
+// ModelToURDF converts the given model into equivalent URDF XML data.
+func ModelToURDF(m Model) ([]byte, error) {
+	cfg, err := NewModelConfig(m)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertConfigToURDF(cfg)
+}
+
+// FrameSystemToModelConfig builds a ModelConfig that describes the frame system as it is at the given inputs. Every frame
+// becomes a static link holding its current transform and geometries, so the config can be viewed by external tools.
+// Frames with more than one geometry, such as arms, are given an additional link for each geometry.
+func FrameSystemToModelConfig(fs FrameSystem, inputs map[string][]Input) (*ModelConfig, error) {
+	// order frames by their depth in the frame system so that parents are described before their children
+	depths := make(map[string]int)
+	names := fs.FrameNames()
+	for _, name := range names {
+		for frame := fs.Frame(name); frame != nil && frame != fs.World(); depths[name]++ {
+			parent, err := fs.Parent(frame)
+			if err != nil {
+				return nil, err
+			}
+			frame = parent
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if depths[names[i]] != depths[names[j]] {
+			return depths[names[i]] < depths[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	cfg := &ModelConfig{Name: fs.Name(), KinParamType: "SVA"}
+	for _, name := range names {
+		frame := fs.Frame(name)
+		parent, err := fs.Parent(frame)
+		if err != nil {
+			return nil, err
+		}
+		frameInputs, err := GetFrameInputs(frame, inputs)
+		if err != nil {
+			return nil, err
+		}
+		pose, err := frame.Transform(frameInputs)
+		if err != nil {
+			return nil, err
+		}
+		geometries, err := frame.Geometries(frameInputs)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.addStaticLink(name, parent.Name(), pose, geometries.Geometries()); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// FrameSystemToURDF converts the frame system as it is at the given inputs into equivalent URDF XML data.
+func FrameSystemToURDF(fs FrameSystem, inputs map[string][]Input) ([]byte, error) {
+	cfg, err := FrameSystemToModelConfig(fs, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertConfigToURDF(cfg)
+}
+
+// addFrame adds a link or joint describing the given frame to the config.
+func (cfg *ModelConfig) addFrame(frame Frame, parent string) error {
+	name := frame.Name()
+	if named, ok := frame.(*namedFrame); ok {
+		frame = named.Frame
+	}
+
+	switch f := frame.(type) {
+	case *staticFrame:
+		return cfg.addStaticLink(name, parent, f.transform, geometriesOf(f.geometry))
+	case *tailGeometryStaticFrame:
+		// geometries of a link are placed before its transform, so the tail geometry has to be moved along it
+		var geometries []spatial.Geometry
+		if f.geometry != nil {
+			geometries = []spatial.Geometry{f.geometry.Transform(f.transform)}
+		}
+		return cfg.addStaticLink(name, parent, f.transform, geometries)
+	case *translationalFrame:
+		if len(f.limits) > 1 {
+			return ErrMarshalingHighDOFFrame
+		}
+		joint := JointConfig{
+			ID:     name,
+			Type:   PrismaticJoint,
+			Parent: parent,
+			Axis:   spatial.AxisConfig{f.transAxis.X, f.transAxis.Y, f.transAxis.Z},
+			Max:    f.limits[0].Max,
+			Min:    f.limits[0].Min,
+		}
+		if f.geometry != nil {
+			var err error
+			if joint.Geometry, err = spatial.NewGeometryConfig(f.geometry); err != nil {
+				return err
+			}
+		}
+		cfg.Joints = append(cfg.Joints, joint)
+	case *rotationalFrame:
+		if len(f.limits) > 1 {
+			return ErrMarshalingHighDOFFrame
+		}
+		cfg.Joints = append(cfg.Joints, JointConfig{
+			ID:     name,
+			Type:   RevoluteJoint,
+			Parent: parent,
+			Axis:   spatial.AxisConfig{f.rotAxis.X, f.rotAxis.Y, f.rotAxis.Z},
+			Max:    utils.RadToDeg(f.limits[0].Max),
+			Min:    utils.RadToDeg(f.limits[0].Min),
+		})
+	default:
+		// any other frame without degrees of freedom can still be described by its fixed transform
+		if len(frame.DoF()) > 0 {
+			return errors.Errorf("cannot describe frame %s of type %T with %d degrees of freedom", name, frame, len(frame.DoF()))
+		}
+		pose, err := frame.Transform([]Input{})
+		if err != nil {
+			return err
+		}
+		geometries, err := frame.Geometries([]Input{})
+		if err != nil {
+			return err
+		}
+		return cfg.addStaticLink(name, parent, pose, geometries.Geometries())
+	}
+	return nil
+}
+
+// addStaticLink adds a link with the given transform and geometries to the config. A link holds at most one geometry, so
+// any others are held by additional links alongside it, named after the geometry's label where possible.
+func (cfg *ModelConfig) addStaticLink(name, parent string, pose spatial.Pose, geometries []spatial.Geometry) error {
+	orientation, err := spatial.NewOrientationConfig(pose.Orientation())
+	if err != nil {
+		return err
+	}
+	link := LinkConfig{ID: name, Parent: parent, Translation: pose.Point(), Orientation: orientation}
+	if len(geometries) == 1 {
+		if link.Geometry, err = spatial.NewGeometryConfig(geometries[0]); err != nil {
+			return err
+		}
+		geometries = nil
+	}
+	cfg.Links = append(cfg.Links, link)
+
+	for i, geometry := range geometries {
+		geometryLink := LinkConfig{ID: geometry.Label(), Parent: parent, Orientation: &spatial.OrientationConfig{}}
+		if geometryLink.ID == "" || cfg.hasFrame(geometryLink.ID) {
+			geometryLink.ID = fmt.Sprintf("%s_geometry_%d", name, i)
+		}
+		if geometryLink.Geometry, err = spatial.NewGeometryConfig(geometry); err != nil {
+			return err
+		}
+		cfg.Links = append(cfg.Links, geometryLink)
+	}
+	return nil
+}
+
+// hasFrame returns whether the config already has a link or joint with the given name.
+func (cfg *ModelConfig) hasFrame(name string) bool {
+	for _, link := range cfg.Links {
+		if link.ID == name {
+			return true
+		}
+	}
+	for _, joint := range cfg.Joints {
+		if joint.ID == name {
+			return true
+		}
+	}
+	return false
+}
+
+func geometriesOf(geometry spatial.Geometry) []spatial.Geometry {
+	if geometry == nil {
+		return nil
+	}
+	return []spatial.Geometry{geometry}
+}
@@ -0,0 +1,183 @@
+package referenceframe
+
+import (
+	"encoding/xml"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// testModelsEquivalent checks that two models have the same end effector pose and geometries at random inputs.
+func testModelsEquivalent(t *testing.T, m1, m2 Model) {
+	t.Helper()
+	test.That(t, len(m2.DoF()), test.ShouldEqual, len(m1.DoF()))
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		inputs := FloatsToInputs(GenerateRandomConfiguration(m1, rng))
+		pose1, err := m1.Transform(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		pose2, err := m2.Transform(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatial.PoseAlmostCoincidentEps(pose1, pose2, 1e-6), test.ShouldBeTrue)
+
+		geometries1, err := m1.Geometries(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		geometries2, err := m2.Geometries(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		testGeometriesEquivalent(t, geometries1.Geometries(), geometries2.Geometries())
+	}
+}
+
+// testGeometriesEquivalent checks that two lists of geometries have the same shapes in the same places, ignoring labels.
+func testGeometriesEquivalent(t *testing.T, geometries1, geometries2 []spatial.Geometry) {
+	t.Helper()
+	test.That(t, len(geometries2), test.ShouldEqual, len(geometries1))
+	for _, g1 := range geometries1 {
+		found := false
+		for _, g2 := range geometries2 {
+			if g1.AlmostEqual(g2) {
+				found = true
+				break
+			}
+		}
+		test.That(t, found, test.ShouldBeTrue)
+	}
+}
+
+func TestModelToURDF(t *testing.T) {
+	for _, f := range []string{
+		"referenceframe/testurdf/ur5_viam.urdf",
+		"referenceframe/testurdf/example_gantry.urdf",
+		"referenceframe/testurdf/mesh_arm.urdf",
+		"referenceframe/testjson/ur5eDH.json",
+		"components/arm/xarm/xarm6_kinematics.json",
+	} {
+		t.Run(f, func(t *testing.T) {
+			model, err := ModelFromPath(utils.ResolveFile(f), "")
+			test.That(t, err, test.ShouldBeNil)
+
+			data, err := ModelToURDF(model)
+			test.That(t, err, test.ShouldBeNil)
+			cfg, err := ConvertURDFToConfig(data, "")
+			test.That(t, err, test.ShouldBeNil)
+			model2, err := cfg.ParseConfig("")
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, model2.Name(), test.ShouldEqual, model.Name())
+			testModelsEquivalent(t, model, model2)
+		})
+	}
+}
+
+func TestModelToJSON(t *testing.T) {
+	// a model built from frames rather than a config is serialized by describing its frames
+	box, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{Z: 50}), r3.Vector{X: 100, Y: 100, Z: 100}, "base")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := New2DMobileModelFrame("rover", []Limit{{Min: -1000, Max: 1000}, {Min: -1000, Max: 1000}}, box)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, model.ModelConfig(), test.ShouldBeNil)
+
+	data, err := model.MarshalJSON()
+	test.That(t, err, test.ShouldBeNil)
+	model2, err := UnmarshalModelJSON(data, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, model2.Name(), test.ShouldEqual, "rover")
+	test.That(t, model.AlmostEquals(model2), test.ShouldBeTrue)
+	testModelsEquivalent(t, model, model2)
+}
+
+func TestConvertConfigToURDF(t *testing.T) {
+	capsule, err := spatial.NewCapsule(spatial.NewPoseFromPoint(r3.Vector{Z: 100}), 20, 200, "capsule")
+	test.That(t, err, test.ShouldBeNil)
+	capsuleCfg, err := spatial.NewGeometryConfig(capsule)
+	test.That(t, err, test.ShouldBeNil)
+	cfg := &ModelConfig{
+		Name: "capsule_arm",
+		Links: []LinkConfig{
+			{ID: "base", Translation: r3.Vector{Z: 100}, Geometry: capsuleCfg},
+			{ID: "tool", Parent: "wrist", Translation: r3.Vector{X: 50}},
+		},
+		Joints: []JointConfig{
+			{ID: "wrist", Type: RevoluteJoint, Parent: "base", Axis: spatial.AxisConfig{Z: 1}, Min: math.Inf(-1), Max: math.Inf(1)},
+		},
+	}
+	data, err := ConvertConfigToURDF(cfg)
+	test.That(t, err, test.ShouldBeNil)
+
+	urdf := &URDFConfig{}
+	test.That(t, xml.Unmarshal(data, urdf), test.ShouldBeNil)
+	test.That(t, len(urdf.Links), test.ShouldEqual, 4)
+	test.That(t, len(urdf.Joints), test.ShouldEqual, 3)
+	test.That(t, urdf.Joints[2].Type, test.ShouldEqual, ContinuousJoint)
+
+	// the capsule is a cylinder capped by two spheres, placed relative to the base link rather than its parent
+	collisions := urdf.Links[1].Collision
+	test.That(t, len(collisions), test.ShouldEqual, 3)
+	test.That(t, collisions[0].Geometry.Cylinder.Length, test.ShouldAlmostEqual, 0.16)
+	test.That(t, collisions[0].Origin.XYZ, test.ShouldEqual, "0 0 0")
+	test.That(t, collisions[1].Geometry.Sphere.Radius, test.ShouldAlmostEqual, 0.02)
+	test.That(t, collisions[1].Origin.XYZ, test.ShouldEqual, "0 0 -0.08")
+	test.That(t, collisions[2].Origin.XYZ, test.ShouldEqual, "0 0 0.08")
+
+	cfg.Links[1].Parent = "elbow"
+	_, err = ConvertConfigToURDF(cfg)
+	test.That(t, err, test.ShouldBeError, NewParentFrameMissingError("tool", "elbow"))
+}
+
+func TestFrameSystemToModelConfig(t *testing.T) {
+	fs := NewEmptyFrameSystem("test")
+	arm, err := ParseURDFFile(utils.ResolveFile("referenceframe/testurdf/ur5_viam.urdf"), "arm")
+	test.That(t, err, test.ShouldBeNil)
+	table, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{Z: -25}), r3.Vector{X: 1000, Y: 1000, Z: 50}, "table")
+	test.That(t, err, test.ShouldBeNil)
+	tableFrame, err := NewStaticFrameWithGeometry("table", spatial.NewPoseFromPoint(r3.Vector{X: 100}), table)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(tableFrame, fs.World()), test.ShouldBeNil)
+	test.That(t, fs.AddFrame(arm, tableFrame), test.ShouldBeNil)
+	gripper, err := spatial.NewCapsule(spatial.NewPoseFromPoint(r3.Vector{Z: 50}), 20, 100, "gripper")
+	test.That(t, err, test.ShouldBeNil)
+	gripperFrame, err := NewStaticFrameWithGeometry("gripper", spatial.NewZeroPose(), gripper)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripperFrame, arm), test.ShouldBeNil)
+
+	inputs := StartPositions(fs)
+	inputs["arm"] = FloatsToInputs([]float64{0.5, -1, 1, 0.2, 0.3, 0.4})
+	cfg, err := FrameSystemToModelConfig(fs, inputs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(cfg.Joints), test.ShouldEqual, 0)
+
+	// rebuilding a frame system from the described links places all geometries where they currently are
+	fs2 := NewEmptyFrameSystem("test")
+	for _, link := range cfg.Links {
+		lif, err := link.ParseConfig()
+		test.That(t, err, test.ShouldBeNil)
+		frame, err := lif.ToStaticFrame(link.ID)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs2.AddFrame(frame, fs2.Frame(link.Parent)), test.ShouldBeNil)
+	}
+
+	flatten := func(geometries map[string]*GeometriesInFrame) []spatial.Geometry {
+		var all []spatial.Geometry
+		for _, gif := range geometries {
+			all = append(all, gif.Geometries()...)
+		}
+		return all
+	}
+	geometries, err := FrameSystemGeometries(fs, inputs)
+	test.That(t, err, test.ShouldBeNil)
+	geometries2, err := FrameSystemGeometries(fs2, StartPositions(fs2))
+	test.That(t, err, test.ShouldBeNil)
+	testGeometriesEquivalent(t, flatten(geometries), flatten(geometries2))
+
+	data, err := FrameSystemToURDF(fs, inputs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, strings.Count(string(data), "<joint "), test.ShouldEqual, len(cfg.Links))
+	_, err = ConvertURDFToConfig(data, "")
+	test.That(t, err, test.ShouldBeNil)
+}
@@ -14,6 +14,11 @@ type ModelConfig struct {
 	Links        []LinkConfig    `json:"links,omitempty"`
 	Joints       []JointConfig   `json:"joints,omitempty"`
 	DHParams     []DHParamConfig `json:"dhParams,omitempty"`
+	// AllowedCollisions are pairs of links of the model allowed to be in collision with each other.
+	AllowedCollisions []AllowedCollision `json:"allowed_collisions,omitempty"`
+	// IKSolver names the inverse kinematics solver motion planning uses for the model unless told otherwise. If left
+	// empty the default numerical solvers are used.
+	IKSolver string `json:"ik_solver,omitempty"`
 }
 
 // ParseConfig converts the ModelConfig struct into a full Model with the name modelName.
@@ -23,6 +28,12 @@ func (cfg *ModelConfig) ParseConfig(modelName string) (Model, error) {
 		modelName = cfg.Name
 	}
 
+	for _, allowed := range cfg.AllowedCollisions {
+		if err := allowed.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	model := NewSimpleModel(modelName)
 	model.modelConfig = cfg
 	transforms := map[string]Frame{}
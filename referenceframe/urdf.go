@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"math"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -24,28 +25,53 @@ type URDFConfig struct {
 
 // URDFLink is a struct which details the XML used in a URDF link element.
 type URDFLink struct {
-	XMLName   xml.Name `xml:"link"`
-	Name      string   `xml:"name,attr"`
-	Collision []struct {
-		XMLName xml.Name `xml:"collision"`
-		Name    string   `xml:"name,attr"`
-		Origin  struct {
-			XMLName xml.Name `xml:"origin"`
-			RPY     string   `xml:"rpy,attr"` // Fixed frame angle "r p y" format, in radians
-			XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-		} `xml:"origin"`
-		Geometry struct {
-			XMLName xml.Name `xml:"geometry"`
-			Box     struct {
-				XMLName xml.Name `xml:"box"`
-				Size    string   `xml:"size,attr"` // "x y z" format, in meters
-			} `xml:"box"`
-			Sphere struct {
-				XMLName xml.Name `xml:"sphere"`
-				Radius  float64  `xml:"radius,attr"` // in meters
-			} `xml:"sphere"`
-		} `xml:"geometry"`
-	} `xml:"collision"`
+	XMLName   xml.Name        `xml:"link"`
+	Name      string          `xml:"name,attr"`
+	Collision []URDFCollision `xml:"collision"`
+}
+
+// URDFCollision is a struct which details the XML used in a URDF collision element.
+type URDFCollision struct {
+	XMLName xml.Name `xml:"collision"`
+	Name    string   `xml:"name,attr,omitempty"`
+	Origin  struct {
+		XMLName xml.Name `xml:"origin"`
+		RPY     string   `xml:"rpy,attr,omitempty"` // Fixed frame angle "r p y" format, in radians
+		XYZ     string   `xml:"xyz,attr,omitempty"` // "x y z" format, in meters
+	} `xml:"origin"`
+	Geometry struct {
+		XMLName  xml.Name      `xml:"geometry"`
+		Box      *URDFBox      `xml:"box"`
+		Sphere   *URDFSphere   `xml:"sphere"`
+		Cylinder *URDFCylinder `xml:"cylinder"`
+		Mesh     *URDFMesh     `xml:"mesh"`
+	} `xml:"geometry"`
+}
+
+// URDFBox is a struct which details the XML used in a URDF box geometry element.
+type URDFBox struct {
+	XMLName xml.Name `xml:"box"`
+	Size    string   `xml:"size,attr"` // "x y z" format, in meters
+}
+
+// URDFSphere is a struct which details the XML used in a URDF sphere geometry element.
+type URDFSphere struct {
+	XMLName xml.Name `xml:"sphere"`
+	Radius  float64  `xml:"radius,attr"` // in meters
+}
+
+// URDFCylinder is a struct which details the XML used in a URDF cylinder geometry element.
+type URDFCylinder struct {
+	XMLName xml.Name `xml:"cylinder"`
+	Radius  float64  `xml:"radius,attr"` // in meters
+	Length  float64  `xml:"length,attr"` // in meters
+}
+
+// URDFMesh is a struct which details the XML used in a URDF mesh geometry element.
+type URDFMesh struct {
+	XMLName  xml.Name `xml:"mesh"`
+	Filename string   `xml:"filename,attr"`
+	Scale    string   `xml:"scale,attr,omitempty"` // "x y z" format
 }
 
 // URDFJoint is a struct which details the XML used in a URDF joint element.
@@ -55,8 +81,8 @@ type URDFJoint struct {
 	Type    string   `xml:"type,attr"`
 	Origin  struct {
 		XMLName xml.Name `xml:"origin"`
-		RPY     string   `xml:"rpy,attr"` // Fixed frame angle "r p y" format, in radians
-		XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
+		RPY     string   `xml:"rpy,attr,omitempty"` // Fixed frame angle "r p y" format, in radians
+		XYZ     string   `xml:"xyz,attr,omitempty"` // "x y z" format, in meters
 	} `xml:"origin"`
 	Parent struct {
 		XMLName xml.Name `xml:"parent"`
@@ -66,15 +92,23 @@ type URDFJoint struct {
 		XMLName xml.Name `xml:"child"`
 		Link    string   `xml:"link,attr"`
 	} `xml:"child"`
-	Axis struct {
-		XMLName xml.Name `xml:"axis"`
-		XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-	} `xml:"axis"`
-	Limit struct {
-		XMLName xml.Name `xml:"limit"`
-		Lower   float64  `xml:"lower,attr"` // translation limits are in meters, revolute limits are in radians
-		Upper   float64  `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
-	} `xml:"limit"`
+	Axis  *URDFAxis  `xml:"axis"`
+	Limit *URDFLimit `xml:"limit"`
+}
+
+// URDFAxis is a struct which details the XML used in a URDF joint axis element.
+type URDFAxis struct {
+	XMLName xml.Name `xml:"axis"`
+	XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
+}
+
+// URDFLimit is a struct which details the XML used in a URDF joint limit element.
+type URDFLimit struct {
+	XMLName  xml.Name `xml:"limit"`
+	Lower    float64  `xml:"lower,attr,omitempty"` // translation limits are in meters, revolute limits are in radians
+	Upper    float64  `xml:"upper,attr,omitempty"` // translation limits are in meters, revolute limits are in radians
+	Effort   float64  `xml:"effort,attr"`          // in newtons or newton meters
+	Velocity float64  `xml:"velocity,attr"`        // translation limits are in meters/s, revolute limits are in radians/s
 }
 
 // ParseURDFFile will read a given file and parse the contained URDF XML data into an equivalent ModelConfig struct.
@@ -90,9 +124,45 @@ func ParseURDFFile(filename, modelName string) (Model, error) {
 		return nil, err
 	}
 
+	// Mesh filenames are relative to the URDF file, or to a ROS package containing it
+	for _, link := range mc.Links {
+		if link.Geometry != nil && link.Geometry.Type == spatial.MeshType {
+			link.Geometry.MeshFile = resolveURDFMeshFile(filepath.Dir(filename), link.Geometry.MeshFile)
+		}
+	}
+
 	return mc.ParseConfig(modelName)
 }
 
+// resolveURDFMeshFile returns the path of a mesh file referenced by a URDF file in dir. Since ROS packages cannot be
+// looked up, a package:// filename is resolved relative to the closest ancestor of dir that contains the path within
+// the package.
+func resolveURDFMeshFile(dir, filename string) string {
+	switch {
+	case strings.HasPrefix(filename, "file://"):
+		filename = strings.TrimPrefix(filename, "file://")
+	case strings.HasPrefix(filename, "package://"):
+		rel := strings.TrimPrefix(filename, "package://")
+		// drop the package name
+		if idx := strings.Index(rel, "/"); idx >= 0 {
+			rel = rel[idx+1:]
+		}
+		for ancestor := dir; ; ancestor = filepath.Dir(ancestor) {
+			candidate := filepath.Join(ancestor, rel)
+			if _, err := os.Stat(candidate); err == nil {
+				return candidate
+			}
+			if ancestor == filepath.Dir(ancestor) {
+				return filepath.Join(dir, rel)
+			}
+		}
+	}
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	return filepath.Join(dir, filename)
+}
+
 // ConvertURDFToConfig will transfer the given URDF XML data into an equivalent ModelConfig. Direct unmarshaling in the
 // same fashion as ModelJSON is not possible, as URDF data will need to be evaluated to accommodate differences
 // between the two kinematics encoding schemes.
@@ -136,8 +206,15 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 
 		switch jointElem.Type {
 		case ContinuousJoint, RevoluteJoint, PrismaticJoint:
-			// Parse important details about each joint, including axes and limits
-			jointAxes := convStringAttrToFloats(jointElem.Axis.XYZ)
+			// Parse important details about each joint, including axes and limits, which default to the X axis and zero
+			jointAxes := []float64{1, 0, 0}
+			if jointElem.Axis != nil {
+				jointAxes = convStringAttrToFloats(jointElem.Axis.XYZ)
+			}
+			limit := jointElem.Limit
+			if limit == nil {
+				limit = &URDFLimit{}
+			}
 			thisJoint := JointConfig{
 				ID:     jointElem.Name,
 				Type:   jointElem.Type,
@@ -150,10 +227,13 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 			case ContinuousJoint:
 				thisJoint.Type = RevoluteJoint // Currently, we treate a continuous joint as a special case of a revolute joint
 				thisJoint.Min, thisJoint.Max = math.Inf(-1), math.Inf(1)
+				thisJoint.MaxVelocity = utils.RadToDeg(limit.Velocity)
 			case PrismaticJoint:
-				thisJoint.Min, thisJoint.Max = metersToMM(jointElem.Limit.Lower), metersToMM(jointElem.Limit.Upper)
+				thisJoint.Min, thisJoint.Max = metersToMM(limit.Lower), metersToMM(limit.Upper)
+				thisJoint.MaxVelocity = metersToMM(limit.Velocity)
 			case RevoluteJoint:
-				thisJoint.Min, thisJoint.Max = utils.RadToDeg(jointElem.Limit.Lower), utils.RadToDeg(jointElem.Limit.Upper)
+				thisJoint.Min, thisJoint.Max = utils.RadToDeg(limit.Lower), utils.RadToDeg(limit.Upper)
+				thisJoint.MaxVelocity = utils.RadToDeg(limit.Velocity)
 			default:
 				return nil, err
 			}
@@ -161,16 +241,16 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 			mc.Joints = append(mc.Joints, thisJoint)
 
 			// Generate child link translation and orientation data, which is held by this joint per the URDF design
-			childXYZ := convStringAttrToFloats(jointElem.Origin.XYZ)
-			childRPY := convStringAttrToFloats(jointElem.Origin.RPY)
-			childEA := spatial.EulerAngles{Roll: childRPY[0], Pitch: childRPY[1], Yaw: childRPY[2]}
+			childXYZ := convStringAttrToVector(jointElem.Origin.XYZ)
+			childRPY := convStringAttrToVector(jointElem.Origin.RPY)
+			childEA := spatial.EulerAngles{Roll: childRPY.X, Pitch: childRPY.Y, Yaw: childRPY.Z}
 			childOrient, err := spatial.NewOrientationConfig(childEA.AxisAngles())
 
 			// Note the conversion from meters to mm
 			childLink.Translation = r3.Vector{
-				metersToMM(childXYZ[0]),
-				metersToMM(childXYZ[1]),
-				metersToMM(childXYZ[2]),
+				metersToMM(childXYZ.X),
+				metersToMM(childXYZ.Y),
+				metersToMM(childXYZ.Z),
 			}
 			childLink.Orientation = childOrient
 
@@ -181,16 +261,16 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 			// Handle fixed joint -> static link conversion instead of adding to Joints[]
 			thisLink := LinkConfig{ID: jointElem.Name, Parent: jointElem.Parent.Link}
 
-			linkXYZ := convStringAttrToFloats(jointElem.Origin.XYZ)
-			linkRPY := convStringAttrToFloats(jointElem.Origin.RPY)
-			linkEA := spatial.EulerAngles{Roll: linkRPY[0], Pitch: linkRPY[1], Yaw: linkRPY[2]}
+			linkXYZ := convStringAttrToVector(jointElem.Origin.XYZ)
+			linkRPY := convStringAttrToVector(jointElem.Origin.RPY)
+			linkEA := spatial.EulerAngles{Roll: linkRPY.X, Pitch: linkRPY.Y, Yaw: linkRPY.Z}
 			linkOrient, err := spatial.NewOrientationConfig(linkEA.AxisAngles())
 
 			// Note the conversion from meters to mm
 			thisLink.Translation = r3.Vector{
-				metersToMM(linkXYZ[0]),
-				metersToMM(linkXYZ[1]),
-				metersToMM(linkXYZ[2]),
+				metersToMM(linkXYZ.X),
+				metersToMM(linkXYZ.Y),
+				metersToMM(linkXYZ.Z),
 			}
 			thisLink.Orientation = linkOrient
 
@@ -247,6 +327,192 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 	return mc, nil
 }
 
+// ConvertConfigToURDF will transfer the given ModelConfig into equivalent URDF XML data. Every link and joint in the
+// config becomes a URDF joint of the same name, whose child URDF link is named after it with a "_link" suffix and carries
+// its geometry. Frames parented to the world are attached to a root link named "world".
+// Capsules have no URDF equivalent and are written as a cylinder capped by two spheres, while points have no volume
+// and are omitted.
+func ConvertConfigToURDF(cfg *ModelConfig) ([]byte, error) {
+	// DH parameters are converted to the equivalent links and joints by building the model they describe
+	if cfg.KinParamType == "DH" {
+		model, err := cfg.ParseConfig(cfg.Name)
+		if err != nil {
+			return nil, err
+		}
+		if cfg, err = NewModelConfig(model); err != nil {
+			return nil, err
+		}
+	}
+
+	urdf := &URDFConfig{Name: cfg.Name, Links: []URDFLink{{Name: World}}}
+	linkNames := urdfLinkNames(cfg)
+
+	for _, link := range cfg.Links {
+		pose, err := link.Pose()
+		if err != nil {
+			return nil, err
+		}
+		parentLink, ok := linkNames[link.Parent]
+		if !ok {
+			return nil, NewParentFrameMissingError(link.ID, link.Parent)
+		}
+		urdfLink := URDFLink{Name: linkNames[link.ID]}
+		if link.Geometry != nil {
+			// Link geometries are placed before the link's transform, but URDF collisions are placed after it
+			urdfLink.Collision, err = createCollisionsFromConfig(link.Geometry, spatial.PoseInverse(pose))
+			if err != nil {
+				return nil, err
+			}
+		}
+		joint := URDFJoint{Name: link.ID, Type: FixedJoint}
+		joint.Parent.Link = parentLink
+		joint.Child.Link = urdfLink.Name
+		joint.Origin.XYZ, joint.Origin.RPY = convPoseToStringAttrs(pose)
+
+		urdf.Links = append(urdf.Links, urdfLink)
+		urdf.Joints = append(urdf.Joints, joint)
+	}
+
+	for _, jointCfg := range cfg.Joints {
+		parentLink, ok := linkNames[jointCfg.Parent]
+		if !ok {
+			return nil, NewParentFrameMissingError(jointCfg.ID, jointCfg.Parent)
+		}
+		urdfLink := URDFLink{Name: linkNames[jointCfg.ID]}
+		joint := URDFJoint{Name: jointCfg.ID, Type: jointCfg.Type}
+		joint.Parent.Link = parentLink
+		joint.Child.Link = urdfLink.Name
+		joint.Axis = &URDFAxis{XYZ: convVectorToStringAttr(r3.Vector(jointCfg.Axis).Normalize())}
+
+		switch jointCfg.Type {
+		case RevoluteJoint:
+			joint.Limit = &URDFLimit{Velocity: utils.DegToRad(jointCfg.MaxVelocity)}
+			if math.IsInf(jointCfg.Min, -1) && math.IsInf(jointCfg.Max, 1) {
+				joint.Type = ContinuousJoint
+			} else {
+				joint.Limit.Lower, joint.Limit.Upper = utils.DegToRad(jointCfg.Min), utils.DegToRad(jointCfg.Max)
+			}
+		case PrismaticJoint:
+			joint.Limit = &URDFLimit{
+				Lower:    mmToMeters(jointCfg.Min),
+				Upper:    mmToMeters(jointCfg.Max),
+				Velocity: mmToMeters(jointCfg.MaxVelocity),
+			}
+			if jointCfg.Geometry != nil {
+				// The geometry of a prismatic joint moves with it
+				var err error
+				urdfLink.Collision, err = createCollisionsFromConfig(jointCfg.Geometry, spatial.NewZeroPose())
+				if err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, NewUnsupportedJointTypeError(jointCfg.Type)
+		}
+
+		urdf.Links = append(urdf.Links, urdfLink)
+		urdf.Joints = append(urdf.Joints, joint)
+	}
+
+	xmlData, err := xml.MarshalIndent(urdf, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert ModelConfig to URDF data")
+	}
+	return append([]byte(xml.Header), xmlData...), nil
+}
+
+// urdfLinkNames names the URDF link at the end of each link and joint in the config after it, with a "_link" suffix. Since
+// links and joints share a namespace when converted back to a ModelConfig, the suffix is repeated for any name in use.
+func urdfLinkNames(cfg *ModelConfig) map[string]string {
+	ids := map[string]bool{}
+	for _, link := range cfg.Links {
+		ids[link.ID] = true
+	}
+	for _, joint := range cfg.Joints {
+		ids[joint.ID] = true
+	}
+	linkNames := map[string]string{"": World, World: World}
+	used := map[string]bool{World: true}
+	addName := func(id string) {
+		name := id + "_link"
+		for ids[name] || used[name] {
+			name += "_link"
+		}
+		linkNames[id] = name
+		used[name] = true
+	}
+	for _, link := range cfg.Links {
+		addName(link.ID)
+	}
+	for _, joint := range cfg.Joints {
+		addName(joint.ID)
+	}
+	return linkNames
+}
+
+// Convenience method to create URDF collision elements from a geometry config, re-expressed by the given transform.
+func createCollisionsFromConfig(geoCfg *spatial.GeometryConfig, transform spatial.Pose) ([]URDFCollision, error) {
+	orientation, err := geoCfg.OrientationOffset.ParseConfig()
+	if err != nil {
+		return nil, err
+	}
+	offset := spatial.Compose(transform, spatial.NewPose(geoCfg.TranslationOffset, orientation))
+
+	newCollision := func(pose spatial.Pose) URDFCollision {
+		collision := URDFCollision{Name: geoCfg.Label}
+		collision.Origin.XYZ, collision.Origin.RPY = convPoseToStringAttrs(pose)
+		return collision
+	}
+
+	collision := newCollision(offset)
+	switch geoCfg.Type {
+	case spatial.BoxType:
+		collision.Geometry.Box = &URDFBox{Size: convVectorToStringAttr(r3.Vector{geoCfg.X, geoCfg.Y, geoCfg.Z}.Mul(mmToMeters(1)))}
+	case spatial.SphereType:
+		collision.Geometry.Sphere = &URDFSphere{Radius: mmToMeters(geoCfg.R)}
+	case spatial.CylinderType:
+		collision.Geometry.Cylinder = &URDFCylinder{Radius: mmToMeters(geoCfg.R), Length: mmToMeters(geoCfg.L)}
+	case spatial.CapsuleType:
+		// The spheres capping the cylinder are centered on the ends of the capsule's segment
+		halfSegment := geoCfg.L/2 - geoCfg.R
+		collision.Geometry.Cylinder = &URDFCylinder{Radius: mmToMeters(geoCfg.R), Length: mmToMeters(2 * halfSegment)}
+		collisions := []URDFCollision{collision}
+		for _, sign := range []float64{-1, 1} {
+			endCap := newCollision(spatial.Compose(offset, spatial.NewPoseFromPoint(r3.Vector{Z: sign * halfSegment})))
+			endCap.Geometry.Sphere = &URDFSphere{Radius: mmToMeters(geoCfg.R)}
+			collisions = append(collisions, endCap)
+		}
+		return collisions, nil
+	case spatial.MeshType:
+		// Mesh vertices are scaled to mm by a config, but to meters by a URDF
+		scale := geoCfg.MeshScale
+		if scale.Norm2() == 0 {
+			scale = r3.Vector{1, 1, 1}
+		}
+		collision.Geometry.Mesh = &URDFMesh{Filename: geoCfg.MeshFile, Scale: convVectorToStringAttr(scale.Mul(mmToMeters(1)))}
+	case spatial.PointType:
+		return nil, nil
+	default:
+		return nil, errors.Wrapf(spatial.ErrGeometryTypeUnsupported, "cannot convert %s geometry to URDF", geoCfg.Type)
+	}
+	return []URDFCollision{collision}, nil
+}
+
+// Convenience method to format a pose as the xyz and rpy attributes of a URDF origin element, in meters and radians.
+func convPoseToStringAttrs(pose spatial.Pose) (string, string) {
+	ea := pose.Orientation().EulerAngles()
+	return convVectorToStringAttr(pose.Point().Mul(mmToMeters(1))), convVectorToStringAttr(r3.Vector{ea.Roll, ea.Pitch, ea.Yaw})
+}
+
+// Convenience method to format a vector as a space-delimited "x y z" attribute in URDFs.
+func convVectorToStringAttr(vec r3.Vector) string {
+	return strings.Join([]string{
+		strconv.FormatFloat(vec.X, 'g', -1, 64),
+		strconv.FormatFloat(vec.Y, 'g', -1, 64),
+		strconv.FormatFloat(vec.Z, 'g', -1, 64),
+	}, " ")
+}
+
 // Convenience method to split up space-delimited fields in URDFs, such as xyz or rpy attributes.
 func convStringAttrToFloats(attr string) []float64 {
 	var converted []float64
@@ -266,54 +532,103 @@ func convStringAttrToFloats(attr string) []float64 {
 
 // Convenience method to simplify creating geometry configs from URDF XML that has a collision element specified.
 func createConfigFromCollision(link URDFLink) (spatial.GeometryConfig, error) {
-	var geoCfg spatial.GeometryConfig
-	boxGeometry := link.Collision[0].Geometry.Box
-	sphereGeometry := link.Collision[0].Geometry.Sphere
-
-	// Offset for the geometry origin from the reference link origin
-	geomXYZ := convStringAttrToFloats(link.Collision[0].Origin.XYZ)
-	geomTx := r3.Vector{geomXYZ[0], geomXYZ[1], geomXYZ[2]}
-	geomRPY := convStringAttrToFloats(link.Collision[0].Origin.RPY)
-	geomEA := spatial.EulerAngles{
-		Roll:  utils.RadToDeg(geomRPY[0]),
-		Pitch: utils.RadToDeg(geomRPY[1]),
-		Yaw:   utils.RadToDeg(geomRPY[2]),
+	if geoCfg, ok := createCapsuleConfigFromCollisions(link.Collision); ok {
+		return geoCfg, nil
 	}
-	geomOx, err := spatial.NewOrientationConfig(geomEA.AxisAngles())
+	collision := link.Collision[0]
+	geometry := collision.Geometry
+
+	// Offset for the geometry origin from the reference link origin, which may be omitted. Like joint origins, it is
+	// in meters with fixed frame angles in radians.
+	offset := convOriginToPose(collision.Origin.XYZ, collision.Origin.RPY)
+	geomOx, err := spatial.NewOrientationConfig(offset.Orientation())
 	if err != nil {
 		return spatial.GeometryConfig{}, err
 	}
+	geoCfg := spatial.GeometryConfig{TranslationOffset: offset.Point(), OrientationOffset: *geomOx}
 
 	// Logic specific to the geometry type
 	switch {
-	case len(boxGeometry.Size) > 0:
-		boxDims := convStringAttrToFloats(boxGeometry.Size)
-		geoCfg = spatial.GeometryConfig{
-			Type:              "box",
-			X:                 metersToMM(boxDims[0]),
-			Y:                 metersToMM(boxDims[1]),
-			Z:                 metersToMM(boxDims[2]),
-			TranslationOffset: geomTx,
-			OrientationOffset: *geomOx,
-			Label:             "box",
-		}
-	case sphereGeometry.Radius > 0:
-		sphereRadius := metersToMM(sphereGeometry.Radius)
-		geoCfg = spatial.GeometryConfig{
-			Type:              "sphere",
-			R:                 sphereRadius,
-			TranslationOffset: geomTx,
-			OrientationOffset: *geomOx,
-			Label:             "sphere",
+	case geometry.Box != nil:
+		boxDims := convStringAttrToFloats(geometry.Box.Size)
+		geoCfg.Type = spatial.BoxType
+		geoCfg.X, geoCfg.Y, geoCfg.Z = metersToMM(boxDims[0]), metersToMM(boxDims[1]), metersToMM(boxDims[2])
+	case geometry.Sphere != nil:
+		geoCfg.Type = spatial.SphereType
+		geoCfg.R = metersToMM(geometry.Sphere.Radius)
+	case geometry.Cylinder != nil:
+		geoCfg.Type = spatial.CylinderType
+		geoCfg.R = metersToMM(geometry.Cylinder.Radius)
+		geoCfg.L = metersToMM(geometry.Cylinder.Length)
+	case geometry.Mesh != nil:
+		// Mesh vertices are in meters
+		scale := r3.Vector{1, 1, 1}
+		if geometry.Mesh.Scale != "" {
+			scale = convStringAttrToVector(geometry.Mesh.Scale)
 		}
+		geoCfg.Type = spatial.MeshType
+		geoCfg.MeshFile = geometry.Mesh.Filename
+		geoCfg.MeshScale = scale.Mul(metersToMM(1))
 	default:
-		return spatial.GeometryConfig{}, errors.Errorf("Unsupported collision geometry type detected for [ %v ] link", link.Collision[0].Name)
+		return spatial.GeometryConfig{}, errors.Errorf("Unsupported collision geometry type detected for [ %v ] link", collision.Name)
 	}
+	geoCfg.Label = string(geoCfg.Type)
 
 	return geoCfg, nil
 }
 
+// Convenience method to recognize the cylinder capped by two spheres which ConvertConfigToURDF writes for a capsule.
+func createCapsuleConfigFromCollisions(collisions []URDFCollision) (spatial.GeometryConfig, bool) {
+	if len(collisions) != 3 || collisions[0].Geometry.Cylinder == nil {
+		return spatial.GeometryConfig{}, false
+	}
+	cylinder := collisions[0].Geometry.Cylinder
+	offset := convOriginToPose(collisions[0].Origin.XYZ, collisions[0].Origin.RPY)
+	for i, sign := range []float64{-1, 1} {
+		sphere := collisions[i+1].Geometry.Sphere
+		if sphere == nil || !utils.Float64AlmostEqual(sphere.Radius, cylinder.Radius, 1e-9) {
+			return spatial.GeometryConfig{}, false
+		}
+		center := spatial.Compose(offset, spatial.NewPoseFromPoint(r3.Vector{Z: sign * metersToMM(cylinder.Length) / 2}))
+		if !spatial.R3VectorAlmostEqual(center.Point(), convOriginToPose(collisions[i+1].Origin.XYZ, "").Point(), 1e-6) {
+			return spatial.GeometryConfig{}, false
+		}
+	}
+	orientation, err := spatial.NewOrientationConfig(offset.Orientation())
+	if err != nil {
+		return spatial.GeometryConfig{}, false
+	}
+	return spatial.GeometryConfig{
+		Type:              spatial.CapsuleType,
+		R:                 metersToMM(cylinder.Radius),
+		L:                 metersToMM(cylinder.Length + 2*cylinder.Radius),
+		TranslationOffset: offset.Point(),
+		OrientationOffset: *orientation,
+		Label:             string(spatial.CapsuleType),
+	}, true
+}
+
+// Convenience method to parse the xyz and rpy attributes of a URDF origin element into a pose, in mm.
+func convOriginToPose(xyz, rpy string) spatial.Pose {
+	angles := convStringAttrToVector(rpy)
+	return spatial.NewPose(
+		convStringAttrToVector(xyz).Mul(metersToMM(1)),
+		&spatial.EulerAngles{Roll: angles.X, Pitch: angles.Y, Yaw: angles.Z},
+	)
+}
+
+// Convenience method to parse space-delimited "x y z" attributes in URDFs, which are zero if omitted.
+func convStringAttrToVector(attr string) r3.Vector {
+	values := append(convStringAttrToFloats(attr), 0, 0, 0)
+	return r3.Vector{values[0], values[1], values[2]}
+}
+
 // Convenience function to change engineering unit scale for the given input.
 func metersToMM(valMeters float64) float64 {
 	return valMeters * 1000
 }
+
+// Convenience function to change engineering unit scale for the given input.
+func mmToMeters(valMM float64) float64 {
+	return valMM / 1000
+}
@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 
 	spatial "go.viam.com/rdk/spatialmath"
@@ -86,3 +87,69 @@ func TestURDFGeometries(t *testing.T) {
 	modelGeo, _ = ur5ViamModel.Geometries(inputs)
 	test.That(t, len(modelGeo.geometries), test.ShouldEqual, 5)
 }
+
+func TestURDFCylinderAndMeshGeometries(t *testing.T) {
+	u, err := ParseURDFFile(utils.ResolveFile("referenceframe/testurdf/mesh_arm.urdf"), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	geometries, err := u.Geometries([]Input{{0}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(geometries.Geometries()), test.ShouldEqual, 3)
+
+	// the upper arm is a cylinder lying along the X axis
+	upperArm := geometries.GeometryByName(u.Name() + ":upper_arm_link")
+	test.That(t, upperArm, test.ShouldNotBeNil)
+	for _, pt := range []r3.Vector{{X: 10}, {X: 390}, {X: 200, Z: 39}} {
+		inside, err := spatial.NewPoint(pt, "").EncompassedBy(upperArm)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inside, test.ShouldBeTrue)
+	}
+
+	// the tool is a 100mm cube mesh, stretched to 200mm in Z, at the end of the upper arm
+	tool := geometries.GeometryByName(u.Name() + ":tool_link")
+	test.That(t, tool, test.ShouldNotBeNil)
+	dist, err := tool.DistanceFrom(spatial.NewPoint(r3.Vector{X: 400, Z: 200}, ""))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dist, test.ShouldAlmostEqual, 100)
+}
+
+func TestURDFCollisionOrigin(t *testing.T) {
+	// A 0.2 x 0.1 x 0.1 m box, offset 0.1 m in Z and rotated a quarter turn about Z so its long side lies along Y.
+	xmlData := []byte(`<robot name="origin_test">
+  <link name="base_link">
+    <collision>
+      <origin xyz="0 0 0.1" rpy="0 0 1.5707963267948966"/>
+      <geometry>
+        <box size="0.2 0.1 0.1"/>
+      </geometry>
+    </collision>
+  </link>
+</robot>`)
+	cfg, err := ConvertURDFToConfig(xmlData, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(cfg.Links), test.ShouldEqual, 1)
+	geoCfg := cfg.Links[0].Geometry
+	test.That(t, geoCfg, test.ShouldNotBeNil)
+
+	// The offset is converted from meters and the angles are read as radians.
+	test.That(t, spatial.R3VectorAlmostEqual(geoCfg.TranslationOffset, r3.Vector{Z: 100}, 1e-9), test.ShouldBeTrue)
+	orientation, err := geoCfg.OrientationOffset.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.OrientationAlmostEqual(orientation, &spatial.OrientationVectorDegrees{OZ: 1, Theta: 90}), test.ShouldBeTrue)
+
+	geometry, err := geoCfg.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	for _, tc := range []struct {
+		pt     r3.Vector
+		inside bool
+	}{
+		{r3.Vector{Y: 90, Z: 100}, true},
+		{r3.Vector{X: 90, Z: 100}, false},
+		{r3.Vector{Z: 140}, true},
+		{r3.Vector{Z: 40}, false},
+	} {
+		inside, err := spatial.NewPoint(tc.pt, "").EncompassedBy(geometry)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inside, test.ShouldEqual, tc.inside)
+	}
+}
@@ -243,6 +243,7 @@ func (r *localRobot) Status(ctx context.Context, resourceNames []resource.Name)
 		}
 		resources[name] = res
 	}
+	moduleStatuses := r.manager.degradedModuleStatuses()
 	r.mu.Unlock()
 
 	namesToDedupe := resourceNames
@@ -324,6 +325,14 @@ func (r *localRobot) Status(ctx context.Context, resourceNames []resource.Name)
 					return nil, errors.Wrapf(err, "failed to get status from %q", name)
 				}
 			}
+			// surface the state of a module that is not running normally in the
+			// statuses of the resources it serves
+			if modStatus, ok := moduleStatuses[name]; ok {
+				status, err = withModuleStatus(status, modStatus)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to add module status to %q", name)
+				}
+			}
 			resourceStatus = robot.Status{Name: name, Status: status}
 		}
 		statuses = append(statuses, resourceStatus)
@@ -790,7 +799,11 @@ func (r *localRobot) getLocalFrameSystemParts() ([]*referenceframe.FrameSystemPa
 			return nil, err
 		}
 
-		parts = append(parts, &referenceframe.FrameSystemPart{FrameConfig: lif, ModelFrame: model})
+		part := &referenceframe.FrameSystemPart{FrameConfig: lif, ModelFrame: model}
+		for _, name := range component.Frame.AllowedCollisions {
+			part.AllowedCollisions = append(part.AllowedCollisions, referenceframe.AllowedCollision{Frame1: cfgCopy.ID, Frame2: name})
+		}
+		parts = append(parts, part)
 	}
 	return parts, nil
 }
@@ -8,12 +8,14 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	"go.viam.com/utils/pexec"
+	vprotoutils "go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/config"
@@ -1025,6 +1027,44 @@ func (manager *resourceManager) markResourcesRemoved(
 	return resourcesToCloseBeforeComplete
 }
 
+// degradedModuleStatuses returns the statuses of modules that are not running
+// normally, keyed by the resources they serve.
+func (manager *resourceManager) degradedModuleStatuses() map[resource.Name]modif.ModuleStatus {
+	statuses := map[resource.Name]modif.ModuleStatus{}
+	if manager.moduleManager == nil {
+		return statuses
+	}
+	for _, status := range manager.moduleManager.Statuses() {
+		if status.State == modif.ModuleStateRunning {
+			continue
+		}
+		for _, name := range status.Resources {
+			statuses[name] = status
+		}
+	}
+	return statuses
+}
+
+// withModuleStatus adds the status of the module serving a resource to the
+// resource's status under the "module" key.
+func withModuleStatus(status interface{}, modStatus modif.ModuleStatus) (map[string]interface{}, error) {
+	statusMap, err := vprotoutils.InterfaceToMap(status)
+	if err != nil {
+		return nil, err
+	}
+	moduleMap := map[string]interface{}{
+		"name":                  modStatus.Name,
+		"state":                 string(modStatus.State),
+		"restarts":              modStatus.Restarts,
+		"health_check_failures": modStatus.HealthCheckFailures,
+	}
+	if !modStatus.LastRestart.IsZero() {
+		moduleMap["last_restart"] = modStatus.LastRestart.UTC().Format(time.RFC3339Nano)
+	}
+	statusMap["module"] = moduleMap
+	return statusMap, nil
+}
+
 // removeOrphanedResources is called by the module manager to remove resources
 // orphaned due to module crashes.
 func (manager *resourceManager) removeOrphanedResources(ctx context.Context,
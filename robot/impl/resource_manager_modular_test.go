@@ -446,6 +446,12 @@ func (m *dummyModMan) Provides(cfg resource.Config) bool {
 	return cfg.Name != "builtin"
 }
 
+func (m *dummyModMan) Statuses() []modmaninterface.ModuleStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return nil
+}
+
 func (m *dummyModMan) ValidateConfig(ctx context.Context, cfg resource.Config) ([]string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -8,12 +8,12 @@ import (
 	"path/filepath"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	clk "github.com/benbjohnson/clock"
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
-	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/internal"
 	"go.viam.com/rdk/internal/cloud"
@@ -74,11 +74,64 @@ type Config struct {
 	ScheduledSyncDisabled bool                             `json:"sync_disabled"`
 	Tags                  []string                         `json:"tags"`
 	ResourceConfigs       []*datamanager.DataCaptureConfig `json:"resource_configs"`
+	Retention             *RetentionConfig                 `json:"retention,omitempty"`
+	SyncTargets           []datasync.TargetConfig          `json:"sync_targets,omitempty"`
+	DefaultSyncTarget     string                           `json:"default_sync_target,omitempty"`
+	CaptureCompression    string                           `json:"capture_compression,omitempty"`
+	Encryption            *EncryptionConfig                `json:"encryption,omitempty"`
+	SyncPolicy            *SyncPolicyConfig                `json:"sync_policy,omitempty"`
 }
 
 // components will be depended upon weakly due to the above matcher.
 func (c *Config) Validate(path string) ([]string, error) {
-	return []string{cloud.InternalServiceName.String()}, nil
+	names := map[string]bool{}
+	for idx, target := range c.SyncTargets {
+		targetPath := fmt.Sprintf("%s.sync_targets.%d", path, idx)
+		if err := target.Validate(targetPath); err != nil {
+			return nil, err
+		}
+		if names[target.Name] {
+			return nil, errors.Errorf("%s: duplicate sync target name %q", targetPath, target.Name)
+		}
+		if target.Name == datasync.CloudTargetName && target.Type != datasync.TargetTypeCloud {
+			return nil, errors.Errorf("%s: sync target name %q is reserved for the cloud", targetPath, target.Name)
+		}
+		names[target.Name] = true
+	}
+	names[datasync.CloudTargetName] = true
+	if err := (datacapture.EncodingOptions{Compression: c.CaptureCompression}).Validate(); err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	if c.Encryption != nil {
+		if err := c.Encryption.Validate(path + ".encryption"); err != nil {
+			return nil, err
+		}
+	}
+	if c.SyncPolicy != nil {
+		if err := c.SyncPolicy.Validate(path + ".sync_policy"); err != nil {
+			return nil, err
+		}
+	}
+	if c.DefaultSyncTarget != "" && !names[c.DefaultSyncTarget] {
+		return nil, errors.Errorf("%s: unknown default sync target %q", path, c.DefaultSyncTarget)
+	}
+	for idx, resConf := range c.ResourceConfigs {
+		if resConf.SyncTarget != "" && !names[resConf.SyncTarget] {
+			return nil, errors.Errorf("%s.resource_configs.%d: unknown sync target %q", path, idx, resConf.SyncTarget)
+		}
+		if resConf.ChangeDetection == nil {
+			continue
+		}
+		changePath := fmt.Sprintf("%s.resource_configs.%d.change_detection", path, idx)
+		if err := resConf.ChangeDetection.Validate(changePath); err != nil {
+			return nil, err
+		}
+	}
+	deps, err := triggerDependencies(path, c.ResourceConfigs)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{cloud.InternalServiceName.String()}, deps...), nil
 }
 
 // builtIn initializes and orchestrates data capture collectors for registered component/methods.
@@ -88,6 +141,7 @@ type builtIn struct {
 	captureDir                  string
 	captureDisabled             bool
 	collectors                  map[componentMethodMetadata]*collectorAndConfig
+	captureEncoding             datacapture.EncodingOptions
 	lock                        sync.Mutex
 	backgroundWorkers           sync.WaitGroup
 	waitAfterLastModifiedMillis int
@@ -102,6 +156,21 @@ type builtIn struct {
 	cloudConnSvc        cloud.ConnectionService
 	cloudConn           rpc.ClientConn
 	syncTicker          *clk.Ticker
+	syncTargetConfigs   []datasync.TargetConfig
+	defaultSyncTarget   string
+	syncTargetRoutes    map[string]string
+	syncPolicy          *SyncPolicyConfig
+
+	retention         *retentionManager
+	retentionCancelFn context.CancelFunc
+	retentionWorkers  sync.WaitGroup
+
+	// The collectors to run once free disk space is back above the retention floor, and whether they are stopped
+	// until then.
+	captureConfigs           []*datamanager.DataCaptureConfig
+	captureDeps              resource.Dependencies
+	captureStoppedBelowFloor atomic.Bool
+	floorWorkers             sync.WaitGroup
 }
 
 var viamCaptureDotDir = filepath.Join(os.Getenv("HOME"), ".viam", "capture")
@@ -123,6 +192,7 @@ func NewBuiltIn(
 		tags:                        []string{},
 		waitAfterLastModifiedMillis: 10000,
 		syncerConstructor:           datasync.NewManager,
+		retention:                   newRetentionManager(logger),
 	}
 
 	if err := svc.Reconfigure(ctx, deps, conf); err != nil {
@@ -137,9 +207,11 @@ func (svc *builtIn) Close(_ context.Context) error {
 	svc.closeCollectors()
 	svc.closeSyncer()
 	svc.cancelSyncScheduler()
+	svc.cancelRetentionScheduler()
 
 	svc.lock.Unlock()
 	svc.backgroundWorkers.Wait()
+	svc.floorWorkers.Wait()
 	return nil
 }
 
@@ -150,7 +222,7 @@ func (svc *builtIn) closeCollectors() {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			currCollector.Collector.Close()
+			currCollector.close()
 		}()
 		delete(svc.collectors, md)
 	}
@@ -174,6 +246,15 @@ func (svc *builtIn) flushCollectors() {
 type collectorAndConfig struct {
 	Collector data.Collector
 	Config    datamanager.DataCaptureConfig
+	Trigger   *conditionTrigger
+}
+
+// close closes the collector and stops evaluating its trigger, if any.
+func (c *collectorAndConfig) close() {
+	c.Collector.Close()
+	if c.Trigger != nil {
+		c.Trigger.Close()
+	}
 }
 
 // Identifier for a particular collector: component name, component model, component type,
@@ -198,6 +279,7 @@ func getDurationFromHz(captureFrequencyHz float32) time.Duration {
 func (svc *builtIn) initializeOrUpdateCollector(
 	md componentMethodMetadata,
 	config *datamanager.DataCaptureConfig,
+	deps resource.Dependencies,
 ) (
 	*collectorAndConfig, error,
 ) {
@@ -215,13 +297,21 @@ func (svc *builtIn) initializeOrUpdateCollector(
 
 	// TODO(DATA-451): validate method params
 
+	var triggerConditions []triggerCondition
+	if config.Trigger != nil {
+		if triggerConditions, err = resolveTriggerConditions(config.Trigger, deps); err != nil {
+			return nil, err
+		}
+	}
+
 	if storedCollectorAndConfig, ok := svc.collectors[md]; ok {
-		if storedCollectorAndConfig.Config.Equals(config) {
+		if storedCollectorAndConfig.Config.Equals(config) &&
+			(storedCollectorAndConfig.Trigger == nil || storedCollectorAndConfig.Trigger.usesResources(triggerConditions)) {
 			// If the attributes have not changed, do nothing and leave the existing collector.
 			return svc.collectors[md], nil
 		} else {
 			// If the attributes have changed, close the existing collector.
-			storedCollectorAndConfig.Collector.Close()
+			storedCollectorAndConfig.close()
 		}
 	}
 
@@ -251,8 +341,7 @@ func (svc *builtIn) initializeOrUpdateCollector(
 	}
 
 	// Create a collector for this resource and method.
-	targetDir := filepath.Join(svc.captureDir, captureMetadata.GetComponentType(), captureMetadata.GetComponentName(),
-		captureMetadata.GetMethodName())
+	targetDir := captureTargetDir(svc.captureDir, config)
 	if err := os.MkdirAll(targetDir, 0o700); err != nil {
 		return nil, err
 	}
@@ -260,19 +349,50 @@ func (svc *builtIn) initializeOrUpdateCollector(
 		ComponentName: config.Name.ShortName(),
 		Interval:      interval,
 		MethodParams:  methodParams,
-		Target:        datacapture.NewBuffer(targetDir, captureMetadata),
+		Target:        datacapture.NewEncodedBuffer(targetDir, captureMetadata, svc.captureEncoding),
 		QueueSize:     captureQueueSize,
 		BufferSize:    captureBufferSize,
 		Logger:        svc.logger,
 		Clock:         clock,
 	}
+	if config.ChangeDetection != nil {
+		params.Filter = newChangeFilter(config.ChangeDetection)
+	}
+	var trigger *conditionTrigger
+	if config.Trigger != nil {
+		trigger = newConditionTrigger(config.Trigger, triggerConditions, svc.logger)
+		params.Trigger = trigger
+		params.TriggeredInterval = getDurationFromHz(config.Trigger.TriggeredFrequencyHz)
+		params.PreTriggerWindow = time.Duration(config.Trigger.PreTriggerSecs * float64(time.Second))
+	}
 	collector, err := (*collectorConstructor)(config.Resource, params)
 	if err != nil {
+		if trigger != nil {
+			trigger.Close()
+		}
 		return nil, err
 	}
 	collector.Collect()
 
-	return &collectorAndConfig{collector, *config}, nil
+	return &collectorAndConfig{Collector: collector, Config: *config, Trigger: trigger}, nil
+}
+
+// captureTargetDir returns the directory the collector for config writes its data capture files to.
+func captureTargetDir(captureDir string, config *datamanager.DataCaptureConfig) string {
+	return filepath.Join(captureDir, config.Name.API.String(), config.Name.ShortName(), config.Method)
+}
+
+// newChangeFilter returns a filter that only keeps readings that have changed as described by conf.
+func newChangeFilter(conf *datamanager.ChangeDetectionConfig) *data.ChangeFilter {
+	deadbands := make(map[string]data.Deadband, len(conf.Deadbands))
+	for field, deadband := range conf.Deadbands {
+		deadbands[field] = data.Deadband{Absolute: deadband.Absolute, Relative: deadband.Relative}
+	}
+	return data.NewChangeFilter(data.ChangeFilterConfig{
+		Deadbands:         deadbands,
+		FrameHashDistance: conf.FrameHashDistance,
+		MaxSuppressed:     time.Duration(conf.MaxSuppressedSecs * float64(time.Second)),
+	})
 }
 
 func (svc *builtIn) closeSyncer() {
@@ -292,30 +412,131 @@ func (svc *builtIn) initSyncer(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, grpcConnectionTimeout)
 	defer cancel()
 
-	identity, conn, err := svc.cloudConnSvc.AcquireConnection(ctx)
-	if errors.Is(err, cloud.ErrNotCloudManaged) {
-		svc.logger.Debug("Using no-op sync manager when not cloud managed")
-		svc.syncer = datasync.NewNoopManager()
+	targetConfigs := svc.syncTargetConfigsByName()
+	requiresCloud := false
+	for _, name := range svc.referencedSyncTargets() {
+		if conf, ok := targetConfigs[name]; ok && conf.Type == datasync.TargetTypeCloud {
+			requiresCloud = true
+		}
 	}
-	if err != nil {
-		return err
+
+	// A cloud connection is only needed if some data is synced to the cloud. Otherwise data can be synced to the
+	// other targets even if the robot is not cloud managed.
+	var identity string
+	var conn rpc.ClientConn
+	if requiresCloud {
+		var err error
+		identity, conn, err = svc.cloudConnSvc.AcquireConnection(ctx)
+		if errors.Is(err, cloud.ErrNotCloudManaged) {
+			svc.logger.Debug("Using no-op sync manager when not cloud managed")
+			svc.syncer = datasync.NewNoopManager()
+		}
+		if err != nil {
+			return err
+		}
 	}
 
-	client := v1.NewDataSyncServiceClient(conn)
+	targets, err := svc.buildSyncTargets(ctx, targetConfigs, conn)
+	if err != nil {
+		if conn != nil {
+			goutils.UncheckedError(conn.Close())
+		}
+		return errors.Wrap(err, "failed to initialize sync targets")
+	}
 
-	syncer, err := svc.syncerConstructor(identity, client, svc.logger)
+	syncer, err := svc.syncerConstructor(identity, targets, svc.logger)
 	if err != nil {
+		if conn != nil {
+			goutils.UncheckedError(conn.Close())
+		}
 		return errors.Wrap(err, "failed to initialize new syncer")
 	}
+	syncer.SetEncryptionKey(svc.captureEncoding.Key)
+	syncer.SetUploadLimits(svc.syncPolicy.uploadLimits())
 	svc.syncer = syncer
 	svc.cloudConn = conn
 	return nil
 }
 
+// syncTargetConfigsByName returns all configured sync targets, including the implicit cloud target, by name.
+func (svc *builtIn) syncTargetConfigsByName() map[string]datasync.TargetConfig {
+	confs := map[string]datasync.TargetConfig{
+		datasync.CloudTargetName: {Name: datasync.CloudTargetName, Type: datasync.TargetTypeCloud},
+	}
+	for _, conf := range svc.syncTargetConfigs {
+		confs[conf.Name] = conf
+	}
+	return confs
+}
+
+// defaultSyncTargetName returns the name of the sync target used for data that does not select one.
+func (svc *builtIn) defaultSyncTargetName() string {
+	if svc.defaultSyncTarget == "" {
+		return datasync.CloudTargetName
+	}
+	return svc.defaultSyncTarget
+}
+
+// referencedSyncTargets returns the names of all sync targets that data is synced to.
+func (svc *builtIn) referencedSyncTargets() []string {
+	names := []string{svc.defaultSyncTargetName()}
+	for _, name := range svc.syncTargetRoutes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// buildSyncTargets constructs each referenced sync target once, and routes each collector's data to its target.
+func (svc *builtIn) buildSyncTargets(
+	ctx context.Context,
+	confs map[string]datasync.TargetConfig,
+	cloudConn rpc.ClientConn,
+) (datasync.Targets, error) {
+	targets := datasync.Targets{ByDirectory: make(map[string]datasync.Target)}
+	built := make(map[string]datasync.Target)
+	get := func(name string) (datasync.Target, error) {
+		if target, ok := built[name]; ok {
+			return target, nil
+		}
+		conf, ok := confs[name]
+		if !ok {
+			return nil, errors.Errorf("unknown sync target %q", name)
+		}
+		target, err := datasync.NewTarget(ctx, conf, cloudConn, svc.logger)
+		if err != nil {
+			return nil, err
+		}
+		built[name] = target
+		return target, nil
+	}
+	closeBuilt := func() {
+		for _, target := range built {
+			goutils.UncheckedError(target.Close())
+		}
+	}
+
+	defaultTarget, err := get(svc.defaultSyncTargetName())
+	if err != nil {
+		closeBuilt()
+		return datasync.Targets{}, err
+	}
+	targets.Default = defaultTarget
+	for dir, name := range svc.syncTargetRoutes {
+		target, err := get(name)
+		if err != nil {
+			closeBuilt()
+			return datasync.Targets{}, err
+		}
+		targets.ByDirectory[dir] = target
+	}
+	return targets, nil
+}
+
 // TODO: Determine desired behavior if sync is disabled. Do we wan to allow manual syncs, then?
 //       If so, how could a user cancel it?
 
-// Sync performs a non-scheduled sync of the data in the capture directory.
+// Sync performs a non-scheduled sync of the data in the capture directory. It is not restricted to the configured
+// sync windows or network interfaces, but uploads are still subject to the configured upload limits.
 func (svc *builtIn) Sync(ctx context.Context, _ map[string]interface{}) error {
 	svc.lock.Lock()
 	defer svc.lock.Unlock()
@@ -350,6 +571,7 @@ func (svc *builtIn) Reconfigure(
 
 	reinitSyncer := cloudConnSvc != svc.cloudConnSvc
 	svc.cloudConnSvc = cloudConnSvc
+	targetsChanged := false
 
 	svc.updateDataCaptureConfigs(deps, svcConfig.ResourceConfigs, svcConfig.CaptureDir)
 
@@ -370,51 +592,67 @@ func (svc *builtIn) Reconfigure(
 		svc.collectors = make(map[componentMethodMetadata]*collectorAndConfig)
 	}
 
-	// Initialize or add collectors based on changes to the component configurations.
-	newCollectors := make(map[componentMethodMetadata]*collectorAndConfig)
-	if !svc.captureDisabled {
-		for _, resConf := range svcConfig.ResourceConfigs {
-			if resConf.Resource == nil {
-				// do not have the resource right now
-				continue
-			}
-			if !resConf.Disabled && resConf.CaptureFrequencyHz > 0 {
-				// Create component/method metadata to check if the collector exists.
-				methodMetadata := data.MethodMetadata{
-					API:        resConf.Name.API,
-					MethodName: resConf.Method,
-				}
-
-				componentMethodMetadata := componentMethodMetadata{
-					ComponentName:  resConf.Name.ShortName(),
-					MethodMetadata: methodMetadata,
-					MethodParams:   fmt.Sprintf("%v", resConf.AdditionalParams),
-				}
+	encoding, err := captureEncoding(svcConfig.CaptureCompression, svcConfig.Encryption)
+	if err != nil {
+		return err
+	}
+	// Collectors write with the encoding they were created with, so they must be recreated if it changes.
+	if !reflect.DeepEqual(encoding, svc.captureEncoding) {
+		svc.closeCollectors()
+		svc.collectors = make(map[componentMethodMetadata]*collectorAndConfig)
+		svc.captureEncoding = encoding
+		if svc.syncer != nil {
+			svc.syncer.SetEncryptionKey(encoding.Key)
+		}
+	}
 
-				// We only use service-level tags.
-				resConf.Tags = svcConfig.Tags
+	// Update the retention policy before starting any collectors so that the free space floor is respected.
+	svc.cancelRetentionScheduler()
+	priorities := make(map[string]int)
+	for _, resConf := range svcConfig.ResourceConfigs {
+		if resConf.RetentionPriority != 0 {
+			priorities[captureTargetDir(svc.captureDir, resConf)] = resConf.RetentionPriority
+		}
+	}
+	svc.retention.update(svc.captureDir, svcConfig.Retention, priorities)
+	if svcConfig.Retention.enabled() {
+		svc.startRetentionScheduler(svcConfig.Retention.checkInterval())
+	}
+	// We only use service-level tags.
+	for _, resConf := range svcConfig.ResourceConfigs {
+		resConf.Tags = svcConfig.Tags
+	}
+	svc.captureConfigs = svcConfig.ResourceConfigs
+	svc.captureDeps = deps
+	svc.updateCollectors()
+	svc.additionalSyncPaths = svcConfig.AdditionalSyncPaths
 
-				newCollectorAndConfig, err := svc.initializeOrUpdateCollector(componentMethodMetadata, resConf)
-				if err != nil {
-					svc.logger.Errorw("failed to initialize or update collector", "error", err)
-				} else {
-					newCollectors[componentMethodMetadata] = newCollectorAndConfig
-				}
-			}
+	if !reflect.DeepEqual(svc.syncPolicy, svcConfig.SyncPolicy) {
+		svc.syncPolicy = svcConfig.SyncPolicy
+		if svc.syncer != nil {
+			svc.syncer.SetUploadLimits(svc.syncPolicy.uploadLimits())
 		}
 	}
 
-	// If a component/method has been removed from the config, close the collector.
-	for md, collAndConfig := range svc.collectors {
-		if _, present := newCollectors[md]; !present {
-			collAndConfig.Collector.Close()
+	// Route data from collectors that select their own sync target.
+	syncTargetRoutes := make(map[string]string)
+	for _, resConf := range svcConfig.ResourceConfigs {
+		if resConf.SyncTarget != "" {
+			syncTargetRoutes[captureTargetDir(svc.captureDir, resConf)] = resConf.SyncTarget
 		}
 	}
-	svc.collectors = newCollectors
-	svc.additionalSyncPaths = svcConfig.AdditionalSyncPaths
+	if !reflect.DeepEqual(svc.syncTargetConfigs, svcConfig.SyncTargets) ||
+		svc.defaultSyncTarget != svcConfig.DefaultSyncTarget ||
+		!reflect.DeepEqual(svc.syncTargetRoutes, syncTargetRoutes) {
+		svc.syncTargetConfigs = svcConfig.SyncTargets
+		svc.defaultSyncTarget = svcConfig.DefaultSyncTarget
+		svc.syncTargetRoutes = syncTargetRoutes
+		targetsChanged = true
+		reinitSyncer = true
+	}
 
 	if svc.syncDisabled != svcConfig.ScheduledSyncDisabled || svc.syncIntervalMins != svcConfig.SyncIntervalMins ||
-		!reflect.DeepEqual(svc.tags, svcConfig.Tags) {
+		!reflect.DeepEqual(svc.tags, svcConfig.Tags) || targetsChanged {
 		svc.syncDisabled = svcConfig.ScheduledSyncDisabled
 		svc.syncIntervalMins = svcConfig.SyncIntervalMins
 		svc.tags = svcConfig.Tags
@@ -445,6 +683,53 @@ func (svc *builtIn) Reconfigure(
 	return nil
 }
 
+// updateCollectors initializes, updates and closes collectors to match svc.captureConfigs. No new collectors are
+// started, and running ones are closed, while free disk space is below the retention floor, since they would only fill
+// the disk further. It must be called with svc.lock held.
+func (svc *builtIn) updateCollectors() {
+	belowFloor := svc.retention.belowFreeSpaceFloor()
+	svc.captureStoppedBelowFloor.Store(belowFloor)
+
+	// Initialize or add collectors based on changes to the component configurations.
+	newCollectors := make(map[componentMethodMetadata]*collectorAndConfig)
+	if !svc.captureDisabled && !belowFloor {
+		for _, resConf := range svc.captureConfigs {
+			if resConf.Resource == nil {
+				// do not have the resource right now
+				continue
+			}
+			if !resConf.Disabled && resConf.CaptureFrequencyHz > 0 {
+				// Create component/method metadata to check if the collector exists.
+				methodMetadata := data.MethodMetadata{
+					API:        resConf.Name.API,
+					MethodName: resConf.Method,
+				}
+
+				componentMethodMetadata := componentMethodMetadata{
+					ComponentName:  resConf.Name.ShortName(),
+					MethodMetadata: methodMetadata,
+					MethodParams:   fmt.Sprintf("%v", resConf.AdditionalParams),
+				}
+
+				newCollectorAndConfig, err := svc.initializeOrUpdateCollector(componentMethodMetadata, resConf, svc.captureDeps)
+				if err != nil {
+					svc.logger.Errorw("failed to initialize or update collector", "error", err)
+				} else {
+					newCollectors[componentMethodMetadata] = newCollectorAndConfig
+				}
+			}
+		}
+	}
+
+	// If a component/method has been removed from the config, close the collector.
+	for md, collAndConfig := range svc.collectors {
+		if _, present := newCollectors[md]; !present {
+			collAndConfig.close()
+		}
+	}
+	svc.collectors = newCollectors
+}
+
 // startSyncScheduler starts the goroutine that calls Sync repeatedly if scheduled sync is enabled.
 func (svc *builtIn) startSyncScheduler(intervalMins float64) {
 	cancelCtx, fn := context.WithCancel(context.Background())
@@ -487,7 +772,11 @@ func (svc *builtIn) uploadData(cancelCtx context.Context, intervalMins float64)
 			case <-svc.syncTicker.C:
 				svc.lock.Lock()
 				if svc.syncer != nil {
-					svc.sync()
+					if allowed, reason := svc.syncPolicy.syncAllowed(clock.Now()); allowed {
+						svc.sync()
+					} else {
+						svc.logger.Debugw("skipping scheduled sync", "reason", reason)
+					}
 				}
 				svc.lock.Unlock()
 			}
@@ -501,6 +790,9 @@ func (svc *builtIn) sync() {
 	for _, ap := range svc.additionalSyncPaths {
 		toSync = append(toSync, getAllFilesToSync(ap, svc.waitAfterLastModifiedMillis)...)
 	}
+	if svc.syncPolicy != nil {
+		toSync = prioritizeFiles(toSync, svc.syncPolicy.TagPriorities)
+	}
 	for _, p := range toSync {
 		svc.syncer.SyncFile(p)
 	}
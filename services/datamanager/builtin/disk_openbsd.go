@@ -0,0 +1,14 @@
+package builtin
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// freeDiskBytes returns the number of bytes available to unprivileged users on the filesystem containing dir.
+func freeDiskBytes(dir string) (int64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return stat.F_bavail * int64(stat.F_bsize), nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package builtin
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// freeDiskBytes cannot find the free space on this platform, so a configured free space floor cannot be enforced.
+func freeDiskBytes(dir string) (int64, error) {
+	return 0, errors.Errorf("finding free disk space is not supported on %s", runtime.GOOS)
+}
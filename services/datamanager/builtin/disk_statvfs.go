@@ -0,0 +1,17 @@
+//go:build netbsd || solaris
+
+package builtin
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// freeDiskBytes returns the number of bytes available to unprivileged users on the filesystem containing dir. These
+// platforms have no statfs, so statvfs is used instead.
+func freeDiskBytes(dir string) (int64, error) {
+	var stat unix.Statvfs_t
+	if err := unix.Statvfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Bavail) * int64(stat.Frsize), nil
+}
@@ -0,0 +1,18 @@
+//go:build unix && !netbsd && !openbsd && !solaris
+
+package builtin
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// freeDiskBytes returns the number of bytes available to unprivileged users on the filesystem containing dir.
+func freeDiskBytes(dir string) (int64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	// the field types differ between platforms
+	//nolint:unconvert
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package builtin
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// freeDiskBytes returns the number of bytes available to the user running the process on the volume containing dir.
+func freeDiskBytes(dir string) (int64, error) {
+	dirPtr, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var freeBytesAvailable, totalBytes, totalFreeBytes uint64
+	if err := windows.GetDiskFreeSpaceEx(dirPtr, &freeBytesAvailable, &totalBytes, &totalFreeBytes); err != nil {
+		return 0, err
+	}
+	return int64(freeBytesAvailable), nil
+}
@@ -0,0 +1,51 @@
+package builtin
+
+import (
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// EncryptionConfig describes the key data capture files are encrypted with. If neither field is set, the key is read
+// from the file named by the datacapture.KeyFileEnvVar environment variable.
+type EncryptionConfig struct {
+	// Key is a base64 encoded 16, 24 or 32 byte AES key.
+	Key     string `json:"key,omitempty"`
+	KeyFile string `json:"key_file,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *EncryptionConfig) Validate(path string) error {
+	if c.Key != "" && c.KeyFile != "" {
+		return errors.Errorf("%s: only one of key and key_file may be set", path)
+	}
+	if c.Key != "" {
+		if _, err := datacapture.ParseKey(c.Key); err != nil {
+			return errors.Wrap(err, path)
+		}
+	}
+	return nil
+}
+
+// captureEncoding returns how newly captured data should be stored. Data is encrypted if encryption is configured,
+// or if a key file is provided through the environment.
+func captureEncoding(compression string, conf *EncryptionConfig) (datacapture.EncodingOptions, error) {
+	opts := datacapture.EncodingOptions{Compression: compression}
+	var err error
+	switch {
+	case conf != nil && conf.Key != "":
+		opts.Key, err = datacapture.ParseKey(conf.Key)
+	case conf != nil && conf.KeyFile != "":
+		opts.Key, err = datacapture.ReadKeyFile(conf.KeyFile)
+	default:
+		opts.Key, err = datacapture.KeyFromEnv()
+		if err == nil && conf != nil && opts.Key == nil {
+			err = errors.Errorf("encryption is enabled but no key is configured and %s is not set",
+				datacapture.KeyFileEnvVar)
+		}
+	}
+	if err != nil {
+		return datacapture.EncodingOptions{}, err
+	}
+	return opts, opts.Validate()
+}
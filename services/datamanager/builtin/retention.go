@@ -0,0 +1,324 @@
+package builtin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// Default interval at which the capture directory is checked against the retention policy.
+const defaultRetentionCheckInterval = time.Minute
+
+// Interval at which free disk space is checked against the floor. The disk can fill long before the next round of
+// eviction, so this is much shorter than the default check interval.
+const freeSpaceFloorCheckInterval = 5 * time.Second
+
+// The maximum number of evicted files reported in the service status.
+const maxRecentEvictions = 50
+
+const (
+	evictionReasonAge       = "max_age"
+	evictionReasonSize      = "max_capture_dir_bytes"
+	evictionReasonFreeSpace = "min_free_disk_bytes"
+)
+
+// RetentionConfig describes how long captured data is kept on local storage when it is not being synced. Only
+// completed .capture files in the capture directory are ever evicted; files in additional sync paths are left alone.
+type RetentionConfig struct {
+	MaxCaptureDirBytes int64   `json:"max_capture_dir_bytes"`
+	MaxAgeHours        float64 `json:"max_age_hours"`
+	MinFreeDiskBytes   int64   `json:"min_free_disk_bytes"`
+	CheckIntervalSecs  float64 `json:"check_interval_secs"`
+}
+
+func (c *RetentionConfig) enabled() bool {
+	return c != nil && (c.MaxCaptureDirBytes > 0 || c.MaxAgeHours > 0 || c.MinFreeDiskBytes > 0)
+}
+
+func (c *RetentionConfig) checkInterval() time.Duration {
+	if c.CheckIntervalSecs <= 0 {
+		return defaultRetentionCheckInterval
+	}
+	return time.Duration(c.CheckIntervalSecs * float64(time.Second))
+}
+
+// retentionManager evicts data capture files from the capture directory according to a RetentionConfig.
+type retentionManager struct {
+	logger        golog.Logger
+	lock          sync.Mutex
+	captureDir    string
+	conf          RetentionConfig
+	priorities    map[string]int
+	status        datamanager.RetentionStatus
+	freeDiskBytes func(dir string) (int64, error)
+}
+
+func newRetentionManager(logger golog.Logger) *retentionManager {
+	return &retentionManager{
+		logger:        logger,
+		priorities:    make(map[string]int),
+		freeDiskBytes: freeDiskBytes,
+	}
+}
+
+// update sets the directory and policy that are enforced. priorities maps a collector's target directory to its
+// retention priority.
+func (r *retentionManager) update(captureDir string, conf *RetentionConfig, priorities map[string]int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.captureDir = captureDir
+	r.conf = RetentionConfig{}
+	if conf != nil {
+		r.conf = *conf
+	}
+	r.priorities = priorities
+}
+
+// belowFreeSpaceFloor checks whether the free space on the capture directory's filesystem is below the configured
+// floor. While it is, no collectors are run.
+func (r *retentionManager) belowFreeSpaceFloor() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, low := r.freeSpaceDeficit()
+	return low
+}
+
+// freeSpaceDeficit returns how many bytes must be freed to get back above the free space floor. It must be called
+// with r.lock held.
+func (r *retentionManager) freeSpaceDeficit() (int64, bool) {
+	if r.conf.MinFreeDiskBytes <= 0 {
+		r.setLowDiskSpace(false)
+		return 0, false
+	}
+	free, err := r.freeDiskBytes(r.captureDir)
+	if err != nil {
+		// The capture directory might not have been created yet, in which case nothing can be evicted anyway.
+		if !errors.Is(err, os.ErrNotExist) {
+			r.logger.Errorw("failed to determine free disk space", "dir", r.captureDir, "error", err)
+		}
+		return 0, false
+	}
+	deficit := r.conf.MinFreeDiskBytes - free
+	r.setLowDiskSpace(deficit > 0)
+	return deficit, deficit > 0
+}
+
+// setLowDiskSpace records whether free space is below the floor. It must be called with r.lock held.
+func (r *retentionManager) setLowDiskSpace(low bool) {
+	if low && !r.status.LowDiskSpace {
+		r.logger.Errorw("free disk space is below the configured floor, stopping data capture until it is freed",
+			"dir", r.captureDir, "min_free_disk_bytes", r.conf.MinFreeDiskBytes)
+	}
+	r.status.LowDiskSpace = low
+}
+
+// retentionCandidate is a completed data capture file which may be evicted.
+type retentionCandidate struct {
+	path     string
+	size     int64
+	modTime  time.Time
+	priority int
+}
+
+// evict deletes files in the capture directory which violate the retention policy. Files older than the max age are
+// always deleted. Then, while the capture directory is over its max size or the disk is below its free space floor,
+// the lowest priority files are deleted, oldest first.
+func (r *retentionManager) evict() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.conf.enabled() {
+		return
+	}
+
+	candidates, totalBytes := r.listCandidates()
+
+	// Evict anything older than the max age.
+	if r.conf.MaxAgeHours > 0 {
+		maxAge := time.Duration(r.conf.MaxAgeHours * float64(time.Hour))
+		remaining := candidates[:0]
+		for _, c := range candidates {
+			if clock.Since(c.modTime) > maxAge {
+				if r.remove(c, evictionReasonAge) {
+					totalBytes -= c.size
+				}
+				continue
+			}
+			remaining = append(remaining, c)
+		}
+		candidates = remaining
+	}
+
+	// Lowest priority first, then oldest first.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].modTime.Before(candidates[j].modTime)
+	})
+
+	deficit, _ := r.freeSpaceDeficit()
+	for _, c := range candidates {
+		reason := ""
+		switch {
+		case r.conf.MaxCaptureDirBytes > 0 && totalBytes > r.conf.MaxCaptureDirBytes:
+			reason = evictionReasonSize
+		case deficit > 0:
+			reason = evictionReasonFreeSpace
+		}
+		if reason == "" {
+			break
+		}
+		if r.remove(c, reason) {
+			totalBytes -= c.size
+			deficit -= c.size
+		}
+	}
+	// Refresh whether or not we are still low on disk space.
+	r.freeSpaceDeficit()
+}
+
+// listCandidates returns all completed data capture files in the capture directory, along with the total number of
+// bytes used by all data capture files (including those still being written).
+func (r *retentionManager) listCandidates() ([]retentionCandidate, int64) {
+	var candidates []retentionCandidate
+	var totalBytes int64
+	_ = filepath.Walk(r.captureDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != datacapture.FileExt && ext != datacapture.InProgressFileExt {
+			return nil
+		}
+		totalBytes += info.Size()
+		if ext == datacapture.InProgressFileExt {
+			return nil
+		}
+		candidates = append(candidates, retentionCandidate{
+			path:     path,
+			size:     info.Size(),
+			modTime:  info.ModTime(),
+			priority: r.priorityOf(path),
+		})
+		return nil
+	})
+	return candidates, totalBytes
+}
+
+// priorityOf returns the retention priority of the collector that wrote the file at path.
+func (r *retentionManager) priorityOf(path string) int {
+	dir := filepath.Dir(path)
+	for targetDir, priority := range r.priorities {
+		if dir == targetDir || strings.HasPrefix(dir, targetDir+string(filepath.Separator)) {
+			return priority
+		}
+	}
+	return 0
+}
+
+// remove deletes the file described by c and records it in the status. It returns whether the file was deleted.
+func (r *retentionManager) remove(c retentionCandidate, reason string) bool {
+	if err := os.Remove(c.path); err != nil {
+		// The file may have been synced and deleted since the capture directory was walked.
+		if !errors.Is(err, os.ErrNotExist) {
+			r.logger.Errorw("failed to evict data capture file", "path", c.path, "error", err)
+		}
+		return false
+	}
+	r.logger.Debugw("evicted data capture file", "path", c.path, "reason", reason)
+	r.status.FilesEvicted++
+	r.status.BytesEvicted += c.size
+	r.status.RecentEvictions = append(r.status.RecentEvictions,
+		datamanager.NewEvictedFile(c.path, c.size, reason, clock.Now()))
+	if len(r.status.RecentEvictions) > maxRecentEvictions {
+		r.status.RecentEvictions = r.status.RecentEvictions[len(r.status.RecentEvictions)-maxRecentEvictions:]
+	}
+	return true
+}
+
+// getStatus returns a copy of the current retention status.
+func (r *retentionManager) getStatus() datamanager.RetentionStatus {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ret := r.status
+	ret.RecentEvictions = append([]datamanager.EvictedFile{}, r.status.RecentEvictions...)
+	return ret
+}
+
+// RetentionStatus returns the files the data manager has evicted from local storage.
+func (svc *builtIn) RetentionStatus() datamanager.RetentionStatus {
+	return svc.retention.getStatus()
+}
+
+// startRetentionScheduler starts the goroutine that enforces the retention policy every interval, and checks the free
+// space floor every freeSpaceFloorCheckInterval in between.
+func (svc *builtIn) startRetentionScheduler(interval time.Duration) {
+	cancelCtx, fn := context.WithCancel(context.Background())
+	svc.retentionCancelFn = fn
+	// The tickers must be created before returning for the same reason as in uploadData.
+	ticker := clock.Ticker(interval)
+	floorTicker := clock.Ticker(freeSpaceFloorCheckInterval)
+	svc.retentionWorkers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer svc.retentionWorkers.Done()
+		defer ticker.Stop()
+		defer floorTicker.Stop()
+
+		svc.retention.evict()
+		svc.checkFreeSpaceFloor(cancelCtx)
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			case <-ticker.C:
+				svc.retention.evict()
+				svc.checkFreeSpaceFloor(cancelCtx)
+			case <-floorTicker.C:
+				svc.checkFreeSpaceFloor(cancelCtx)
+			}
+		}
+	})
+}
+
+// checkFreeSpaceFloor stops the collectors once free disk space is below the floor, and starts them again once it is
+// freed. The collectors are updated on another goroutine, since Reconfigure and Close wait for the retention scheduler
+// while holding svc.lock.
+func (svc *builtIn) checkFreeSpaceFloor(cancelCtx context.Context) {
+	if svc.retention.belowFreeSpaceFloor() == svc.captureStoppedBelowFloor.Load() {
+		return
+	}
+	svc.floorWorkers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer svc.floorWorkers.Done()
+		svc.lock.Lock()
+		defer svc.lock.Unlock()
+		// The scheduler was cancelled while waiting for the lock, so the collectors are up to date.
+		if cancelCtx.Err() != nil {
+			return
+		}
+		svc.updateCollectors()
+	})
+}
+
+// cancelRetentionScheduler stops the goroutine that enforces the retention policy.
+func (svc *builtIn) cancelRetentionScheduler() {
+	if svc.retentionCancelFn != nil {
+		svc.retentionCancelFn()
+		svc.retentionWorkers.Wait()
+		svc.retentionCancelFn = nil
+	}
+}
@@ -0,0 +1,172 @@
+package builtin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clk "github.com/benbjohnson/clock"
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// writeRetentionTestFile writes a file of size bytes to dir/name, last modified age ago.
+func writeRetentionTestFile(t *testing.T, dir, name string, size int, age time.Duration) string {
+	t.Helper()
+	test.That(t, os.MkdirAll(dir, 0o700), test.ShouldBeNil)
+	path := filepath.Join(dir, name)
+	test.That(t, os.WriteFile(path, make([]byte, size), 0o600), test.ShouldBeNil)
+	modTime := time.Now().Add(-age)
+	test.That(t, os.Chtimes(path, modTime, modTime), test.ShouldBeNil)
+	return path
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestRetentionEviction(t *testing.T) {
+	clock = clk.New()
+	lowPriorityDir := filepath.Join("rdk:component:arm", "arm1", "EndPosition")
+	highPriorityDir := filepath.Join("rdk:component:camera", "c1", "ReadImage")
+
+	tests := []struct {
+		name        string
+		conf        RetentionConfig
+		freeBytes   int64
+		expEvicted  []string
+		expRetained []string
+	}{
+		{
+			name:        "files older than the max age should be evicted",
+			conf:        RetentionConfig{MaxAgeHours: 1},
+			freeBytes:   1000,
+			expEvicted:  []string{"low_old", "high_old"},
+			expRetained: []string{"low_new", "high_new", "in_progress"},
+		},
+		{
+			name:        "lowest priority files should be evicted first, oldest first, until under the max size",
+			conf:        RetentionConfig{MaxCaptureDirBytes: 250},
+			freeBytes:   1000,
+			expEvicted:  []string{"low_old", "low_new"},
+			expRetained: []string{"high_old", "high_new", "in_progress"},
+		},
+		{
+			name:        "files should be evicted until the free space floor is reached",
+			conf:        RetentionConfig{MinFreeDiskBytes: 1050},
+			freeBytes:   1000,
+			expEvicted:  []string{"low_old"},
+			expRetained: []string{"low_new", "high_old", "high_new", "in_progress"},
+		},
+		{
+			name:        "nothing should be evicted when the policy is satisfied",
+			conf:        RetentionConfig{MaxCaptureDirBytes: 1000, MaxAgeHours: 10, MinFreeDiskBytes: 10},
+			freeBytes:   1000,
+			expRetained: []string{"low_old", "low_new", "high_old", "high_new", "in_progress"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			captureDir := t.TempDir()
+			files := map[string]string{
+				"low_old": writeRetentionTestFile(t, filepath.Join(captureDir, lowPriorityDir),
+					"a"+datacapture.FileExt, 100, 2*time.Hour),
+				"low_new": writeRetentionTestFile(t, filepath.Join(captureDir, lowPriorityDir),
+					"b"+datacapture.FileExt, 100, time.Minute),
+				"high_old": writeRetentionTestFile(t, filepath.Join(captureDir, highPriorityDir),
+					"c"+datacapture.FileExt, 100, 3*time.Hour),
+				"high_new": writeRetentionTestFile(t, filepath.Join(captureDir, highPriorityDir),
+					"d"+datacapture.FileExt, 100, time.Minute),
+				"in_progress": writeRetentionTestFile(t, filepath.Join(captureDir, highPriorityDir),
+					"e"+datacapture.InProgressFileExt, 50, 5*time.Hour),
+			}
+
+			rm := newRetentionManager(golog.NewTestLogger(t))
+			// Simulate each evicted file freeing up its space on disk.
+			rm.freeDiskBytes = func(string) (int64, error) {
+				return tc.freeBytes + rm.status.BytesEvicted, nil
+			}
+			rm.update(captureDir, &tc.conf, map[string]int{
+				filepath.Join(captureDir, lowPriorityDir):  0,
+				filepath.Join(captureDir, highPriorityDir): 10,
+			})
+			rm.evict()
+
+			status := rm.getStatus()
+			test.That(t, status.FilesEvicted, test.ShouldEqual, len(tc.expEvicted))
+			test.That(t, status.BytesEvicted, test.ShouldEqual, 100*len(tc.expEvicted))
+			test.That(t, len(status.RecentEvictions), test.ShouldEqual, len(tc.expEvicted))
+			test.That(t, status.LowDiskSpace, test.ShouldBeFalse)
+			for i, name := range tc.expEvicted {
+				test.That(t, fileExists(files[name]), test.ShouldBeFalse)
+				test.That(t, status.RecentEvictions[i].Path, test.ShouldEqual, files[name])
+			}
+			for _, name := range tc.expRetained {
+				test.That(t, fileExists(files[name]), test.ShouldBeTrue)
+			}
+		})
+	}
+}
+
+func TestRetentionStopsCollectorsBelowFreeSpaceFloor(t *testing.T) {
+	clock = clk.New()
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+	svc := dmsvc.(*builtIn)
+	setFreeDiskBytes := func(free int64) {
+		svc.retention.lock.Lock()
+		defer svc.retention.lock.Unlock()
+		svc.retention.freeDiskBytes = func(string) (int64, error) {
+			return free, nil
+		}
+	}
+	numCollectors := func() int {
+		svc.lock.Lock()
+		defer svc.lock.Unlock()
+		return len(svc.collectors)
+	}
+	setFreeDiskBytes(10)
+
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.CaptureDir = t.TempDir()
+	cfg.Retention = &RetentionConfig{MinFreeDiskBytes: 100}
+	resources := resourcesFromDeps(t, r, deps)
+	err := dmsvc.Reconfigure(context.Background(), resources, resource.Config{ConvertedAttributes: cfg})
+	test.That(t, err, test.ShouldBeNil)
+
+	// No collectors are started while below the floor.
+	test.That(t, numCollectors(), test.ShouldEqual, 0)
+	status, err := datamanager.CreateStatus(context.Background(), svc)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status.(map[string]interface{})["retention"].(datamanager.RetentionStatus).LowDiskSpace, test.ShouldBeTrue)
+	fullStatus, err := svc.GetStatus(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fullStatus.Retention.LowDiskSpace, test.ShouldBeTrue)
+
+	// Once a floor check finds enough free space again, the collectors are started without reconfiguring.
+	setFreeDiskBytes(1000)
+	svc.checkFreeSpaceFloor(context.Background())
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, numCollectors(), test.ShouldEqual, 1)
+	})
+	test.That(t, svc.RetentionStatus().LowDiskSpace, test.ShouldBeFalse)
+
+	// And once free space runs low while capturing, they are stopped again.
+	setFreeDiskBytes(10)
+	svc.checkFreeSpaceFloor(context.Background())
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, numCollectors(), test.ShouldEqual, 0)
+	})
+	test.That(t, svc.RetentionStatus().LowDiskSpace, test.ShouldBeTrue)
+}
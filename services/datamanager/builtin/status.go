@@ -0,0 +1,92 @@
+package builtin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// GetStatus returns the health of the service's collectors, syncing and retention.
+func (svc *builtIn) GetStatus(_ context.Context, _ map[string]interface{}) (datamanager.Status, error) {
+	status, dirs := svc.statusSnapshot()
+	// Walking the sync directories can take a while, so it is done without holding the lock.
+	for _, dir := range dirs {
+		files, bytes := pendingFiles(dir)
+		status.PendingFiles += files
+		status.PendingBytes += bytes
+	}
+	status.Retention = svc.retention.getStatus()
+	return status, nil
+}
+
+// statusSnapshot returns the status of the collectors and syncing, and the directories whose files are synced.
+func (svc *builtIn) statusSnapshot() (datamanager.Status, []string) {
+	svc.lock.Lock()
+	defer svc.lock.Unlock()
+
+	status := datamanager.Status{Collectors: []datamanager.CollectorStatus{}}
+	for _, collAndConfig := range svc.collectors {
+		stats := collAndConfig.Collector.Stats()
+		status.Collectors = append(status.Collectors, datamanager.CollectorStatus{
+			Resource:           collAndConfig.Config.Name.String(),
+			Method:             collAndConfig.Config.Method,
+			CaptureFrequencyHz: collAndConfig.Config.CaptureFrequencyHz,
+			CaptureRateHz:      stats.CaptureRateHz,
+			LastCaptureTime:    datamanager.FormatStatusTime(stats.LastCapture),
+			LastError:          datamanager.FormatStatusError(stats.LastError),
+			LastErrorTime:      datamanager.FormatStatusTime(stats.LastErrorTime),
+			QueueDepth:         stats.QueueDepth,
+			QueueCapacity:      stats.QueueCapacity,
+			CapturedReadings:   stats.Captured,
+			SuppressedReadings: stats.Suppressed,
+			FailedCaptures:     stats.Errors,
+		})
+	}
+	sort.Slice(status.Collectors, func(i, j int) bool {
+		if status.Collectors[i].Resource != status.Collectors[j].Resource {
+			return status.Collectors[i].Resource < status.Collectors[j].Resource
+		}
+		return status.Collectors[i].Method < status.Collectors[j].Method
+	})
+
+	status.Sync = datamanager.SyncStatus{
+		Enabled: !svc.syncDisabled && svc.syncIntervalMins != 0,
+		Uploads: []datamanager.UploadStatus{},
+	}
+	if svc.syncer != nil {
+		syncStatus := svc.syncer.Status()
+		status.Sync.LastError = datamanager.FormatStatusError(syncStatus.LastError)
+		status.Sync.LastErrorTime = datamanager.FormatStatusTime(syncStatus.LastErrorTime)
+		for _, upload := range syncStatus.Uploads {
+			status.Sync.Uploads = append(status.Sync.Uploads, datamanager.UploadStatus{
+				Path:        upload.Path,
+				State:       string(upload.State),
+				Attempts:    upload.Attempts,
+				LastError:   datamanager.FormatStatusError(upload.LastError),
+				NextAttempt: datamanager.FormatStatusTime(upload.NextAttempt),
+			})
+		}
+	}
+	return status, append([]string{svc.captureDir}, svc.additionalSyncPaths...)
+}
+
+// pendingFiles returns the number and total size of the files in dir that are waiting to be synced. Data capture
+// files that are still being written are not included.
+func pendingFiles(dir string) (int, int64) {
+	var files int
+	var bytes int64
+	//nolint:errcheck
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) == datacapture.InProgressFileExt {
+			return nil
+		}
+		files++
+		bytes += info.Size()
+		return nil
+	})
+	return files, bytes
+}
@@ -0,0 +1,70 @@
+package builtin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	clk "github.com/benbjohnson/clock"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager/datasync"
+)
+
+func TestGetStatus(t *testing.T) {
+	mockClock := clk.NewMock()
+	clock = mockClock
+	captureDir := t.TempDir()
+
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+	mockClient := mockDataSyncServiceClient{
+		succesfulDCRequests: make(chan *v1.DataCaptureUploadRequest, 100),
+		failedDCRequests:    make(chan *v1.DataCaptureUploadRequest, 100),
+		fail:                &atomic.Bool{},
+	}
+	mockClient.fail.Store(true)
+	dmsvc.SetSyncerConstructor(getTestSyncerConstructorMock(mockClient))
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.CaptureDir = captureDir
+	cfg.ScheduledSyncDisabled = true
+	resources := resourcesFromDeps(t, r, deps)
+	err := dmsvc.Reconfigure(context.Background(), resources, resource.Config{ConvertedAttributes: cfg})
+	test.That(t, err, test.ShouldBeNil)
+
+	// Collectors report what they have captured.
+	for i := 0; i < 20; i++ {
+		mockClock.Add(time.Millisecond * 10)
+	}
+	status, err := dmsvc.GetStatus(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(status.Collectors), test.ShouldEqual, len(cfg.ResourceConfigs))
+	test.That(t, status.Collectors[0].CapturedReadings, test.ShouldBeGreaterThan, 0)
+	test.That(t, status.Collectors[0].LastCaptureTime, test.ShouldNotBeEmpty)
+	test.That(t, status.Collectors[0].QueueCapacity, test.ShouldBeGreaterThan, 0)
+	test.That(t, status.Sync.Enabled, test.ShouldBeFalse)
+	test.That(t, status.Retention.LowDiskSpace, test.ShouldBeFalse)
+	test.That(t, status.Retention.RecentEvictions, test.ShouldBeEmpty)
+
+	// Failed uploads are reported as retrying until they succeed.
+	test.That(t, dmsvc.Sync(context.Background(), nil), test.ShouldBeNil)
+	<-mockClient.failedDCRequests
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		status, err := dmsvc.GetStatus(context.Background(), nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, status.PendingFiles, test.ShouldBeGreaterThan, 0)
+		test.That(tb, status.PendingBytes, test.ShouldBeGreaterThan, 0)
+		test.That(tb, status.Sync.LastError, test.ShouldContainSubstring, "oh no error")
+		test.That(tb, len(status.Sync.Uploads), test.ShouldBeGreaterThan, 0)
+		upload := status.Sync.Uploads[0]
+		test.That(tb, upload.State, test.ShouldEqual, string(datasync.UploadStateRetrying))
+		test.That(tb, upload.Attempts, test.ShouldEqual, 1)
+		test.That(tb, upload.LastError, test.ShouldContainSubstring, "oh no error")
+		test.That(tb, upload.NextAttempt, test.ShouldNotBeEmpty)
+	})
+}
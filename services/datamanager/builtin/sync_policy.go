@@ -0,0 +1,238 @@
+package builtin
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+	"go.viam.com/rdk/services/datamanager/datasync"
+)
+
+const syncWindowTimeLayout = "15:04"
+
+// SyncPolicyConfig limits when and how quickly scheduled syncs upload data.
+type SyncPolicyConfig struct {
+	// MaxUploadBytesPerSec is the average upload rate across all files. If 0, the rate is not limited.
+	MaxUploadBytesPerSec int64 `json:"max_upload_bytes_per_sec,omitempty"`
+	// MaxParallelUploads is the number of files uploaded at once. If 0, all files are uploaded at once.
+	MaxParallelUploads int `json:"max_parallel_uploads,omitempty"`
+	// Windows are the times of day scheduled syncs may run in. If empty, scheduled syncs may run at any time.
+	Windows []SyncWindow `json:"windows,omitempty"`
+	// NetworkInterfaces are the interfaces scheduled syncs may run over. Scheduled syncs only run while one of them
+	// is the interface traffic to the internet is routed over. If empty, scheduled syncs run over any interface.
+	NetworkInterfaces []string `json:"network_interfaces,omitempty"`
+	// TagPriorities maps capture tags to priorities. Files whose tags have a higher priority are uploaded first.
+	// Files without a prioritized tag have priority 0.
+	TagPriorities map[string]int `json:"tag_priorities,omitempty"`
+}
+
+// SyncWindow is a time of day, in the robot's local time, during which scheduled syncs may run. Times are formatted
+// as HH:MM. A window whose end is before its start spans midnight, and a window whose start and end are equal spans
+// the whole day.
+type SyncWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *SyncPolicyConfig) Validate(path string) error {
+	if c.MaxUploadBytesPerSec < 0 {
+		return errors.Errorf("%s: max_upload_bytes_per_sec cannot be negative", path)
+	}
+	if c.MaxParallelUploads < 0 {
+		return errors.Errorf("%s: max_parallel_uploads cannot be negative", path)
+	}
+	for idx, window := range c.Windows {
+		if _, _, err := window.bounds(); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("%s.windows.%d", path, idx))
+		}
+	}
+	for idx, name := range c.NetworkInterfaces {
+		if name == "" {
+			return errors.Errorf("%s.network_interfaces.%d: interface name cannot be empty", path, idx)
+		}
+	}
+	return nil
+}
+
+// uploadLimits returns the limits uploads are subject to under the policy c, which may be nil.
+func (c *SyncPolicyConfig) uploadLimits() datasync.UploadLimits {
+	if c == nil {
+		return datasync.UploadLimits{}
+	}
+	return datasync.UploadLimits{MaxParallelUploads: c.MaxParallelUploads, MaxBytesPerSec: c.MaxUploadBytesPerSec}
+}
+
+// bounds returns the start and end of w as offsets from midnight.
+func (w SyncWindow) bounds() (time.Duration, time.Duration, error) {
+	start, err := time.Parse(syncWindowTimeLayout, w.Start)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid start %q, must be formatted as HH:MM", w.Start)
+	}
+	end, err := time.Parse(syncWindowTimeLayout, w.End)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid end %q, must be formatted as HH:MM", w.End)
+	}
+	return sinceMidnight(start), sinceMidnight(end), nil
+}
+
+func (w SyncWindow) contains(t time.Time) bool {
+	start, end, err := w.bounds()
+	if err != nil {
+		return false
+	}
+	now := sinceMidnight(t)
+	switch {
+	case start == end:
+		return true
+	case start < end:
+		return now >= start && now < end
+	default:
+		return now >= start || now < end
+	}
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+}
+
+// routeProbeAddress is a public address used to find the route traffic to the internet takes. Nothing is ever sent
+// to it.
+const routeProbeAddress = "8.8.8.8:53"
+
+// activeInterface returns the name of the network interface that traffic to the internet is routed over. Connecting
+// a UDP socket sends no packets, but does select the route, and so the local address, the socket would send from. It
+// is a variable so that it can be replaced in tests.
+var activeInterface = func() (string, error) {
+	conn, err := net.Dial("udp", routeProbeAddress)
+	if err != nil {
+		return "", err
+	}
+	localAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if err := conn.Close(); err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errors.Errorf("unexpected local address %v", conn.LocalAddr())
+	}
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(localAddr.IP) {
+				return iface.Name, nil
+			}
+		}
+	}
+	return "", errors.Errorf("no network interface has the address %s", localAddr.IP)
+}
+
+// syncAllowed returns whether a scheduled sync may run at t under the policy c, which may be nil. If it may not, the
+// reason is returned.
+func (c *SyncPolicyConfig) syncAllowed(t time.Time) (bool, string) {
+	if c == nil {
+		return true, ""
+	}
+	if len(c.Windows) > 0 {
+		inWindow := false
+		local := t.Local()
+		for _, window := range c.Windows {
+			if window.contains(local) {
+				inWindow = true
+				break
+			}
+		}
+		if !inWindow {
+			return false, "outside of sync windows"
+		}
+	}
+	if len(c.NetworkInterfaces) > 0 {
+		active, err := activeInterface()
+		if err != nil {
+			return false, fmt.Sprintf("no network route: %v", err)
+		}
+		for _, name := range c.NetworkInterfaces {
+			if name == active {
+				return true, ""
+			}
+		}
+		return false, fmt.Sprintf("network traffic is routed over %s, which is not an allowed network interface", active)
+	}
+	return true, ""
+}
+
+// Files of the same tag priority are uploaded in order of kind, so that tabular data, which is small and often
+// needed promptly, is not held up behind images and arbitrary files.
+const (
+	fileKindTabular = iota
+	fileKindBinary
+	fileKindOther
+)
+
+type syncFile struct {
+	path     string
+	priority int
+	kind     int
+	size     int64
+}
+
+// prioritizeFiles orders paths in the order they should be uploaded: by descending tag priority, then tabular
+// capture files, binary capture files and arbitrary files, and finally by ascending size.
+func prioritizeFiles(paths []string, tagPriorities map[string]int) []string {
+	files := make([]syncFile, 0, len(paths))
+	for _, path := range paths {
+		file := syncFile{path: path, kind: fileKindOther}
+		if info, err := os.Stat(path); err == nil {
+			file.size = info.Size()
+		}
+		if ext := filepath.Ext(path); ext == datacapture.FileExt || ext == datacapture.InProgressFileExt {
+			if md, err := datacapture.ReadFileMetadata(path); err == nil {
+				file.kind = fileKindBinary
+				if md.GetType() == v1.DataType_DATA_TYPE_TABULAR_SENSOR {
+					file.kind = fileKindTabular
+				}
+				file.priority = tagPriority(md.GetTags(), tagPriorities)
+			}
+		}
+		files = append(files, file)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].priority != files[j].priority {
+			return files[i].priority > files[j].priority
+		}
+		if files[i].kind != files[j].kind {
+			return files[i].kind < files[j].kind
+		}
+		return files[i].size < files[j].size
+	})
+	sorted := make([]string, len(files))
+	for i, file := range files {
+		sorted[i] = file.path
+	}
+	return sorted
+}
+
+// tagPriority returns the highest priority of tags, or 0 if none are prioritized.
+func tagPriority(tags []string, tagPriorities map[string]int) int {
+	priority, found := 0, false
+	for _, tag := range tags {
+		if p, ok := tagPriorities[tag]; ok && (!found || p > priority) {
+			priority, found = p, true
+		}
+	}
+	return priority
+}
@@ -0,0 +1,112 @@
+package builtin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+func TestSyncPolicyAllowed(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2023, 6, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	var nilPolicy *SyncPolicyConfig
+	allowed, _ := nilPolicy.syncAllowed(at(12, 0))
+	test.That(t, allowed, test.ShouldBeTrue)
+
+	policy := &SyncPolicyConfig{Windows: []SyncWindow{{Start: "22:00", End: "06:00"}, {Start: "12:00", End: "12:30"}}}
+	test.That(t, policy.Validate("path"), test.ShouldBeNil)
+	for _, tc := range []struct {
+		t       time.Time
+		allowed bool
+	}{
+		{at(23, 0), true},
+		{at(3, 0), true},
+		{at(6, 0), false},
+		{at(12, 15), true},
+		{at(12, 30), false},
+		{at(18, 0), false},
+	} {
+		allowed, _ := policy.syncAllowed(tc.t)
+		test.That(t, allowed, test.ShouldEqual, tc.allowed)
+	}
+
+	active, activeErr := "wlan0", error(nil)
+	prevActiveInterface := activeInterface
+	activeInterface = func() (string, error) { return active, activeErr }
+	defer func() { activeInterface = prevActiveInterface }()
+
+	policy = &SyncPolicyConfig{NetworkInterfaces: []string{"eth0", "wlan0"}}
+	allowed, _ = policy.syncAllowed(at(12, 0))
+	test.That(t, allowed, test.ShouldBeTrue)
+
+	// An allowed interface that is up but not routed over does not allow syncing.
+	active = "usb0"
+	allowed, reason := policy.syncAllowed(at(12, 0))
+	test.That(t, allowed, test.ShouldBeFalse)
+	test.That(t, reason, test.ShouldContainSubstring, "usb0")
+
+	active, activeErr = "", errors.New("network is unreachable")
+	allowed, reason = policy.syncAllowed(at(12, 0))
+	test.That(t, allowed, test.ShouldBeFalse)
+	test.That(t, reason, test.ShouldContainSubstring, "network is unreachable")
+}
+
+func TestSyncPolicyValidation(t *testing.T) {
+	policy := &SyncPolicyConfig{Windows: []SyncWindow{{Start: "9am", End: "17:00"}}}
+	err := policy.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "path.windows.0")
+
+	policy = &SyncPolicyConfig{MaxUploadBytesPerSec: -1}
+	test.That(t, policy.Validate("path"), test.ShouldNotBeNil)
+
+	conf := &Config{SyncPolicy: &SyncPolicyConfig{NetworkInterfaces: []string{""}}}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "path.sync_policy.network_interfaces.0")
+}
+
+func TestPrioritizeFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeCaptureFile := func(dataType v1.DataType, tags []string, readings int) string {
+		f, err := datacapture.NewFile(dir, &v1.DataCaptureMetadata{
+			ComponentType: "rdk:component:camera",
+			ComponentName: "c1",
+			MethodName:    "ReadImage",
+			Type:          dataType,
+			Tags:          tags,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		for i := 0; i < readings; i++ {
+			reading := &v1.SensorData{Data: &v1.SensorData_Binary{Binary: make([]byte, 100)}}
+			test.That(t, f.WriteNext(reading), test.ShouldBeNil)
+		}
+		path := f.GetPath()
+		test.That(t, f.Close(), test.ShouldBeNil)
+		// Files are renamed to .capture when closed.
+		return strings.TrimSuffix(path, datacapture.InProgressFileExt) + datacapture.FileExt
+	}
+
+	largeImage := writeCaptureFile(v1.DataType_DATA_TYPE_BINARY_SENSOR, nil, 10)
+	smallImage := writeCaptureFile(v1.DataType_DATA_TYPE_BINARY_SENSOR, nil, 1)
+	tabular := writeCaptureFile(v1.DataType_DATA_TYPE_TABULAR_SENSOR, nil, 1)
+	urgentImage := writeCaptureFile(v1.DataType_DATA_TYPE_BINARY_SENSOR, []string{"urgent"}, 10)
+	arbitrary := filepath.Join(dir, "notes.txt")
+	test.That(t, os.WriteFile(arbitrary, []byte("hi"), 0o600), test.ShouldBeNil)
+
+	sorted := prioritizeFiles(
+		[]string{arbitrary, largeImage, smallImage, tabular, urgentImage},
+		map[string]int{"urgent": 1},
+	)
+	test.That(t, sorted, test.ShouldResemble, []string{urgentImage, tabular, smallImage, largeImage, arbitrary})
+}
@@ -2,6 +2,7 @@ package builtin
 
 import (
 	"context"
+	"encoding/base64"
 	"io"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/services/datamanager/datacapture"
 	"go.viam.com/rdk/services/datamanager/datasync"
 	"go.viam.com/test"
@@ -554,6 +556,144 @@ func TestSyncConfigUpdateBehavior(t *testing.T) {
 
 }
 
+func TestSyncToLocalTarget(t *testing.T) {
+	mockClock := clk.NewMock()
+	clock = mockClock
+	captureDir := t.TempDir()
+	mirrorDir := t.TempDir()
+
+	// The real syncer is used, since no data should go through a DataSyncServiceClient.
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+	dmsvc.SetWaitAfterLastModifiedMillis(0)
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.CaptureDir = captureDir
+	cfg.ScheduledSyncDisabled = true
+	cfg.SyncTargets = []datasync.TargetConfig{{Name: "mirror", Type: datasync.TargetTypeLocal, Directory: mirrorDir}}
+	cfg.DefaultSyncTarget = "mirror"
+	_, err := cfg.Validate("services.0.attributes")
+	test.That(t, err, test.ShouldBeNil)
+
+	resources := resourcesFromDeps(t, r, deps)
+	err = dmsvc.Reconfigure(context.Background(), resources, resource.Config{ConvertedAttributes: cfg})
+	test.That(t, err, test.ShouldBeNil)
+	mockClock.Add(time.Millisecond * 10)
+	waitForCaptureFiles(captureDir)
+
+	err = dmsvc.Sync(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	waitUntilNoFiles(captureDir)
+	test.That(t, len(getAllFileInfos(captureDir)), test.ShouldEqual, 0)
+
+	mirrored := getAllFilePaths(mirrorDir)
+	test.That(t, len(mirrored), test.ShouldBeGreaterThan, 0)
+	for _, path := range mirrored {
+		sd, err := datacapture.SensorDataFromFilePath(path)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(sd), test.ShouldBeGreaterThan, 0)
+	}
+}
+
+func TestEncryptedCaptureUpload(t *testing.T) {
+	mockClock := clk.NewMock()
+	clock = mockClock
+	captureDir := t.TempDir()
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	t.Setenv(datacapture.KeyFileEnvVar, "")
+
+	// Capture compressed and encrypted data.
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+	cfg, deps := setupConfig(t, enabledTabularCollectorConfigPath)
+	cfg.CaptureDir = captureDir
+	cfg.ScheduledSyncDisabled = true
+	cfg.CaptureCompression = datacapture.CompressionZstd
+	cfg.Encryption = &EncryptionConfig{Key: encodedKey}
+	_, err := cfg.Validate("services.0.attributes")
+	test.That(t, err, test.ShouldBeNil)
+	resources := resourcesFromDeps(t, r, deps)
+	err = dmsvc.Reconfigure(context.Background(), resources, resource.Config{ConvertedAttributes: cfg})
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 20; i++ {
+		mockClock.Add(time.Millisecond * 10)
+	}
+	test.That(t, dmsvc.Close(context.Background()), test.ShouldBeNil)
+
+	// The captured files cannot be read without the key.
+	filePaths := getAllFilePaths(captureDir)
+	test.That(t, len(filePaths), test.ShouldBeGreaterThan, 0)
+	_, err = datacapture.SensorDataFromFilePath(filePaths[0])
+	test.That(t, err, test.ShouldNotBeNil)
+	keyFile := filepath.Join(t.TempDir(), "capture.key")
+	test.That(t, os.WriteFile(keyFile, []byte(encodedKey), 0o600), test.ShouldBeNil)
+	t.Setenv(datacapture.KeyFileEnvVar, keyFile)
+	numFiles, capturedData, err := getCapturedData(captureDir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(capturedData), test.ShouldBeGreaterThan, 0)
+	t.Setenv(datacapture.KeyFileEnvVar, "")
+
+	// The data is decrypted before it is uploaded.
+	newDMSvc, r := newTestDataManager(t)
+	defer newDMSvc.Close(context.Background())
+	mockClient := mockDataSyncServiceClient{
+		succesfulDCRequests: make(chan *v1.DataCaptureUploadRequest, 100),
+		failedDCRequests:    make(chan *v1.DataCaptureUploadRequest, 100),
+		fail:                &atomic.Bool{},
+	}
+	newDMSvc.SetSyncerConstructor(getTestSyncerConstructorMock(mockClient))
+	cfg.CaptureDisabled = true
+	resources = resourcesFromDeps(t, r, deps)
+	err = newDMSvc.Reconfigure(context.Background(), resources, resource.Config{ConvertedAttributes: cfg})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, newDMSvc.Sync(context.Background(), nil), test.ShouldBeNil)
+
+	var successfulReqs []*v1.DataCaptureUploadRequest
+	for i := 0; i < numFiles; i++ {
+		select {
+		case <-time.After(time.Second * 5):
+			t.Fatalf("timed out waiting for sync request")
+		case r := <-mockClient.succesfulDCRequests:
+			successfulReqs = append(successfulReqs, r)
+		}
+	}
+	waitUntilNoFiles(captureDir)
+	compareSensorData(t, v1.DataType_DATA_TYPE_TABULAR_SENSOR, getUploadedData(successfulReqs), capturedData)
+}
+
+func TestSyncTargetConfigValidation(t *testing.T) {
+	cfg := &Config{
+		SyncTargets: []datasync.TargetConfig{
+			{Name: "mirror", Type: datasync.TargetTypeLocal, Directory: "/tmp/mirror"},
+			{Name: "minio", Type: datasync.TargetTypeS3, Endpoint: "http://localhost:9000", Bucket: "data"},
+		},
+		DefaultSyncTarget: "minio",
+	}
+	_, err := cfg.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	cfg.DefaultSyncTarget = "unknown"
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, errors.New(`path: unknown default sync target "unknown"`))
+
+	cfg.DefaultSyncTarget = ""
+	cfg.ResourceConfigs = []*datamanager.DataCaptureConfig{{SyncTarget: "mirror"}, {SyncTarget: "mirorr"}}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, errors.New(`path.resource_configs.1: unknown sync target "mirorr"`))
+
+	cfg.ResourceConfigs = nil
+	cfg.SyncTargets = append(cfg.SyncTargets, datasync.TargetConfig{Name: "mirror", Type: datasync.TargetTypeGRPC, Endpoint: "x"})
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, errors.New(`path.sync_targets.2: duplicate sync target name "mirror"`))
+
+	cfg.SyncTargets = []datasync.TargetConfig{{Name: "cloud", Type: datasync.TargetTypeLocal, Directory: "/tmp"}}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldBeError, errors.New(`path.sync_targets.0: sync target name "cloud" is reserved for the cloud`))
+}
+
 func getAllFilePaths(dir string) []string {
 	var filePaths []string
 
@@ -710,8 +850,8 @@ func (m *mockFileUploadClient) CloseSend() error {
 }
 
 func getTestSyncerConstructorMock(client mockDataSyncServiceClient) datasync.ManagerConstructor {
-	return func(identity string, _ v1.DataSyncServiceClient, logger golog.Logger) (datasync.Manager, error) {
-		return datasync.NewManager(identity, client, logger)
+	return func(identity string, _ datasync.Targets, logger golog.Logger) (datasync.Manager, error) {
+		return datasync.NewManager(identity, datasync.Targets{Default: datasync.NewCloudTarget(client)}, logger)
 	}
 }
 
@@ -0,0 +1,283 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// The resource methods trigger conditions are evaluated with. These are declared here rather than asserting the
+// sensor, vision and motor APIs so that any resource exposing the method can be used.
+type (
+	readingsResource interface {
+		Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error)
+	}
+	detectionsResource interface {
+		DetectionsFromCamera(ctx context.Context, cameraName string, extra map[string]interface{}) (
+			[]objectdetection.Detection, error)
+	}
+	poweredResource interface {
+		IsPowered(ctx context.Context, extra map[string]interface{}) (bool, float64, error)
+	}
+)
+
+// triggerCondition is a datamanager.TriggerCondition along with the resource it is evaluated against.
+type triggerCondition struct {
+	datamanager.TriggerCondition
+	res resource.Resource
+}
+
+// resolveTriggerConditions looks up the resources of conf's conditions in deps.
+func resolveTriggerConditions(conf *datamanager.TriggerConfig, deps resource.Dependencies) ([]triggerCondition, error) {
+	conds := make([]triggerCondition, 0, len(conf.Conditions))
+	for _, cond := range conf.Conditions {
+		res, err := deps.Lookup(cond.Resource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find resource for %s trigger condition", cond.Type)
+		}
+		var ok bool
+		switch cond.Type {
+		case datamanager.TriggerConditionSensorReading:
+			_, ok = res.(readingsResource)
+		case datamanager.TriggerConditionDetection:
+			_, ok = res.(detectionsResource)
+		case datamanager.TriggerConditionPowered:
+			_, ok = res.(poweredResource)
+		}
+		if !ok {
+			return nil, errors.Errorf("resource %s does not support %s trigger conditions", cond.Resource, cond.Type)
+		}
+		conds = append(conds, triggerCondition{TriggerCondition: cond, res: res})
+	}
+	return conds, nil
+}
+
+// met returns whether the condition currently holds.
+func (c *triggerCondition) met(ctx context.Context) (bool, error) {
+	met, err := c.evaluate(ctx)
+	if err != nil {
+		return false, err
+	}
+	return met != c.Negate, nil
+}
+
+func (c *triggerCondition) evaluate(ctx context.Context) (bool, error) {
+	switch c.Type {
+	case datamanager.TriggerConditionSensorReading:
+		readings, err := c.res.(readingsResource).Readings(ctx, nil)
+		if err != nil {
+			return false, err
+		}
+		value, err := readingValue(readings, c.Key)
+		if err != nil {
+			return false, err
+		}
+		return compareTriggerValue(value, c.Operator, c.Value), nil
+	case datamanager.TriggerConditionDetection:
+		detections, err := c.res.(detectionsResource).DetectionsFromCamera(ctx, c.Camera, nil)
+		if err != nil {
+			return false, err
+		}
+		for _, det := range detections {
+			if det.Label() != c.Label {
+				continue
+			}
+			if c.Operator == "" || compareTriggerValue(det.Score(), c.Operator, c.Value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case datamanager.TriggerConditionPowered:
+		powered, _, err := c.res.(poweredResource).IsPowered(ctx, nil)
+		return powered, err
+	default:
+		return false, errors.Errorf("unsupported trigger condition type %q", c.Type)
+	}
+}
+
+// readingValue returns the numeric value at the dot separated key in readings. Booleans are returned as 0 or 1.
+func readingValue(readings map[string]interface{}, key string) (float64, error) {
+	var curr interface{} = readings
+	for _, part := range strings.Split(key, ".") {
+		m, ok := curr.(map[string]interface{})
+		if !ok {
+			return 0, errors.Errorf("reading %q is not a map", key)
+		}
+		if curr, ok = m[part]; !ok {
+			return 0, errors.Errorf("no reading with key %q", key)
+		}
+	}
+	switch v := curr.(type) {
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	default:
+		return 0, errors.Errorf("reading %q has non-numeric type %T", key, curr)
+	}
+}
+
+func compareTriggerValue(value float64, operator string, threshold float64) bool {
+	switch operator {
+	case datamanager.TriggerOperatorGreaterThan:
+		return value > threshold
+	case datamanager.TriggerOperatorGreaterThanEqual:
+		return value >= threshold
+	case datamanager.TriggerOperatorLessThan:
+		return value < threshold
+	case datamanager.TriggerOperatorLessThanEqual:
+		return value <= threshold
+	case datamanager.TriggerOperatorEqual:
+		return value == threshold
+	case datamanager.TriggerOperatorNotEqual:
+		return value != threshold
+	default:
+		return false
+	}
+}
+
+// conditionTrigger is a data.Trigger which evaluates its conditions in the background. It stays triggered for the
+// configured post trigger duration after its conditions stop holding.
+type conditionTrigger struct {
+	logger      golog.Logger
+	conditions  []triggerCondition
+	requireAll  bool
+	postTrigger time.Duration
+
+	triggered     atomic.Bool
+	lastMet       time.Time
+	cancel        context.CancelFunc
+	activeWorkers sync.WaitGroup
+}
+
+// newConditionTrigger starts evaluating conditions at the configured frequency.
+func newConditionTrigger(
+	conf *datamanager.TriggerConfig,
+	conditions []triggerCondition,
+	logger golog.Logger,
+) *conditionTrigger {
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	t := &conditionTrigger{
+		logger:      logger,
+		conditions:  conditions,
+		requireAll:  conf.RequireAll,
+		postTrigger: time.Duration(conf.PostTriggerSecs * float64(time.Second)),
+		cancel:      cancel,
+	}
+	// The ticker must be created before returning so that tests can advance the mock clock deterministically.
+	ticker := clock.Ticker(getDurationFromHz(conf.EvaluationFrequencyHzOrDefault()))
+	t.activeWorkers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer t.activeWorkers.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			case <-ticker.C:
+				t.update(cancelCtx)
+			}
+		}
+	})
+	return t
+}
+
+// Triggered returns whether data should currently be persisted.
+func (t *conditionTrigger) Triggered() bool {
+	return t.triggered.Load()
+}
+
+func (t *conditionTrigger) update(ctx context.Context) {
+	if t.evaluate(ctx) {
+		t.lastMet = clock.Now()
+		t.triggered.Store(true)
+		return
+	}
+	if t.triggered.Load() && clock.Since(t.lastMet) >= t.postTrigger {
+		t.triggered.Store(false)
+	}
+}
+
+// evaluate returns whether the conditions hold. A condition that fails to evaluate is not met.
+func (t *conditionTrigger) evaluate(ctx context.Context) bool {
+	for _, cond := range t.conditions {
+		met, err := cond.met(ctx)
+		if err != nil && ctx.Err() == nil {
+			t.logger.Debugw("failed to evaluate trigger condition", "resource", cond.Resource.String(), "error", err)
+		}
+		if met && !t.requireAll {
+			return true
+		}
+		if !met && t.requireAll {
+			return false
+		}
+	}
+	return t.requireAll
+}
+
+// usesResources returns whether the trigger is evaluated against exactly the resources of conditions.
+func (t *conditionTrigger) usesResources(conditions []triggerCondition) bool {
+	if len(t.conditions) != len(conditions) {
+		return false
+	}
+	for i := range conditions {
+		if t.conditions[i].res != conditions[i].res {
+			return false
+		}
+	}
+	return true
+}
+
+// Close stops evaluating the trigger's conditions.
+func (t *conditionTrigger) Close() {
+	t.cancel()
+	t.activeWorkers.Wait()
+}
+
+// triggerDependencies returns the names of the non-component resources trigger conditions are evaluated against,
+// since components are already weak dependencies of the data manager.
+func triggerDependencies(path string, resConfs []*datamanager.DataCaptureConfig) ([]string, error) {
+	var deps []string
+	for idx, resConf := range resConfs {
+		if resConf.Trigger == nil {
+			continue
+		}
+		if err := resConf.Trigger.Validate(fmt.Sprintf("%s.resource_configs.%d.trigger", path, idx)); err != nil {
+			return nil, err
+		}
+		for _, cond := range resConf.Trigger.Conditions {
+			if cond.Resource.API.Type.Name != resource.APITypeComponentName {
+				deps = append(deps, cond.Resource.String())
+			}
+		}
+	}
+	return deps, nil
+}
@@ -0,0 +1,105 @@
+package builtin
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	clk "github.com/benbjohnson/clock"
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestConditionTrigger(t *testing.T) {
+	mockClock := clk.NewMock()
+	clock = mockClock
+
+	var temperature atomic.Int64
+	readingsCalls := make(chan struct{}, 10)
+	thermometer := inject.NewSensor("thermometer")
+	thermometer.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		readingsCalls <- struct{}{}
+		return map[string]interface{}{"air": map[string]interface{}{"temp": float64(temperature.Load())}}, nil
+	}
+	deps := resource.Dependencies{thermometer.Name(): thermometer}
+
+	conf := &datamanager.TriggerConfig{
+		Conditions: []datamanager.TriggerCondition{{
+			Type:     datamanager.TriggerConditionSensorReading,
+			Resource: sensor.Named("thermometer"),
+			Key:      "air.temp",
+			Operator: datamanager.TriggerOperatorGreaterThan,
+			Value:    30,
+		}},
+		PostTriggerSecs: 2,
+	}
+	test.That(t, conf.Validate("path"), test.ShouldBeNil)
+	conditions, err := resolveTriggerConditions(conf, deps)
+	test.That(t, err, test.ShouldBeNil)
+	trigger := newConditionTrigger(conf, conditions, golog.NewTestLogger(t))
+	defer trigger.Close()
+	test.That(t, trigger.Triggered(), test.ShouldBeFalse)
+
+	temperature.Store(35)
+	mockClock.Add(time.Second)
+	<-readingsCalls
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, trigger.Triggered(), test.ShouldBeTrue)
+	})
+
+	// The trigger stays fired until the post trigger duration has passed since the condition last held.
+	temperature.Store(20)
+	mockClock.Add(time.Second)
+	<-readingsCalls
+	time.Sleep(10 * time.Millisecond)
+	test.That(t, trigger.Triggered(), test.ShouldBeTrue)
+
+	mockClock.Add(time.Second)
+	<-readingsCalls
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, trigger.Triggered(), test.ShouldBeFalse)
+	})
+}
+
+func TestTriggerConfigValidation(t *testing.T) {
+	visionName := resource.NewName(resource.APINamespaceRDK.WithServiceType("vision"), "detector")
+	detection := datamanager.TriggerCondition{
+		Type:     datamanager.TriggerConditionDetection,
+		Resource: visionName,
+		Camera:   "cam",
+		Label:    "person",
+	}
+	powered := datamanager.TriggerCondition{
+		Type:     datamanager.TriggerConditionPowered,
+		Resource: resource.NewName(resource.APINamespaceRDK.WithComponentType("motor"), "m1"),
+	}
+
+	conf := &Config{ResourceConfigs: []*datamanager.DataCaptureConfig{
+		{Trigger: &datamanager.TriggerConfig{Conditions: []datamanager.TriggerCondition{detection, powered}}},
+	}}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	// Only the vision service is added, since components are weak dependencies.
+	test.That(t, deps, test.ShouldContain, visionName.String())
+	test.That(t, len(deps), test.ShouldEqual, 2)
+
+	badOperator := detection
+	badOperator.Operator = "~="
+	conf.ResourceConfigs[0].Trigger.Conditions = []datamanager.TriggerCondition{badOperator}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "path.resource_configs.0.trigger.conditions.0")
+
+	conf.ResourceConfigs[0].Trigger.Conditions = nil
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,48 @@
+package datamanager
+
+import "github.com/pkg/errors"
+
+// maxFrameHashDistance is the number of bits in a camera frame's perceptual hash.
+const maxFrameHashDistance = 64
+
+// ChangeDetectionConfig makes a capture method only persist readings that have changed since the last reading
+// persisted. Tabular readings are compared field by field, and camera frames by their perceptual hash. The number of
+// readings suppressed is recorded in the metadata of the data capture files written, and reported in the status of the
+// data manager.
+type ChangeDetectionConfig struct {
+	// Deadbands are keyed by the dot separated path of a field in tabular readings, such as "readings.temp", and apply
+	// to every field nested within it. Numeric fields without a deadband are changed by any change in value.
+	Deadbands map[string]DeadbandConfig `json:"deadbands,omitempty"`
+	// FrameHashDistance is the number of bits, out of 64, the perceptual hashes of two camera frames may differ by
+	// for the frames to be considered identical.
+	FrameHashDistance int `json:"frame_hash_distance,omitempty"`
+	// MaxSuppressedSecs, if set, persists a reading at least this often even if readings are unchanged.
+	MaxSuppressedSecs float64 `json:"max_suppressed_secs,omitempty"`
+}
+
+// DeadbandConfig is how much a numeric field must change by for a reading to be considered changed. If both are set,
+// a change must exceed both.
+type DeadbandConfig struct {
+	Absolute float64 `json:"absolute,omitempty"`
+	// Relative is a fraction of the field's last persisted value.
+	Relative float64 `json:"relative,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *ChangeDetectionConfig) Validate(path string) error {
+	for field, deadband := range c.Deadbands {
+		if field == "" {
+			return errors.Errorf("%s.deadbands: field cannot be empty", path)
+		}
+		if deadband.Absolute < 0 || deadband.Relative < 0 {
+			return errors.Errorf("%s.deadbands.%s: thresholds cannot be negative", path, field)
+		}
+	}
+	if c.FrameHashDistance < 0 || c.FrameHashDistance > maxFrameHashDistance {
+		return errors.Errorf("%s: frame_hash_distance must be between 0 and %d", path, maxFrameHashDistance)
+	}
+	if c.MaxSuppressedSecs < 0 {
+		return errors.Errorf("%s: max_suppressed_secs cannot be negative", path)
+	}
+	return nil
+}
@@ -53,6 +53,15 @@ func (c *client) Sync(ctx context.Context, extra map[string]interface{}) error {
 	return nil
 }
 
+// GetStatus returns the status of the remote service. It fails if the service does not report its status.
+func (c *client) GetStatus(ctx context.Context, extra map[string]interface{}) (Status, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": GetStatusCommand, "extra": extra})
+	if err != nil {
+		return Status{}, err
+	}
+	return statusFromMap(resp)
+}
+
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
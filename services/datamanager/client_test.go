@@ -69,6 +69,34 @@ func TestClient(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, extraOptions, test.ShouldResemble, extra)
 
+		// GetStatus
+		status := datamanager.Status{
+			Collectors: []datamanager.CollectorStatus{{
+				Resource:        "rdk:component:arm/arm1",
+				Method:          "EndPosition",
+				CaptureRateHz:   9.5,
+				LastCaptureTime: "2023-06-01T12:00:00Z",
+				QueueDepth:      3,
+			}},
+			PendingFiles: 2,
+			PendingBytes: 1024,
+			Sync: datamanager.SyncStatus{
+				Enabled: true,
+				Uploads: []datamanager.UploadStatus{{Path: "a.capture", State: "retrying", Attempts: 2, LastError: "unavailable"}},
+			},
+		}
+		injectDS.GetStatusFunc = func(ctx context.Context, extra map[string]interface{}) (datamanager.Status, error) {
+			extraOptions = extra
+			return status, nil
+		}
+		extra = map[string]interface{}{"foo": "GetStatus"}
+		reporter, ok := client.(datamanager.StatusReporter)
+		test.That(t, ok, test.ShouldBeTrue)
+		respStatus, err := reporter.GetStatus(context.Background(), extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, respStatus, test.ShouldResemble, status)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
 		// DoCommand
 		injectDS.DoCommandFunc = testutils.EchoFunc
 		resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"time"
 
 	servicepb "go.viam.com/api/service/datamanager/v1"
 	"golang.org/x/exp/slices"
@@ -22,6 +23,7 @@ func init() {
 			RPCServiceDesc:              &servicepb.DataManagerService_ServiceDesc,
 			RPCClient:                   NewClientFromConn,
 			MaxInstance:                 resource.DefaultMaxInstance,
+			Status:                      CreateStatus,
 		},
 		resource.AssociatedConfigRegistration[*DataCaptureConfigs]{
 			AttributeMapConverter: func(attributes utils.AttributeMap) (*DataCaptureConfigs, error) {
@@ -45,6 +47,53 @@ type Service interface {
 	Sync(ctx context.Context, extra map[string]interface{}) error
 }
 
+// StatusReporter is implemented by data manager services that report the health of their collectors and syncing.
+type StatusReporter interface {
+	GetStatus(ctx context.Context, extra map[string]interface{}) (Status, error)
+}
+
+// RetentionReporter is implemented by data manager services that evict captured data from local storage.
+type RetentionReporter interface {
+	RetentionStatus() RetentionStatus
+}
+
+// RetentionStatus describes the captured data a data manager has evicted from local storage.
+type RetentionStatus struct {
+	// LowDiskSpace is whether free disk space is below the floor, in which case data capture is stopped.
+	LowDiskSpace    bool          `json:"low_disk_space"`
+	FilesEvicted    int64         `json:"files_evicted"`
+	BytesEvicted    int64         `json:"bytes_evicted"`
+	RecentEvictions []EvictedFile `json:"recent_evictions"`
+}
+
+// EvictedFile describes a single data capture file that was deleted by a retention policy.
+type EvictedFile struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+	Reason    string `json:"reason"`
+	EvictedAt string `json:"evicted_at"`
+}
+
+// NewEvictedFile returns an EvictedFile for the file at path which was deleted at evictedAt.
+func NewEvictedFile(path string, sizeBytes int64, reason string, evictedAt time.Time) EvictedFile {
+	return EvictedFile{
+		Path:      path,
+		SizeBytes: sizeBytes,
+		Reason:    reason,
+		EvictedAt: evictedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// CreateStatus creates a status from the data manager. Only services which enforce a retention policy report
+// anything.
+func CreateStatus(ctx context.Context, svc Service) (interface{}, error) {
+	reporter, ok := svc.(RetentionReporter)
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	return map[string]interface{}{"retention": reporter.RetentionStatus()}, nil
+}
+
 // SubtypeName is the name of the type of service.
 const SubtypeName = "data_manager"
 
@@ -80,6 +129,14 @@ type DataCaptureConfig struct {
 	Disabled           bool              `json:"disabled"`
 	Tags               []string          `json:"tags,omitempty"`
 	CaptureDirectory   string            `json:"capture_directory"`
+	// RetentionPriority orders eviction when local storage runs low; lower priority data is evicted first.
+	RetentionPriority int `json:"retention_priority"`
+	// SyncTarget is the name of the sync target this data is synced to, overriding the service's default.
+	SyncTarget string `json:"sync_target,omitempty"`
+	// Trigger, if set, only persists captured data while its conditions hold.
+	Trigger *TriggerConfig `json:"trigger,omitempty"`
+	// ChangeDetection, if set, only persists captured data that has changed.
+	ChangeDetection *ChangeDetectionConfig `json:"change_detection,omitempty"`
 }
 
 // Equals checks if one capture config is equal to another.
@@ -93,5 +150,9 @@ func (c *DataCaptureConfig) Equals(other *DataCaptureConfig) bool {
 		c.Disabled == other.Disabled &&
 		slices.Compare(c.Tags, other.Tags) == 0 &&
 		reflect.DeepEqual(c.AdditionalParams, other.AdditionalParams) &&
-		c.CaptureDirectory == other.CaptureDirectory
+		c.CaptureDirectory == other.CaptureDirectory &&
+		c.RetentionPriority == other.RetentionPriority &&
+		c.SyncTarget == other.SyncTarget &&
+		reflect.DeepEqual(c.Trigger, other.Trigger) &&
+		reflect.DeepEqual(c.ChangeDetection, other.ChangeDetection)
 }
@@ -3,7 +3,11 @@ package datacapture
 import (
 	"sync"
 
+	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // MaxFileSize is the maximum size in bytes of a data capture file.
@@ -16,19 +20,54 @@ type BufferedWriter interface {
 	Path() string
 }
 
+// SuppressedSamplesKey is the key of the DataCaptureMetadata method parameter recording how many samples were
+// captured but deliberately not written, since the previous file in the same directory was completed.
+const SuppressedSamplesKey = "suppressed_samples"
+
+// SuppressionRecorder is implemented by BufferedWriters that record how many samples were not written to them
+// because they were found to be unchanged.
+type SuppressionRecorder interface {
+	RecordSuppressed(n int64)
+}
+
+// SuppressedSamples returns the number of suppressed samples recorded in md.
+func SuppressedSamples(md *v1.DataCaptureMetadata) (int64, error) {
+	param, ok := md.GetMethodParameters()[SuppressedSamplesKey]
+	if !ok {
+		return 0, nil
+	}
+	var count wrapperspb.Int64Value
+	if err := param.UnmarshalTo(&count); err != nil {
+		return 0, err
+	}
+	return count.GetValue(), nil
+}
+
 // Buffer is a persistent queue of SensorData backed by a series of datacapture.Files.
 type Buffer struct {
 	Directory string
 	MetaData  *v1.DataCaptureMetadata
 	nextFile  *File
 	lock      sync.Mutex
+	encoding  EncodingOptions
+
+	// suppressed counts samples suppressed since the last file was completed, and nextFileSuppressed how many of
+	// them are recorded in the metadata nextFile was created with.
+	suppressed         int64
+	nextFileSuppressed int64
 }
 
 // NewBuffer returns a new Buffer.
 func NewBuffer(dir string, md *v1.DataCaptureMetadata) *Buffer {
+	return NewEncodedBuffer(dir, md, EncodingOptions{})
+}
+
+// NewEncodedBuffer returns a new Buffer whose files are compressed and encrypted as described by opts.
+func NewEncodedBuffer(dir string, md *v1.DataCaptureMetadata, opts EncodingOptions) *Buffer {
 	return &Buffer{
 		Directory: dir,
 		MetaData:  md,
+		encoding:  opts,
 	}
 }
 
@@ -40,7 +79,11 @@ func (b *Buffer) Write(item *v1.SensorData) error {
 	defer b.lock.Unlock()
 
 	if item.GetBinary() != nil {
-		binFile, err := NewFile(b.Directory, b.MetaData)
+		md, err := b.metadata()
+		if err != nil {
+			return err
+		}
+		binFile, err := NewEncodedFile(b.Directory, md, b.encoding)
 		if err != nil {
 			return err
 		}
@@ -50,43 +93,88 @@ func (b *Buffer) Write(item *v1.SensorData) error {
 		if err := binFile.Close(); err != nil {
 			return err
 		}
+		b.suppressed = 0
 		return nil
 	}
 
-	if b.nextFile == nil {
-		nextFile, err := NewFile(b.Directory, b.MetaData)
-		if err != nil {
+	if b.nextFile != nil && b.nextFile.Size() > MaxFileSize {
+		if err := b.closeNextFile(); err != nil {
 			return err
 		}
-		b.nextFile = nextFile
-	} else if b.nextFile.Size() > MaxFileSize {
-		if err := b.nextFile.Close(); err != nil {
+	}
+	if b.nextFile == nil {
+		md, err := b.metadata()
+		if err != nil {
 			return err
 		}
-		nextFile, err := NewFile(b.Directory, b.MetaData)
+		nextFile, err := NewEncodedFile(b.Directory, md, b.encoding)
 		if err != nil {
 			return err
 		}
 		b.nextFile = nextFile
+		b.nextFileSuppressed = b.suppressed
 	}
 
 	return b.nextFile.WriteNext(item)
 }
 
-// Flush flushes all buffered data to disk and marks any in progress file as complete.
-func (b *Buffer) Flush() error {
+// RecordSuppressed records that n samples were not written to b. The count is stored in the metadata of the file
+// being written, or the next file written if there is none.
+func (b *Buffer) RecordSuppressed(n int64) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	if b.nextFile == nil {
-		return nil
+	b.suppressed += n
+}
+
+// metadata returns the metadata of the next file, including the number of samples suppressed so far.
+func (b *Buffer) metadata() (*v1.DataCaptureMetadata, error) {
+	if b.suppressed == 0 {
+		return b.MetaData, nil
+	}
+	count, err := anypb.New(wrapperspb.Int64(b.suppressed))
+	if err != nil {
+		return nil, err
+	}
+	md, ok := proto.Clone(b.MetaData).(*v1.DataCaptureMetadata)
+	if !ok {
+		return nil, errors.New("failed to copy data capture metadata")
+	}
+	if md.MethodParameters == nil {
+		md.MethodParameters = make(map[string]*anypb.Any)
+	}
+	md.MethodParameters[SuppressedSamplesKey] = count
+	return md, nil
+}
+
+// closeNextFile completes nextFile, updating its metadata if samples were suppressed while it was being written.
+func (b *Buffer) closeNextFile() error {
+	var err error
+	if b.suppressed == b.nextFileSuppressed {
+		err = b.nextFile.Close()
+	} else {
+		var md *v1.DataCaptureMetadata
+		if md, err = b.metadata(); err == nil {
+			err = b.nextFile.closeWithMetadata(md)
+		}
 	}
-	if err := b.nextFile.Close(); err != nil {
+	if err != nil {
 		return err
 	}
 	b.nextFile = nil
+	b.suppressed = 0
 	return nil
 }
 
+// Flush flushes all buffered data to disk and marks any in progress file as complete.
+func (b *Buffer) Flush() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.nextFile == nil {
+		return nil
+	}
+	return b.closeNextFile()
+}
+
 // Path returns the path to the directory containing the backing data capture files.
 func (b *Buffer) Path() string {
 	return b.Directory
@@ -138,3 +138,56 @@ func getCaptureFiles(dir string) (dcFiles, progFiles []string) {
 	})
 	return dcFiles, progFiles
 }
+
+func TestBufferSuppressedSamples(t *testing.T) {
+	prevMaxFileSize := MaxFileSize
+	MaxFileSize = 64 * 1024
+	defer func() { MaxFileSize = prevMaxFileSize }()
+	key := make([]byte, 32)
+	for _, opts := range []EncodingOptions{{}, {Compression: CompressionZstd, Key: key}} {
+		tmpDir := t.TempDir()
+		md := &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+		b := NewEncodedBuffer(tmpDir, md, opts)
+
+		// Samples suppressed while a file is being written are recorded in its metadata once it is completed.
+		b.RecordSuppressed(1)
+		test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+		b.RecordSuppressed(2)
+		test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+		test.That(t, b.Flush(), test.ShouldBeNil)
+
+		dcFiles, progFiles := getCaptureFiles(tmpDir)
+		test.That(t, len(progFiles), test.ShouldEqual, 0)
+		test.That(t, len(dcFiles), test.ShouldEqual, 1)
+		readMetadataAndReadings := func(path string) (*v1.DataCaptureMetadata, []*v1.SensorData) {
+			//nolint:gosec
+			f, err := os.Open(path)
+			test.That(t, err, test.ShouldBeNil)
+			dcFile, err := ReadFileWithKey(f, opts.Key)
+			test.That(t, err, test.ShouldBeNil)
+			readings, err := SensorDataFromFile(dcFile)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, f.Close(), test.ShouldBeNil)
+			return dcFile.ReadMetadata(), readings
+		}
+		fileMD, readings := readMetadataAndReadings(dcFiles[0])
+		test.That(t, len(readings), test.ShouldEqual, 2)
+		suppressed, err := SuppressedSamples(fileMD)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, suppressed, test.ShouldEqual, 3)
+
+		// Samples suppressed before a binary reading are recorded in the metadata of its file.
+		test.That(t, os.Remove(dcFiles[0]), test.ShouldBeNil)
+		b.RecordSuppressed(4)
+		test.That(t, b.Write(binarySensorData), test.ShouldBeNil)
+		dcFiles, _ = getCaptureFiles(tmpDir)
+		test.That(t, len(dcFiles), test.ShouldEqual, 1)
+		fileMD, readings = readMetadataAndReadings(dcFiles[0])
+		test.That(t, len(readings), test.ShouldEqual, 1)
+		suppressed, err = SuppressedSamples(fileMD)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, suppressed, test.ShouldEqual, 4)
+		// The buffer's own metadata is unchanged.
+		test.That(t, md.GetMethodParameters(), test.ShouldBeEmpty)
+	}
+}
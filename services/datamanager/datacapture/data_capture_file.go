@@ -3,6 +3,7 @@ package datacapture
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -46,10 +48,27 @@ type File struct {
 	initialReadOffset int64
 	readOffset        int64
 	writeOffset       int64
+
+	// codec is set for files written with EncodingOptions. Readings written to such files are held in pending until
+	// a block is written, and the remaining readings of the block most recently read are held in unread. Blocks are
+	// counted as they are read and written, and lastRead is set once the last block of a completed file is read.
+	codec         *codec
+	pending       bytes.Buffer
+	unread        []*v1.SensorData
+	blocksRead    uint64
+	blocksWritten uint64
+	lastRead      bool
 }
 
-// ReadFile creates a File struct from a passed os.File previously constructed using NewFile.
+// ReadFile creates a File struct from a passed os.File previously constructed using NewFile. Encrypted files are
+// decrypted with the key in the file named by KeyFileEnvVar.
 func ReadFile(f *os.File) (*File, error) {
+	return ReadFileWithKey(f, nil)
+}
+
+// ReadFileWithKey creates a File struct from a passed os.File previously constructed using NewFile or
+// NewEncodedFile, decrypting it with key if it is encrypted.
+func ReadFileWithKey(f *os.File, key []byte) (*File, error) {
 	if !IsDataCaptureFile(f) {
 		return nil, errors.Errorf("%s is not a data capture file", f.Name())
 	}
@@ -58,11 +77,17 @@ func ReadFile(f *os.File) (*File, error) {
 		return nil, err
 	}
 
+	c, headerSize, err := readCodec(f, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read header from %s", f.Name())
+	}
+
 	md := &v1.DataCaptureMetadata{}
-	initOffset, err := pbutil.ReadDelimited(f, md)
+	n, err := pbutil.ReadDelimited(f, md)
 	if err != nil {
 		return nil, errors.Wrapf(err, fmt.Sprintf("failed to read DataCaptureMetadata from %s", f.Name()))
 	}
+	initOffset := headerSize + int64(n)
 
 	ret := File{
 		path:              f.Name(),
@@ -70,16 +95,53 @@ func ReadFile(f *os.File) (*File, error) {
 		writer:            bufio.NewWriter(f),
 		size:              finfo.Size(),
 		metadata:          md,
-		initialReadOffset: int64(initOffset),
-		readOffset:        int64(initOffset),
-		writeOffset:       int64(initOffset),
+		initialReadOffset: initOffset,
+		readOffset:        initOffset,
+		writeOffset:       initOffset,
+		codec:             c,
 	}
 
 	return &ret, nil
 }
 
+// ReadFileMetadata returns the metadata of the data capture file at path. Since metadata is never encrypted, no key
+// is needed.
+func ReadFileMetadata(path string) (*v1.DataCaptureMetadata, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer goutils.UncheckedErrorFunc(f.Close)
+	if !IsDataCaptureFile(f) {
+		return nil, errors.Errorf("%s is not a data capture file", f.Name())
+	}
+	if _, err := readHeader(f); err != nil {
+		return nil, errors.Wrapf(err, "failed to read header from %s", f.Name())
+	}
+	md := &v1.DataCaptureMetadata{}
+	if _, err := pbutil.ReadDelimited(f, md); err != nil {
+		return nil, errors.Wrapf(err, "failed to read DataCaptureMetadata from %s", f.Name())
+	}
+	return md, nil
+}
+
 // NewFile creates a new File with the specified md in the specified directory.
 func NewFile(dir string, md *v1.DataCaptureMetadata) (*File, error) {
+	return NewEncodedFile(dir, md, EncodingOptions{})
+}
+
+// NewEncodedFile creates a new File with the specified md in the specified directory, whose readings are compressed
+// and encrypted as described by opts.
+func NewEncodedFile(dir string, md *v1.DataCaptureMetadata, opts EncodingOptions) (*File, error) {
+	var c *codec
+	if opts.encoded() {
+		var err error
+		if c, err = newWriteCodec(opts); err != nil {
+			return nil, err
+		}
+	}
+
 	fileName := filepath.Join(dir, getFileTimestampName()) + InProgressFileExt
 	//nolint:gosec
 	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0o600)
@@ -87,19 +149,30 @@ func NewFile(dir string, md *v1.DataCaptureMetadata) (*File, error) {
 		return nil, err
 	}
 
+	var size int64
+	if c != nil {
+		if _, err := f.Write(c.header); err != nil {
+			return nil, err
+		}
+		size = int64(len(c.header))
+	}
+
 	// Then write first metadata message to the file.
 	n, err := pbutil.WriteDelimited(f, md)
 	if err != nil {
 		return nil, err
 	}
+	size += int64(n)
 	return &File{
 		path:              f.Name(),
 		writer:            bufio.NewWriter(f),
 		file:              f,
-		size:              int64(n),
-		initialReadOffset: int64(n),
-		readOffset:        int64(n),
-		writeOffset:       int64(n),
+		size:              size,
+		metadata:          md,
+		initialReadOffset: size,
+		readOffset:        size,
+		writeOffset:       size,
+		codec:             c,
 	}, nil
 }
 
@@ -108,11 +181,23 @@ func (f *File) ReadMetadata() *v1.DataCaptureMetadata {
 	return f.metadata
 }
 
+// Encoding returns how the readings of f are stored on disk.
+func (f *File) Encoding() EncodingOptions {
+	if f.codec == nil {
+		return EncodingOptions{}
+	}
+	return f.codec.options
+}
+
 // ReadNext returns the next SensorData reading.
 func (f *File) ReadNext() (*v1.SensorData, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
+	if f.codec != nil {
+		return f.readNextEncoded()
+	}
+
 	if err := f.writer.Flush(); err != nil {
 		return nil, err
 	}
@@ -130,11 +215,66 @@ func (f *File) ReadNext() (*v1.SensorData, error) {
 	return &r, nil
 }
 
+func (f *File) readNextEncoded() (*v1.SensorData, error) {
+	for len(f.unread) == 0 {
+		if err := f.writePending(false); err != nil {
+			return nil, err
+		}
+		if err := f.writer.Flush(); err != nil {
+			return nil, err
+		}
+		if _, err := f.file.Seek(f.readOffset, 0); err != nil {
+			return nil, err
+		}
+		sealed, read, err := readBlock(f.file)
+		if errors.Is(err, io.EOF) && f.codec.authenticated() && !f.lastRead && filepath.Ext(f.path) == FileExt {
+			return nil, errors.Errorf("data capture file %s is truncated", f.path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if f.lastRead {
+			return nil, errors.Errorf("data capture file %s has data after its last block", f.path)
+		}
+		block, last, err := f.codec.open(sealed, f.blocksRead)
+		if err != nil {
+			return nil, err
+		}
+		f.readOffset += int64(read)
+		f.blocksRead++
+		f.lastRead = last
+
+		r := bytes.NewReader(block)
+		for r.Len() > 0 {
+			next := &v1.SensorData{}
+			if _, err := pbutil.ReadDelimited(r, next); err != nil {
+				return nil, err
+			}
+			f.unread = append(f.unread, next)
+		}
+	}
+	next := f.unread[0]
+	f.unread = f.unread[1:]
+	return next, nil
+}
+
 // WriteNext writes the next SensorData reading.
 func (f *File) WriteNext(data *v1.SensorData) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
+	if f.codec != nil {
+		n, err := pbutil.WriteDelimited(&f.pending, data)
+		if err != nil {
+			return err
+		}
+		f.size += int64(n)
+		if f.pending.Len() >= blockSize {
+			return f.writePending(false)
+		}
+		return nil
+	}
+
 	if _, err := f.file.Seek(f.writeOffset, 0); err != nil {
 		return err
 	}
@@ -147,10 +287,37 @@ func (f *File) WriteNext(data *v1.SensorData) error {
 	return nil
 }
 
+// writePending writes the readings held in f.pending as a single compressed and encrypted block. The last block of
+// an authenticated file is written even if there are no readings left, so that readers can tell the file is complete.
+func (f *File) writePending(last bool) error {
+	if f.codec == nil || (f.pending.Len() == 0 && !(last && f.codec.authenticated())) {
+		return nil
+	}
+	sealed, err := f.codec.seal(f.pending.Bytes(), f.blocksWritten, last && f.codec.authenticated())
+	if err != nil {
+		return err
+	}
+	if _, err := f.file.Seek(f.writeOffset, 0); err != nil {
+		return err
+	}
+	n, err := writeBlock(f.writer, sealed)
+	if err != nil {
+		return err
+	}
+	f.size += int64(n) - int64(f.pending.Len())
+	f.writeOffset += int64(n)
+	f.blocksWritten++
+	f.pending.Reset()
+	return nil
+}
+
 // Flush flushes any buffered writes to disk.
 func (f *File) Flush() error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
+	if err := f.writePending(false); err != nil {
+		return err
+	}
 	return f.writer.Flush()
 }
 
@@ -159,6 +326,9 @@ func (f *File) Reset() {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 	f.readOffset = f.initialReadOffset
+	f.unread = nil
+	f.blocksRead = 0
+	f.lastRead = false
 }
 
 // Size returns the size of the file.
@@ -177,6 +347,9 @@ func (f *File) GetPath() string {
 func (f *File) Close() error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
+	if err := f.writePending(true); err != nil {
+		return err
+	}
 	if err := f.writer.Flush(); err != nil {
 		return err
 	}
@@ -190,6 +363,47 @@ func (f *File) Close() error {
 	return f.file.Close()
 }
 
+// closeWithMetadata closes the file like Close, replacing the metadata it was created with by md. Since metadata
+// precedes the readings, the file is rewritten to a new file which then replaces it.
+func (f *File) closeWithMetadata(md *v1.DataCaptureMetadata) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err := f.writePending(true); err != nil {
+		return err
+	}
+	if err := f.writer.Flush(); err != nil {
+		return err
+	}
+
+	readings := make([]byte, f.writeOffset-f.initialReadOffset)
+	if _, err := f.file.ReadAt(readings, f.initialReadOffset); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if f.codec != nil {
+		buf.Write(f.codec.header)
+	}
+	if _, err := pbutil.WriteDelimited(&buf, md); err != nil {
+		return err
+	}
+	buf.Write(readings)
+
+	// Write the new file under a temporary in progress name so that it is never synced partially written.
+	withoutExt := strings.TrimSuffix(f.file.Name(), filepath.Ext(f.file.Name()))
+	tmpName := withoutExt + ".rewrite" + InProgressFileExt
+	if err := os.WriteFile(tmpName, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, withoutExt+FileExt); err != nil {
+		return err
+	}
+	f.metadata = md
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(f.file.Name())
+}
+
 // Delete deletes the file.
 func (f *File) Delete() error {
 	f.lock.Lock()
@@ -1,10 +1,20 @@
 package datacapture
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
@@ -115,3 +125,163 @@ func TestReadCorruptedFile(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(sd), test.ShouldEqual, numReadings)
 }
+
+func TestEncodedFile(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	test.That(t, err, test.ShouldBeNil)
+	md := &v1.DataCaptureMetadata{ComponentName: "sensor1", Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	secret := "a very secret reading"
+	// Enough readings to span several blocks.
+	numReadings := 500
+	readings := make([]*v1.SensorData, 0, numReadings)
+	for i := 0; i < numReadings; i++ {
+		reading, err := structpb.NewStruct(map[string]interface{}{"index": float64(i), "secret": secret})
+		test.That(t, err, test.ShouldBeNil)
+		readings = append(readings, &v1.SensorData{
+			Metadata: &v1.SensorMetadata{},
+			Data:     &v1.SensorData_Struct{Struct: reading},
+		})
+	}
+
+	plainPath := writeTestFile(t, t.TempDir(), md, EncodingOptions{}, readings)
+	plainInfo, err := os.Stat(plainPath)
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, compression := range []string{CompressionNone, CompressionGzip, CompressionZstd} {
+		for _, encrypted := range []bool{false, true} {
+			opts := EncodingOptions{Compression: compression}
+			if encrypted {
+				opts.Key = key
+			}
+			if !opts.encoded() {
+				continue
+			}
+			t.Run(compression+"/encrypted="+strconv.FormatBool(encrypted), func(t *testing.T) {
+				path := writeTestFile(t, t.TempDir(), md, opts, readings)
+				//nolint:gosec
+				contents, err := os.ReadFile(path)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, bytes.Contains(contents, []byte(secret)), test.ShouldEqual, compression == CompressionNone && !encrypted)
+				if compression != CompressionNone {
+					test.That(t, int64(len(contents)), test.ShouldBeLessThan, plainInfo.Size())
+				}
+
+				//nolint:gosec
+				f, err := os.Open(path)
+				test.That(t, err, test.ShouldBeNil)
+				defer f.Close()
+				captureFile, err := ReadFileWithKey(f, key)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, proto.Equal(captureFile.ReadMetadata(), md), test.ShouldBeTrue)
+				actual, err := SensorDataFromFile(captureFile)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, len(actual), test.ShouldEqual, numReadings)
+				for i := range actual {
+					test.That(t, proto.Equal(actual[i], readings[i]), test.ShouldBeTrue)
+				}
+			})
+		}
+	}
+
+	t.Run("wrong or missing key", func(t *testing.T) {
+		path := writeTestFile(t, t.TempDir(), md, EncodingOptions{Compression: CompressionZstd, Key: key}, readings)
+		t.Setenv(KeyFileEnvVar, "")
+		_, err := SensorDataFromFilePath(path)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "no key")
+
+		wrongKey := make([]byte, 32)
+		//nolint:gosec
+		f, err := os.Open(path)
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		captureFile, err := ReadFileWithKey(f, wrongKey)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = captureFile.ReadNext()
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "failed to decrypt")
+	})
+
+	t.Run("reordered, duplicated or truncated blocks", func(t *testing.T) {
+		opts := EncodingOptions{Compression: CompressionGzip, Key: key}
+		path := writeTestFile(t, t.TempDir(), md, opts, readings)
+		prefix, blocks := splitBlocks(t, path)
+		test.That(t, len(blocks), test.ShouldBeGreaterThan, 2)
+
+		//nolint:gosec
+		f, err := os.Open(path)
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		captureFile, err := ReadFileWithKey(f, key)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, captureFile.Encoding(), test.ShouldResemble, opts)
+
+		for name, tampered := range map[string][][]byte{
+			"reordered":  append([][]byte{blocks[1], blocks[0]}, blocks[2:]...),
+			"duplicated": append([][]byte{blocks[0]}, blocks...),
+			"truncated":  blocks[:len(blocks)-1],
+			"appended":   append(append([][]byte{}, blocks...), blocks[len(blocks)-1]),
+		} {
+			tamperedPath := filepath.Join(t.TempDir(), name+FileExt)
+			test.That(t, os.WriteFile(tamperedPath, append(prefix, bytes.Join(tampered, nil)...), 0o600), test.ShouldBeNil)
+			//nolint:gosec
+			f, err := os.Open(tamperedPath)
+			test.That(t, err, test.ShouldBeNil)
+			defer f.Close()
+			captureFile, err := ReadFileWithKey(f, key)
+			test.That(t, err, test.ShouldBeNil)
+			_, err = SensorDataFromFile(captureFile)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("key file from environment", func(t *testing.T) {
+		path := writeTestFile(t, t.TempDir(), md, EncodingOptions{Key: key}, readings)
+		keyFile := filepath.Join(t.TempDir(), "capture.key")
+		test.That(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600), test.ShouldBeNil)
+		t.Setenv(KeyFileEnvVar, keyFile)
+		actual, err := SensorDataFromFilePath(path)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(actual), test.ShouldEqual, numReadings)
+	})
+}
+
+// writeTestFile writes readings to a completed data capture file in dir and returns its path.
+func writeTestFile(t *testing.T, dir string, md *v1.DataCaptureMetadata, opts EncodingOptions, readings []*v1.SensorData) string {
+	t.Helper()
+	f, err := NewEncodedFile(dir, md, opts)
+	test.That(t, err, test.ShouldBeNil)
+	for _, reading := range readings {
+		test.That(t, f.WriteNext(reading), test.ShouldBeNil)
+	}
+	test.That(t, f.Close(), test.ShouldBeNil)
+	return strings.TrimSuffix(f.GetPath(), InProgressFileExt) + FileExt
+}
+
+// splitBlocks returns the header and metadata of the encoded data capture file at path, followed by its length
+// delimited blocks.
+func splitBlocks(t *testing.T, path string) ([]byte, [][]byte) {
+	t.Helper()
+	//nolint:gosec
+	contents, err := os.ReadFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	r := bytes.NewReader(contents)
+	header, err := readHeader(r)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = pbutil.ReadDelimited(r, &v1.DataCaptureMetadata{})
+	test.That(t, err, test.ShouldBeNil)
+	prefix := contents[:len(contents)-r.Len()]
+	test.That(t, bytes.HasPrefix(prefix, header), test.ShouldBeTrue)
+
+	var blocks [][]byte
+	for r.Len() > 0 {
+		start := len(contents) - r.Len()
+		_, n, err := readBlock(r)
+		test.That(t, err, test.ShouldBeNil)
+		blocks = append(blocks, contents[start:start+n])
+		_, err = r.Seek(int64(start+n), io.SeekStart)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	return append([]byte{}, prefix...), blocks
+}
@@ -0,0 +1,375 @@
+package datacapture
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Supported data capture file compression algorithms.
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// KeyFileEnvVar names an environment variable holding the path to a file containing the base64 encoded AES key used
+// to encrypt and decrypt data capture files when no key is otherwise provided.
+const KeyFileEnvVar = "VIAM_CAPTURE_KEY_FILE"
+
+// blockSize is the number of bytes of readings buffered before they are compressed and encrypted as a block. It
+// matches the default bufio buffer size so that encoding a file does not change how much data can be lost on a crash.
+const blockSize = 4096
+
+// maxBlockSize bounds the size of a single block read from disk, so that a corrupt length cannot exhaust memory.
+const maxBlockSize = 1 << 30
+
+const (
+	encodingVersion = 1
+
+	compressionIDNone byte = 0
+	compressionIDGzip byte = 1
+	compressionIDZstd byte = 2
+
+	encryptionNone   byte = 0
+	encryptionAESGCM byte = 1
+)
+
+// encodedFileMagic starts encoded data capture files. Plain files start with the varint length of their metadata,
+// which is never 0 since metadata always names the captured resource, so the two cannot be confused.
+var encodedFileMagic = []byte{0x00, 'V', 'C', 'F'}
+
+var compressionIDs = map[string]byte{
+	CompressionNone: compressionIDNone,
+	CompressionGzip: compressionIDGzip,
+	CompressionZstd: compressionIDZstd,
+}
+
+// EncodingOptions describe how the readings of a data capture file are stored on disk. The zero value stores them
+// as plain length delimited protobuf messages.
+//
+// Encoded files start with a short header recording their encoding, followed by their DataCaptureMetadata, which
+// is never compressed or encrypted so that files can still be identified without the key. Readings are then stored
+// in blocks, each of which is compressed and then sealed with AES-GCM. Each block is authenticated along with its
+// position in the file, and the last block of a completed file is marked as such, so that blocks cannot be
+// reordered, duplicated or cut off without the file failing to decrypt.
+type EncodingOptions struct {
+	Compression string
+	// Key is a 16, 24 or 32 byte AES key. Files are not encrypted if it is empty.
+	Key []byte
+}
+
+func (o EncodingOptions) encoded() bool {
+	return o.Compression != CompressionNone || len(o.Key) > 0
+}
+
+// Validate ensures the encoding options are supported.
+func (o EncodingOptions) Validate() error {
+	if _, ok := compressionIDs[o.Compression]; !ok {
+		return errors.Errorf("unsupported compression %q, must be %s or %s", o.Compression, CompressionGzip, CompressionZstd)
+	}
+	if len(o.Key) > 0 {
+		if _, err := aes.NewCipher(o.Key); err != nil {
+			return errors.Wrap(err, "invalid encryption key")
+		}
+	}
+	return nil
+}
+
+// ParseKey decodes a base64 encoded AES key.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.Wrap(err, "encryption key must be base64 encoded")
+	}
+	if _, err := aes.NewCipher(key); err != nil {
+		return nil, errors.Wrap(err, "invalid encryption key")
+	}
+	return key, nil
+}
+
+// ReadKeyFile reads a base64 encoded AES key from the file at path.
+func ReadKeyFile(path string) ([]byte, error) {
+	//nolint:gosec
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParseKey(string(contents))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read key file %s", path)
+	}
+	return key, nil
+}
+
+// KeyFromEnv returns the key in the file named by KeyFileEnvVar, or nil if the variable is not set.
+func KeyFromEnv() ([]byte, error) {
+	path := os.Getenv(KeyFileEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	return ReadKeyFile(path)
+}
+
+// codec compresses and encrypts the blocks of an encoded file.
+type codec struct {
+	compression byte
+	aead        cipher.AEAD
+	header      []byte
+	options     EncodingOptions
+}
+
+func newCodec(compression byte, encryption byte, key []byte) (*codec, error) {
+	c := &codec{
+		compression: compression,
+		header:      append(append([]byte{}, encodedFileMagic...), encodingVersion, compression, encryption),
+	}
+	for name, id := range compressionIDs {
+		if id == compression {
+			c.options.Compression = name
+		}
+	}
+	switch encryption {
+	case encryptionNone:
+	case encryptionAESGCM:
+		if len(key) == 0 {
+			return nil, errors.Errorf("file is encrypted but no key was provided; set %s to the path of a key file",
+				KeyFileEnvVar)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid encryption key")
+		}
+		if c.aead, err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+		c.options.Key = key
+	default:
+		return nil, errors.Errorf("unsupported encryption %d", encryption)
+	}
+	return c, nil
+}
+
+func newWriteCodec(opts EncodingOptions) (*codec, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	encryption := encryptionNone
+	if len(opts.Key) > 0 {
+		encryption = encryptionAESGCM
+	}
+	return newCodec(compressionIDs[opts.Compression], encryption, opts.Key)
+}
+
+// readHeader reads the header of an encoded file from r, if there is one, returning the header and its size. If
+// there is not, nil is returned and r is left at the start of the file.
+func readHeader(r io.ReadSeeker) ([]byte, error) {
+	header := make([]byte, len(encodedFileMagic)+3)
+	n, err := io.ReadFull(r, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n < len(encodedFileMagic) || !bytes.Equal(header[:len(encodedFileMagic)], encodedFileMagic) {
+		_, err := r.Seek(0, io.SeekStart)
+		return nil, err
+	}
+	if n < len(header) {
+		return nil, errors.New("truncated data capture file header")
+	}
+	if version := header[len(encodedFileMagic)]; version != encodingVersion {
+		return nil, errors.Errorf("unsupported data capture file version %d", version)
+	}
+	return header, nil
+}
+
+// readCodec reads the header of an encoded file from r and returns the codec for its blocks, or nil if the file is
+// not encoded.
+func readCodec(r io.ReadSeeker, key []byte) (*codec, int64, error) {
+	header, err := readHeader(r)
+	if err != nil || header == nil {
+		return nil, 0, err
+	}
+	if len(key) == 0 && header[len(header)-1] != encryptionNone {
+		if key, err = KeyFromEnv(); err != nil {
+			return nil, 0, err
+		}
+	}
+	c, err := newCodec(header[len(header)-2], header[len(header)-1], key)
+	if err != nil {
+		return nil, 0, err
+	}
+	return c, int64(len(header)), nil
+}
+
+// authenticated returns whether the blocks of the file are authenticated, so that a file whose last block is missing
+// can be told apart from one that is complete.
+func (c *codec) authenticated() bool {
+	return c.aead != nil
+}
+
+// additionalData returns the data authenticated along with the block at index, which is the file header, so that the
+// encoding of a file cannot be tampered with, followed by the block's position.
+func (c *codec) additionalData(index uint64, last bool) []byte {
+	ad := binary.BigEndian.AppendUint64(append([]byte{}, c.header...), index)
+	if last {
+		return append(ad, 1)
+	}
+	return append(ad, 0)
+}
+
+// seal compresses and encrypts block, which is the index'th block of the file and the last one if last is set.
+func (c *codec) seal(block []byte, index uint64, last bool) ([]byte, error) {
+	compressed, err := c.compress(block)
+	if err != nil {
+		return nil, err
+	}
+	if c.aead == nil {
+		return compressed, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(compressed)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, compressed, c.additionalData(index, last)), nil
+}
+
+// open decrypts and decompresses a block sealed by seal as the index'th block of the file, returning whether it was
+// sealed as the last block.
+func (c *codec) open(sealed []byte, index uint64) ([]byte, bool, error) {
+	compressed := sealed
+	var last bool
+	if c.aead != nil {
+		if len(sealed) < c.aead.NonceSize() {
+			return nil, false, errors.New("encrypted block is too short")
+		}
+		nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+		var err error
+		if compressed, err = c.aead.Open(nil, nonce, ciphertext, c.additionalData(index, false)); err != nil {
+			last = true
+			if compressed, err = c.aead.Open(nil, nonce, ciphertext, c.additionalData(index, true)); err != nil {
+				return nil, false, errors.Wrap(err, "failed to decrypt data capture file, the key may be incorrect or the "+
+					"file may have been modified")
+			}
+		}
+	}
+	block, err := c.decompress(compressed)
+	return block, last, err
+}
+
+func (c *codec) compress(block []byte) ([]byte, error) {
+	switch c.compression {
+	case compressionIDNone:
+		return block, nil
+	case compressionIDGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(block); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case compressionIDZstd:
+		enc, err := zstdEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(block, nil), nil
+	default:
+		return nil, errors.Errorf("unsupported compression %d", c.compression)
+	}
+}
+
+func (c *codec) decompress(block []byte) ([]byte, error) {
+	switch c.compression {
+	case compressionIDNone:
+		return block, nil
+	case compressionIDGzip:
+		r, err := gzip.NewReader(bytes.NewReader(block))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	case compressionIDZstd:
+		dec, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(block, nil)
+	default:
+		return nil, errors.Errorf("unsupported compression %d", c.compression)
+	}
+}
+
+// The zstd encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll, so they are shared.
+var (
+	zstdOnce sync.Once
+	zstdEnc  *zstd.Encoder
+	zstdDec  *zstd.Decoder
+	zstdErr  error
+)
+
+func initZstd() {
+	zstdOnce.Do(func() {
+		if zstdEnc, zstdErr = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1)); zstdErr != nil {
+			return
+		}
+		zstdDec, zstdErr = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	})
+}
+
+func zstdEncoder() (*zstd.Encoder, error) {
+	initZstd()
+	return zstdEnc, zstdErr
+}
+
+func zstdDecoder() (*zstd.Decoder, error) {
+	initZstd()
+	return zstdDec, zstdErr
+}
+
+// writeBlock writes a length delimited block to w, returning the number of bytes written.
+func writeBlock(w io.Writer, block []byte) (int, error) {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(block)))
+	written, err := w.Write(lenBuf[:n])
+	if err != nil {
+		return written, err
+	}
+	m, err := w.Write(block)
+	return written + m, err
+}
+
+// readBlock reads a length delimited block written by writeBlock from r, returning it and the number of bytes read.
+func readBlock(r io.Reader) ([]byte, int, error) {
+	br := bufio.NewReader(r)
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, 0, err
+	}
+	if length > maxBlockSize {
+		return nil, 0, errors.Errorf("data capture file block of %d bytes is too large", length)
+	}
+	lenSize := len(binary.AppendUvarint(nil, length))
+	block := make([]byte, length)
+	if _, err := io.ReadFull(br, block); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, 0, err
+	}
+	return block, lenSize + int(length), nil
+}
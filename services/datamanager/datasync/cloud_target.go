@@ -0,0 +1,52 @@
+package datasync
+
+import (
+	"context"
+	"os"
+
+	"github.com/edaniels/golog"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// cloudTarget uploads files to a DataSyncService, such as the one hosted by the Viam cloud.
+type cloudTarget struct {
+	client v1.DataSyncServiceClient
+	conn   rpc.ClientConn
+}
+
+// NewCloudTarget returns a Target which uploads files using client.
+func NewCloudTarget(client v1.DataSyncServiceClient) Target {
+	return &cloudTarget{client: client}
+}
+
+// NewGRPCTarget returns a Target which uploads files to the DataSyncService hosted at address. The connection to
+// address is closed when the Target is closed.
+func NewGRPCTarget(ctx context.Context, address string, insecure bool, logger golog.Logger) (Target, error) {
+	var dialOpts []rpc.DialOption
+	if insecure {
+		dialOpts = append(dialOpts, rpc.WithInsecure())
+	}
+	conn, err := rpc.DialDirectGRPC(ctx, address, logger, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudTarget{client: v1.NewDataSyncServiceClient(conn), conn: conn}, nil
+}
+
+func (t *cloudTarget) UploadDataCaptureFile(ctx context.Context, f *datacapture.File, partID string) error {
+	return uploadDataCaptureFile(ctx, t.client, f, partID)
+}
+
+func (t *cloudTarget) UploadArbitraryFile(ctx context.Context, f *os.File, partID string, tags []string) error {
+	return uploadArbitraryFile(ctx, t.client, f, partID, tags)
+}
+
+func (t *cloudTarget) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	return t.conn.Close()
+}
@@ -0,0 +1,205 @@
+package datasync
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// UploadLimits bound the network usage of a Manager.
+type UploadLimits struct {
+	// MaxParallelUploads is the maximum number of files uploaded at once. Uploads start in the order files are passed
+	// to SyncFile. If 0, files are uploaded with unbounded parallelism.
+	MaxParallelUploads int
+	// MaxBytesPerSec is the average rate, across all uploads, that bytes may be sent at. If 0, the rate is not
+	// limited.
+	MaxBytesPerSec int64
+}
+
+// uploadQueue starts uploads in the order they were enqueued, with at most limit running at once.
+type uploadQueue struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiting []chan struct{}
+}
+
+// enqueue returns a channel that is closed once the upload may start. The caller must call done once the upload
+// finishes, or cancel if it gives up waiting.
+func (q *uploadQueue) enqueue() chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ready := make(chan struct{})
+	if q.limit <= 0 || q.active < q.limit {
+		q.active++
+		close(ready)
+	} else {
+		q.waiting = append(q.waiting, ready)
+	}
+	return ready
+}
+
+// done releases a running upload's slot to the next waiting upload.
+func (q *uploadQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.active--
+	q.startWaiting()
+}
+
+// cancel stops waiting for the upload to start. If it had already been started, its slot is released.
+func (q *uploadQueue) cancel(ready chan struct{}) {
+	q.mu.Lock()
+	for i, w := range q.waiting {
+		if w == ready {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			q.mu.Unlock()
+			return
+		}
+	}
+	q.mu.Unlock()
+	q.done()
+}
+
+func (q *uploadQueue) setLimit(limit int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.limit = limit
+	q.startWaiting()
+}
+
+// startWaiting starts waiting uploads while there are free slots. q.mu must be held.
+func (q *uploadQueue) startWaiting() {
+	for len(q.waiting) > 0 && (q.limit <= 0 || q.active < q.limit) {
+		close(q.waiting[0])
+		q.waiting = q.waiting[1:]
+		q.active++
+	}
+}
+
+// rateLimiter is a token bucket of bytes, refilled at bytesPerSec and holding at most one second's worth. Bytes are
+// taken from the bucket as they are sent. A single send may overdraw the bucket, since a message cannot be split, so
+// later sends wait for the debt to be repaid.
+type rateLimiter struct {
+	mu          sync.Mutex
+	bytesPerSec float64
+	available   float64
+	last        time.Time
+	now         func() time.Time
+}
+
+func newRateLimiter(bytesPerSec int64) *rateLimiter {
+	return &rateLimiter{bytesPerSec: float64(bytesPerSec), now: time.Now}
+}
+
+func (l *rateLimiter) setRate(bytesPerSec int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bytesPerSec = float64(bytesPerSec)
+	l.available = math.Min(l.available, l.bytesPerSec)
+}
+
+// burst returns the most bytes that should be sent at once, or 0 if the rate is not limited.
+func (l *rateLimiter) burst() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(math.Ceil(l.bytesPerSec))
+}
+
+// wait blocks until n bytes may be sent. If it has to block, throttled, which may be nil, is called with true
+// beforehand and with false once the bytes may be sent.
+func (l *rateLimiter) wait(ctx context.Context, n int64, throttled func(bool)) error {
+	waited := false
+	defer func() {
+		if waited {
+			throttled(false)
+		}
+	}()
+	for {
+		l.mu.Lock()
+		if l.bytesPerSec <= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+		now := l.now()
+		if !l.last.IsZero() {
+			l.available = math.Min(l.available+now.Sub(l.last).Seconds()*l.bytesPerSec, l.bytesPerSec)
+		} else {
+			l.available = l.bytesPerSec
+		}
+		l.last = now
+		if l.available >= 0 {
+			l.available -= float64(n)
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration(-l.available / l.bytesPerSec * float64(time.Second))
+		l.mu.Unlock()
+		if !waited && throttled != nil {
+			waited = true
+			throttled(true)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+type uploadThrottleKey struct{}
+
+// uploadThrottle limits the rate the bytes of an upload are sent at. It is carried by the context passed to a Target,
+// so that each Target can throttle the bytes it sends, however it sends them.
+type uploadThrottle struct {
+	limiter   *rateLimiter
+	throttled func(bool)
+}
+
+// withUploadThrottle returns a context that throttles the uploads made with it using limiter. throttled is called
+// with true when an upload starts waiting for the rate to drop, and with false when it resumes.
+func withUploadThrottle(ctx context.Context, limiter *rateLimiter, throttled func(bool)) context.Context {
+	return context.WithValue(ctx, uploadThrottleKey{}, &uploadThrottle{limiter: limiter, throttled: throttled})
+}
+
+// throttleUpload blocks until n more bytes of the upload made with ctx may be sent.
+func throttleUpload(ctx context.Context, n int) error {
+	t, ok := ctx.Value(uploadThrottleKey{}).(*uploadThrottle)
+	if !ok || n <= 0 {
+		return nil
+	}
+	return t.limiter.wait(ctx, int64(n), t.throttled)
+}
+
+// throttledReader returns a reader of r for the upload made with ctx, which does not return bytes faster than they may
+// be sent.
+func throttledReader(ctx context.Context, r io.Reader) io.Reader {
+	t, ok := ctx.Value(uploadThrottleKey{}).(*uploadThrottle)
+	if !ok {
+		return r
+	}
+	return &throttledRead{ctx: ctx, r: r, limiter: t.limiter}
+}
+
+type throttledRead struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rateLimiter
+}
+
+func (tr *throttledRead) Read(p []byte) (int, error) {
+	// Reading no more than a second's worth at a time keeps the rate smooth rather than bursty.
+	if burst := tr.limiter.burst(); burst > 0 && len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := tr.r.Read(p)
+	if waitErr := throttleUpload(tr.ctx, n); waitErr != nil {
+		return 0, waitErr
+	}
+	return n, err
+}
@@ -0,0 +1,103 @@
+package datasync
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func isReady(ready chan struct{}) bool {
+	select {
+	case <-ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestUploadQueue(t *testing.T) {
+	var q uploadQueue
+	q.setLimit(2)
+	first, second, third, fourth := q.enqueue(), q.enqueue(), q.enqueue(), q.enqueue()
+	test.That(t, isReady(first), test.ShouldBeTrue)
+	test.That(t, isReady(second), test.ShouldBeTrue)
+	test.That(t, isReady(third), test.ShouldBeFalse)
+
+	// Uploads start in the order they were enqueued.
+	q.done()
+	test.That(t, isReady(third), test.ShouldBeTrue)
+	test.That(t, isReady(fourth), test.ShouldBeFalse)
+
+	// Cancelling a waiting upload does not release a slot.
+	fifth := q.enqueue()
+	q.cancel(fourth)
+	test.That(t, isReady(fifth), test.ShouldBeFalse)
+	q.done()
+	test.That(t, isReady(fifth), test.ShouldBeTrue)
+
+	// Raising the limit starts waiting uploads.
+	sixth := q.enqueue()
+	test.That(t, isReady(sixth), test.ShouldBeFalse)
+	q.setLimit(0)
+	test.That(t, isReady(sixth), test.ShouldBeTrue)
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(100)
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// A full bucket may be overdrawn by a single upload.
+	test.That(t, l.wait(ctx, 300, nil), test.ShouldBeNil)
+
+	// The next upload must wait for the debt to be repaid.
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	test.That(t, l.wait(shortCtx, 10, nil), test.ShouldBeError, context.DeadlineExceeded)
+
+	now = now.Add(2 * time.Second)
+	test.That(t, l.wait(ctx, 10, nil), test.ShouldBeNil)
+
+	// Removing the limit lets uploads through immediately.
+	l.setRate(0)
+	test.That(t, l.wait(ctx, 1e9, nil), test.ShouldBeNil)
+}
+
+func TestThrottledReader(t *testing.T) {
+	// Readers of uploads made without a throttle are not wrapped.
+	r := strings.NewReader("data")
+	test.That(t, throttledReader(context.Background(), r), test.ShouldEqual, r)
+
+	var throttled []bool
+	ctx := withUploadThrottle(context.Background(), newRateLimiter(10000), func(b bool) { throttled = append(throttled, b) })
+	reader := throttledReader(ctx, strings.NewReader(strings.Repeat("x", 25000)))
+
+	// No more than a second's worth is read at once.
+	buf := make([]byte, 25000)
+	n, err := reader.Read(buf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, n, test.ShouldEqual, 10000)
+	test.That(t, throttled, test.ShouldBeEmpty)
+
+	// The first second's worth overdraws the bucket, so the rest waits for the debt to be repaid.
+	start := time.Now()
+	rest, err := io.ReadAll(reader)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(rest), test.ShouldEqual, 15000)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 900*time.Millisecond)
+	test.That(t, throttled, test.ShouldNotBeEmpty)
+	test.That(t, throttled[0], test.ShouldBeTrue)
+	test.That(t, throttled[len(throttled)-1], test.ShouldBeFalse)
+
+	// Cancelling the upload stops reading.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	reader = throttledReader(cancelCtx, strings.NewReader(strings.Repeat("x", 25000)))
+	_, err = io.ReadAll(reader)
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+}
@@ -0,0 +1,79 @@
+package datasync
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// arbitraryFilesDir is the directory, relative to the root of a Target, that arbitrary files are synced to.
+const arbitraryFilesDir = "files"
+
+// localTarget mirrors files into a directory, e.g. a removable drive or a mounted network share. Data capture files
+// are copied as-is so that they can still be read with datacapture.ReadFile.
+type localTarget struct {
+	dir string
+}
+
+// NewLocalTarget returns a Target which mirrors files into dir.
+func NewLocalTarget(dir string) (Target, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &localTarget{dir: dir}, nil
+}
+
+func (t *localTarget) UploadDataCaptureFile(ctx context.Context, f *datacapture.File, partID string) error {
+	md := f.ReadMetadata()
+	dst := filepath.Join(t.dir, partID, md.GetComponentType(), md.GetComponentName(), md.GetMethodName(),
+		filepath.Base(f.GetPath()))
+	return copyFileAtomic(ctx, f.GetPath(), dst)
+}
+
+func (t *localTarget) UploadArbitraryFile(ctx context.Context, f *os.File, partID string, tags []string) error {
+	dst := filepath.Join(t.dir, partID, arbitraryFilesDir, filepath.Base(f.Name()))
+	return copyFileAtomic(ctx, f.Name(), dst)
+}
+
+func (t *localTarget) Close() error {
+	return nil
+}
+
+// copyFileAtomic copies the file at src to dst. The copy is written to a temporary file first so that a partial copy
+// is never visible at dst.
+func copyFileAtomic(ctx context.Context, src, dst string) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+	//nolint:gosec
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, in.Close())
+	}()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, throttledReader(ctx, in)); err != nil {
+		return multierr.Combine(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+	if err := tmp.Sync(); err != nil {
+		return multierr.Combine(err, tmp.Close(), os.Remove(tmp.Name()))
+	}
+	if err := tmp.Close(); err != nil {
+		return multierr.Combine(err, os.Remove(tmp.Name()))
+	}
+	return os.Rename(tmp.Name(), dst)
+}
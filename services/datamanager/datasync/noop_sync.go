@@ -13,4 +13,12 @@ func (m *noopManager) SyncFile(path string) {}
 
 func (m *noopManager) SetArbitraryFileTags(tags []string) {}
 
+func (m *noopManager) SetEncryptionKey(key []byte) {}
+
+func (m *noopManager) SetUploadLimits(limits UploadLimits) {}
+
+func (m *noopManager) Status() Status {
+	return Status{}
+}
+
 func (m *noopManager) Close() {}
@@ -0,0 +1,183 @@
+package datasync
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+const (
+	defaultS3Region    = "us-east-1"
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3TimeFormat       = "20060102T150405Z"
+	s3DateFormat       = "20060102"
+)
+
+// s3Target uploads files as objects to an S3 compatible HTTP endpoint (e.g. AWS S3 or MinIO) using path style
+// addressing. Requests are signed with AWS Signature Version 4 when credentials are configured.
+type s3Target struct {
+	client *http.Client
+	conf   TargetConfig
+}
+
+// NewS3Target returns a Target which uploads files to the bucket described by conf.
+func NewS3Target(conf TargetConfig) Target {
+	if conf.Region == "" {
+		conf.Region = defaultS3Region
+	}
+	return &s3Target{client: &http.Client{}, conf: conf}
+}
+
+func (t *s3Target) UploadDataCaptureFile(ctx context.Context, f *datacapture.File, partID string) error {
+	md := f.ReadMetadata()
+	key := path.Join(t.conf.Prefix, partID, md.GetComponentType(), md.GetComponentName(), md.GetMethodName(),
+		filepath.Base(f.GetPath()))
+	return t.putObject(ctx, f.GetPath(), key, md.GetTags())
+}
+
+func (t *s3Target) UploadArbitraryFile(ctx context.Context, f *os.File, partID string, tags []string) error {
+	key := path.Join(t.conf.Prefix, partID, arbitraryFilesDir, filepath.Base(f.Name()))
+	return t.putObject(ctx, f.Name(), key, tags)
+}
+
+func (t *s3Target) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// putObject uploads the file at src to key in the configured bucket.
+func (t *s3Target) putObject(ctx context.Context, src, key string, tags []string) (err error) {
+	//nolint:gosec
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, f.Close())
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	objectURL := strings.TrimSuffix(t.conf.Endpoint, "/") + "/" + s3EscapePath(t.conf.Bucket+"/"+key)
+	// The file is closed by this function rather than by the HTTP client.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, io.NopCloser(throttledReader(ctx, f)))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	if len(tags) > 0 {
+		req.Header.Set("X-Amz-Meta-Tags", strings.Join(tags, ","))
+	}
+	if t.conf.AccessKeyID != "" {
+		signS3RequestV4(req, t.conf.AccessKeyID, t.conf.SecretAccessKey, t.conf.Region, time.Now())
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, resp.Body.Close())
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("failed to upload %s to %s: %s: %s", src, objectURL, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// signS3RequestV4 adds an AWS Signature Version 4 Authorization header to req. The host and every header already
+// set on req are signed, and the payload hash is taken from the X-Amz-Content-Sha256 header.
+func signS3RequestV4(req *http.Request, accessKeyID, secretAccessKey, region string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		trimmed := make([]string, 0, len(values))
+		for _, v := range values {
+			trimmed = append(trimmed, strings.Join(strings.Fields(v), " "))
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(s3DateFormat), region, "s3", "aws4_request"}, "/")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		now.Format(s3TimeFormat),
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{now.Format(s3DateFormat), region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	//nolint:errcheck
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3EscapePath escapes every byte of p except unreserved characters and '/', as required by S3.
+func s3EscapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package datasync
+
+import (
+	"sort"
+	"time"
+)
+
+// UploadState describes what a file being synced is waiting on.
+type UploadState string
+
+// The states a file being synced can be in.
+const (
+	// UploadStateQueued files are waiting for one of the MaxParallelUploads upload slots.
+	UploadStateQueued UploadState = "queued"
+	// UploadStateRateLimited files have started uploading, and are waiting for the upload rate to drop below
+	// MaxBytesPerSec to send more.
+	UploadStateRateLimited UploadState = "rate_limited"
+	// UploadStateUploading files are being uploaded.
+	UploadStateUploading UploadState = "uploading"
+	// UploadStateRetrying files failed to upload and are waiting to be retried.
+	UploadStateRetrying UploadState = "retrying"
+)
+
+// UploadStatus describes a file being synced.
+type UploadStatus struct {
+	Path  string
+	State UploadState
+	// Attempts is the number of times the file has started uploading.
+	Attempts  int
+	LastError error
+	// NextAttempt is when the file will next be retried, if it is UploadStateRetrying.
+	NextAttempt time.Time
+}
+
+// Status describes the files a Manager is syncing and the last error it encountered.
+type Status struct {
+	Uploads       []UploadStatus
+	LastError     error
+	LastErrorTime time.Time
+}
+
+func (s *syncer) Status() Status {
+	s.progressLock.Lock()
+	uploads := make([]UploadStatus, 0, len(s.inProgress))
+	for _, upload := range s.inProgress {
+		uploads = append(uploads, *upload)
+	}
+	s.progressLock.Unlock()
+	sort.Slice(uploads, func(i, j int) bool { return uploads[i].Path < uploads[j].Path })
+
+	s.errLock.Lock()
+	defer s.errLock.Unlock()
+	return Status{Uploads: uploads, LastError: s.lastErr, LastErrorTime: s.lastErrTime}
+}
+
+// updateUpload applies update to the status of the in progress upload of path.
+func (s *syncer) updateUpload(path string, update func(upload *UploadStatus)) {
+	s.progressLock.Lock()
+	defer s.progressLock.Unlock()
+	if upload, ok := s.inProgress[path]; ok {
+		update(upload)
+	}
+}
+
+func (s *syncer) setUploadState(path string, state UploadState) {
+	s.updateUpload(path, func(upload *UploadStatus) {
+		upload.State = state
+		if state == UploadStateUploading {
+			upload.Attempts++
+			upload.NextAttempt = time.Time{}
+		}
+	})
+}
+
+func (s *syncer) recordUploadFailure(path string, err error, nextWait time.Duration) {
+	s.updateUpload(path, func(upload *UploadStatus) {
+		upload.State = UploadStateRetrying
+		upload.LastError = err
+		upload.NextAttempt = time.Now().Add(nextWait)
+	})
+}
@@ -1,4 +1,5 @@
-// Package datasync contains interfaces for syncing data from robots to the app.viam.com cloud.
+// Package datasync contains interfaces for syncing data from robots to the app.viam.com cloud, or to other
+// configured sync Targets.
 package datasync
 
 import (
@@ -12,7 +13,6 @@ import (
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
 	"go.uber.org/atomic"
-	v1 "go.viam.com/api/app/datasync/v1"
 	goutils "go.viam.com/utils"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -28,46 +28,67 @@ var (
 	maxRetryInterval       = time.Hour
 )
 
-// Manager is responsible for enqueuing files in captureDir and uploading them to the cloud.
+// Manager is responsible for enqueuing files in captureDir and uploading them to their sync Targets.
 type Manager interface {
 	SyncFile(path string)
 	SetArbitraryFileTags(tags []string)
+	// SetEncryptionKey sets the key encrypted data capture files are decrypted with before being uploaded.
+	SetEncryptionKey(key []byte)
+	// SetUploadLimits bounds the parallelism and rate of uploads. Files already being uploaded are not affected by a
+	// lower parallelism limit.
+	SetUploadLimits(limits UploadLimits)
+	// Status returns the state of each file being synced.
+	Status() Status
 	Close()
 }
 
-// syncer is responsible for uploading files in captureDir to the cloud.
+// syncer is responsible for uploading files in captureDir to their sync Targets.
 type syncer struct {
 	partID            string
-	client            v1.DataSyncServiceClient
+	targets           Targets
 	logger            golog.Logger
 	backgroundWorkers sync.WaitGroup
 	cancelCtx         context.Context
 	cancelFunc        func()
 	arbitraryFileTags []string
 
+	keyLock       sync.Mutex
+	encryptionKey []byte
+
 	progressLock sync.Mutex
-	inProgress   map[string]bool
+	inProgress   map[string]*UploadStatus
+
+	uploads uploadQueue
+	rate    *rateLimiter
 
-	syncErrs   chan error
-	closed     atomic.Bool
-	logRoutine sync.WaitGroup
+	syncErrs    chan error
+	errLock     sync.Mutex
+	lastErr     error
+	lastErrTime time.Time
+	closed      atomic.Bool
+	logRoutine  sync.WaitGroup
 }
 
 // ManagerConstructor is a function for building a Manager.
-type ManagerConstructor func(identity string, client v1.DataSyncServiceClient, logger golog.Logger) (Manager, error)
+type ManagerConstructor func(identity string, targets Targets, logger golog.Logger) (Manager, error)
 
-// NewManager returns a new syncer.
-func NewManager(identity string, client v1.DataSyncServiceClient, logger golog.Logger) (Manager, error) {
+// NewManager returns a new syncer which uploads files to targets. The syncer takes ownership of targets, and closes
+// them when it is closed.
+func NewManager(identity string, targets Targets, logger golog.Logger) (Manager, error) {
+	if targets.Default == nil {
+		return nil, errors.New("a default sync target is required")
+	}
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 	ret := syncer{
 		partID:            identity,
-		client:            client,
+		targets:           targets,
 		logger:            logger,
 		cancelCtx:         cancelCtx,
 		cancelFunc:        cancelFunc,
 		arbitraryFileTags: []string{},
-		inProgress:        make(map[string]bool),
+		inProgress:        make(map[string]*UploadStatus),
 		syncErrs:          make(chan error, 10),
+		rate:              newRateLimiter(0),
 	}
 	ret.logRoutine.Add(1)
 	goutils.PanicCapturingGo(func() {
@@ -84,6 +105,9 @@ func (s *syncer) Close() {
 	s.backgroundWorkers.Wait()
 	close(s.syncErrs)
 	s.logRoutine.Wait()
+	if err := s.targets.close(); err != nil {
+		s.logger.Errorw("error closing sync targets", "error", err)
+	}
 	//nolint:errcheck
 	_ = s.logger.Sync()
 }
@@ -92,17 +116,42 @@ func (s *syncer) SetArbitraryFileTags(tags []string) {
 	s.arbitraryFileTags = tags
 }
 
+func (s *syncer) SetEncryptionKey(key []byte) {
+	s.keyLock.Lock()
+	defer s.keyLock.Unlock()
+	s.encryptionKey = key
+}
+
+func (s *syncer) getEncryptionKey() []byte {
+	s.keyLock.Lock()
+	defer s.keyLock.Unlock()
+	return s.encryptionKey
+}
+
+func (s *syncer) SetUploadLimits(limits UploadLimits) {
+	s.uploads.setLimit(limits.MaxParallelUploads)
+	s.rate.setRate(limits.MaxBytesPerSec)
+}
+
+// SyncFile queues path to be uploaded. Files are uploaded in the order they are passed to SyncFile, subject to the
+// configured UploadLimits.
 func (s *syncer) SyncFile(path string) {
+	// Files are marked in progress before waiting for an upload slot so that a file is only queued once, even if it
+	// is passed to SyncFile again while it is waiting.
+	if !s.markInProgress(path) {
+		return
+	}
 	s.backgroundWorkers.Add(1)
+	ready := s.uploads.enqueue()
 	goutils.PanicCapturingGo(func() {
 		defer s.backgroundWorkers.Done()
 		select {
 		case <-s.cancelCtx.Done():
+			s.uploads.cancel(ready)
+			s.unmarkInProgress(path)
 			return
-		default:
-			if !s.markInProgress(path) {
-				return
-			}
+		case <-ready:
+			defer s.uploads.done()
 			//nolint:gosec
 			f, err := os.Open(path)
 			if err != nil {
@@ -111,17 +160,19 @@ func (s *syncer) SyncFile(path string) {
 				if !errors.Is(err, os.ErrNotExist) {
 					s.logger.Errorw("error opening file", "error", err)
 				}
+				s.unmarkInProgress(path)
 				return
 			}
 
 			if datacapture.IsDataCaptureFile(f) {
-				captureFile, err := datacapture.ReadFile(f)
+				captureFile, err := datacapture.ReadFileWithKey(f, s.getEncryptionKey())
 				if err != nil {
 					s.syncErrs <- errors.Wrap(err, "error reading data capture file")
 					err := f.Close()
 					if err != nil {
 						s.syncErrs <- errors.Wrap(err, "error closing data capture file")
 					}
+					s.unmarkInProgress(path)
 					return
 				}
 				s.syncDataCaptureFile(captureFile)
@@ -134,15 +185,20 @@ func (s *syncer) SyncFile(path string) {
 }
 
 func (s *syncer) syncDataCaptureFile(f *datacapture.File) {
+	target := s.targets.targetFor(f.GetPath())
 	uploadErr := exponentialRetry(
 		s.cancelCtx,
 		func(ctx context.Context) error {
-			err := uploadDataCaptureFile(ctx, s.client, f, s.partID)
+			s.setUploadState(f.GetPath(), UploadStateUploading)
+			err := target.UploadDataCaptureFile(s.throttle(ctx, f.GetPath()), f, s.partID)
 			if err != nil {
 				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error uploading file %s", f.GetPath()))
 			}
 			return err
 		},
+		func(err error, nextWait time.Duration) {
+			s.recordUploadFailure(f.GetPath(), err, nextWait)
+		},
 	)
 	if uploadErr != nil {
 		err := f.Close()
@@ -158,14 +214,19 @@ func (s *syncer) syncDataCaptureFile(f *datacapture.File) {
 }
 
 func (s *syncer) syncArbitraryFile(f *os.File) {
+	target := s.targets.targetFor(f.Name())
 	uploadErr := exponentialRetry(
 		s.cancelCtx,
 		func(ctx context.Context) error {
-			err := uploadArbitraryFile(ctx, s.client, f, s.partID, s.arbitraryFileTags)
+			s.setUploadState(f.Name(), UploadStateUploading)
+			err := target.UploadArbitraryFile(s.throttle(ctx, f.Name()), f, s.partID, s.arbitraryFileTags)
 			if err != nil {
 				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error uploading file %s", f.Name()))
 			}
 			return err
+		},
+		func(err error, nextWait time.Duration) {
+			s.recordUploadFailure(f.Name(), err, nextWait)
 		})
 	if uploadErr != nil {
 		err := f.Close()
@@ -180,15 +241,29 @@ func (s *syncer) syncArbitraryFile(f *os.File) {
 	}
 }
 
+// throttle returns a context for uploading path that limits it to the upload rate, and that marks it as rate limited
+// while it waits for the rate to drop.
+func (s *syncer) throttle(ctx context.Context, path string) context.Context {
+	return withUploadThrottle(ctx, s.rate, func(throttled bool) {
+		s.updateUpload(path, func(upload *UploadStatus) {
+			if throttled {
+				upload.State = UploadStateRateLimited
+			} else {
+				upload.State = UploadStateUploading
+			}
+		})
+	})
+}
+
 // markInProgress marks path as in progress in s.inProgress. It returns true if it changed the progress status,
 // or false if the path was already in progress.
 func (s *syncer) markInProgress(path string) bool {
 	s.progressLock.Lock()
 	defer s.progressLock.Unlock()
-	if s.inProgress[path] {
+	if _, ok := s.inProgress[path]; ok {
 		return false
 	}
-	s.inProgress[path] = true
+	s.inProgress[path] = &UploadStatus{Path: path, State: UploadStateQueued}
 	return true
 }
 
@@ -207,13 +282,21 @@ func (s *syncer) logSyncErrs() {
 				continue
 			}
 		}
+		s.errLock.Lock()
+		s.lastErr, s.lastErrTime = err, time.Now()
+		s.errLock.Unlock()
 		s.logger.Error(err)
 	}
 }
 
 // exponentialRetry calls fn and retries with exponentially increasing waits from initialWait to a
-// maximum of maxRetryInterval.
-func exponentialRetry(cancelCtx context.Context, fn func(cancelCtx context.Context) error) error {
+// maximum of maxRetryInterval. onRetry, if not nil, is called with each error that will be retried and the wait
+// before the retry.
+func exponentialRetry(
+	cancelCtx context.Context,
+	fn func(cancelCtx context.Context) error,
+	onRetry func(err error, nextWait time.Duration),
+) error {
 	// Only create a ticker and enter the retry loop if we actually need to retry.
 	var err error
 	if err = fn(cancelCtx); err == nil {
@@ -227,6 +310,9 @@ func exponentialRetry(cancelCtx context.Context, fn func(cancelCtx context.Conte
 
 	// First call failed, so begin exponentialRetry with a factor of RetryExponentialFactor
 	nextWait := time.Millisecond * time.Duration(InitialWaitTimeMillis.Load())
+	if onRetry != nil {
+		onRetry(err, nextWait)
+	}
 	ticker := time.NewTicker(nextWait)
 	for {
 		if err := cancelCtx.Err(); err != nil {
@@ -243,6 +329,9 @@ func exponentialRetry(cancelCtx context.Context, fn func(cancelCtx context.Conte
 				// If error, retry with a new nextWait.
 				ticker.Stop()
 				nextWait = getNextWait(nextWait)
+				if onRetry != nil {
+					onRetry(err, nextWait)
+				}
 				ticker = time.NewTicker(nextWait)
 				continue
 			}
@@ -0,0 +1,143 @@
+package datasync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+// The types of sync targets that can be configured.
+const (
+	TargetTypeCloud = "cloud"
+	TargetTypeLocal = "local"
+	TargetTypeS3    = "s3"
+	TargetTypeGRPC  = "grpc"
+)
+
+// CloudTargetName is the name of the implicit target that uploads to the Viam cloud. It is used by default when no
+// other target is selected.
+const CloudTargetName = "cloud"
+
+// Target is a destination that files can be synced to. Once a file has been successfully uploaded to its Target, the
+// Manager deletes it from local storage.
+type Target interface {
+	// UploadDataCaptureFile uploads the contents of a data capture file.
+	UploadDataCaptureFile(ctx context.Context, f *datacapture.File, partID string) error
+	// UploadArbitraryFile uploads an arbitrary file with the given tags.
+	UploadArbitraryFile(ctx context.Context, f *os.File, partID string, tags []string) error
+	// Close releases any resources (e.g. connections) held by the Target.
+	Close() error
+}
+
+// TargetConfig describes a sync Target.
+type TargetConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Directory is the directory a local target mirrors files into.
+	Directory string `json:"directory,omitempty"`
+
+	// Endpoint is the base URL of an S3 compatible HTTP endpoint, or the address of a gRPC endpoint that implements
+	// the DataSyncService.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// S3 specific options.
+	Bucket          string `json:"bucket,omitempty"`
+	Prefix          string `json:"prefix,omitempty"`
+	Region          string `json:"region,omitempty"`
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+
+	// Insecure disables TLS when dialing a gRPC endpoint.
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *TargetConfig) Validate(path string) error {
+	if c.Name == "" {
+		return errors.Errorf("%s: missing required field name", path)
+	}
+	switch c.Type {
+	case TargetTypeCloud:
+	case TargetTypeLocal:
+		if c.Directory == "" {
+			return errors.Errorf("%s: local sync target %q requires a directory", path, c.Name)
+		}
+	case TargetTypeS3:
+		if c.Endpoint == "" || c.Bucket == "" {
+			return errors.Errorf("%s: s3 sync target %q requires an endpoint and a bucket", path, c.Name)
+		}
+		if (c.AccessKeyID == "") != (c.SecretAccessKey == "") {
+			return errors.Errorf("%s: s3 sync target %q requires both or neither of access_key_id and secret_access_key",
+				path, c.Name)
+		}
+	case TargetTypeGRPC:
+		if c.Endpoint == "" {
+			return errors.Errorf("%s: grpc sync target %q requires an endpoint", path, c.Name)
+		}
+	default:
+		return errors.Errorf("%s: sync target %q has unknown type %q", path, c.Name, c.Type)
+	}
+	return nil
+}
+
+// NewTarget builds the Target described by conf. cloudConn is only used by cloud targets.
+func NewTarget(ctx context.Context, conf TargetConfig, cloudConn rpc.ClientConn, logger golog.Logger) (Target, error) {
+	switch conf.Type {
+	case TargetTypeCloud:
+		return NewCloudTarget(v1.NewDataSyncServiceClient(cloudConn)), nil
+	case TargetTypeLocal:
+		return NewLocalTarget(conf.Directory)
+	case TargetTypeS3:
+		return NewS3Target(conf), nil
+	case TargetTypeGRPC:
+		return NewGRPCTarget(ctx, conf.Endpoint, conf.Insecure, logger)
+	default:
+		return nil, errors.Errorf("sync target %q has unknown type %q", conf.Name, conf.Type)
+	}
+}
+
+// Targets is the set of Targets a Manager syncs files to. Files within a directory in ByDirectory are synced to that
+// directory's Target, and all other files are synced to Default.
+type Targets struct {
+	Default     Target
+	ByDirectory map[string]Target
+}
+
+// targetFor returns the Target the file at path should be synced to.
+func (t Targets) targetFor(path string) Target {
+	dir := filepath.Dir(path)
+	for targetDir, target := range t.ByDirectory {
+		if dir == targetDir || strings.HasPrefix(dir, targetDir+string(filepath.Separator)) {
+			return target
+		}
+	}
+	return t.Default
+}
+
+// close closes every distinct Target in t.
+func (t Targets) close() error {
+	closed := map[Target]bool{}
+	var err error
+	closeOnce := func(target Target) {
+		if target == nil || closed[target] {
+			return
+		}
+		closed[target] = true
+		err = multierr.Combine(err, target.Close())
+	}
+	closeOnce(t.Default)
+	for _, target := range t.ByDirectory {
+		closeOnce(target)
+	}
+	return err
+}
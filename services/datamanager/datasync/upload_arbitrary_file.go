@@ -37,7 +37,7 @@ func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f
 		return err
 	}
 
-	if err := sendFileUploadRequests(ctx, stream, f); err != nil {
+	if err := sendFileUploadRequests(ctx, stream, throttledReader(ctx, f)); err != nil {
 		return errors.Wrapf(err, "error syncing %s", f.Name())
 	}
 
@@ -49,7 +49,7 @@ func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f
 	return nil
 }
 
-func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileUploadClient, f *os.File) error {
+func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileUploadClient, f io.Reader) error {
 	//nolint:errcheck
 	defer stream.CloseSend()
 	// Loop until there is no more content to be read from file.
@@ -77,7 +77,7 @@ func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileU
 	}
 }
 
-func getNextFileUploadRequest(ctx context.Context, f *os.File) (*v1.FileUploadRequest, error) {
+func getNextFileUploadRequest(ctx context.Context, f io.Reader) (*v1.FileUploadRequest, error) {
 	select {
 	case <-ctx.Done():
 		return nil, context.Canceled
@@ -96,7 +96,7 @@ func getNextFileUploadRequest(ctx context.Context, f *os.File) (*v1.FileUploadRe
 	}
 }
 
-func readNextFileChunk(f *os.File) (*v1.FileData, error) {
+func readNextFileChunk(f io.Reader) (*v1.FileData, error) {
 	byteArr := make([]byte, UploadChunkSize)
 	numBytesRead, err := f.Read(byteArr)
 	if numBytesRead < UploadChunkSize {
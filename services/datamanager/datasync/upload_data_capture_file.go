@@ -4,6 +4,7 @@ import (
 	"context"
 
 	v1 "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/proto"
 
 	"go.viam.com/rdk/services/datamanager/datacapture"
 )
@@ -35,6 +36,10 @@ func uploadDataCaptureFile(ctx context.Context, client v1.DataSyncServiceClient,
 		},
 		SensorContents: sensorData,
 	}
+	// The readings are sent in a single message, so the whole message is throttled before it is sent.
+	if err := throttleUpload(ctx, proto.Size(ur)); err != nil {
+		return err
+	}
 	_, err = client.DataCaptureUpload(ctx, ur)
 	if err != nil {
 		return err
@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/services/datamanager/datasync"
 )
 
@@ -14,6 +15,7 @@ import (
 // a circular import caused by the inject package.
 type DMService interface {
 	Sync(ctx context.Context, extra map[string]interface{}) error
+	GetStatus(ctx context.Context, extra map[string]interface{}) (datamanager.Status, error)
 	Reconfigure(
 		ctx context.Context,
 		deps resource.Dependencies,
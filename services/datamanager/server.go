@@ -6,6 +6,7 @@ import (
 
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/datamanager/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -42,5 +43,32 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if reporter, ok := svc.(StatusReporter); ok {
+		if cmd := req.GetCommand().AsMap(); cmd["command"] == GetStatusCommand {
+			return server.getStatus(ctx, reporter, cmd)
+		}
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
+
+// getStatus serves GetStatusCommand, which is how clients call GetStatus on services that report their status.
+func (server *serviceServer) getStatus(
+	ctx context.Context,
+	reporter StatusReporter,
+	cmd map[string]interface{},
+) (*commonpb.DoCommandResponse, error) {
+	extra, _ := cmd["extra"].(map[string]interface{})
+	status, err := reporter.GetStatus(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := statusToMap(status)
+	if err != nil {
+		return nil, err
+	}
+	pbResp, err := structpb.NewStruct(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: pbResp}, nil
+}
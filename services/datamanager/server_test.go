@@ -102,3 +102,29 @@ func TestServerDoCommand(t *testing.T) {
 	test.That(t, respMap["command"], test.ShouldResemble, "test")
 	test.That(t, respMap["data"], test.ShouldResemble, 500.0)
 }
+
+func TestServerGetStatusCommand(t *testing.T) {
+	injectDS := &inject.DataManagerService{
+		GetStatusFunc: func(ctx context.Context, extra map[string]interface{}) (datamanager.Status, error) {
+			return datamanager.Status{PendingFiles: 3}, nil
+		},
+		DoCommandFunc: testutils.EchoFunc,
+	}
+	cmd, err := protoutils.StructToStructPb(map[string]interface{}{"command": datamanager.GetStatusCommand})
+	test.That(t, err, test.ShouldBeNil)
+	doCommandRequest := &commonpb.DoCommandRequest{Name: testDataManagerServiceName, Command: cmd}
+
+	server, err := newServer(map[resource.Name]datamanager.Service{datamanager.Named(testDataManagerServiceName): injectDS})
+	test.That(t, err, test.ShouldBeNil)
+	resp, err := server.DoCommand(context.Background(), doCommandRequest)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Result.AsMap()["pending_files"], test.ShouldEqual, 3.0)
+
+	// Services that do not report their status are passed the command like any other.
+	notReporting := struct{ datamanager.Service }{injectDS}
+	server, err = newServer(map[resource.Name]datamanager.Service{datamanager.Named(testDataManagerServiceName): notReporting})
+	test.That(t, err, test.ShouldBeNil)
+	resp, err = server.DoCommand(context.Background(), doCommandRequest)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Result.AsMap(), test.ShouldResemble, map[string]interface{}{"command": datamanager.GetStatusCommand})
+}
@@ -0,0 +1,102 @@
+package datamanager
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GetStatusCommand is the DoCommand command clients send to call GetStatus on a StatusReporter. Extra parameters are
+// sent under the "extra" key, and the Status is returned as the response.
+const GetStatusCommand = "get_status"
+
+// Status describes the health of a data manager's collectors and syncing. Times are formatted as RFC3339Nano, and
+// are empty if the event has not happened.
+type Status struct {
+	Collectors []CollectorStatus `json:"collectors"`
+	// PendingFiles and PendingBytes count the completed files waiting to be synced.
+	PendingFiles int        `json:"pending_files"`
+	PendingBytes int64      `json:"pending_bytes"`
+	Sync         SyncStatus `json:"sync"`
+	// Retention describes the data evicted from local storage, and whether capture is paused for lack of disk space.
+	Retention RetentionStatus `json:"retention"`
+}
+
+// CollectorStatus describes the health of the collector of a single resource method.
+type CollectorStatus struct {
+	Resource string `json:"resource"`
+	Method   string `json:"method"`
+	// CaptureFrequencyHz is the configured capture frequency, and CaptureRateHz the rate readings were actually
+	// captured at over the last minute.
+	CaptureFrequencyHz float32 `json:"capture_frequency_hz"`
+	CaptureRateHz      float64 `json:"capture_rate_hz"`
+	LastCaptureTime    string  `json:"last_capture_time"`
+	LastError          string  `json:"last_error"`
+	LastErrorTime      string  `json:"last_error_time"`
+	// QueueDepth is the number of captured readings waiting to be written to disk, out of at most QueueCapacity.
+	QueueDepth         int   `json:"queue_depth"`
+	QueueCapacity      int   `json:"queue_capacity"`
+	CapturedReadings   int64 `json:"captured_readings"`
+	SuppressedReadings int64 `json:"suppressed_readings"`
+	FailedCaptures     int64 `json:"failed_captures"`
+}
+
+// SyncStatus describes the health of syncing.
+type SyncStatus struct {
+	// Enabled is whether data is synced on a schedule.
+	Enabled       bool           `json:"enabled"`
+	LastError     string         `json:"last_error"`
+	LastErrorTime string         `json:"last_error_time"`
+	Uploads       []UploadStatus `json:"uploads"`
+}
+
+// UploadStatus describes a file that is being synced.
+type UploadStatus struct {
+	Path string `json:"path"`
+	// State is one of queued, rate_limited, uploading or retrying.
+	State       string `json:"state"`
+	Attempts    int    `json:"attempts"`
+	LastError   string `json:"last_error"`
+	NextAttempt string `json:"next_attempt"`
+}
+
+// FormatStatusTime formats t as a Status time, which is empty if t is the zero time.
+func FormatStatusTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// FormatStatusError formats err as a Status error, which is empty if err is nil.
+func FormatStatusError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// statusToMap converts status to the DoCommand response returned for GetStatusCommand.
+func statusToMap(status Status) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(encoded, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// statusFromMap converts a DoCommand response returned for GetStatusCommand to a Status.
+func statusFromMap(resp map[string]interface{}) (Status, error) {
+	encoded, err := json.Marshal(resp)
+	if err != nil {
+		return Status{}, err
+	}
+	var status Status
+	if err := json.Unmarshal(encoded, &status); err != nil {
+		return Status{}, err
+	}
+	return status, nil
+}
@@ -0,0 +1,126 @@
+package datamanager
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// Supported trigger condition types.
+const (
+	// TriggerConditionSensorReading compares a numeric or boolean value from a sensor's Readings to a threshold.
+	TriggerConditionSensorReading = "sensor_reading"
+	// TriggerConditionDetection is met when a vision service detects a label in a camera's images.
+	TriggerConditionDetection = "detection"
+	// TriggerConditionPowered is met when a motor is powered.
+	TriggerConditionPowered = "powered"
+)
+
+// Supported trigger condition comparison operators.
+const (
+	TriggerOperatorGreaterThan      = ">"
+	TriggerOperatorGreaterThanEqual = ">="
+	TriggerOperatorLessThan         = "<"
+	TriggerOperatorLessThanEqual    = "<="
+	TriggerOperatorEqual            = "=="
+	TriggerOperatorNotEqual         = "!="
+)
+
+// defaultTriggerEvaluationFrequencyHz is how often trigger conditions are evaluated if not configured.
+const defaultTriggerEvaluationFrequencyHz = 1
+
+// TriggerConfig makes a capture method only persist data while a set of conditions hold.
+type TriggerConfig struct {
+	Conditions []TriggerCondition `json:"conditions"`
+	// RequireAll requires every condition to be met for the trigger to fire. Otherwise any one condition suffices.
+	RequireAll bool `json:"require_all"`
+	// TriggeredFrequencyHz, if set, overrides the capture frequency while triggered.
+	TriggeredFrequencyHz  float32 `json:"triggered_frequency_hz"`
+	EvaluationFrequencyHz float32 `json:"evaluation_frequency_hz"`
+	// PreTriggerSecs is how much data from before the trigger fired is also persisted. Data is captured and held in
+	// memory for this long while the trigger has not fired.
+	PreTriggerSecs float64 `json:"pre_trigger_secs"`
+	// PostTriggerSecs is how long the trigger stays fired after its conditions stop holding.
+	PostTriggerSecs float64 `json:"post_trigger_secs"`
+}
+
+// TriggerCondition is a single condition of a TriggerConfig.
+type TriggerCondition struct {
+	Type string `json:"type"`
+	// Resource is the sensor for sensor_reading conditions, the vision service for detection conditions, and the
+	// motor for powered conditions.
+	Resource resource.Name `json:"resource"`
+	// Key is the, possibly dot separated, key of the reading compared by sensor_reading conditions.
+	Key string `json:"key,omitempty"`
+	// Camera and Label select the detections considered by detection conditions.
+	Camera string `json:"camera,omitempty"`
+	Label  string `json:"label,omitempty"`
+	// Operator and Value compare a reading for sensor_reading conditions, and the detection score for detection
+	// conditions. Boolean readings compare as 0 or 1.
+	Operator string  `json:"operator,omitempty"`
+	Value    float64 `json:"value,omitempty"`
+	// Negate inverts the condition.
+	Negate bool `json:"negate,omitempty"`
+}
+
+// EvaluationFrequencyHzOrDefault returns how often the trigger's conditions should be evaluated.
+func (c *TriggerConfig) EvaluationFrequencyHzOrDefault() float32 {
+	if c.EvaluationFrequencyHz > 0 {
+		return c.EvaluationFrequencyHz
+	}
+	return defaultTriggerEvaluationFrequencyHz
+}
+
+// Validate ensures all parts of the trigger config are valid.
+func (c *TriggerConfig) Validate(path string) error {
+	if len(c.Conditions) == 0 {
+		return errors.Errorf("%s: trigger must have at least one condition", path)
+	}
+	if c.TriggeredFrequencyHz < 0 || c.EvaluationFrequencyHz < 0 || c.PreTriggerSecs < 0 || c.PostTriggerSecs < 0 {
+		return errors.Errorf("%s: trigger frequencies and durations cannot be negative", path)
+	}
+	for idx, cond := range c.Conditions {
+		if err := cond.Validate(fmt.Sprintf("%s.conditions.%d", path, idx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate ensures all parts of the trigger condition are valid.
+func (c *TriggerCondition) Validate(path string) error {
+	if c.Resource.Name == "" {
+		return errors.Errorf("%s: condition must specify a resource", path)
+	}
+	switch c.Type {
+	case TriggerConditionSensorReading:
+		if c.Key == "" {
+			return errors.Errorf("%s: %s condition must specify a key", path, c.Type)
+		}
+		return validateTriggerOperator(path, c.Operator, false)
+	case TriggerConditionDetection:
+		if c.Camera == "" || c.Label == "" {
+			return errors.Errorf("%s: %s condition must specify a camera and label", path, c.Type)
+		}
+		return validateTriggerOperator(path, c.Operator, true)
+	case TriggerConditionPowered:
+		return nil
+	default:
+		return errors.Errorf("%s: unsupported condition type %q", path, c.Type)
+	}
+}
+
+func validateTriggerOperator(path, operator string, optional bool) error {
+	switch operator {
+	case TriggerOperatorGreaterThan, TriggerOperatorGreaterThanEqual, TriggerOperatorLessThan,
+		TriggerOperatorLessThanEqual, TriggerOperatorEqual, TriggerOperatorNotEqual:
+		return nil
+	case "":
+		if optional {
+			return nil
+		}
+	}
+	return errors.Errorf("%s: unsupported operator %q", path, operator)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
 
 	servicepb "go.viam.com/api/service/motion/v1"
 	"go.viam.com/rdk/components/arm"
@@ -56,10 +58,17 @@ var ErrNotImplemented = errors.New("function coming soon but not yet implemented
 
 // Config describes how to configure the service; currently only used for specifying dependency on framesystem service
 type Config struct {
+	// DebugRecording names a directory within motionplan.DebugRecordingDir to record every plan of the service to.
+	DebugRecording string `json:"debug_recording,omitempty"`
 }
 
 // Validate here adds a dependency on the internal framesystem service
 func (c *Config) Validate(path string) ([]string, error) {
+	if c.DebugRecording != "" {
+		if _, err := motionplan.DebugRecordingPath(c.DebugRecording); err != nil {
+			return nil, err
+		}
+	}
 	return []string{framesystem.InternalServiceName.String()}, nil
 }
 
@@ -82,9 +91,15 @@ func (ms *builtIn) Reconfigure(
 	deps resource.Dependencies,
 	conf resource.Config,
 ) (err error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+
 	ms.lock.Lock()
 	defer ms.lock.Unlock()
 
+	ms.debugRecording = newConf.DebugRecording
 	localizers := make(map[resource.Name]motion.Localizer)
 	components := make(map[resource.Name]resource.Resource)
 	for name, dep := range deps {
@@ -114,6 +129,24 @@ type builtIn struct {
 	components map[resource.Name]resource.Resource
 	logger     golog.Logger
 	lock       sync.Mutex
+
+	debugRecording string
+}
+
+// planningOptions returns the options to plan a request with the given extra parameters with. Plans are only recorded
+// when the service's config asks for it, since recording writes to the robot's disk, so a request cannot ask for it.
+func (ms *builtIn) planningOptions(extra map[string]interface{}) map[string]interface{} {
+	opts := make(map[string]interface{}, len(extra)+1)
+	for key, value := range extra {
+		opts[key] = value
+	}
+	delete(opts, motionplan.DebugRecordingKey)
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	if ms.debugRecording != "" {
+		opts[motionplan.DebugRecordingKey] = ms.debugRecording
+	}
+	return opts
 }
 
 // Move takes a goal location and will plan and execute a movement to move a component specified by its name to that destination.
@@ -158,10 +191,215 @@ func (ms *builtIn) Move(
 	goalPose, _ := tf.(*referenceframe.PoseInFrame)
 
 	// the goal is to move the component to goalPose which is specified in coordinates of goalFrameName
-	output, err := motionplan.PlanMotion(ctx, ms.logger, goalPose, movingFrame, fsInputs, frameSys, worldState, constraints, extra)
+	planOpts := ms.planningOptions(extra)
+	output, err := motionplan.PlanMotion(ctx, ms.logger, goalPose, movingFrame, fsInputs, frameSys, worldState, constraints, planOpts)
+	if err != nil {
+		return false, err
+	}
+	replan := func(
+		ctx context.Context,
+		fsInputs map[string][]referenceframe.Input,
+		observed *motionplan.ObservedObstacles,
+	) ([]map[string][]referenceframe.Input, error) {
+		delete(planOpts, motionplan.ObservedObstaclesKey)
+		if observed != nil {
+			planOpts[motionplan.ObservedObstaclesKey] = observed
+		}
+		return motionplan.PlanMotion(ctx, ms.logger, goalPose, movingFrame, fsInputs, frameSys, worldState, constraints, planOpts)
+	}
+	return ms.execute(ctx, output, frameSys, movingFrame, resources, worldState, extra, replan)
+}
+
+// MoveAlongPath plans a motion of a component through each waypoint of the path in turn, keeping within the tolerances
+// of each waypoint, and executes the whole path as a single motion.
+func (ms *builtIn) MoveAlongPath(
+	ctx context.Context,
+	componentName resource.Name,
+	path []motion.PathWaypoint,
+	worldState *referenceframe.WorldState,
+	extra map[string]interface{},
+) (bool, error) {
+	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+
+	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
+	if err != nil {
+		return false, err
+	}
+	fsInputs, resources, err := ms.fsService.CurrentInputs(ctx)
 	if err != nil {
 		return false, err
 	}
+	movingFrame := frameSys.Frame(componentName.ShortName())
+	if movingFrame == nil {
+		return false, fmt.Errorf("component named %s not found in robot frame system", componentName.ShortName())
+	}
+
+	// waypoints are planned to in the frame of World, as destinations of Move are
+	segments := make([]motionplan.PathSegment, 0, len(path))
+	for _, waypoint := range path {
+		if waypoint.Pose == nil {
+			return false, errors.New("path waypoints must have a pose")
+		}
+		tf, err := frameSys.Transform(fsInputs, waypoint.Pose, referenceframe.World)
+		if err != nil {
+			return false, err
+		}
+		segments = append(segments, motionplan.PathSegment{
+			Goal:                     tf.(*referenceframe.PoseInFrame),
+			LinearToleranceMm:        waypoint.LinearToleranceMm,
+			OrientationToleranceDegs: waypoint.OrientationToleranceDegs,
+		})
+	}
+
+	output, err := motionplan.PlanMotionAlongPath(
+		ctx, ms.logger, segments, movingFrame, fsInputs, frameSys, worldState, ms.planningOptions(extra),
+	)
+	if err != nil {
+		return false, err
+	}
+	// a path that is predicted to collide is not replanned, as a new plan could leave the tolerances of the path
+	return ms.execute(ctx, output, frameSys, movingFrame, resources, worldState, extra, nil)
+}
+
+// MoveCoordinated plans motions of several components to their own destinations, avoiding each other, and executes them
+// simultaneously as a single synchronized plan.
+func (ms *builtIn) MoveCoordinated(
+	ctx context.Context,
+	destinations []motion.ComponentDestination,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) (bool, error) {
+	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+
+	if _, monitor, err := monitorOptionsFromExtra(extra); err != nil || monitor {
+		if err == nil {
+			err = fmt.Errorf("%s cannot be used when moving components together", monitorRateKey)
+		}
+		return false, err
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
+	if err != nil {
+		return false, err
+	}
+	fsInputs, resources, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	goals := make([]motionplan.FrameGoal, 0, len(destinations))
+	for _, destination := range destinations {
+		movingFrame := frameSys.Frame(destination.ComponentName.ShortName())
+		if movingFrame == nil {
+			return false, fmt.Errorf("component named %s not found in robot frame system", destination.ComponentName.ShortName())
+		}
+		if destination.Destination == nil {
+			return false, fmt.Errorf("no destination given for component named %s", destination.ComponentName.ShortName())
+		}
+		goals = append(goals, motionplan.FrameGoal{Frame: movingFrame, Goal: destination.Destination})
+	}
+
+	output, err := motionplan.PlanCoordinatedMotion(
+		ctx, ms.logger, goals, fsInputs, frameSys, worldState, constraints, ms.planningOptions(extra),
+	)
+	if err != nil {
+		return false, err
+	}
+	return ms.executeSynchronized(ctx, output, frameSys, resources, extra)
+}
+
+// executeSynchronized moves the components of the frame system through each step of a plan, either along a trajectory or
+// by moving every component to its inputs of a step at the same time before moving on to the next step.
+func (ms *builtIn) executeSynchronized(
+	ctx context.Context,
+	output []map[string][]referenceframe.Input,
+	frameSys referenceframe.FrameSystem,
+	resources map[string]referenceframe.InputEnabled,
+	extra map[string]interface{},
+) (bool, error) {
+	trajOpts, period, useTrajectory, err := trajectoryOptionsFromExtra(extra)
+	if err != nil {
+		return false, err
+	}
+	if useTrajectory {
+		traj, err := motionplan.NewTrajectory(output, frameSys, trajOpts)
+		if err != nil {
+			return false, err
+		}
+		if err := motionplan.ExecuteTrajectory(ctx, traj, resources, period); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	for _, step := range output {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var stepErr error
+		for name, inputs := range step {
+			if len(inputs) == 0 {
+				continue
+			}
+			component, ok := resources[name]
+			if !ok {
+				return false, fmt.Errorf("no component to move frame %q", name)
+			}
+			inputs := inputs
+			wg.Add(1)
+			goutils.PanicCapturingGo(func() {
+				defer wg.Done()
+				if err := component.GoToInputs(ctx, inputs); err != nil {
+					mu.Lock()
+					stepErr = multierr.Combine(stepErr, err)
+					mu.Unlock()
+				}
+			})
+		}
+		wg.Wait()
+		if stepErr != nil {
+			return false, stepErr
+		}
+	}
+	return true, nil
+}
+
+// execute moves the components of the frame system through each step of a plan, either directly, along a trajectory or
+// while monitoring the plan for collisions, as requested in extra. Monitored plans that are predicted to collide are
+// planned again with replan, unless it is nil.
+func (ms *builtIn) execute(
+	ctx context.Context,
+	output []map[string][]referenceframe.Input,
+	frameSys referenceframe.FrameSystem,
+	movingFrame referenceframe.Frame,
+	resources map[string]referenceframe.InputEnabled,
+	worldState *referenceframe.WorldState,
+	extra map[string]interface{},
+	replan replanFunc,
+) (bool, error) {
+	trajOpts, period, useTrajectory, err := trajectoryOptionsFromExtra(extra)
+	if err != nil {
+		return false, err
+	}
+	monitorOpts, monitor, err := monitorOptionsFromExtra(extra)
+	if err != nil {
+		return false, err
+	}
+	if monitor {
+		if useTrajectory {
+			return false, fmt.Errorf("%s cannot be used with %s", monitorRateKey, trajectoryProfileKey)
+		}
+		return ms.executeMonitored(ctx, output, frameSys, movingFrame, resources, worldState, monitorOpts, replan)
+	}
+	if useTrajectory {
+		traj, err := motionplan.NewTrajectory(output, frameSys, trajOpts)
+		if err != nil {
+			return false, err
+		}
+		if err := motionplan.ExecuteTrajectory(ctx, traj, resources, period); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
 
 	// move all the components
 	for _, step := range output {
@@ -233,7 +471,7 @@ func (ms *builtIn) MoveOnMap(
 	// make call to motionplan
 	dst := spatialmath.NewPoseFromPoint(destination.Point())
 	ms.logger.Debugf("goal position: %v", dst)
-	plan, err := motionplan.PlanFrameMotion(ctx, ms.logger, dst, kb.ModelFrame(), inputs, nil, extra)
+	plan, err := motionplan.PlanFrameMotion(ctx, ms.logger, dst, kb.ModelFrame(), inputs, nil, ms.planningOptions(extra))
 	if err != nil {
 		return false, err
 	}
@@ -333,7 +571,7 @@ func (ms *builtIn) MoveOnGlobe(
 	}
 
 	// make call to motionplan
-	plan, err := motionplan.PlanMotion(ctx, ms.logger, dstPIF, kb.ModelFrame(), inputMap, fs, wrldst, nil, extra)
+	plan, err := motionplan.PlanMotion(ctx, ms.logger, dstPIF, kb.ModelFrame(), inputMap, fs, wrldst, nil, ms.planningOptions(extra))
 	if err != nil {
 		return false, err
 	}
@@ -3,11 +3,14 @@ package builtin_test
 import (
 	"context"
 	"math"
+	"os"
 	"testing"
 
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	armpb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/arm"
@@ -20,12 +23,18 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	_ "go.viam.com/rdk/components/register"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/motion/builtin"
 	_ "go.viam.com/rdk/services/register"
 	"go.viam.com/rdk/services/slam"
 	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
 )
 
 func setupMotionServiceFromConfig(t *testing.T, configFilename string) (motion.Service, func()) {
@@ -119,6 +128,242 @@ func TestMove(t *testing.T) {
 	})
 }
 
+func TestDebugRecording(t *testing.T) {
+	recordingDir := motionplan.DebugRecordingDir
+	defer func() {
+		motionplan.DebugRecordingDir = recordingDir
+	}()
+	motionplan.DebugRecordingDir = t.TempDir()
+
+	_, err := (&builtin.Config{DebugRecording: "arm"}).Validate("")
+	test.That(t, err, test.ShouldBeNil)
+	for _, name := range []string{"../arm", t.TempDir()} {
+		_, err := (&builtin.Config{DebugRecording: name}).Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	// requests cannot turn recording on, so they cannot write anywhere
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+	grabPose := referenceframe.NewPoseInFrame("c", spatialmath.NewPoseFromPoint(r3.Vector{0, -30, -50}))
+	outside := t.TempDir()
+	for _, name := range []string{"arm", "../arm", outside} {
+		_, err = ms.Move(context.Background(), gripper.Named("pieceGripper"), grabPose, nil, nil,
+			map[string]interface{}{motionplan.DebugRecordingKey: name})
+		test.That(t, err, test.ShouldBeNil)
+	}
+	for _, dir := range []string{motionplan.DebugRecordingDir, outside} {
+		entries, err := os.ReadDir(dir)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, entries, test.ShouldBeEmpty)
+	}
+}
+
+func TestMoveAlongPath(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	cfg, err := config.Read(ctx, "../data/moving_arm.json", logger)
+	test.That(t, err, test.ShouldBeNil)
+	myRobot, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer myRobot.Close(context.Background())
+	ms, err := motion.FromRobot(myRobot, "builtin")
+	test.That(t, err, test.ShouldBeNil)
+	mover, ok := ms.(motion.PathMover)
+	test.That(t, ok, test.ShouldBeTrue)
+	extra := map[string]interface{}{"max_ik_solutions": 1}
+
+	// start away from the singular, outstretched home position of the arm
+	pieceArm, err := arm.FromRobot(myRobot, "pieceArm")
+	test.That(t, err, test.ShouldBeNil)
+	err = pieceArm.MoveToJointPositions(ctx, &armpb.JointPositions{Values: []float64{0, -60, 60, -60, -60, 0}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// move down and then sideways, relative to where the gripper starts
+	path := []motion.PathWaypoint{
+		{Pose: referenceframe.NewPoseInFrame("pieceGripper", spatialmath.NewPoseFromPoint(r3.Vector{Z: -50})), LinearToleranceMm: 1},
+		{Pose: referenceframe.NewPoseInFrame("pieceGripper", spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Z: -50}))},
+	}
+	goal, err := ms.GetPose(ctx, gripper.Named("pieceGripper"), referenceframe.World, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	goal = referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.Compose(goal.Pose(), path[1].Pose.Pose()))
+
+	success, err := mover.MoveAlongPath(ctx, gripper.Named("pieceGripper"), path, nil, extra)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, success, test.ShouldBeTrue)
+	pose, err := ms.GetPose(ctx, gripper.Named("pieceGripper"), referenceframe.World, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostCoincidentEps(pose.Pose(), goal.Pose(), 1e-1), test.ShouldBeTrue)
+
+	_, err = mover.MoveAlongPath(ctx, gripper.Named("missing"), path, nil, extra)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMoveCoordinated(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	cfg, err := config.Read(ctx, "../data/dual_arm.json", logger)
+	test.That(t, err, test.ShouldBeNil)
+	myRobot, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer myRobot.Close(context.Background())
+	ms, err := motion.FromRobot(myRobot, "builtin")
+	test.That(t, err, test.ShouldBeNil)
+	mover, ok := ms.(motion.CoordinatedMover)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// start away from the singular, outstretched home position of the arms
+	var destinations []motion.ComponentDestination
+	var goals []*referenceframe.PoseInFrame
+	for _, name := range []string{"leftArm", "rightArm"} {
+		a, err := arm.FromRobot(myRobot, name)
+		test.That(t, err, test.ShouldBeNil)
+		err = a.MoveToJointPositions(ctx, &armpb.JointPositions{Values: []float64{0, -60, 60, -60, -60, 0}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		destination := referenceframe.NewPoseInFrame(name, spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Z: -50}))
+		destinations = append(destinations, motion.ComponentDestination{ComponentName: arm.Named(name), Destination: destination})
+		goal, err := ms.GetPose(ctx, arm.Named(name), referenceframe.World, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		goals = append(goals, referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.Compose(goal.Pose(), destination.Pose())))
+	}
+
+	success, err := mover.MoveCoordinated(ctx, destinations, nil, nil, map[string]interface{}{"max_ik_solutions": 1})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, success, test.ShouldBeTrue)
+	for i, destination := range destinations {
+		pose, err := ms.GetPose(ctx, destination.ComponentName, referenceframe.World, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(pose.Pose(), goals[i].Pose(), 1e-1), test.ShouldBeTrue)
+	}
+
+	_, err = mover.MoveCoordinated(ctx, destinations, nil, nil, map[string]interface{}{"monitor_rate_hz": 10.})
+	test.That(t, err, test.ShouldNotBeNil)
+	missing := []motion.ComponentDestination{{ComponentName: arm.Named("missing"), Destination: destinations[0].Destination}}
+	_, err = mover.MoveCoordinated(ctx, missing, nil, nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMoveMonitored(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	cfg, err := config.Read(ctx, "../data/moving_arm.json", logger)
+	test.That(t, err, test.ShouldBeNil)
+	// watch the arm from the origin of the world, rather than from its gripper
+	for i, c := range cfg.Components {
+		if c.Name == "c" {
+			cfg.Components[i].Frame.Parent = referenceframe.World
+		}
+	}
+	myRobot, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer myRobot.Close(context.Background())
+	pieceArm, err := arm.FromRobot(myRobot, "pieceArm")
+	test.That(t, err, test.ShouldBeNil)
+	res, err := myRobot.ResourceByName(framesystem.InternalServiceName)
+	test.That(t, err, test.ShouldBeNil)
+	fsSvc, ok := res.(framesystem.Service)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// the camera sees whatever obstacle is placed in front of it
+	var obstacle []r3.Vector
+	cam := inject.NewCamera("c")
+	cam.NextPointCloudFunc = func(ctx context.Context) (pointcloud.PointCloud, error) {
+		cloud := pointcloud.New()
+		for _, p := range obstacle {
+			if err := cloud.Set(p, nil); err != nil {
+				return nil, err
+			}
+		}
+		return cloud, nil
+	}
+	ms, err := builtin.NewBuiltIn(
+		ctx,
+		resource.Dependencies{
+			framesystem.InternalServiceName: fsSvc,
+			arm.Named("pieceArm"):           pieceArm,
+			camera.Named("c"):               cam,
+		},
+		resource.Config{Name: "builtin", API: motion.API},
+		logger,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	startJoints := &armpb.JointPositions{Values: []float64{0, -60, 60, -60, -60, 0}}
+	test.That(t, pieceArm.MoveToJointPositions(ctx, startJoints, nil), test.ShouldBeNil)
+	start, err := ms.GetPose(ctx, arm.Named("pieceArm"), referenceframe.World, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	goal := referenceframe.NewPoseInFrame(
+		referenceframe.World,
+		spatialmath.Compose(spatialmath.NewPoseFromPoint(r3.Vector{Y: 300}), start.Pose()),
+	)
+
+	// place the obstacle halfway along the plan made without it
+	frameSys, err := fsSvc.FrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	fsInputs, _, err := fsSvc.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	plan, err := motionplan.PlanMotion(ctx, logger, goal, frameSys.Frame("pieceArm"), fsInputs, frameSys, nil, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	halfway := (len(plan) - 1) / 2
+	inputs := plan[halfway]
+	inputs["pieceArm"] = referenceframe.InterpolateInputs(plan[halfway]["pieceArm"], plan[halfway+1]["pieceArm"], 0.5)
+	tf, err := frameSys.Transform(inputs, referenceframe.NewPoseInFrame("pieceArm", spatialmath.NewZeroPose()), referenceframe.World)
+	test.That(t, err, test.ShouldBeNil)
+	obstacle = []r3.Vector{tf.(*referenceframe.PoseInFrame).Pose().Point()}
+
+	extra := map[string]interface{}{"monitor_rate_hz": 100., "monitor_cameras": []interface{}{"c"}}
+	t.Run("stop on predicted collision", func(t *testing.T) {
+		success, err := ms.Move(ctx, arm.Named("pieceArm"), goal, nil, nil, extra)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "stopped executing plan")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "obstacle")
+		test.That(t, errors.Is(err, motion.ErrStoppedForObstacle), test.ShouldBeTrue)
+		test.That(t, success, test.ShouldBeFalse)
+		pose, err := ms.GetPose(ctx, arm.Named("pieceArm"), referenceframe.World, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(pose.Pose(), start.Pose(), 1e-1), test.ShouldBeTrue)
+	})
+
+	t.Run("replan on predicted collision", func(t *testing.T) {
+		extra["max_replans"] = 1.
+		success, err := ms.Move(ctx, arm.Named("pieceArm"), goal, nil, nil, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, success, test.ShouldBeTrue)
+		pose, err := ms.GetPose(ctx, arm.Named("pieceArm"), referenceframe.World, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(pose.Pose(), goal.Pose(), 1e-1), test.ShouldBeTrue)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := ms.Move(ctx, arm.Named("pieceArm"), goal, nil, nil, map[string]interface{}{"monitor_rate_hz": -1.})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.Move(ctx, arm.Named("pieceArm"), goal, nil, nil, map[string]interface{}{
+			"monitor_rate_hz":    100.,
+			"trajectory_profile": "trapezoidal",
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.Move(ctx, arm.Named("pieceArm"), goal, nil, nil, map[string]interface{}{
+			"monitor_rate_hz":    100.,
+			"monitor_max_points": 0.,
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("ignore the robot seeing itself", func(t *testing.T) {
+		fsInputs, _, err := fsSvc.CurrentInputs(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		geometries, err := referenceframe.FrameSystemGeometries(frameSys, fsInputs)
+		test.That(t, err, test.ShouldBeNil)
+		obstacle = []r3.Vector{geometries["pieceArm"].Geometries()[0].Pose().Point()}
+		current, err := ms.GetPose(ctx, arm.Named("pieceArm"), referenceframe.World, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		success, err := ms.Move(ctx, arm.Named("pieceArm"), current, nil, nil, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, success, test.ShouldBeTrue)
+	})
+}
+
 func TestMoveWithObstacles(t *testing.T) {
 	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
 	defer teardown()
@@ -168,6 +413,30 @@ func TestMoveWithObstacles(t *testing.T) {
 	})
 }
 
+func TestCollisionPairs(t *testing.T) {
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
+	defer teardown()
+
+	resp, err := ms.DoCommand(context.Background(), map[string]interface{}{
+		"command":        builtin.CollisionPairsCommand,
+		"component_name": arm.Named("pieceArm").String(),
+	})
+	test.That(t, err, test.ShouldBeNil)
+	pairs, ok := resp["collision_pairs"].([]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, pairs, test.ShouldContain, []interface{}{"pieceArm:base_link", "pieceArm:forearm_link"})
+	// adjacent links of the arm are allowed to touch by its model
+	test.That(t, pairs, test.ShouldNotContain, []interface{}{"pieceArm:base_link", "pieceArm:upper_arm_link"})
+
+	_, err = ms.DoCommand(context.Background(), map[string]interface{}{
+		"command":        builtin.CollisionPairsCommand,
+		"component_name": arm.Named("missing").String(),
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = ms.DoCommand(context.Background(), map[string]interface{}{"command": "unknown"})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
+
 func TestMoveSingleComponent(t *testing.T) {
 	ms, teardown := setupMotionServiceFromConfig(t, "../data/moving_arm.json")
 	defer teardown()
@@ -0,0 +1,69 @@
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// CollisionPairsCommand is the DoCommand command that lists the pairs of geometries checked for collisions when moving the
+// component with the full resource name under "component_name" from where it is. Obstacles of a world state sent in its
+// protojson form under "world_state" are included. Pairs already in collision are left out, as when planning, unless
+// "strict_collisions" is true. The response holds the sorted pairs of names under "collision_pairs".
+const CollisionPairsCommand = "collision_pairs"
+
+// DoCommand runs the commands of the builtin motion service, of which there is only CollisionPairsCommand.
+func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if cmd["command"] != CollisionPairsCommand {
+		return nil, resource.ErrDoUnimplemented
+	}
+	nameString, _ := cmd["component_name"].(string)
+	componentName, err := resource.NewFromString(nameString)
+	if err != nil {
+		return nil, err
+	}
+	var worldState *referenceframe.WorldState
+	if cmd["world_state"] != nil {
+		data, err := json.Marshal(cmd["world_state"])
+		if err != nil {
+			return nil, err
+		}
+		worldStateMsg := &commonpb.WorldState{}
+		if err := protojson.Unmarshal(data, worldStateMsg); err != nil {
+			return nil, err
+		}
+		if worldState, err = referenceframe.WorldStateFromProtobuf(worldStateMsg); err != nil {
+			return nil, err
+		}
+	}
+
+	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
+	if err != nil {
+		return nil, err
+	}
+	fsInputs, _, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	movingFrame := frameSys.Frame(componentName.ShortName())
+	if movingFrame == nil {
+		return nil, fmt.Errorf("component named %s not found in robot frame system", componentName.ShortName())
+	}
+	strict, _ := cmd[motionplan.StrictCollisionsKey].(bool)
+	pairs, err := motionplan.CollisionPairs(movingFrame, frameSys, worldState, fsInputs, nil, strict)
+	if err != nil {
+		return nil, err
+	}
+	pairList := make([]interface{}, 0, len(pairs))
+	for _, pair := range pairs {
+		pairList = append(pairList, []interface{}{pair[0], pair[1]})
+	}
+	return map[string]interface{}{"collision_pairs": pairList}, nil
+}
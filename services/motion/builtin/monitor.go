@@ -0,0 +1,371 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/geo/r3"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Keys of the extra parameters of Move and MoveAlongPath that have a plan executed while monitoring it, by observing the
+// obstacles seen by the given cameras and checking the rest of the plan against them and the world state at the given rate.
+// The points seen are downsampled to one per voxel of the given size, coarsening the voxels when more than the maximum
+// number of points would remain. When the rest of a plan is predicted to collide the motion is stopped, and Move replans
+// it up to the given number of times.
+const (
+	monitorRateKey      = "monitor_rate_hz"
+	monitorCamerasKey   = "monitor_cameras"
+	monitorVoxelSizeKey = "monitor_voxel_size_mm"
+	monitorMaxPointsKey = "monitor_max_points"
+	maxReplansKey       = "max_replans"
+)
+
+const (
+	defaultMonitorVoxelSize = 50.
+	defaultMonitorMaxPoints = 10000
+	// the resolution in mm at which the rest of a plan is checked against the observed obstacles.
+	monitorCheckResolution = 5.
+)
+
+type monitorOptions struct {
+	period     time.Duration
+	cameras    []string
+	voxelSize  float64
+	maxPoints  int
+	maxReplans int
+}
+
+// monitorOptionsFromExtra returns the monitoring options requested in extra, or false if extra does not request that the
+// plan be monitored while it is executed.
+func monitorOptionsFromExtra(extra map[string]interface{}) (*monitorOptions, bool, error) {
+	rateVal, ok := extra[monitorRateKey]
+	if !ok {
+		return nil, false, nil
+	}
+	rate, ok := rateVal.(float64)
+	if !ok || rate <= 0 {
+		return nil, false, fmt.Errorf("%s must be a positive number", monitorRateKey)
+	}
+	opts := &monitorOptions{
+		period:    time.Duration(float64(time.Second) / rate),
+		voxelSize: defaultMonitorVoxelSize,
+		maxPoints: defaultMonitorMaxPoints,
+	}
+	switch cameras := extra[monitorCamerasKey].(type) {
+	case nil:
+	case []string:
+		opts.cameras = cameras
+	case []interface{}:
+		for _, c := range cameras {
+			name, ok := c.(string)
+			if !ok {
+				return nil, false, fmt.Errorf("%s must be a list of camera names", monitorCamerasKey)
+			}
+			opts.cameras = append(opts.cameras, name)
+		}
+	default:
+		return nil, false, fmt.Errorf("%s must be a list of camera names", monitorCamerasKey)
+	}
+	if val, ok := extra[monitorVoxelSizeKey]; ok {
+		size, ok := val.(float64)
+		if !ok || size <= 0 {
+			return nil, false, fmt.Errorf("%s must be a positive number", monitorVoxelSizeKey)
+		}
+		opts.voxelSize = size
+	}
+	if val, ok := extra[monitorMaxPointsKey]; ok {
+		maxPoints, ok := val.(float64)
+		if !ok || maxPoints < 1 || maxPoints != math.Trunc(maxPoints) {
+			return nil, false, fmt.Errorf("%s must be a positive integer", monitorMaxPointsKey)
+		}
+		opts.maxPoints = int(maxPoints)
+	}
+	if val, ok := extra[maxReplansKey]; ok {
+		replans, ok := val.(float64)
+		if !ok || replans < 0 || replans != math.Trunc(replans) {
+			return nil, false, fmt.Errorf("%s must be a non-negative integer", maxReplansKey)
+		}
+		opts.maxReplans = int(replans)
+	}
+	return opts, true, nil
+}
+
+// stoppedExecutionError reports that the execution of a plan was stopped because the rest of the plan is predicted to
+// collide, keeping the obstacles observed at the time to replan with.
+type stoppedExecutionError struct {
+	err      error
+	observed *motionplan.ObservedObstacles
+}
+
+func (e *stoppedExecutionError) Error() string {
+	return fmt.Sprintf("stopped executing plan: %v", e.err)
+}
+
+func (e *stoppedExecutionError) Unwrap() error {
+	return e.err
+}
+
+func (e *stoppedExecutionError) Is(target error) bool {
+	return target == motion.ErrStoppedForObstacle
+}
+
+// replanFunc plans a motion again from the given inputs, avoiding the observed obstacles as well as the world state.
+type replanFunc func(ctx context.Context, fsInputs map[string][]referenceframe.Input, observed *motionplan.ObservedObstacles) (
+	[]map[string][]referenceframe.Input, error)
+
+// executeMonitored moves the components of the frame system through each step of a plan while monitoring it. If the
+// rest of the plan is predicted to collide the motion is stopped and, if replan is not nil and replans remain, planned
+// again from where the components stopped.
+func (ms *builtIn) executeMonitored(
+	ctx context.Context,
+	plan []map[string][]referenceframe.Input,
+	frameSys referenceframe.FrameSystem,
+	movingFrame referenceframe.Frame,
+	resources map[string]referenceframe.InputEnabled,
+	worldState *referenceframe.WorldState,
+	opts *monitorOptions,
+	replan replanFunc,
+) (bool, error) {
+	for replans := 0; ; replans++ {
+		err := ms.executeMonitoredPlan(ctx, plan, frameSys, movingFrame, resources, worldState, opts)
+		if err == nil {
+			return true, nil
+		}
+		var stopped *stoppedExecutionError
+		if !errors.As(err, &stopped) || replan == nil || replans >= opts.maxReplans {
+			return false, err
+		}
+		ms.logger.Infof("replanning motion of %s: %v", movingFrame.Name(), err)
+		fsInputs, _, err := ms.fsService.CurrentInputs(ctx)
+		if err != nil {
+			return false, err
+		}
+		if plan, err = replan(ctx, fsInputs, stopped.observed); err != nil {
+			return false, fmt.Errorf("failed to replan after %v: %w", stopped, err)
+		}
+	}
+}
+
+// executeMonitoredPlan moves the components of the frame system through each step of a plan, checking the rest of the plan
+// against the world state and the observed obstacles before moving and then at the rate of the monitor options.
+func (ms *builtIn) executeMonitoredPlan(
+	ctx context.Context,
+	plan []map[string][]referenceframe.Input,
+	frameSys referenceframe.FrameSystem,
+	movingFrame referenceframe.Frame,
+	resources map[string]referenceframe.InputEnabled,
+	worldState *referenceframe.WorldState,
+	opts *monitorOptions,
+) error {
+	var next atomic.Int64
+	check := func(ctx context.Context) error {
+		fsInputs, _, err := ms.fsService.CurrentInputs(ctx)
+		if err != nil {
+			return err
+		}
+		observed, err := ms.observeObstacles(ctx, frameSys, fsInputs, opts)
+		if err != nil {
+			return err
+		}
+		err = motionplan.CheckPlan(movingFrame, fsInputs, plan[next.Load():], frameSys, worldState, observed, monitorCheckResolution)
+		if err != nil {
+			return &stoppedExecutionError{err: err, observed: observed}
+		}
+		return nil
+	}
+	if err := check(ctx); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	moveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	monitorErr := make(chan error, 1)
+	wg.Add(1)
+	goutils.ManagedGo(func() {
+		ticker := time.NewTicker(opts.period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-moveCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := check(moveCtx); err != nil {
+				if moveCtx.Err() == nil {
+					monitorErr <- err
+					cancel()
+				}
+				return
+			}
+		}
+	}, wg.Done)
+
+	// a failed check stops the components moving, and is reported instead of the error from the move it interrupted
+	stop := func(err error, step map[string][]referenceframe.Input) error {
+		select {
+		case mErr := <-monitorErr:
+			err = mErr
+		default:
+		}
+		for name := range step {
+			if actuator, ok := resources[name].(resource.Actuator); ok {
+				if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
+					ms.logger.Warnf("failed to stop %s: %v", name, stopErr)
+				}
+			}
+		}
+		return err
+	}
+	for i, step := range plan {
+		next.Store(int64(i))
+		for name, inputs := range step {
+			if len(inputs) == 0 {
+				continue
+			}
+			if err := resources[name].GoToInputs(moveCtx, inputs); err != nil {
+				return stop(err, step)
+			}
+		}
+		select {
+		case err := <-monitorErr:
+			return stop(err, step)
+		default:
+		}
+	}
+	return nil
+}
+
+// observeObstacles returns the points seen by the monitored cameras as obstacles in the frame of World, or nil if none are
+// seen. The points are downsampled to the center of each occupied voxel, with the voxels doubled in size until no more than
+// the maximum number of points remain. Points that the robot already touches at its current inputs are dropped, as those
+// are the robot seeing itself.
+func (ms *builtIn) observeObstacles(
+	ctx context.Context,
+	frameSys referenceframe.FrameSystem,
+	fsInputs map[string][]referenceframe.Input,
+	opts *monitorOptions,
+) (*motionplan.ObservedObstacles, error) {
+	var points []r3.Vector
+	for _, cameraName := range opts.cameras {
+		component, ok := ms.components[camera.Named(cameraName)]
+		if !ok {
+			return nil, resource.DependencyNotFoundError(camera.Named(cameraName))
+		}
+		cam, ok := component.(camera.Camera)
+		if !ok {
+			return nil, fmt.Errorf("cannot monitor component of type %T because it is not a Camera", component)
+		}
+		tf, err := frameSys.Transform(
+			fsInputs,
+			referenceframe.NewPoseInFrame(cameraName, spatialmath.NewZeroPose()),
+			referenceframe.World,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cameraPose := tf.(*referenceframe.PoseInFrame).Pose()
+		cloud, err := cam.NextPointCloud(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cloud.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+			points = append(points, spatialmath.Compose(cameraPose, spatialmath.NewPoseFromPoint(p)).Point())
+			return true
+		})
+	}
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	voxelSize := opts.voxelSize
+	centers := voxelCenters(points, voxelSize)
+	for len(centers) > opts.maxPoints {
+		voxelSize *= 2
+		centers = voxelCenters(points, voxelSize)
+	}
+	// a point stands for everything within the voxel around it
+	buffer := voxelSize * math.Sqrt(3) / 2
+
+	robotGeometries, err := referenceframe.FrameSystemGeometries(frameSys, fsInputs)
+	if err != nil {
+		return nil, err
+	}
+	var observed []r3.Vector
+	minPt := r3.Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxPt := r3.Vector{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, center := range centers {
+		touched, err := touchesGeometries(center, buffer, robotGeometries)
+		if err != nil {
+			return nil, err
+		}
+		if touched {
+			continue
+		}
+		observed = append(observed, center)
+		minPt = r3.Vector{X: math.Min(minPt.X, center.X), Y: math.Min(minPt.Y, center.Y), Z: math.Min(minPt.Z, center.Z)}
+		maxPt = r3.Vector{X: math.Max(maxPt.X, center.X), Y: math.Max(maxPt.Y, center.Y), Z: math.Max(maxPt.Z, center.Z)}
+	}
+	if len(observed) == 0 {
+		return nil, nil
+	}
+
+	extent := maxPt.Sub(minPt)
+	octree, err := pointcloud.NewBasicOctree(minPt.Add(maxPt).Mul(0.5), math.Max(extent.X, math.Max(extent.Y, extent.Z))+voxelSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range observed {
+		if err := octree.Set(p, pointcloud.NewBasicData()); err != nil {
+			return nil, err
+		}
+	}
+	return &motionplan.ObservedObstacles{Points: octree, Buffer: buffer}, nil
+}
+
+// voxelCenters returns the center of each voxel of the given size occupied by the points.
+func voxelCenters(points []r3.Vector, voxelSize float64) []r3.Vector {
+	voxels := map[r3.Vector]bool{}
+	var centers []r3.Vector
+	for _, p := range points {
+		voxel := r3.Vector{X: math.Floor(p.X / voxelSize), Y: math.Floor(p.Y / voxelSize), Z: math.Floor(p.Z / voxelSize)}
+		if voxels[voxel] {
+			continue
+		}
+		voxels[voxel] = true
+		centers = append(centers, voxel.Add(r3.Vector{X: 0.5, Y: 0.5, Z: 0.5}).Mul(voxelSize))
+	}
+	return centers
+}
+
+// touchesGeometries returns whether a point is within the buffer distance of any of the geometries.
+func touchesGeometries(p r3.Vector, buffer float64, geometries map[string]*referenceframe.GeometriesInFrame) (bool, error) {
+	pt := spatialmath.NewPoint(p, "")
+	for _, geometriesInFrame := range geometries {
+		for _, geometry := range geometriesInFrame.Geometries() {
+			distance, err := geometry.DistanceFrom(pt)
+			if err != nil {
+				return false, err
+			}
+			if distance <= buffer {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
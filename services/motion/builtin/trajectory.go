@@ -0,0 +1,56 @@
+package builtin
+
+import (
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/motionplan"
+)
+
+// Keys of the extra parameters of Move that have a plan executed as a time parameterized trajectory, rather than by
+// moving each component to each step of the plan in turn.
+const (
+	trajectoryProfileKey           = "trajectory_profile"
+	trajectorySamplePeriodKey      = "trajectory_sample_period_ms"
+	trajectoryVelocityScaleKey     = "trajectory_velocity_scale"
+	trajectoryAccelerationScaleKey = "trajectory_acceleration_scale"
+	trajectoryMaxBlendDeviationKey = "trajectory_max_blend_deviation"
+)
+
+const defaultTrajectorySamplePeriod = 50 * time.Millisecond
+
+// trajectoryOptionsFromExtra returns the trajectory options and sample period requested in extra, or false if extra does
+// not request that the plan be executed as a trajectory.
+func trajectoryOptionsFromExtra(extra map[string]interface{}) (*motionplan.TrajectoryOptions, time.Duration, bool, error) {
+	profileVal, ok := extra[trajectoryProfileKey]
+	if !ok {
+		return nil, 0, false, nil
+	}
+	profile, ok := profileVal.(string)
+	if !ok {
+		return nil, 0, false, fmt.Errorf("%s must be a string", trajectoryProfileKey)
+	}
+	opts := &motionplan.TrajectoryOptions{Profile: motionplan.TrajectoryProfile(profile)}
+	period := defaultTrajectorySamplePeriod
+	for key, dst := range map[string]*float64{
+		trajectoryVelocityScaleKey:     &opts.VelocityScale,
+		trajectoryAccelerationScaleKey: &opts.AccelerationScale,
+		trajectoryMaxBlendDeviationKey: &opts.MaxBlendDeviation,
+	} {
+		if val, ok := extra[key]; ok {
+			f, ok := val.(float64)
+			if !ok {
+				return nil, 0, false, fmt.Errorf("%s must be a number", key)
+			}
+			*dst = f
+		}
+	}
+	if val, ok := extra[trajectorySamplePeriodKey]; ok {
+		ms, ok := val.(float64)
+		if !ok || ms <= 0 {
+			return nil, 0, false, fmt.Errorf("%s must be a positive number", trajectorySamplePeriodKey)
+		}
+		period = time.Duration(ms * float64(time.Millisecond))
+	}
+	return opts, period, true, nil
+}
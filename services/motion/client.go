@@ -72,7 +72,7 @@ func (c *client) Move(
 		Extra:         ext,
 	})
 	if err != nil {
-		return false, err
+		return false, fromStatusError(err)
 	}
 	return resp.Success, nil
 }
@@ -96,7 +96,7 @@ func (c *client) MoveOnMap(
 		Extra:           ext,
 	})
 	if err != nil {
-		return false, err
+		return false, fromStatusError(err)
 	}
 	return resp.Success, nil
 }
@@ -155,7 +155,7 @@ func (c *client) MoveOnGlobe(
 
 	resp, err := c.client.MoveOnGlobe(ctx, req)
 	if err != nil {
-		return false, err
+		return false, fromStatusError(err)
 	}
 
 	return resp.Success, nil
@@ -184,11 +184,58 @@ func (c *client) MoveSingleComponent(
 		Extra:         ext,
 	})
 	if err != nil {
-		return false, err
+		return false, fromStatusError(err)
 	}
 	return resp.Success, nil
 }
 
+// MoveAlongPath moves a component of the remote service along a path. It fails if the service does not move along paths.
+func (c *client) MoveAlongPath(
+	ctx context.Context,
+	componentName resource.Name,
+	path []PathWaypoint,
+	worldState *referenceframe.WorldState,
+	extra map[string]interface{},
+) (bool, error) {
+	cmd, err := moveAlongPathToMap(componentName, path, worldState, extra)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.DoCommand(ctx, cmd)
+	if err != nil {
+		return false, err
+	}
+	success, ok := resp["success"].(bool)
+	if !ok {
+		return false, errors.Errorf("motion service %s does not move along paths", c.name)
+	}
+	return success, nil
+}
+
+// MoveCoordinated moves components of the remote service to their own destinations together. It fails if the service
+// does not move components together.
+func (c *client) MoveCoordinated(
+	ctx context.Context,
+	destinations []ComponentDestination,
+	worldState *referenceframe.WorldState,
+	constraints *pb.Constraints,
+	extra map[string]interface{},
+) (bool, error) {
+	cmd, err := moveCoordinatedToMap(destinations, worldState, constraints, extra)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.DoCommand(ctx, cmd)
+	if err != nil {
+		return false, err
+	}
+	success, ok := resp["success"].(bool)
+	if !ok {
+		return false, errors.Errorf("motion service %s does not move components together", c.name)
+	}
+	return success, nil
+}
+
 func (c *client) GetPose(
 	ctx context.Context,
 	componentName resource.Name,
@@ -32,6 +32,45 @@ var (
 	testMotionServiceName2 = motion.Named("motion2")
 )
 
+// extendedMotionService is a motion service which implements the optional motion service interfaces.
+type extendedMotionService struct {
+	*inject.MotionService
+	moveAlongPathFunc func(
+		ctx context.Context,
+		componentName resource.Name,
+		path []motion.PathWaypoint,
+		worldState *referenceframe.WorldState,
+		extra map[string]interface{},
+	) (bool, error)
+	moveCoordinatedFunc func(
+		ctx context.Context,
+		destinations []motion.ComponentDestination,
+		worldState *referenceframe.WorldState,
+		constraints *servicepb.Constraints,
+		extra map[string]interface{},
+	) (bool, error)
+}
+
+func (ms *extendedMotionService) MoveAlongPath(
+	ctx context.Context,
+	componentName resource.Name,
+	path []motion.PathWaypoint,
+	worldState *referenceframe.WorldState,
+	extra map[string]interface{},
+) (bool, error) {
+	return ms.moveAlongPathFunc(ctx, componentName, path, worldState, extra)
+}
+
+func (ms *extendedMotionService) MoveCoordinated(
+	ctx context.Context,
+	destinations []motion.ComponentDestination,
+	worldState *referenceframe.WorldState,
+	constraints *servicepb.Constraints,
+	extra map[string]interface{},
+) (bool, error) {
+	return ms.moveCoordinatedFunc(ctx, destinations, worldState, constraints, extra)
+}
+
 func TestClient(t *testing.T) {
 	ctx := context.Background()
 	logger := golog.NewTestLogger(t)
@@ -41,8 +80,11 @@ func TestClient(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 
 	injectMS := &inject.MotionService{}
+	extendedMS := &extendedMotionService{MotionService: injectMS}
+	// the second service is the same, but without the optional interfaces
 	resources := map[resource.Name]motion.Service{
-		testMotionServiceName: injectMS,
+		testMotionServiceName:  extendedMS,
+		testMotionServiceName2: struct{ motion.Service }{injectMS},
 	}
 	svc, err := resource.NewAPIResourceCollection(motion.API, resources)
 	test.That(t, err, test.ShouldBeNil)
@@ -159,6 +201,79 @@ func TestClient(t *testing.T) {
 		}
 		test.That(t, receivedTransforms, test.ShouldNotBeNil)
 
+		// MoveAlongPath
+		var receivedPath []motion.PathWaypoint
+		extendedMS.moveAlongPathFunc = func(
+			ctx context.Context,
+			componentName resource.Name,
+			path []motion.PathWaypoint,
+			worldState *referenceframe.WorldState,
+			extra map[string]interface{},
+		) (bool, error) {
+			test.That(t, componentName, test.ShouldResemble, gripperName)
+			test.That(t, worldState.ObstacleNames(), test.ShouldResemble, map[string]bool{"box": true})
+			test.That(t, extra, test.ShouldResemble, map[string]interface{}{"foo": "MoveAlongPath"})
+			receivedPath = path
+			return success, nil
+		}
+		box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{10, 10, 10}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		worldState, err := referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{box})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		path := []motion.PathWaypoint{
+			{Pose: referenceframe.NewPoseInFrame("foo", testPose), LinearToleranceMm: 2, OrientationToleranceDegs: 3},
+			{Pose: zeroPoseInFrame},
+		}
+		mover, ok := client.(motion.PathMover)
+		test.That(t, ok, test.ShouldBeTrue)
+		result, err = mover.MoveAlongPath(ctx, gripperName, path, worldState, map[string]interface{}{"foo": "MoveAlongPath"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result, test.ShouldEqual, success)
+		test.That(t, len(receivedPath), test.ShouldEqual, 2)
+		for i, waypoint := range receivedPath {
+			test.That(t, waypoint.Pose.Parent(), test.ShouldEqual, path[i].Pose.Parent())
+			test.That(t, spatialmath.PoseAlmostEqual(waypoint.Pose.Pose(), path[i].Pose.Pose()), test.ShouldBeTrue)
+			test.That(t, waypoint.LinearToleranceMm, test.ShouldEqual, path[i].LinearToleranceMm)
+			test.That(t, waypoint.OrientationToleranceDegs, test.ShouldEqual, path[i].OrientationToleranceDegs)
+		}
+
+		// MoveCoordinated
+		armName := arm.Named("arm1")
+		var receivedDestinations []motion.ComponentDestination
+		extendedMS.moveCoordinatedFunc = func(
+			ctx context.Context,
+			destinations []motion.ComponentDestination,
+			worldState *referenceframe.WorldState,
+			constraints *servicepb.Constraints,
+			extra map[string]interface{},
+		) (bool, error) {
+			test.That(t, worldState.ObstacleNames(), test.ShouldResemble, map[string]bool{"box": true})
+			test.That(t, constraints.GetLinearConstraint(), test.ShouldHaveLength, 1)
+			test.That(t, extra, test.ShouldResemble, map[string]interface{}{"foo": "MoveCoordinated"})
+			receivedDestinations = destinations
+			return success, nil
+		}
+		destinations := []motion.ComponentDestination{
+			{ComponentName: gripperName, Destination: referenceframe.NewPoseInFrame("foo", testPose)},
+			{ComponentName: armName, Destination: zeroPoseInFrame},
+		}
+		constraints := &servicepb.Constraints{LinearConstraint: []*servicepb.LinearConstraint{{}}}
+		coordinatedMover, ok := client.(motion.CoordinatedMover)
+		test.That(t, ok, test.ShouldBeTrue)
+		coordinatedExtra := map[string]interface{}{"foo": "MoveCoordinated"}
+		result, err = coordinatedMover.MoveCoordinated(ctx, destinations, worldState, constraints, coordinatedExtra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result, test.ShouldEqual, success)
+		test.That(t, len(receivedDestinations), test.ShouldEqual, 2)
+		for i, destination := range receivedDestinations {
+			test.That(t, destination.ComponentName, test.ShouldResemble, destinations[i].ComponentName)
+			test.That(t, destination.Destination.Parent(), test.ShouldEqual, destinations[i].Destination.Parent())
+			test.That(t, spatialmath.PoseAlmostEqual(destination.Destination.Pose(), destinations[i].Destination.Pose()), test.ShouldBeTrue)
+		}
+
 		// DoCommand
 		injectMS.DoCommandFunc = testutils.EchoFunc
 		resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
@@ -170,6 +285,36 @@ func TestClient(t *testing.T) {
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 
+	t.Run("motion client without optional interfaces", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		client, err := motion.NewClientFromConn(context.Background(), conn, "", testMotionServiceName2, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		// the commands of the optional interfaces reach DoCommand of services which do not implement them
+		var received map[string]interface{}
+		injectMS.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			received = cmd
+			return cmd, nil
+		}
+		extendedMS.moveAlongPathFunc = nil
+		path := []motion.PathWaypoint{{Pose: zeroPoseInFrame}}
+		_, err = client.(motion.PathMover).MoveAlongPath(ctx, gripperName, path, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not move along paths")
+		test.That(t, received["command"], test.ShouldEqual, motion.MoveAlongPathCommand)
+
+		extendedMS.moveCoordinatedFunc = nil
+		destinations := []motion.ComponentDestination{{ComponentName: gripperName, Destination: zeroPoseInFrame}}
+		_, err = client.(motion.CoordinatedMover).MoveCoordinated(ctx, destinations, nil, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not move components together")
+		test.That(t, received["command"], test.ShouldEqual, motion.MoveCoordinatedCommand)
+
+		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	})
+
 	// broken
 	t.Run("motion client 2", func(t *testing.T) {
 		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
@@ -223,6 +368,26 @@ func TestClient(t *testing.T) {
 		test.That(t, err, test.ShouldNotBeNil)
 		test.That(t, err.Error(), test.ShouldContainSubstring, passedErr.Error())
 		test.That(t, resp, test.ShouldEqual, false)
+		test.That(t, errors.Is(err, motion.ErrStoppedForObstacle), test.ShouldBeFalse)
+
+		// a motion stopped for an obstacle can still be told apart
+		injectMS.MoveOnGlobeFunc = func(
+			ctx context.Context,
+			componentName resource.Name,
+			destination *geo.Point,
+			heading float64,
+			movementSensorName resource.Name,
+			obstacles []*spatialmath.GeoObstacle,
+			linearVelocity float64,
+			angularVelocity float64,
+			extra map[string]interface{},
+		) (bool, error) {
+			return false, errors.Wrap(motion.ErrStoppedForObstacle, "box in the way")
+		}
+		resp, err = client2.MoveOnGlobe(ctx, baseName, globeDest, math.NaN(), gpsName, nil, math.NaN(), math.NaN(), nil)
+		test.That(t, errors.Is(err, motion.ErrStoppedForObstacle), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "box in the way")
+		test.That(t, resp, test.ShouldEqual, false)
 
 		// GetPose
 		_, err = client2.GetPose(context.Background(), arm.Named("arm1"), "foo", nil, map[string]interface{}{})
@@ -0,0 +1,117 @@
+package motion
+
+import (
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	pb "go.viam.com/api/service/motion/v1"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// MoveCoordinatedCommand is the DoCommand command clients send to call MoveCoordinated on a CoordinatedMover. Each entry
+// of "destinations" pairs a full "component_name" with a "destination" pose in protojson form, the world state and
+// constraints are sent in protojson form under "world_state" and "constraints", and the response holds whether every
+// component reached its destination under "success".
+const MoveCoordinatedCommand = "move_coordinated"
+
+// ComponentDestination is the destination of one of the components moved together by MoveCoordinated.
+type ComponentDestination struct {
+	ComponentName resource.Name
+	Destination   *referenceframe.PoseInFrame
+}
+
+// moveCoordinatedToMap converts the arguments of MoveCoordinated to a DoCommand command.
+func moveCoordinatedToMap(
+	destinations []ComponentDestination,
+	worldState *referenceframe.WorldState,
+	constraints *pb.Constraints,
+	extra map[string]interface{},
+) (map[string]interface{}, error) {
+	dests := make([]interface{}, 0, len(destinations))
+	for _, destination := range destinations {
+		pose, err := protoToMap(referenceframe.PoseInFrameToProtobuf(destination.Destination))
+		if err != nil {
+			return nil, err
+		}
+		dests = append(dests, map[string]interface{}{
+			"component_name": destination.ComponentName.String(),
+			"destination":    pose,
+		})
+	}
+	cmd := map[string]interface{}{
+		"command":      MoveCoordinatedCommand,
+		"destinations": dests,
+		"extra":        extra,
+	}
+	if worldState != nil {
+		worldStateMsg, err := worldState.ToProtobuf()
+		if err != nil {
+			return nil, err
+		}
+		if cmd["world_state"], err = protoToMap(worldStateMsg); err != nil {
+			return nil, err
+		}
+	}
+	if constraints != nil {
+		var err error
+		if cmd["constraints"], err = protoToMap(constraints); err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+// moveCoordinatedFromMap converts a DoCommand command sent for MoveCoordinatedCommand to the arguments of MoveCoordinated.
+func moveCoordinatedFromMap(cmd map[string]interface{}) (
+	[]ComponentDestination,
+	*referenceframe.WorldState,
+	*pb.Constraints,
+	map[string]interface{},
+	error,
+) {
+	dests, ok := cmd["destinations"].([]interface{})
+	if !ok {
+		return nil, nil, nil, nil, errors.New("move_coordinated command must have a list of destinations")
+	}
+	destinations := make([]ComponentDestination, 0, len(dests))
+	for i, d := range dests {
+		dest, ok := d.(map[string]interface{})
+		if !ok {
+			return nil, nil, nil, nil, errors.Errorf("destination %d is not an object", i)
+		}
+		nameString, _ := dest["component_name"].(string)
+		componentName, err := resource.NewFromString(nameString)
+		if err != nil {
+			return nil, nil, nil, nil, errors.Wrapf(err, "destination %d", i)
+		}
+		pose := &commonpb.PoseInFrame{}
+		if err := protoFromMap(dest["destination"], pose); err != nil {
+			return nil, nil, nil, nil, errors.Wrapf(err, "destination %d", i)
+		}
+		destinations = append(destinations, ComponentDestination{
+			ComponentName: componentName,
+			Destination:   referenceframe.ProtobufToPoseInFrame(pose),
+		})
+	}
+	var worldState *referenceframe.WorldState
+	if cmd["world_state"] != nil {
+		worldStateMsg := &commonpb.WorldState{}
+		if err := protoFromMap(cmd["world_state"], worldStateMsg); err != nil {
+			return nil, nil, nil, nil, err
+		}
+		var err error
+		if worldState, err = referenceframe.WorldStateFromProtobuf(worldStateMsg); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	var constraints *pb.Constraints
+	if cmd["constraints"] != nil {
+		constraints = &pb.Constraints{}
+		if err := protoFromMap(cmd["constraints"], constraints); err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	extra, _ := cmd["extra"].(map[string]interface{})
+	return destinations, worldState, constraints, extra, nil
+}
@@ -0,0 +1,41 @@
+package motion
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrStoppedForObstacle is matched, using errors.Is, by the errors of motions that were stopped because an obstacle is
+// in the way of the rest of the plan.
+var ErrStoppedForObstacle = errors.New("motion stopped for an obstacle")
+
+// stoppedForObstacleError is how the client reports a motion that the remote service stopped for an obstacle.
+type stoppedForObstacleError struct {
+	msg string
+}
+
+func (e *stoppedForObstacleError) Error() string {
+	return e.msg
+}
+
+func (e *stoppedForObstacleError) Is(target error) bool {
+	return target == ErrStoppedForObstacle
+}
+
+// toStatusError sends a motion stopped for an obstacle as codes.Aborted, as only the code and message of an error reach
+// the client.
+func toStatusError(err error) error {
+	if errors.Is(err, ErrStoppedForObstacle) {
+		return status.Error(codes.Aborted, err.Error())
+	}
+	return err
+}
+
+// fromStatusError turns codes.Aborted back into an error matching ErrStoppedForObstacle.
+func fromStatusError(err error) error {
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Aborted {
+		return &stoppedForObstacleError{msg: s.Message()}
+	}
+	return err
+}
@@ -67,6 +67,28 @@ type Service interface {
 	) (*referenceframe.PoseInFrame, error)
 }
 
+// PathMover is implemented by motion services that can move a component through a path of waypoints.
+type PathMover interface {
+	MoveAlongPath(
+		ctx context.Context,
+		componentName resource.Name,
+		path []PathWaypoint,
+		worldState *referenceframe.WorldState,
+		extra map[string]interface{},
+	) (bool, error)
+}
+
+// CoordinatedMover is implemented by motion services that can move several components to their own destinations together.
+type CoordinatedMover interface {
+	MoveCoordinated(
+		ctx context.Context,
+		destinations []ComponentDestination,
+		worldState *referenceframe.WorldState,
+		constraints *servicepb.Constraints,
+		extra map[string]interface{},
+	) (bool, error)
+}
+
 // SubtypeName is the name of the type of service.
 const SubtypeName = "motion"
 
@@ -0,0 +1,134 @@
+package motion
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// MoveAlongPathCommand is the DoCommand command clients send to call MoveAlongPath on a PathMover. Each waypoint of the
+// "path" key holds its pose in protojson form along with its tolerances, the other arguments are sent under the
+// "component_name", "world_state" and "extra" keys, and the response holds whether the move succeeded under "success".
+const MoveAlongPathCommand = "move_along_path"
+
+// PathWaypoint is a pose for a component to pass through when moving along a path. While moving to the waypoint from
+// the previous one, the component stays within LinearToleranceMm of the straight line between them, and within
+// OrientationToleranceDegs of the orientation interpolated between them. Tolerances of zero use the defaults of the
+// linear constraint.
+type PathWaypoint struct {
+	Pose                     *referenceframe.PoseInFrame
+	LinearToleranceMm        float64
+	OrientationToleranceDegs float64
+}
+
+// moveAlongPathToMap converts the arguments of MoveAlongPath to a DoCommand command.
+func moveAlongPathToMap(
+	componentName resource.Name,
+	path []PathWaypoint,
+	worldState *referenceframe.WorldState,
+	extra map[string]interface{},
+) (map[string]interface{}, error) {
+	waypoints := make([]interface{}, 0, len(path))
+	for _, waypoint := range path {
+		pose, err := protoToMap(referenceframe.PoseInFrameToProtobuf(waypoint.Pose))
+		if err != nil {
+			return nil, err
+		}
+		waypoints = append(waypoints, map[string]interface{}{
+			"pose":                       pose,
+			"line_tolerance_mm":          waypoint.LinearToleranceMm,
+			"orientation_tolerance_degs": waypoint.OrientationToleranceDegs,
+		})
+	}
+	cmd := map[string]interface{}{
+		"command":        MoveAlongPathCommand,
+		"component_name": componentName.String(),
+		"path":           waypoints,
+		"extra":          extra,
+	}
+	if worldState != nil {
+		worldStateMsg, err := worldState.ToProtobuf()
+		if err != nil {
+			return nil, err
+		}
+		if cmd["world_state"], err = protoToMap(worldStateMsg); err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+// moveAlongPathFromMap converts a DoCommand command sent for MoveAlongPathCommand to the arguments of MoveAlongPath.
+func moveAlongPathFromMap(cmd map[string]interface{}) (
+	resource.Name,
+	[]PathWaypoint,
+	*referenceframe.WorldState,
+	map[string]interface{},
+	error,
+) {
+	nameString, _ := cmd["component_name"].(string)
+	componentName, err := resource.NewFromString(nameString)
+	if err != nil {
+		return resource.Name{}, nil, nil, nil, err
+	}
+	waypoints, ok := cmd["path"].([]interface{})
+	if !ok {
+		return resource.Name{}, nil, nil, nil, errors.New("move_along_path command must have a list of waypoints as its path")
+	}
+	path := make([]PathWaypoint, 0, len(waypoints))
+	for i, w := range waypoints {
+		waypoint, ok := w.(map[string]interface{})
+		if !ok {
+			return resource.Name{}, nil, nil, nil, errors.Errorf("path waypoint %d is not an object", i)
+		}
+		pose := &commonpb.PoseInFrame{}
+		if err := protoFromMap(waypoint["pose"], pose); err != nil {
+			return resource.Name{}, nil, nil, nil, errors.Wrapf(err, "path waypoint %d", i)
+		}
+		lineTolerance, _ := waypoint["line_tolerance_mm"].(float64)
+		orientationTolerance, _ := waypoint["orientation_tolerance_degs"].(float64)
+		path = append(path, PathWaypoint{
+			Pose:                     referenceframe.ProtobufToPoseInFrame(pose),
+			LinearToleranceMm:        lineTolerance,
+			OrientationToleranceDegs: orientationTolerance,
+		})
+	}
+	var worldState *referenceframe.WorldState
+	if cmd["world_state"] != nil {
+		worldStateMsg := &commonpb.WorldState{}
+		if err := protoFromMap(cmd["world_state"], worldStateMsg); err != nil {
+			return resource.Name{}, nil, nil, nil, err
+		}
+		if worldState, err = referenceframe.WorldStateFromProtobuf(worldStateMsg); err != nil {
+			return resource.Name{}, nil, nil, nil, err
+		}
+	}
+	extra, _ := cmd["extra"].(map[string]interface{})
+	return componentName, path, worldState, extra, nil
+}
+
+func protoToMap(msg proto.Message) (map[string]interface{}, error) {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func protoFromMap(m interface{}, msg proto.Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(data, msg)
+}
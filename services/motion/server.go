@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/motion/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/referenceframe"
@@ -45,7 +46,7 @@ func (server *serviceServer) Move(ctx context.Context, req *pb.MoveRequest) (*pb
 		req.GetConstraints(),
 		req.Extra.AsMap(),
 	)
-	return &pb.MoveResponse{Success: success}, err
+	return &pb.MoveResponse{Success: success}, toStatusError(err)
 }
 
 func (server *serviceServer) MoveOnMap(ctx context.Context, req *pb.MoveOnMapRequest) (*pb.MoveOnMapResponse, error) {
@@ -60,7 +61,7 @@ func (server *serviceServer) MoveOnMap(ctx context.Context, req *pb.MoveOnMapReq
 		protoutils.ResourceNameFromProto(req.GetSlamServiceName()),
 		req.Extra.AsMap(),
 	)
-	return &pb.MoveOnMapResponse{Success: success}, err
+	return &pb.MoveOnMapResponse{Success: success}, toStatusError(err)
 }
 
 func (server *serviceServer) MoveOnGlobe(ctx context.Context, req *pb.MoveOnGlobeRequest) (*pb.MoveOnGlobeResponse, error) {
@@ -106,7 +107,7 @@ func (server *serviceServer) MoveOnGlobe(ctx context.Context, req *pb.MoveOnGlob
 		angular,
 		req.Extra.AsMap(),
 	)
-	return &pb.MoveOnGlobeResponse{Success: success}, err
+	return &pb.MoveOnGlobeResponse{Success: success}, toStatusError(err)
 }
 
 func (server *serviceServer) MoveSingleComponent(
@@ -128,7 +129,7 @@ func (server *serviceServer) MoveSingleComponent(
 		worldState,
 		req.Extra.AsMap(),
 	)
-	return &pb.MoveSingleComponentResponse{Success: success}, err
+	return &pb.MoveSingleComponentResponse{Success: success}, toStatusError(err)
 }
 
 func (server *serviceServer) GetPose(ctx context.Context, req *pb.GetPoseRequest) (*pb.GetPoseResponse, error) {
@@ -158,5 +159,59 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	cmd := req.GetCommand().AsMap()
+	switch cmd["command"] {
+	case MoveAlongPathCommand:
+		if mover, ok := svc.(PathMover); ok {
+			return server.moveAlongPath(ctx, mover, cmd)
+		}
+	case MoveCoordinatedCommand:
+		if mover, ok := svc.(CoordinatedMover); ok {
+			return server.moveCoordinated(ctx, mover, cmd)
+		}
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
+
+// moveAlongPath serves MoveAlongPathCommand, which is how clients call MoveAlongPath on services that move along paths.
+func (server *serviceServer) moveAlongPath(
+	ctx context.Context,
+	mover PathMover,
+	cmd map[string]interface{},
+) (*commonpb.DoCommandResponse, error) {
+	componentName, path, worldState, extra, err := moveAlongPathFromMap(cmd)
+	if err != nil {
+		return nil, err
+	}
+	success, err := mover.MoveAlongPath(ctx, componentName, path, worldState, extra)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := structpb.NewStruct(map[string]interface{}{"success": success})
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: resp}, nil
+}
+
+// moveCoordinated serves MoveCoordinatedCommand, which is how clients call MoveCoordinated on services that move
+// components together.
+func (server *serviceServer) moveCoordinated(
+	ctx context.Context,
+	mover CoordinatedMover,
+	cmd map[string]interface{},
+) (*commonpb.DoCommandResponse, error) {
+	destinations, worldState, constraints, extra, err := moveCoordinatedFromMap(cmd)
+	if err != nil {
+		return nil, err
+	}
+	success, err := mover.MoveCoordinated(ctx, destinations, worldState, constraints, extra)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := structpb.NewStruct(map[string]interface{}{"success": success})
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: resp}, nil
+}
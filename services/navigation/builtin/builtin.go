@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"reflect"
 	"sync"
 	"time"
 
@@ -25,8 +26,10 @@ import (
 )
 
 const (
-	metersPerSecDefault = 0.5
-	degPerSecDefault    = 45
+	metersPerSecDefault    = 0.5
+	degPerSecDefault       = 45
+	loiterRadiusMDefault   = 2.
+	exploreSpacingMDefault = 1.
 )
 
 func init() {
@@ -66,6 +69,12 @@ type Config struct {
 	DegPerSec    float64                          `json:"degs_per_sec"`
 	MetersPerSec float64                          `json:"meters_per_sec"`
 	Obstacles    []*spatialmath.GeoObstacleConfig `json:"obstacles,omitempty"`
+	// ActionResources are the full names of the resources that waypoint actions may send commands to.
+	ActionResources []string `json:"action_resources,omitempty"`
+	// LoiterRadiusM is how far, in meters, the robot may drift in loiter mode before it drives back.
+	LoiterRadiusM float64 `json:"loiter_radius_m,omitempty"`
+	// ExploreSpacingM is how far apart, in meters, the lines swept in explore mode are, e.g. the width of a mower deck.
+	ExploreSpacingM float64 `json:"explore_spacing_m,omitempty"`
 }
 
 // Validate creates the list of implicit dependencies.
@@ -87,6 +96,14 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	}
 	deps = append(deps, resource.NewName(motion.API, conf.MotionServiceName).String())
 
+	for _, actionResource := range conf.ActionResources {
+		name, err := resource.NewFromString(actionResource)
+		if err != nil {
+			return nil, errors.Wrap(err, "action_resources")
+		}
+		deps = append(deps, name.String())
+	}
+
 	// get default speeds from config if set, else defaults from nav services const
 	if conf.MetersPerSec == 0 {
 		conf.MetersPerSec = metersPerSecDefault
@@ -94,6 +111,18 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	if conf.DegPerSec == 0 {
 		conf.DegPerSec = degPerSecDefault
 	}
+	if conf.LoiterRadiusM < 0 {
+		return nil, errors.Errorf("loiter_radius_m must not be negative, is %v", conf.LoiterRadiusM)
+	}
+	if conf.LoiterRadiusM == 0 {
+		conf.LoiterRadiusM = loiterRadiusMDefault
+	}
+	if conf.ExploreSpacingM < 0 {
+		return nil, errors.Errorf("explore_spacing_m must not be negative, is %v", conf.ExploreSpacingM)
+	}
+	if conf.ExploreSpacingM == 0 {
+		conf.ExploreSpacingM = exploreSpacingMDefault
+	}
 
 	return deps, nil
 }
@@ -106,6 +135,7 @@ func NewBuiltIn(ctx context.Context, deps resource.Dependencies, conf resource.C
 		logger:     logger,
 		cancelCtx:  cancelCtx,
 		cancelFunc: cancelFunc,
+		events:     navigation.NewEventLog(),
 	}
 	if err := navSvc.Reconfigure(ctx, deps, conf); err != nil {
 		return nil, err
@@ -113,29 +143,81 @@ func NewBuiltIn(ctx context.Context, deps resource.Dependencies, conf resource.C
 	return navSvc, nil
 }
 
+// builtIn is the builtin navigation service. mu guards the fields Reconfigure and SetMode replace, which the background
+// workers never read: each is given a navDeps copy of them when it starts instead.
 type builtIn struct {
 	resource.Named
-	mu        sync.RWMutex
-	store     navigation.NavStore
-	storeType string
-	mode      navigation.Mode
+	mu          sync.RWMutex
+	store       navigation.NavStore
+	storeConfig navigation.StoreConfig
+	mode        navigation.Mode
+	// waypoints holds the waypoints the robot is driving through: those of store in waypoint mode, or ones made for the
+	// other modes
+	waypoints navigation.NavStore
 
 	base           base.Base
 	movementSensor movementsensor.MovementSensor
 	motion         motion.Service
 	obstacles      []*spatialmath.GeoObstacle
+	// actionResources holds the resources that waypoint actions may send commands to
+	actionResources resource.Dependencies
 
 	metersPerSec            float64
 	degPerSec               float64
+	loiterRadiusM           float64
+	exploreSpacingM         float64
 	logger                  golog.Logger
 	cancelCtx               context.Context
 	cancelFunc              func()
 	activeBackgroundWorkers sync.WaitGroup
+
+	violationMu sync.Mutex
+	violation   *navigation.ZoneViolation
+
+	events *navigation.EventLog
+	legMu  sync.Mutex
+	leg    *navigation.Leg
+
+	// trail is the way the robot has come since it started navigating, starting at home
+	trailMu sync.Mutex
+	trail   []*geo.Point
+}
+
+// navDeps are the stores, resources and settings the service navigates with.
+type navDeps struct {
+	store           navigation.NavStore
+	waypoints       navigation.NavStore
+	base            base.Base
+	movementSensor  movementsensor.MovementSensor
+	motion          motion.Service
+	obstacles       []*spatialmath.GeoObstacle
+	actionResources resource.Dependencies
+	metersPerSec    float64
+	degPerSec       float64
+	loiterRadiusM   float64
+	exploreSpacingM float64
+}
+
+// deps returns a copy of what the service navigates with. The caller must hold mu.
+func (svc *builtIn) deps() *navDeps {
+	return &navDeps{
+		store:           svc.store,
+		waypoints:       svc.waypoints,
+		base:            svc.base,
+		movementSensor:  svc.movementSensor,
+		motion:          svc.motion,
+		obstacles:       svc.obstacles,
+		actionResources: svc.actionResources,
+		metersPerSec:    svc.metersPerSec,
+		degPerSec:       svc.degPerSec,
+		loiterRadiusM:   svc.loiterRadiusM,
+		exploreSpacingM: svc.exploreSpacingM,
+	}
 }
 
 func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
-	svc.mu.RLock()
-	defer svc.mu.RUnlock()
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
 
 	svcConfig, err := resource.NativeConfig[*Config](conf)
 	if err != nil {
@@ -154,22 +236,15 @@ func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies,
 		return err
 	}
 
-	var newStore navigation.NavStore
-	if svc.storeType != string(svcConfig.Store.Type) {
-		switch svcConfig.Store.Type {
-		case navigation.StoreTypeMemory:
-			newStore = navigation.NewMemoryNavigationStore()
-		case navigation.StoreTypeMongoDB:
-			var err error
-			newStore, err = navigation.NewMongoDBNavigationStore(ctx, svcConfig.Store.Config)
-			if err != nil {
-				return err
-			}
-		default:
-			return errors.Errorf("unknown store type %q", svcConfig.Store.Type)
+	actionResources := resource.Dependencies{}
+	for _, actionResource := range svcConfig.ActionResources {
+		name, err := resource.NewFromString(actionResource)
+		if err != nil {
+			return err
+		}
+		if actionResources[name], err = deps.Lookup(name); err != nil {
+			return err
 		}
-	} else {
-		newStore = svc.store
 	}
 
 	// Parse obstacles from the passed in configuration
@@ -178,39 +253,116 @@ func (svc *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies,
 		return err
 	}
 
-	svc.store = newStore
-	svc.storeType = string(svcConfig.Store.Type)
+	// the store is opened last, so that it is not left open when the rest of the config is invalid
+	if svc.store == nil || !reflect.DeepEqual(svc.storeConfig, svcConfig.Store) {
+		newStore, err := newNavStore(ctx, conf.ResourceName().Name, svcConfig.Store)
+		if err != nil {
+			return err
+		}
+		oldStore := svc.store
+		// navigation stops, as the waypoints and zones it follows are in the old store
+		svc.cancelFunc()
+		svc.activeBackgroundWorkers.Wait()
+		svc.cancelCtx, svc.cancelFunc = context.WithCancel(context.Background())
+		svc.mode = navigation.ModeManual
+		svc.setZoneViolation(nil)
+		svc.setLeg(nil)
+		svc.store = newStore
+		svc.waypoints = newStore
+		svc.storeConfig = svcConfig.Store
+		if oldStore != nil {
+			if err := oldStore.Close(ctx); err != nil {
+				svc.logger.Errorw("failed to close the old navigation store", "error", err)
+			}
+		}
+	}
 	svc.base = base1
 	svc.movementSensor = movementSensor
 	svc.motion = motionSrv
 	svc.obstacles = newObstacles
+	svc.actionResources = actionResources
 	svc.metersPerSec = svcConfig.MetersPerSec
 	svc.degPerSec = svcConfig.DegPerSec
+	svc.loiterRadiusM = svcConfig.LoiterRadiusM
+	svc.exploreSpacingM = svcConfig.ExploreSpacingM
 
 	return nil
 }
 
+// newNavStore opens the navigation store of the config for the named service.
+func newNavStore(ctx context.Context, name string, config navigation.StoreConfig) (navigation.NavStore, error) {
+	switch config.Type {
+	case navigation.StoreTypeMemory:
+		return navigation.NewMemoryNavigationStore(), nil
+	case navigation.StoreTypeMongoDB:
+		return navigation.NewMongoDBNavigationStore(ctx, config.Config)
+	case navigation.StoreTypeBoltDB:
+		return navigation.NewBoltDBNavigationStore(name, config.Config)
+	default:
+		return nil, errors.Errorf("unknown store type %q", config.Type)
+	}
+}
+
 func (svc *builtIn) Mode(ctx context.Context, extra map[string]interface{}) (navigation.Mode, error) {
 	svc.mu.RLock()
 	defer svc.mu.RUnlock()
-	return svc.mode, nil
+	return svc.currentMode(), nil
+}
+
+// currentMode returns the mode the service is operating in. The caller must hold mu.
+func (svc *builtIn) currentMode() navigation.Mode {
+	// a zone violation stops navigation, leaving the robot under manual control
+	if violation, _ := svc.ZoneViolation(context.Background(), nil); violation != nil {
+		return navigation.ModeManual
+	}
+	return svc.mode
 }
 
 func (svc *builtIn) SetMode(ctx context.Context, mode navigation.Mode, extra map[string]interface{}) error {
 	svc.mu.Lock()
 	defer svc.mu.Unlock()
-	if svc.mode == mode {
+	// setting the other modes again starts them over from where the robot is now
+	violation, _ := svc.ZoneViolation(ctx, extra)
+	if violation == nil && svc.mode == mode && (mode == navigation.ModeManual || mode == navigation.ModeWaypoint) {
 		return nil
 	}
 
+	// work out where the new mode drives before stopping the current one, so that a mode that cannot start leaves the
+	// current one running
+	waypoints, err := svc.modeWaypoints(ctx, mode, extra)
+	if err != nil {
+		return err
+	}
+	// home is where the robot starts navigating from, and stays put while it switches between waypoint and explore mode
+	var home *geo.Point
+	startsMission := mode == navigation.ModeWaypoint || mode == navigation.ModeExplore
+	if startsMission && (svc.currentMode() != navigation.ModeWaypoint && svc.currentMode() != navigation.ModeExplore) {
+		if home, err = svc.deps().location(ctx, extra); err != nil {
+			return err
+		}
+	}
+
 	// switch modes
 	svc.cancelFunc()
 	svc.activeBackgroundWorkers.Wait()
+	svc.setZoneViolation(nil)
+	svc.setLeg(nil)
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 	svc.cancelCtx = cancelCtx
 	svc.cancelFunc = cancelFunc
 	svc.mode = navigation.ModeManual
-	if mode == navigation.ModeWaypoint {
+	svc.waypoints = waypoints
+	if home != nil {
+		svc.setHome(home)
+	}
+	if mode != navigation.ModeManual {
+		svc.startZoneMonitor(extra)
+		if startsMission {
+			svc.startBreadcrumbs(extra)
+		}
+		if mode == navigation.ModeLoiter {
+			svc.startLoiter(extra)
+		}
 		if extra != nil && extra["experimental"] == true {
 			if err := svc.startWaypointExperimental(extra); err != nil {
 				return err
@@ -221,32 +373,36 @@ func (svc *builtIn) SetMode(ctx context.Context, mode navigation.Mode, extra map
 		}
 		svc.mode = mode
 	}
+	svc.events.Publish(navigation.EventKindModeChange, primitive.NilObjectID, svc.mode.String())
 	return nil
 }
 
-func (svc *builtIn) computeCurrentBearing(ctx context.Context, path []*geo.Point) (float64, error) {
-	props, err := svc.movementSensor.Properties(ctx, nil)
+func (nav *navDeps) computeCurrentBearing(ctx context.Context, path []*geo.Point) (float64, error) {
+	props, err := nav.movementSensor.Properties(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 	if props.CompassHeadingSupported {
-		return svc.movementSensor.CompassHeading(ctx, nil)
+		return nav.movementSensor.CompassHeading(ctx, nil)
 	}
 	pathLen := len(path)
 	return fixAngle(path[pathLen-2].BearingTo(path[pathLen-1])), nil
 }
 
 func (svc *builtIn) startWaypoint(extra map[string]interface{}) error {
+	cancelCtx, nav := svc.cancelCtx, svc.deps()
 	svc.activeBackgroundWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
 		defer svc.activeBackgroundWorkers.Done()
 
 		path := []*geo.Point{}
+		// a failing leg is retried on every loop, but only reported when it fails in a new way
+		var lastLegFailure string
 		for {
-			if !utils.SelectContextOrWait(svc.cancelCtx, 500*time.Millisecond) {
+			if !utils.SelectContextOrWait(cancelCtx, 500*time.Millisecond) {
 				return
 			}
-			currentLoc, _, err := svc.movementSensor.Position(svc.cancelCtx, extra)
+			currentLoc, _, err := nav.movementSensor.Position(cancelCtx, extra)
 			if err != nil {
 				svc.logger.Errorw("failed to get gps location", "error", err)
 				continue
@@ -265,21 +421,39 @@ func (svc *builtIn) startWaypoint(extra map[string]interface{}) error {
 					return errors.New("not enough gps data")
 				}
 
-				currentBearing, err := svc.computeCurrentBearing(ctx, path)
+				currentBearing, err := nav.computeCurrentBearing(ctx, path)
 				if err != nil {
 					return err
 				}
 
-				bearingToGoal, distanceToGoal, err := svc.waypointDirectionAndDistanceToGo(ctx, currentLoc)
+				wp, bearingToGoal, distanceToGoal, err := nav.waypointDirectionAndDistanceToGo(ctx, currentLoc)
 				if err != nil {
 					return err
 				}
 
-				if distanceToGoal < .005 {
+				// distances are in km
+				if distanceToGoal < wp.ArrivalRadius()/1000 {
 					svc.logger.Debug("i made it")
-					return svc.waypointReached(ctx)
+					return svc.arrive(ctx, nav, wp, currentBearing)
 				}
 
+				failLeg := func(err error) error {
+					if err.Error() != lastLegFailure && ctx.Err() == nil {
+						svc.legFailed(wp, err)
+					}
+					lastLegFailure = err.Error()
+					return err
+				}
+
+				zones, err := nav.store.Zones(ctx)
+				if err != nil {
+					return err
+				}
+				if err := navigation.CheckLeg(zones, currentLoc, wp.ToPoint()); err != nil {
+					return failLeg(err)
+				}
+				svc.startLeg(currentLoc, wp, nav.waypointMetersPerSec(wp))
+
 				bearingDelta := computeBearing(bearingToGoal, currentBearing)
 				steeringDir := -bearingDelta / 180.0
 
@@ -289,22 +463,23 @@ func (svc *builtIn) startWaypoint(extra map[string]interface{}) error {
 				// TODO(erh->erd): maybe need an arc/stroke abstraction?
 				// - Remember that we added -1*bearingDelta instead of steeringDir
 				// - Test both naval/land to prove it works
-				if err := svc.base.Spin(ctx, -1*bearingDelta, svc.degPerSec, nil); err != nil {
-					return fmt.Errorf("error turning: %w", err)
+				if err := nav.base.Spin(ctx, -1*bearingDelta, nav.degPerSec, nil); err != nil {
+					return failLeg(fmt.Errorf("error turning: %w", err))
 				}
 
 				distanceMm := distanceToGoal * 1000 * 1000
 				distanceMm = math.Min(distanceMm, 10*1000)
 
 				// TODO: handle swap from mm to meters
-				if err := svc.base.MoveStraight(ctx, int(distanceMm), (svc.metersPerSec * 1000), nil); err != nil {
-					return fmt.Errorf("error moving %w", err)
+				if err := nav.base.MoveStraight(ctx, int(distanceMm), (nav.waypointMetersPerSec(wp) * 1000), nil); err != nil {
+					return failLeg(fmt.Errorf("error moving %w", err))
 				}
 
+				lastLegFailure = ""
 				return nil
 			}
 
-			if err := navOnce(svc.cancelCtx); err != nil {
+			if err := navOnce(cancelCtx); err != nil {
 				svc.logger.Infof("error navigating: %s", err)
 			}
 		}
@@ -312,26 +487,166 @@ func (svc *builtIn) startWaypoint(extra map[string]interface{}) error {
 	return nil
 }
 
-func (svc *builtIn) waypointDirectionAndDistanceToGo(ctx context.Context, currentLoc *geo.Point) (float64, float64, error) {
-	wp, err := svc.nextWaypoint(ctx)
+func (nav *navDeps) waypointDirectionAndDistanceToGo(
+	ctx context.Context,
+	currentLoc *geo.Point,
+) (navigation.Waypoint, float64, float64, error) {
+	wp, err := nav.waypoints.NextWaypoint(ctx)
 	if err != nil {
-		return 0, 0, err
+		return navigation.Waypoint{}, 0, 0, err
 	}
 
 	goal := wp.ToPoint()
 
-	return fixAngle(currentLoc.BearingTo(goal)), currentLoc.GreatCircleDistance(goal), nil
+	return wp, fixAngle(currentLoc.BearingTo(goal)), currentLoc.GreatCircleDistance(goal), nil
+}
+
+// waypointMetersPerSec returns the speed to drive to the waypoint at.
+func (nav *navDeps) waypointMetersPerSec(wp navigation.Waypoint) float64 {
+	if wp.MetersPerSec > 0 {
+		return wp.MetersPerSec
+	}
+	return nav.metersPerSec
+}
+
+// arrive carries out the behavior of a waypoint the robot has just reached, turning to its heading, dwelling and running
+// its action, before marking it visited. An action that still fails after a few attempts is reported and skipped.
+// currentBearing is the compass heading of the robot, or NaN if it is unknown.
+func (svc *builtIn) arrive(ctx context.Context, nav *navDeps, wp navigation.Waypoint, currentBearing float64) error {
+	if wp.Heading != nil && !math.IsNaN(currentBearing) {
+		if err := nav.base.Spin(ctx, -1*computeBearing(*wp.Heading, currentBearing), nav.degPerSec, nil); err != nil {
+			return fmt.Errorf("error turning to heading: %w", err)
+		}
+	}
+	if !utils.SelectContextOrWait(ctx, wp.Dwell) {
+		return ctx.Err()
+	}
+	if wp.Action != nil {
+		if err := nav.runAction(ctx, wp.Action); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the robot moves on rather than waiting at the waypoint for an action that keeps failing
+			svc.logger.Errorw("skipping waypoint action", "error", err)
+			svc.events.Publish(navigation.EventKindActionFailed, wp.ID, err.Error())
+		}
+	}
+	if err := nav.waypoints.WaypointVisited(ctx, wp.ID); err != nil {
+		return err
+	}
+	svc.setLeg(nil)
+	svc.events.Publish(navigation.EventKindWaypointReached, wp.ID, "")
+	return nil
+}
+
+// maxWaypointActionAttempts is how many times the action of a waypoint is run before it is given up on.
+const maxWaypointActionAttempts = 3
+
+// waypointActionRetryInterval is how long to wait before running a failed waypoint action again.
+const waypointActionRetryInterval = 500 * time.Millisecond
+
+// runAction sends the command of a waypoint action to its resource, trying again a few times if it fails.
+func (nav *navDeps) runAction(ctx context.Context, action *navigation.WaypointAction) error {
+	name, err := resource.NewFromString(action.Resource)
+	if err != nil {
+		return err
+	}
+	res, err := nav.actionResources.Lookup(name)
+	if err != nil {
+		return fmt.Errorf("waypoint action resource %q must be listed in action_resources: %w", action.Resource, err)
+	}
+	for attempt := 1; ; attempt++ {
+		if _, err = res.DoCommand(ctx, action.Command); err == nil {
+			return nil
+		}
+		if attempt == maxWaypointActionAttempts || !utils.SelectContextOrWait(ctx, waypointActionRetryInterval) {
+			return fmt.Errorf("error running waypoint action: %w", err)
+		}
+	}
+}
+
+// legFailed reports that driving to the waypoint failed.
+func (svc *builtIn) legFailed(wp navigation.Waypoint, err error) {
+	kind := navigation.EventKindLegFailed
+	if errors.Is(err, motion.ErrStoppedForObstacle) {
+		kind = navigation.EventKindObstacleStop
+	}
+	svc.events.Publish(kind, wp.ID, err.Error())
+}
+
+// startLeg records that the robot is driving from its location to the waypoint at the given speed, unless it already is.
+func (svc *builtIn) startLeg(from *geo.Point, wp navigation.Waypoint, metersPerSec float64) {
+	svc.legMu.Lock()
+	defer svc.legMu.Unlock()
+	to := wp.ToPoint()
+	if svc.leg != nil && *svc.leg.To == *to {
+		return
+	}
+	svc.leg = &navigation.Leg{From: from, To: to, MetersPerSec: metersPerSec, Started: time.Now()}
+}
+
+func (svc *builtIn) setLeg(leg *navigation.Leg) {
+	svc.legMu.Lock()
+	defer svc.legMu.Unlock()
+	svc.leg = leg
+}
+
+func (svc *builtIn) Progress(ctx context.Context, extra map[string]interface{}) (navigation.Progress, error) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	progress := navigation.Progress{Mode: svc.currentMode(), Home: svc.home()}
+	if progress.Mode == navigation.ModeManual {
+		return progress, nil
+	}
+	nav := svc.deps()
+	if wps, err := nav.waypoints.Waypoints(ctx); err != nil || len(wps) == 0 {
+		return progress, err
+	}
+	currentLoc, err := nav.location(ctx, extra)
+	if err != nil {
+		return navigation.Progress{}, err
+	}
+	wp, bearingToGoal, distanceToGoal, err := nav.waypointDirectionAndDistanceToGo(ctx, currentLoc)
+	if err != nil {
+		return navigation.Progress{}, err
+	}
+	progress.Waypoint = &wp
+	// distances are in km
+	progress.DistanceToGoM = distanceToGoal * 1000
+	progress.BearingToGo = bearingToGoal
+	progress.ETA = time.Now().Add(time.Duration(progress.DistanceToGoM / nav.waypointMetersPerSec(wp) * float64(time.Second)))
+
+	svc.legMu.Lock()
+	defer svc.legMu.Unlock()
+	if svc.leg != nil {
+		leg := *svc.leg
+		progress.Leg = &leg
+	}
+	return progress, nil
+}
+
+func (svc *builtIn) Events(ctx context.Context, after uint64, extra map[string]interface{}) ([]navigation.Event, error) {
+	return svc.events.Events(ctx, after)
 }
 
 func (svc *builtIn) Location(ctx context.Context, extra map[string]interface{}) (*geo.Point, error) {
-	if svc.movementSensor == nil {
+	svc.mu.RLock()
+	nav := svc.deps()
+	svc.mu.RUnlock()
+	return nav.location(ctx, extra)
+}
+
+func (nav *navDeps) location(ctx context.Context, extra map[string]interface{}) (*geo.Point, error) {
+	if nav.movementSensor == nil {
 		return nil, errors.New("no way to get location")
 	}
-	loc, _, err := svc.movementSensor.Position(ctx, extra)
+	loc, _, err := nav.movementSensor.Position(ctx, extra)
 	return loc, err
 }
 
 func (svc *builtIn) Waypoints(ctx context.Context, extra map[string]interface{}) ([]navigation.Waypoint, error) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
 	wps, err := svc.store.Waypoints(ctx)
 	if err != nil {
 		return nil, err
@@ -342,27 +657,151 @@ func (svc *builtIn) Waypoints(ctx context.Context, extra map[string]interface{})
 }
 
 func (svc *builtIn) AddWaypoint(ctx context.Context, point *geo.Point, extra map[string]interface{}) error {
-	_, err := svc.store.AddWaypoint(ctx, point)
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	zones, err := svc.store.Zones(ctx)
+	if err != nil {
+		return err
+	}
+	if violation := navigation.CheckZones(zones, point); violation != nil {
+		return errors.Wrap(violation, "cannot add waypoint")
+	}
+	_, err = svc.store.AddWaypoint(ctx, point)
 	return err
 }
 
 func (svc *builtIn) RemoveWaypoint(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
 	return svc.store.RemoveWaypoint(ctx, id)
 }
 
-func (svc *builtIn) nextWaypoint(ctx context.Context) (navigation.Waypoint, error) {
-	return svc.store.NextWaypoint(ctx)
+func (svc *builtIn) Zones(ctx context.Context, extra map[string]interface{}) ([]navigation.Zone, error) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.store.Zones(ctx)
+}
+
+func (svc *builtIn) AddZone(ctx context.Context, zone navigation.Zone, extra map[string]interface{}) error {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	_, err := svc.store.AddZone(ctx, zone)
+	return err
+}
+
+func (svc *builtIn) RemoveZone(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.store.RemoveZone(ctx, id)
+}
+
+func (svc *builtIn) ZoneViolation(ctx context.Context, extra map[string]interface{}) (*navigation.ZoneViolation, error) {
+	svc.violationMu.Lock()
+	defer svc.violationMu.Unlock()
+	return svc.violation, nil
+}
+
+func (svc *builtIn) setZoneViolation(violation *navigation.ZoneViolation) {
+	svc.violationMu.Lock()
+	defer svc.violationMu.Unlock()
+	svc.violation = violation
+}
+
+// startZoneMonitor watches the location of the robot while it navigates. If the robot leaves the geofences or enters a
+// keep-out zone, navigation is cancelled, the base is stopped, and the violation is kept until the mode is next set. A
+// robot that starts outside every geofence is left to drive back into one.
+func (svc *builtIn) startZoneMonitor(extra map[string]interface{}) {
+	ctx, cancel, nav := svc.cancelCtx, svc.cancelFunc, svc.deps()
+	svc.activeBackgroundWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer svc.activeBackgroundWorkers.Done()
+		withinZones := false
+		for utils.SelectContextOrWait(ctx, 500*time.Millisecond) {
+			zones, err := nav.store.Zones(ctx)
+			if err != nil {
+				svc.logger.Errorw("failed to get zones", "error", err)
+				continue
+			}
+			if len(zones) == 0 {
+				continue
+			}
+			loc, err := nav.location(ctx, extra)
+			if err != nil {
+				svc.logger.Errorw("failed to get location", "error", err)
+				continue
+			}
+			violation := navigation.CheckZones(zones, loc)
+			if violation == nil {
+				withinZones = true
+				continue
+			}
+			if violation.Zone == nil && !withinZones {
+				continue
+			}
+			svc.logger.Errorw("stopping navigation", "error", violation)
+			svc.setZoneViolation(violation)
+			svc.events.Publish(navigation.EventKindZoneViolation, primitive.NilObjectID, violation.Error())
+			cancel()
+			if err := nav.base.Stop(context.Background(), nil); err != nil {
+				svc.logger.Errorw("failed to stop base", "error", err)
+			}
+			return
+		}
+	})
+}
+
+// planningObstacles returns the configured obstacles together with the walls of every zone, so that plans stay within the
+// geofences and out of the keep-out zones.
+func (nav *navDeps) planningObstacles(zones []navigation.Zone) ([]*spatialmath.GeoObstacle, error) {
+	obstacles := make([]*spatialmath.GeoObstacle, 0, len(nav.obstacles)+len(zones))
+	obstacles = append(obstacles, nav.obstacles...)
+	for i := range zones {
+		walls, err := zones[i].GeoObstacle()
+		if err != nil {
+			return nil, err
+		}
+		obstacles = append(obstacles, walls)
+	}
+	return obstacles, nil
 }
 
-func (svc *builtIn) waypointReached(ctx context.Context) error {
-	wp, err := svc.nextWaypoint(ctx)
+func (svc *builtIn) Routes(ctx context.Context, extra map[string]interface{}) ([]navigation.Route, error) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.store.Routes(ctx)
+}
+
+func (svc *builtIn) AddRoute(ctx context.Context, route navigation.Route, extra map[string]interface{}) error {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	zones, err := svc.store.Zones(ctx)
 	if err != nil {
-		return fmt.Errorf("can't mark waypoint reached: %w", err)
+		return err
+	}
+	for i, wp := range route.Waypoints {
+		if violation := navigation.CheckZones(zones, geo.NewPoint(wp.Lat, wp.Long)); violation != nil {
+			return errors.Wrapf(violation, "cannot add route waypoint %d", i)
+		}
 	}
-	return svc.store.WaypointVisited(ctx, wp.ID)
+	_, err = svc.store.AddRoute(ctx, route)
+	return err
+}
+
+func (svc *builtIn) RemoveRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.store.RemoveRoute(ctx, id)
+}
+
+func (svc *builtIn) LoadRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.store.LoadRoute(ctx, id)
 }
 
 func (svc *builtIn) Close(ctx context.Context) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
 	svc.cancelFunc()
 	svc.activeBackgroundWorkers.Wait()
 	return svc.store.Close(ctx)
@@ -395,43 +834,71 @@ func computeBearing(a, b float64) float64 {
 }
 
 func (svc *builtIn) startWaypointExperimental(extra map[string]interface{}) error {
+	cancelCtx, nav := svc.cancelCtx, svc.deps()
 	svc.activeBackgroundWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
 		defer svc.activeBackgroundWorkers.Done()
 
 		navOnce := func(ctx context.Context, wp navigation.Waypoint) error {
-			currentLoc, err := svc.Location(svc.cancelCtx, extra)
+			currentLoc, err := nav.location(ctx, extra)
 			if err != nil {
 				return err
 			}
 
-			// have ability to define destination heading here, but waypoint structure
-			// doesn't allow for that so using bearingToGoal as heading
+			// the motion service decides when the base has arrived, so the arrival radius only lets a leg that starts
+			// close enough to the waypoint be skipped
 			goal := wp.ToPoint()
-			_, err = svc.motion.MoveOnGlobe(
+			if currentLoc.GreatCircleDistance(goal) < wp.ArrivalRadius()/1000 {
+				return svc.arrive(ctx, nav, wp, math.NaN())
+			}
+			heading := currentLoc.BearingTo(goal)
+			if wp.Heading != nil {
+				heading = *wp.Heading
+			}
+			svc.startLeg(currentLoc, wp, nav.waypointMetersPerSec(wp))
+			zones, err := nav.store.Zones(ctx)
+			if err != nil {
+				return err
+			}
+			if violation := navigation.CheckZones(zones, goal); violation != nil {
+				return violation
+			}
+			obstacles, err := nav.planningObstacles(zones)
+			if err != nil {
+				return err
+			}
+			_, err = nav.motion.MoveOnGlobe(
 				ctx,
-				svc.base.Name(),
+				nav.base.Name(),
 				goal,
-				currentLoc.BearingTo(goal),
-				svc.movementSensor.Name(),
-				svc.obstacles,
-				svc.metersPerSec*1000,
-				svc.degPerSec,
+				heading,
+				nav.movementSensor.Name(),
+				obstacles,
+				nav.waypointMetersPerSec(wp)*1000,
+				nav.degPerSec,
 				extra,
 			)
 			if err != nil {
 				return err
 			}
 
-			return svc.waypointReached(ctx)
+			// the heading was part of the goal, so there is no need to turn again
+			return svc.arrive(ctx, nav, wp, math.NaN())
 		}
 
-		// loop until no waypoints remaining
-		for wp, err := svc.nextWaypoint(svc.cancelCtx); err == nil; wp, err = svc.nextWaypoint(svc.cancelCtx) {
+		// loop until no waypoints remaining, reporting a failing leg only when it fails in a new way
+		var lastLegFailure string
+		for wp, err := nav.waypoints.NextWaypoint(cancelCtx); err == nil; wp, err = nav.waypoints.NextWaypoint(cancelCtx) {
 			svc.logger.Infof("navigating to waypoint: %+v", wp)
-			if err := navOnce(svc.cancelCtx, wp); err != nil {
+			if err := navOnce(cancelCtx, wp); err != nil {
 				svc.logger.Infof("error navigating: %s", err)
+				if err.Error() != lastLegFailure && cancelCtx.Err() == nil {
+					svc.legFailed(wp, err)
+				}
+				lastLegFailure = err.Error()
+				continue
 			}
+			lastLegFailure = ""
 		}
 
 	})
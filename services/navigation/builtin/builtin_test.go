@@ -4,10 +4,15 @@ package builtin
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.viam.com/rdk/components/base"
 	fakebase "go.viam.com/rdk/components/base/fake"
 	"go.viam.com/rdk/components/base/kinematicbase"
@@ -24,6 +29,7 @@ import (
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 )
 
 func setupNavigationServiceFromConfig(t *testing.T, configFilename string) (navigation.Service, func()) {
@@ -167,3 +173,406 @@ func TestStartWaypoint(t *testing.T) {
 	test.That(t, actualPt.Lat(), test.ShouldEqual, pt.Lat())
 	test.That(t, actualPt.Lng(), test.ShouldEqual, pt.Lng())
 }
+
+func TestReconfigureStore(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	injectBase := inject.NewBase("test_base")
+	injectMovementSensor := inject.NewMovementSensor("test_movement")
+	injectMS := inject.NewMotionService("test_motion")
+	deps := resource.Dependencies{
+		injectBase.Name():           injectBase,
+		injectMovementSensor.Name(): injectMovementSensor,
+		injectMS.Name():             injectMS,
+	}
+	storeConf := func(path string) resource.Config {
+		return resource.Config{
+			ConvertedAttributes: &Config{
+				Store: navigation.StoreConfig{
+					Type:   navigation.StoreTypeBoltDB,
+					Config: map[string]interface{}{"path": path},
+				},
+				BaseName:           "test_base",
+				MovementSensorName: "test_movement",
+				MotionServiceName:  "test_motion",
+			},
+		}
+	}
+	dir := t.TempDir()
+	firstPath := filepath.Join(dir, "first.db")
+	ns, err := NewBuiltIn(ctx, deps, storeConf(firstPath), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, ns.Close(ctx), test.ShouldBeNil)
+	}()
+	test.That(t, ns.AddWaypoint(ctx, geo.NewPoint(1, 2), nil), test.ShouldBeNil)
+
+	// the same store config keeps the store open
+	store := ns.(*builtIn).store
+	test.That(t, ns.Reconfigure(ctx, deps, storeConf(firstPath)), test.ShouldBeNil)
+	test.That(t, ns.(*builtIn).store, test.ShouldEqual, store)
+
+	// a store at another path replaces the old one, which is closed
+	test.That(t, ns.Reconfigure(ctx, deps, storeConf(filepath.Join(dir, "second.db"))), test.ShouldBeNil)
+	waypoints, err := ns.Waypoints(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, waypoints, test.ShouldBeEmpty)
+	firstStore, err := navigation.NewBoltDBNavigationStore("test", map[string]interface{}{"path": firstPath})
+	test.That(t, err, test.ShouldBeNil)
+	waypoints, err = firstStore.Waypoints(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(waypoints), test.ShouldEqual, 1)
+	test.That(t, firstStore.Close(ctx), test.ShouldBeNil)
+
+	// a config that fails leaves the store as it was
+	badConf := storeConf(filepath.Join(dir, "third.db"))
+	badConf.ConvertedAttributes.(*Config).ActionResources = []string{"rdk:component:camera/missing"}
+	store = ns.(*builtIn).store
+	test.That(t, ns.Reconfigure(ctx, deps, badConf), test.ShouldNotBeNil)
+	test.That(t, ns.(*builtIn).store, test.ShouldEqual, store)
+}
+
+func TestZones(t *testing.T) {
+	ns, teardown := setupNavigationServiceFromConfig(t, "../data/nav_cfg.json")
+	defer teardown()
+	ctx := context.Background()
+	zoner, ok := ns.(navigation.ZoneNavigator)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// the fake movement sensor is at 40.7, -73.98, outside of this geofence
+	geofence := navigation.Zone{
+		Name:     "yard",
+		Kind:     navigation.ZoneKindGeofence,
+		Vertices: []navigation.ZoneVertex{{Lat: 0, Long: 0}, {Lat: 0, Long: 0.01}, {Lat: 0.01, Long: 0.01}, {Lat: 0.01, Long: 0}},
+	}
+	test.That(t, zoner.AddZone(ctx, geofence, nil), test.ShouldBeNil)
+	zones, err := zoner.Zones(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(zones), test.ShouldEqual, 1)
+	test.That(t, zones[0].Name, test.ShouldEqual, "yard")
+
+	err = ns.AddWaypoint(ctx, geo.NewPoint(1, 1), nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "outside every geofence")
+	test.That(t, ns.AddWaypoint(ctx, geo.NewPoint(0.005, 0.005), nil), test.ShouldBeNil)
+
+	t.Run("recover from outside the geofence", func(t *testing.T) {
+		test.That(t, ns.SetMode(ctx, navigation.ModeWaypoint, nil), test.ShouldBeNil)
+		// give the zone monitor time to check the location a few times
+		time.Sleep(1500 * time.Millisecond)
+		violation, err := zoner.ZoneViolation(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, violation, test.ShouldBeNil)
+		mode, err := ns.Mode(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mode, test.ShouldEqual, navigation.ModeWaypoint)
+	})
+
+	t.Run("stop inside a keep-out zone", func(t *testing.T) {
+		pond := navigation.Zone{
+			Name:     "pond",
+			Kind:     navigation.ZoneKindKeepOut,
+			Vertices: []navigation.ZoneVertex{{Lat: 40.69, Long: -73.99}, {Lat: 40.69, Long: -73.97}, {Lat: 40.71, Long: -73.98}},
+		}
+		test.That(t, zoner.AddZone(ctx, pond, nil), test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			violation, err := zoner.ZoneViolation(ctx, nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, violation, test.ShouldNotBeNil)
+		})
+		violation, err := zoner.ZoneViolation(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, violation.Location, test.ShouldResemble, geo.NewPoint(40.7, -73.98))
+		test.That(t, violation.Zone.Name, test.ShouldEqual, "pond")
+		mode, err := ns.Mode(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mode, test.ShouldEqual, navigation.ModeManual)
+	})
+
+	zones, err = zoner.Zones(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	for _, zone := range zones {
+		test.That(t, zoner.RemoveZone(ctx, zone.ID, nil), test.ShouldBeNil)
+	}
+	test.That(t, ns.SetMode(ctx, navigation.ModeWaypoint, nil), test.ShouldBeNil)
+	violation, err := zoner.ZoneViolation(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, violation, test.ShouldBeNil)
+	mode, err := ns.Mode(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mode, test.ShouldEqual, navigation.ModeWaypoint)
+}
+
+func TestArriveAtRouteWaypoint(t *testing.T) {
+	ctx := context.Background()
+	injectBase := inject.NewBase("test_base")
+	var spunDeg float64
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		spunDeg = angleDeg
+		return nil
+	}
+	injectCamera := inject.NewCamera("cam1")
+	var receivedCmd map[string]interface{}
+	injectCamera.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		receivedCmd = cmd
+		return nil, nil
+	}
+	svc := &builtIn{
+		store:           navigation.NewMemoryNavigationStore(),
+		base:            injectBase,
+		actionResources: resource.Dependencies{injectCamera.Name(): injectCamera},
+		degPerSec:       45,
+		metersPerSec:    0.5,
+		logger:          golog.NewTestLogger(t),
+		events:          navigation.NewEventLog(),
+	}
+	svc.waypoints = svc.store
+
+	heading := 90.
+	cmd := map[string]interface{}{"capture": true}
+	err := svc.AddRoute(ctx, navigation.Route{
+		Name: "survey",
+		Waypoints: []navigation.RouteWaypoint{
+			{
+				Lat:  1,
+				Long: 1,
+				WaypointBehavior: navigation.WaypointBehavior{
+					Heading:      &heading,
+					Dwell:        10 * time.Millisecond,
+					MetersPerSec: 0.2,
+					Action:       &navigation.WaypointAction{Resource: injectCamera.Name().String(), Command: cmd},
+				},
+			},
+		},
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	routes, err := svc.Routes(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.LoadRoute(ctx, routes[0].ID, nil), test.ShouldBeNil)
+
+	wp, err := svc.waypoints.NextWaypoint(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.deps().waypointMetersPerSec(wp), test.ShouldEqual, 0.2)
+	start := time.Now()
+	test.That(t, svc.arrive(ctx, svc.deps(), wp, 60), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 10*time.Millisecond)
+	test.That(t, spunDeg, test.ShouldEqual, 30)
+	test.That(t, receivedCmd, test.ShouldResemble, cmd)
+	_, err = svc.waypoints.NextWaypoint(ctx)
+	test.That(t, err, test.ShouldNotBeNil)
+	events, err := svc.Events(ctx, 0, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(events), test.ShouldEqual, 1)
+	test.That(t, events[0].Kind, test.ShouldEqual, navigation.EventKindWaypointReached)
+	test.That(t, events[0].WaypointID, test.ShouldEqual, wp.ID)
+
+	t.Run("failing action", func(t *testing.T) {
+		attempts := 0
+		injectCamera.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			attempts++
+			return nil, errors.New("lens cap on")
+		}
+		test.That(t, svc.LoadRoute(ctx, routes[0].ID, nil), test.ShouldBeNil)
+		wp, err := svc.waypoints.NextWaypoint(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		// the action is given up on, and the robot moves on
+		test.That(t, svc.arrive(ctx, svc.deps(), wp, 60), test.ShouldBeNil)
+		test.That(t, attempts, test.ShouldEqual, maxWaypointActionAttempts)
+		_, err = svc.waypoints.NextWaypoint(ctx)
+		test.That(t, err, test.ShouldNotBeNil)
+		failureEvents, err := svc.Events(ctx, events[0].Sequence, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(failureEvents), test.ShouldEqual, 2)
+		test.That(t, failureEvents[0].Kind, test.ShouldEqual, navigation.EventKindActionFailed)
+		test.That(t, failureEvents[0].Message, test.ShouldContainSubstring, "lens cap on")
+		test.That(t, failureEvents[1].Kind, test.ShouldEqual, navigation.EventKindWaypointReached)
+	})
+}
+
+func TestProgress(t *testing.T) {
+	ns, teardown := setupNavigationServiceFromConfig(t, "../data/nav_cfg.json")
+	defer teardown()
+	ctx := context.Background()
+	reporter, ok := ns.(navigation.ProgressReporter)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	progress, err := reporter.Progress(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, progress, test.ShouldResemble, navigation.Progress{Mode: navigation.ModeManual})
+
+	// the fake movement sensor is at 40.7, -73.98, so this waypoint is about 1112m due north
+	test.That(t, ns.AddWaypoint(ctx, geo.NewPoint(40.71, -73.98), nil), test.ShouldBeNil)
+	test.That(t, ns.SetMode(ctx, navigation.ModeWaypoint, nil), test.ShouldBeNil)
+	progress, err = reporter.Progress(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, progress.Mode, test.ShouldEqual, navigation.ModeWaypoint)
+	test.That(t, progress.Waypoint.Lat, test.ShouldEqual, 40.71)
+	test.That(t, progress.DistanceToGoM, test.ShouldAlmostEqual, 1112, 1)
+	test.That(t, progress.BearingToGo, test.ShouldAlmostEqual, 0, 0.01)
+	// at the default 0.5m/s
+	test.That(t, time.Until(progress.ETA), test.ShouldAlmostEqual, 2224*time.Second, 5*time.Second)
+
+	events, err := reporter.Events(ctx, 0, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events[len(events)-1].Kind, test.ShouldEqual, navigation.EventKindModeChange)
+	test.That(t, events[len(events)-1].Message, test.ShouldEqual, "waypoint")
+
+	t.Run("failed legs", func(t *testing.T) {
+		svc := ns.(*builtIn)
+		wp := navigation.Waypoint{ID: primitive.NewObjectID()}
+		svc.legFailed(wp, errors.New("no path"))
+		svc.legFailed(wp, errors.Wrap(motion.ErrStoppedForObstacle, "box in the way"))
+		failures, err := reporter.Events(ctx, events[len(events)-1].Sequence, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(failures), test.ShouldEqual, 2)
+		test.That(t, failures[0].Kind, test.ShouldEqual, navigation.EventKindLegFailed)
+		test.That(t, failures[1].Kind, test.ShouldEqual, navigation.EventKindObstacleStop)
+		test.That(t, failures[1].WaypointID, test.ShouldEqual, wp.ID)
+	})
+}
+
+func TestModes(t *testing.T) {
+	ns, teardown := setupNavigationServiceFromConfig(t, "../data/nav_cfg.json")
+	defer teardown()
+	ctx := context.Background()
+	reporter := ns.(navigation.ProgressReporter)
+	start := geo.NewPoint(40.7, -73.98)
+
+	// there is no home, or zone to explore, before navigating
+	test.That(t, ns.SetMode(ctx, navigation.ModeReturnHome, nil), test.ShouldNotBeNil)
+	test.That(t, ns.SetMode(ctx, navigation.ModeExplore, nil), test.ShouldNotBeNil)
+	mode, err := ns.Mode(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mode, test.ShouldEqual, navigation.ModeManual)
+
+	// the fake movement sensor is at 40.7, -73.98, inside this geofence
+	geofence := navigation.Zone{
+		Name: "lawn",
+		Kind: navigation.ZoneKindGeofence,
+		Vertices: []navigation.ZoneVertex{
+			{Lat: 40.6995, Long: -73.9805},
+			{Lat: 40.6995, Long: -73.9795},
+			{Lat: 40.7005, Long: -73.9795},
+			{Lat: 40.7005, Long: -73.9805},
+		},
+	}
+	// and a keep-out zone in its corner, away from the robot, which cannot be explored
+	flowerbed := navigation.Zone{
+		Name: "flowerbed",
+		Kind: navigation.ZoneKindKeepOut,
+		Vertices: []navigation.ZoneVertex{
+			{Lat: 40.6996, Long: -73.9804},
+			{Lat: 40.6996, Long: -73.9801},
+			{Lat: 40.6999, Long: -73.9801},
+			{Lat: 40.6999, Long: -73.9804},
+		},
+	}
+	zoner := ns.(navigation.ZoneNavigator)
+	test.That(t, zoner.AddZone(ctx, geofence, nil), test.ShouldBeNil)
+	test.That(t, zoner.AddZone(ctx, flowerbed, nil), test.ShouldBeNil)
+	zones, err := zoner.Zones(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	for _, zone := range zones {
+		if zone.Kind == navigation.ZoneKindKeepOut {
+			test.That(t, ns.SetMode(ctx, navigation.ModeExplore, map[string]interface{}{"zone": zone.ID.Hex()}), test.ShouldNotBeNil)
+		}
+	}
+	mode, err = ns.Mode(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mode, test.ShouldEqual, navigation.ModeManual)
+
+	test.That(t, ns.SetMode(ctx, navigation.ModeExplore, nil), test.ShouldBeNil)
+	progress, err := reporter.Progress(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, progress.Mode, test.ShouldEqual, navigation.ModeExplore)
+	test.That(t, progress.Home, test.ShouldResemble, start)
+	test.That(t, geofence.Contains(progress.Waypoint.ToPoint()), test.ShouldBeTrue)
+	test.That(t, flowerbed.Contains(progress.Waypoint.ToPoint()), test.ShouldBeFalse)
+	// the robot navigated through its own waypoints, leaving those of the store alone
+	wps, err := ns.Waypoints(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, wps, test.ShouldBeEmpty)
+
+	test.That(t, ns.SetMode(ctx, navigation.ModeReturnHome, nil), test.ShouldBeNil)
+	progress, err = reporter.Progress(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, progress.Mode, test.ShouldEqual, navigation.ModeReturnHome)
+	test.That(t, progress.Waypoint.ToPoint(), test.ShouldResemble, start)
+
+	test.That(t, ns.SetMode(ctx, navigation.ModeLoiter, nil), test.ShouldBeNil)
+	progress, err = reporter.Progress(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, progress.Mode, test.ShouldEqual, navigation.ModeLoiter)
+	test.That(t, progress.Waypoint, test.ShouldBeNil)
+	events, err := reporter.Events(ctx, 0, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events[len(events)-1].Message, test.ShouldEqual, "loiter")
+}
+
+func TestTrail(t *testing.T) {
+	svc := &builtIn{}
+	north := func(m float64) *geo.Point {
+		return geo.NewPoint(40.7+m/111195, -73.98)
+	}
+	test.That(t, svc.wayHome(north(0)), test.ShouldBeNil)
+
+	svc.setHome(north(0))
+	for _, m := range []float64{4, 12, 15, 24, 36, 48} {
+		svc.addBreadcrumb(north(m))
+	}
+	// breadcrumbs are left at least 10m apart
+	test.That(t, svc.home(), test.ShouldResemble, north(0))
+	test.That(t, svc.trail, test.ShouldResemble, []*geo.Point{north(0), north(12), north(24), north(36), north(48)})
+	test.That(t, svc.wayHome(north(38)), test.ShouldResemble, []*geo.Point{north(36), north(24), north(12), north(0)})
+
+	// coming back near the trail cuts out the loop
+	svc.addBreadcrumb(north(25))
+	test.That(t, svc.trail, test.ShouldResemble, []*geo.Point{north(0), north(12), north(24)})
+}
+
+func TestLoiter(t *testing.T) {
+	ctx := context.Background()
+	loc := geo.NewPoint(40.7, -73.98)
+	var locMu sync.Mutex
+	injectMovementSensor := inject.NewMovementSensor("test_movement")
+	injectMovementSensor.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		locMu.Lock()
+		defer locMu.Unlock()
+		return loc, 0, nil
+	}
+	cancelCtx, cancelFunc := context.WithCancel(ctx)
+	svc := &builtIn{
+		movementSensor: injectMovementSensor,
+		loiterRadiusM:  2,
+		logger:         golog.NewTestLogger(t),
+		cancelCtx:      cancelCtx,
+	}
+	defer func() {
+		cancelFunc()
+		svc.activeBackgroundWorkers.Wait()
+	}()
+	var err error
+	svc.waypoints, err = svc.modeWaypoints(ctx, navigation.ModeLoiter, nil)
+	test.That(t, err, test.ShouldBeNil)
+	svc.startLoiter(nil)
+
+	// drifting within the loiter radius is left alone
+	locMu.Lock()
+	loc = geo.NewPoint(40.7+1/111195., -73.98)
+	locMu.Unlock()
+	time.Sleep(time.Second)
+	_, err = svc.waypoints.NextWaypoint(ctx)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	locMu.Lock()
+	loc = geo.NewPoint(40.7+3/111195., -73.98)
+	locMu.Unlock()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		wp, err := svc.waypoints.NextWaypoint(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, wp.ToPoint(), test.ShouldResemble, geo.NewPoint(40.7, -73.98))
+		test.That(tb, wp.ArrivalRadius(), test.ShouldEqual, 1)
+	})
+}
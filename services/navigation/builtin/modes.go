@@ -0,0 +1,228 @@
+package builtin
+
+import (
+	"context"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/services/navigation"
+)
+
+// breadcrumbSpacingM is how far apart, in meters, the points of the trail the robot leaves while navigating are.
+const breadcrumbSpacingM = 2 * navigation.DefaultArrivalRadiusM
+
+// modeWaypoints returns the store holding the waypoints the robot drives through in the mode:
+//   - in manual and waypoint mode, the configured store.
+//   - in return-home mode, the trail back to home.
+//   - in loiter mode, a route holding the current location, which is loaded whenever the robot drifts away from it.
+//   - in explore mode, a route sweeping the geofence given under the "zone" key of extra, or the first geofence, around
+//     the keep-out zones.
+func (svc *builtIn) modeWaypoints(
+	ctx context.Context,
+	mode navigation.Mode,
+	extra map[string]interface{},
+) (navigation.NavStore, error) {
+	switch mode {
+	case navigation.ModeManual, navigation.ModeWaypoint:
+		return svc.store, nil
+	case navigation.ModeReturnHome:
+		loc, err := svc.deps().location(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		route := navigation.Route{Name: "home"}
+		for _, point := range svc.wayHome(loc) {
+			route.Waypoints = append(route.Waypoints, navigation.RouteWaypoint{Lat: point.Lat(), Long: point.Lng()})
+		}
+		if len(route.Waypoints) == 0 {
+			return nil, errors.New("cannot return home before navigating in waypoint or explore mode")
+		}
+		return routeStore(ctx, route, true)
+	case navigation.ModeLoiter:
+		loc, err := svc.deps().location(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		// drive back until well within the loiter radius, so that the robot does not hover at its edge
+		return routeStore(ctx, navigation.Route{
+			Name: "hold position",
+			Waypoints: []navigation.RouteWaypoint{{
+				Lat:              loc.Lat(),
+				Long:             loc.Lng(),
+				WaypointBehavior: navigation.WaypointBehavior{ArrivalRadiusM: svc.loiterRadiusM / 2},
+			}},
+		}, false)
+	case navigation.ModeExplore:
+		zones, err := svc.store.Zones(ctx)
+		if err != nil {
+			return nil, err
+		}
+		zone, err := exploreZone(zones, extra)
+		if err != nil {
+			return nil, err
+		}
+		route, err := zone.CoverageRoute(svc.exploreSpacingM, zones)
+		if err != nil {
+			return nil, err
+		}
+		return routeStore(ctx, route, true)
+	default:
+		return nil, errors.Errorf("unknown mode %v", mode)
+	}
+}
+
+// routeStore returns a store holding only the route, with its waypoints loaded if load is set.
+func routeStore(ctx context.Context, route navigation.Route, load bool) (navigation.NavStore, error) {
+	store := navigation.NewMemoryNavigationStore()
+	route, err := store.AddRoute(ctx, route)
+	if err != nil {
+		return nil, err
+	}
+	if load {
+		if err := store.LoadRoute(ctx, route.ID); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// exploreZone returns the geofence whose ID is under the "zone" key of extra, or the first geofence if there is none.
+func exploreZone(zones []navigation.Zone, extra map[string]interface{}) (navigation.Zone, error) {
+	if idString, ok := extra["zone"].(string); ok {
+		id, err := primitive.ObjectIDFromHex(idString)
+		if err != nil {
+			return navigation.Zone{}, err
+		}
+		for _, zone := range zones {
+			if zone.ID != id {
+				continue
+			}
+			// a keep-out zone is where the robot must never go, so it cannot be swept
+			if zone.Kind != navigation.ZoneKindGeofence {
+				return navigation.Zone{}, errors.Errorf("cannot explore zone %s, which is a %s zone rather than a geofence", idString, zone.Kind)
+			}
+			return zone, nil
+		}
+		return navigation.Zone{}, errors.Errorf("no zone with id %s to explore", idString)
+	}
+	for _, zone := range zones {
+		if zone.Kind == navigation.ZoneKindGeofence {
+			return zone, nil
+		}
+	}
+	return navigation.Zone{}, errors.New("no zone to explore, add a geofence or give the id of a geofence under the \"zone\" key")
+}
+
+// setHome sets where the robot returns to, starting a new trail from there.
+func (svc *builtIn) setHome(home *geo.Point) {
+	svc.trailMu.Lock()
+	defer svc.trailMu.Unlock()
+	svc.trail = []*geo.Point{home}
+}
+
+// home returns where the robot returns to, or nil if it has not started navigating.
+func (svc *builtIn) home() *geo.Point {
+	svc.trailMu.Lock()
+	defer svc.trailMu.Unlock()
+	if len(svc.trail) == 0 {
+		return nil
+	}
+	return svc.trail[0]
+}
+
+// addBreadcrumb extends the trail to the location once the robot has moved far enough from its end. When the robot comes
+// back near a point already on the trail, the loop since then is cut out, so that the way home is never longer than it
+// needs to be.
+func (svc *builtIn) addBreadcrumb(loc *geo.Point) {
+	svc.trailMu.Lock()
+	defer svc.trailMu.Unlock()
+	if len(svc.trail) == 0 {
+		return
+	}
+	// distances are in km
+	for i, point := range svc.trail {
+		if point.GreatCircleDistance(loc) < breadcrumbSpacingM/1000 {
+			svc.trail = svc.trail[:i+1]
+			return
+		}
+	}
+	svc.trail = append(svc.trail, loc)
+}
+
+// wayHome returns the points of the trail to drive through to get home from the location, retracing the way the robot
+// came from the point of the trail closest to it, or nil if there is no home.
+func (svc *builtIn) wayHome(loc *geo.Point) []*geo.Point {
+	svc.trailMu.Lock()
+	defer svc.trailMu.Unlock()
+	if len(svc.trail) == 0 {
+		return nil
+	}
+	closest := 0
+	for i, point := range svc.trail {
+		if point.GreatCircleDistance(loc) < svc.trail[closest].GreatCircleDistance(loc) {
+			closest = i
+		}
+	}
+	way := make([]*geo.Point, 0, closest+1)
+	for i := closest; i >= 0; i-- {
+		way = append(way, svc.trail[i])
+	}
+	return way
+}
+
+// startBreadcrumbs lays the trail that return-home mode retraces while the robot navigates.
+func (svc *builtIn) startBreadcrumbs(extra map[string]interface{}) {
+	ctx, nav := svc.cancelCtx, svc.deps()
+	svc.activeBackgroundWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer svc.activeBackgroundWorkers.Done()
+		for utils.SelectContextOrWait(ctx, 500*time.Millisecond) {
+			loc, err := nav.location(ctx, extra)
+			if err != nil {
+				svc.logger.Errorw("failed to get location", "error", err)
+				continue
+			}
+			svc.addBreadcrumb(loc)
+		}
+	})
+}
+
+// startLoiter watches the location of the robot while it holds position, and loads the route back to where it is holding
+// whenever it drifts further than the loiter radius and is not already on its way back.
+func (svc *builtIn) startLoiter(extra map[string]interface{}) {
+	ctx, nav := svc.cancelCtx, svc.deps()
+	waypoints := nav.waypoints
+	svc.activeBackgroundWorkers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer svc.activeBackgroundWorkers.Done()
+		routes, err := waypoints.Routes(ctx)
+		if err != nil || len(routes) == 0 {
+			svc.logger.Errorw("failed to get position to hold", "error", err)
+			return
+		}
+		hold := routes[0]
+		holdPoint := geo.NewPoint(hold.Waypoints[0].Lat, hold.Waypoints[0].Long)
+		for utils.SelectContextOrWait(ctx, 500*time.Millisecond) {
+			loc, err := nav.location(ctx, extra)
+			if err != nil {
+				svc.logger.Errorw("failed to get location", "error", err)
+				continue
+			}
+			// distances are in km
+			if loc.GreatCircleDistance(holdPoint) <= nav.loiterRadiusM/1000 {
+				continue
+			}
+			if wps, err := waypoints.Waypoints(ctx); err != nil || len(wps) > 0 {
+				continue
+			}
+			svc.logger.Debugw("drifted from position, driving back", "location", loc)
+			if err := waypoints.LoadRoute(ctx, hold.ID); err != nil {
+				svc.logger.Errorw("failed to drive back to position", "error", err)
+			}
+		}
+	})
+}
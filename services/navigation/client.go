@@ -60,7 +60,13 @@ func (c *client) Mode(ctx context.Context, extra map[string]interface{}) (Mode,
 	case pb.Mode_MODE_WAYPOINT:
 		return ModeWaypoint, nil
 	case pb.Mode_MODE_UNSPECIFIED:
-		fallthrough
+		// the mode has no protobuf equivalent, so ask for it by name
+		resp, err := c.DoCommand(ctx, map[string]interface{}{"command": ModeCommand, "extra": extra})
+		if err != nil {
+			return 0, err
+		}
+		name, _ := resp["mode"].(string)
+		return modeFromString(name)
 	default:
 		return 0, errors.New("mode error")
 	}
@@ -77,6 +83,9 @@ func (c *client) SetMode(ctx context.Context, mode Mode, extra map[string]interf
 		pbMode = pb.Mode_MODE_MANUAL
 	case ModeWaypoint:
 		pbMode = pb.Mode_MODE_WAYPOINT
+	case ModeReturnHome, ModeLoiter, ModeExplore:
+		_, err := c.DoCommand(ctx, map[string]interface{}{"command": SetModeCommand, "mode": mode.String(), "extra": extra})
+		return err
 	default:
 		pbMode = pb.Mode_MODE_UNSPECIFIED
 	}
@@ -161,6 +170,161 @@ func (c *client) RemoveWaypoint(ctx context.Context, id primitive.ObjectID, extr
 	return nil
 }
 
+// Zones returns the zones of the remote service. It fails if the service does not keep to zones.
+func (c *client) Zones(ctx context.Context, extra map[string]interface{}) ([]Zone, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": ZonesCommand, "extra": extra})
+	if err != nil {
+		return nil, err
+	}
+	zoneMaps, ok := resp["zones"].([]interface{})
+	if !ok {
+		return nil, c.noZonesError()
+	}
+	zones := make([]Zone, 0, len(zoneMaps))
+	for _, zoneMap := range zoneMaps {
+		zone, err := zoneFromMap(zoneMap)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// AddZone adds a zone to the remote service. It fails if the service does not keep to zones.
+func (c *client) AddZone(ctx context.Context, zone Zone, extra map[string]interface{}) error {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": AddZoneCommand, "zone": zoneToMap(zone), "extra": extra})
+	if err != nil {
+		return err
+	}
+	if _, ok := resp["success"].(bool); !ok {
+		return c.noZonesError()
+	}
+	return nil
+}
+
+// RemoveZone removes a zone from the remote service. It fails if the service does not keep to zones.
+func (c *client) RemoveZone(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": RemoveZoneCommand, "id": id.Hex(), "extra": extra})
+	if err != nil {
+		return err
+	}
+	if _, ok := resp["success"].(bool); !ok {
+		return c.noZonesError()
+	}
+	return nil
+}
+
+// ZoneViolation returns the violation that stopped the remote service. It fails if the service does not keep to zones.
+func (c *client) ZoneViolation(ctx context.Context, extra map[string]interface{}) (*ZoneViolation, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": ZoneViolationCommand, "extra": extra})
+	if err != nil {
+		return nil, err
+	}
+	violationMap, ok := resp["violation"]
+	if !ok {
+		return nil, c.noZonesError()
+	}
+	if violationMap == nil {
+		return nil, nil
+	}
+	return zoneViolationFromMap(violationMap)
+}
+
+func (c *client) noZonesError() error {
+	return errors.Errorf("navigation service %s does not keep to zones", c.name)
+}
+
+// Routes returns the routes of the remote service. It fails if the service does not follow routes.
+func (c *client) Routes(ctx context.Context, extra map[string]interface{}) ([]Route, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": RoutesCommand, "extra": extra})
+	if err != nil {
+		return nil, err
+	}
+	routeMaps, ok := resp["routes"].([]interface{})
+	if !ok {
+		return nil, c.noRoutesError()
+	}
+	routes := make([]Route, 0, len(routeMaps))
+	for _, routeMap := range routeMaps {
+		route, err := routeFromMap(routeMap)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// AddRoute adds a route to the remote service. It fails if the service does not follow routes.
+func (c *client) AddRoute(ctx context.Context, route Route, extra map[string]interface{}) error {
+	return c.routeCommand(ctx, map[string]interface{}{"command": AddRouteCommand, "route": routeToMap(route), "extra": extra})
+}
+
+// RemoveRoute removes a route from the remote service. It fails if the service does not follow routes.
+func (c *client) RemoveRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	return c.routeCommand(ctx, map[string]interface{}{"command": RemoveRouteCommand, "id": id.Hex(), "extra": extra})
+}
+
+// LoadRoute loads a route into the remote service. It fails if the service does not follow routes.
+func (c *client) LoadRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	return c.routeCommand(ctx, map[string]interface{}{"command": LoadRouteCommand, "id": id.Hex(), "extra": extra})
+}
+
+// routeCommand sends a route command that changes the routes of the remote service.
+func (c *client) routeCommand(ctx context.Context, cmd map[string]interface{}) error {
+	resp, err := c.DoCommand(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	if _, ok := resp["success"].(bool); !ok {
+		return c.noRoutesError()
+	}
+	return nil
+}
+
+func (c *client) noRoutesError() error {
+	return errors.Errorf("navigation service %s does not follow routes", c.name)
+}
+
+// Progress returns the progress of the remote service. It fails if the service does not report its progress.
+func (c *client) Progress(ctx context.Context, extra map[string]interface{}) (Progress, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": ProgressCommand, "extra": extra})
+	if err != nil {
+		return Progress{}, err
+	}
+	if _, ok := resp["progress"]; !ok {
+		return Progress{}, c.noProgressError()
+	}
+	return progressFromMap(resp["progress"])
+}
+
+// Events returns the events of the remote service after the given sequence number. It fails if the service does not
+// report its progress.
+func (c *client) Events(ctx context.Context, after uint64, extra map[string]interface{}) ([]Event, error) {
+	resp, err := c.DoCommand(ctx, map[string]interface{}{"command": EventsCommand, "after": float64(after), "extra": extra})
+	if err != nil {
+		return nil, err
+	}
+	eventMaps, ok := resp["events"].([]interface{})
+	if !ok {
+		return nil, c.noProgressError()
+	}
+	events := make([]Event, 0, len(eventMaps))
+	for _, eventMap := range eventMaps {
+		event, err := eventFromMap(eventMap)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (c *client) noProgressError() error {
+	return errors.Errorf("navigation service %s does not report its progress", c.name)
+}
+
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
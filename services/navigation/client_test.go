@@ -4,7 +4,9 @@ import (
 	"context"
 	"math"
 	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	geo "github.com/kellydunn/golang-geo"
@@ -27,6 +29,68 @@ var (
 	testSvcName2 = navigation.Named("nav2")
 )
 
+// extendedNavigationService is a navigation service which implements the optional navigation service interfaces.
+type extendedNavigationService struct {
+	*inject.NavigationService
+	zonesFunc         func(ctx context.Context, extra map[string]interface{}) ([]navigation.Zone, error)
+	addZoneFunc       func(ctx context.Context, zone navigation.Zone, extra map[string]interface{}) error
+	removeZoneFunc    func(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error
+	zoneViolationFunc func(ctx context.Context, extra map[string]interface{}) (*navigation.ZoneViolation, error)
+	routesFunc        func(ctx context.Context, extra map[string]interface{}) ([]navigation.Route, error)
+	addRouteFunc      func(ctx context.Context, route navigation.Route, extra map[string]interface{}) error
+	removeRouteFunc   func(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error
+	loadRouteFunc     func(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error
+	progressFunc      func(ctx context.Context, extra map[string]interface{}) (navigation.Progress, error)
+	eventsFunc        func(ctx context.Context, after uint64, extra map[string]interface{}) ([]navigation.Event, error)
+}
+
+func (ns *extendedNavigationService) Zones(ctx context.Context, extra map[string]interface{}) ([]navigation.Zone, error) {
+	return ns.zonesFunc(ctx, extra)
+}
+
+func (ns *extendedNavigationService) AddZone(ctx context.Context, zone navigation.Zone, extra map[string]interface{}) error {
+	return ns.addZoneFunc(ctx, zone, extra)
+}
+
+func (ns *extendedNavigationService) RemoveZone(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	return ns.removeZoneFunc(ctx, id, extra)
+}
+
+func (ns *extendedNavigationService) ZoneViolation(
+	ctx context.Context,
+	extra map[string]interface{},
+) (*navigation.ZoneViolation, error) {
+	return ns.zoneViolationFunc(ctx, extra)
+}
+
+func (ns *extendedNavigationService) Routes(ctx context.Context, extra map[string]interface{}) ([]navigation.Route, error) {
+	return ns.routesFunc(ctx, extra)
+}
+
+func (ns *extendedNavigationService) AddRoute(ctx context.Context, route navigation.Route, extra map[string]interface{}) error {
+	return ns.addRouteFunc(ctx, route, extra)
+}
+
+func (ns *extendedNavigationService) RemoveRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	return ns.removeRouteFunc(ctx, id, extra)
+}
+
+func (ns *extendedNavigationService) LoadRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+	return ns.loadRouteFunc(ctx, id, extra)
+}
+
+func (ns *extendedNavigationService) Progress(ctx context.Context, extra map[string]interface{}) (navigation.Progress, error) {
+	return ns.progressFunc(ctx, extra)
+}
+
+func (ns *extendedNavigationService) Events(
+	ctx context.Context,
+	after uint64,
+	extra map[string]interface{},
+) ([]navigation.Event, error) {
+	return ns.eventsFunc(ctx, after, extra)
+}
+
 func TestClient(t *testing.T) {
 	logger := golog.NewTestLogger(t)
 	listener1, err := net.Listen("tcp", "localhost:0")
@@ -39,18 +103,23 @@ func TestClient(t *testing.T) {
 
 	var extraOptions map[string]interface{}
 	workingNavigationService := &inject.NavigationService{}
+	extendedNavigationService := &extendedNavigationService{NavigationService: workingNavigationService}
 	failingNavigationService := &inject.NavigationService{}
 
-	modeTested := false
+	modeCalls := 0
+	var receivedMode navigation.Mode
 	workingNavigationService.ModeFunc = func(ctx context.Context, extra map[string]interface{}) (navigation.Mode, error) {
 		extraOptions = extra
-		if !modeTested {
-			modeTested = true
+		modeCalls++
+		switch modeCalls {
+		case 1:
 			return navigation.ModeManual, nil
+		case 2:
+			return navigation.ModeWaypoint, nil
+		default:
+			return receivedMode, nil
 		}
-		return navigation.ModeWaypoint, nil
 	}
-	var receivedMode navigation.Mode
 	workingNavigationService.SetModeFunc = func(ctx context.Context, mode navigation.Mode, extra map[string]interface{}) error {
 		extraOptions = extra
 		receivedMode = mode
@@ -85,6 +154,121 @@ func TestClient(t *testing.T) {
 		receivedID = id
 		return nil
 	}
+	zones := []navigation.Zone{
+		{
+			ID:       primitive.NewObjectID(),
+			Name:     "yard",
+			Kind:     navigation.ZoneKindGeofence,
+			Vertices: []navigation.ZoneVertex{{Lat: 40, Long: 20}, {Lat: 41, Long: 20}, {Lat: 41, Long: 21}},
+		},
+	}
+	extendedNavigationService.zonesFunc = func(ctx context.Context, extra map[string]interface{}) ([]navigation.Zone, error) {
+		extraOptions = extra
+		return zones, nil
+	}
+	var receivedZone navigation.Zone
+	extendedNavigationService.addZoneFunc = func(ctx context.Context, zone navigation.Zone, extra map[string]interface{}) error {
+		extraOptions = extra
+		receivedZone = zone
+		return nil
+	}
+	var receivedZoneID primitive.ObjectID
+	extendedNavigationService.removeZoneFunc = func(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+		extraOptions = extra
+		receivedZoneID = id
+		return nil
+	}
+	violation := &navigation.ZoneViolation{
+		Time:     time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+		Location: geo.NewPoint(42, 20),
+	}
+	extendedNavigationService.zoneViolationFunc = func(
+		ctx context.Context,
+		extra map[string]interface{},
+	) (*navigation.ZoneViolation, error) {
+		extraOptions = extra
+		return violation, nil
+	}
+	heading := 180.
+	routes := []navigation.Route{
+		{
+			ID:   primitive.NewObjectID(),
+			Name: "survey",
+			Waypoints: []navigation.RouteWaypoint{
+				{Lat: 40, Long: 20},
+				{
+					Lat:  41,
+					Long: 20,
+					WaypointBehavior: navigation.WaypointBehavior{
+						ArrivalRadiusM: 1,
+						Heading:        &heading,
+						Dwell:          time.Second,
+						MetersPerSec:   0.2,
+						Action: &navigation.WaypointAction{
+							Resource: "rdk:component:camera/cam1",
+							Command:  map[string]interface{}{"capture": true},
+						},
+					},
+				},
+			},
+		},
+	}
+	extendedNavigationService.routesFunc = func(ctx context.Context, extra map[string]interface{}) ([]navigation.Route, error) {
+		extraOptions = extra
+		return routes, nil
+	}
+	var receivedRoute navigation.Route
+	extendedNavigationService.addRouteFunc = func(ctx context.Context, route navigation.Route, extra map[string]interface{}) error {
+		extraOptions = extra
+		receivedRoute = route
+		return nil
+	}
+	var receivedRemovedRouteID, receivedLoadedRouteID primitive.ObjectID
+	extendedNavigationService.removeRouteFunc = func(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+		extraOptions = extra
+		receivedRemovedRouteID = id
+		return nil
+	}
+	extendedNavigationService.loadRouteFunc = func(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
+		extraOptions = extra
+		receivedLoadedRouteID = id
+		return nil
+	}
+	progress := navigation.Progress{
+		Mode:          navigation.ModeWaypoint,
+		Waypoint:      &navigation.Waypoint{ID: primitive.NewObjectID(), RouteID: routes[0].ID, Lat: 41, Long: 20},
+		DistanceToGoM: 120,
+		BearingToGo:   45,
+		ETA:           time.Date(2023, 6, 1, 12, 4, 0, 0, time.UTC),
+		Leg: &navigation.Leg{
+			From:         geo.NewPoint(40, 20),
+			To:           geo.NewPoint(41, 20),
+			MetersPerSec: 0.5,
+			Started:      time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	extendedNavigationService.progressFunc = func(ctx context.Context, extra map[string]interface{}) (navigation.Progress, error) {
+		extraOptions = extra
+		return progress, nil
+	}
+	events := []navigation.Event{
+		{
+			Sequence:   7,
+			Time:       time.Date(2023, 6, 1, 12, 4, 0, 0, time.UTC),
+			Kind:       navigation.EventKindWaypointReached,
+			WaypointID: progress.Waypoint.ID,
+		},
+	}
+	var receivedAfter uint64
+	extendedNavigationService.eventsFunc = func(
+		ctx context.Context,
+		after uint64,
+		extra map[string]interface{},
+	) ([]navigation.Event, error) {
+		extraOptions = extra
+		receivedAfter = after
+		return events, nil
+	}
 
 	failingNavigationService.ModeFunc = func(ctx context.Context, extra map[string]interface{}) (navigation.Mode, error) {
 		return navigation.ModeManual, errors.New("failure to retrieve mode")
@@ -111,8 +295,10 @@ func TestClient(t *testing.T) {
 		return errors.New("failure to remove waypoint")
 	}
 
+	// the second service is the same, but without the optional interfaces
 	workingSvc, err := resource.NewAPIResourceCollection(navigation.API, map[resource.Name]navigation.Service{
-		testSvcName1: workingNavigationService,
+		testSvcName1: extendedNavigationService,
+		testSvcName2: struct{ navigation.Service }{workingNavigationService},
 	})
 	test.That(t, err, test.ShouldBeNil)
 	failingSvc, err := resource.NewAPIResourceCollection(navigation.API, map[resource.Name]navigation.Service{
@@ -161,6 +347,16 @@ func TestClient(t *testing.T) {
 		test.That(t, receivedMode, test.ShouldEqual, navigation.ModeManual)
 		test.That(t, extraOptions, test.ShouldResemble, extra)
 
+		// test the modes that have no protobuf equivalent
+		extra = map[string]interface{}{"foo": "SetMode", "zone": "lawn"}
+		err = workingNavClient.SetMode(context.Background(), navigation.ModeExplore, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedMode, test.ShouldEqual, navigation.ModeExplore)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		mode, err = workingNavClient.Mode(context.Background(), map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mode, test.ShouldEqual, navigation.ModeExplore)
+
 		// test add waypoint
 		point := geo.NewPoint(90, 1)
 		extra = map[string]interface{}{"foo": "AddWaypoint"}
@@ -214,6 +410,151 @@ func TestClient(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, receivedWpts, test.ShouldResemble, waypoints)
 		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test zones
+		zoner, ok := dialedClient.(navigation.ZoneNavigator)
+		test.That(t, ok, test.ShouldBeTrue)
+		extra = map[string]interface{}{"foo": "Zones"}
+		receivedZones, err := zoner.Zones(context.Background(), extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedZones, test.ShouldResemble, zones)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test add zone
+		extra = map[string]interface{}{"foo": "AddZone"}
+		newZone := navigation.Zone{
+			Kind:     navigation.ZoneKindKeepOut,
+			Vertices: []navigation.ZoneVertex{{Lat: 1, Long: 2}, {Lat: 3, Long: 4}, {Lat: 5, Long: 2}},
+		}
+		err = zoner.AddZone(context.Background(), newZone, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedZone, test.ShouldResemble, newZone)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test remove zone
+		extra = map[string]interface{}{"foo": "RemoveZone"}
+		err = zoner.RemoveZone(context.Background(), zones[0].ID, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedZoneID, test.ShouldEqual, zones[0].ID)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test zone violation
+		extra = map[string]interface{}{"foo": "ZoneViolation"}
+		receivedViolation, err := zoner.ZoneViolation(context.Background(), extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedViolation, test.ShouldResemble, violation)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		violation = nil
+		receivedViolation, err = zoner.ZoneViolation(context.Background(), extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedViolation, test.ShouldBeNil)
+
+		// test routes
+		router, ok := dialedClient.(navigation.RouteNavigator)
+		test.That(t, ok, test.ShouldBeTrue)
+		extra = map[string]interface{}{"foo": "Routes"}
+		receivedRoutes, err := router.Routes(context.Background(), extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedRoutes, test.ShouldResemble, routes)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test add route
+		extra = map[string]interface{}{"foo": "AddRoute"}
+		newRoute := routes[0]
+		newRoute.ID = primitive.NilObjectID
+		err = router.AddRoute(context.Background(), newRoute, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedRoute, test.ShouldResemble, newRoute)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test remove and load route
+		extra = map[string]interface{}{"foo": "RemoveRoute"}
+		err = router.RemoveRoute(context.Background(), routes[0].ID, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedRemovedRouteID, test.ShouldEqual, routes[0].ID)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		extra = map[string]interface{}{"foo": "LoadRoute"}
+		err = router.LoadRoute(context.Background(), routes[0].ID, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedLoadedRouteID, test.ShouldEqual, routes[0].ID)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test route files
+		var geoJSON strings.Builder
+		test.That(t, navigation.WriteGeoJSONRoute(&geoJSON, routes[0]), test.ShouldBeNil)
+		_, err = dialedClient.DoCommand(context.Background(), map[string]interface{}{
+			"command": navigation.ImportRouteCommand,
+			"format":  string(navigation.RouteFormatGeoJSON),
+			"data":    geoJSON.String(),
+			"name":    "imported",
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedRoute.Name, test.ShouldEqual, "imported")
+		test.That(t, receivedRoute.Waypoints, test.ShouldResemble, routes[0].Waypoints)
+		resp, err := dialedClient.DoCommand(context.Background(), map[string]interface{}{
+			"command": navigation.ExportRouteCommand,
+			"format":  string(navigation.RouteFormatKML),
+			"id":      routes[0].ID.Hex(),
+		})
+		test.That(t, err, test.ShouldBeNil)
+		data, _ := resp["data"].(string)
+		exported, err := navigation.ReadKMLRoute(strings.NewReader(data))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, exported.Waypoints, test.ShouldResemble, routes[0].Waypoints)
+		_, err = dialedClient.DoCommand(context.Background(), map[string]interface{}{
+			"command": navigation.ExportRouteCommand,
+			"format":  "gpx",
+			"id":      routes[0].ID.Hex(),
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+
+		// test progress
+		reporter, ok := dialedClient.(navigation.ProgressReporter)
+		test.That(t, ok, test.ShouldBeTrue)
+		extra = map[string]interface{}{"foo": "Progress"}
+		receivedProgress, err := reporter.Progress(context.Background(), extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedProgress, test.ShouldResemble, progress)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+
+		// test events
+		extra = map[string]interface{}{"foo": "Events"}
+		receivedEvents, err := reporter.Events(context.Background(), 6, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedEvents, test.ShouldResemble, events)
+		test.That(t, receivedAfter, test.ShouldEqual, 6)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	})
+
+	t.Run("dialed client without optional interfaces", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		dialedClient, err := resourceAPI.RPCClient(context.Background(), conn, "", testSvcName2, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		// the commands of the optional interfaces reach DoCommand of services which do not implement them
+		var received map[string]interface{}
+		workingNavigationService.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			received = cmd
+			return cmd, nil
+		}
+		extendedNavigationService.zonesFunc = nil
+		_, err = dialedClient.(navigation.ZoneNavigator).Zones(context.Background(), nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not keep to zones")
+		test.That(t, received["command"], test.ShouldEqual, navigation.ZonesCommand)
+		err = dialedClient.(navigation.ZoneNavigator).AddZone(context.Background(), zones[0], nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, received["command"], test.ShouldEqual, navigation.AddZoneCommand)
+		_, err = dialedClient.(navigation.RouteNavigator).Routes(context.Background(), nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not follow routes")
+		test.That(t, received["command"], test.ShouldEqual, navigation.RoutesCommand)
+		_, err = dialedClient.(navigation.ProgressReporter).Events(context.Background(), 0, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not report its progress")
+		test.That(t, received["command"], test.ShouldEqual, navigation.EventsCommand)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 
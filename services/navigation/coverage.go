@@ -0,0 +1,209 @@
+package navigation
+
+import (
+	"math"
+	"sort"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+)
+
+// metersPerDegree is the length of a degree of latitude, on the same spherical earth golang-geo measures distances on.
+var metersPerDegree = 2 * math.Pi * geo.EARTH_RADIUS * 1000 / 360
+
+// CoverageRoute returns a route that sweeps back and forth across the zone in a boustrophedon pattern, like a mower, so
+// that every part of it passes within spacingM/2 meters of the robot. The sweep lines are spacingM meters apart and run
+// parallel to the longest edge of the zone, which keeps the number of turns low, and stop spacingM/2 meters short of its
+// edges and of the keep-out zones among avoid. Where keep-out zones, or the shape of the zone, split the sweep lines, the
+// parts are swept nearest first, and the robot retraces the route to reach parts that it cannot drive straight to
+// without crossing an edge. Parts that cannot be reached even then are left out.
+func (z *Zone) CoverageRoute(spacingM float64, avoid []Zone) (Route, error) {
+	if err := z.Validate(); err != nil {
+		return Route{}, err
+	}
+	if spacingM <= 0 {
+		return Route{}, errors.Errorf("coverage spacing must be positive, is %v", spacingM)
+	}
+
+	// work in meters on a plane touching the globe at the first vertex, rotated so that the longest edge runs along u
+	origin := z.Vertices[0]
+	cosLat := math.Cos(origin.Lat * math.Pi / 180)
+	toPlane := func(v ZoneVertex) (float64, float64) {
+		return (v.Long - origin.Long) * cosLat * metersPerDegree, (v.Lat - origin.Lat) * metersPerDegree
+	}
+	var angle, longest float64
+	for i, j := 0, len(z.Vertices)-1; i < len(z.Vertices); j, i = i, i+1 {
+		xi, yi := toPlane(z.Vertices[i])
+		xj, yj := toPlane(z.Vertices[j])
+		if length := math.Hypot(xi-xj, yi-yj); length > longest {
+			longest = length
+			angle = math.Atan2(yi-yj, xi-xj)
+		}
+	}
+	sin, cos := math.Sincos(angle)
+	project := func(vertices []ZoneVertex) coveragePolygon {
+		polygon := coveragePolygon{us: make([]float64, len(vertices)), vs: make([]float64, len(vertices))}
+		for i, vertex := range vertices {
+			x, y := toPlane(vertex)
+			polygon.us[i] = x*cos + y*sin
+			polygon.vs[i] = -x*sin + y*cos
+		}
+		return polygon
+	}
+	toWaypoint := func(u, v float64) RouteWaypoint {
+		x, y := u*cos-v*sin, u*sin+v*cos
+		return RouteWaypoint{Lat: origin.Lat + y/metersPerDegree, Long: origin.Long + x/cosLat/metersPerDegree}
+	}
+
+	// the route may not cross the edges of the zone or of any keep-out zone
+	barriers := []coveragePolygon{project(z.Vertices)}
+	for _, zone := range avoid {
+		if zone.Kind == ZoneKindKeepOut && len(zone.Vertices) >= 3 {
+			barriers = append(barriers, project(zone.Vertices))
+		}
+	}
+	minV, maxV := math.Inf(1), math.Inf(-1)
+	for _, v := range barriers[0].vs {
+		minV = math.Min(minV, v)
+		maxV = math.Max(maxV, v)
+	}
+	lines := []float64{(minV + maxV) / 2}
+	if maxV-minV > spacingM {
+		lines = nil
+		for v := minV + spacingM/2; v < maxV; v += spacingM {
+			lines = append(lines, v)
+		}
+	}
+
+	// split the lines into the parts within the zone and outside the keep-out zones
+	var parts []coveragePart
+	for _, v := range lines {
+		inside := barriers[0].intervals(v)
+		for _, keepOut := range barriers[1:] {
+			inside = subtractIntervals(inside, keepOut.intervals(v))
+		}
+		for _, interval := range inside {
+			start, end := interval[0], interval[1]
+			if end-start > spacingM {
+				parts = append(parts, coveragePart{v: v, us: []float64{start + spacingM/2, end - spacingM/2}})
+			} else {
+				parts = append(parts, coveragePart{v: v, us: []float64{(start + end) / 2}})
+			}
+		}
+	}
+	crosses := func(u1, v1, u2, v2 float64) bool {
+		for _, barrier := range barriers {
+			if barrier.crosses(u1, v1, u2, v2) {
+				return true
+			}
+		}
+		return false
+	}
+	swept := make([]bool, len(parts))
+	// nearest returns the nearest part not yet swept that can be driven straight to from (u, v), and whether it is swept
+	// from its far end, or -1 if there is none
+	nearest := func(u, v float64) (int, bool) {
+		best, reverse, bestDistance := -1, false, math.Inf(1)
+		for i, part := range parts {
+			if swept[i] {
+				continue
+			}
+			for end, partU := range []float64{part.us[0], part.us[len(part.us)-1]} {
+				if distance := math.Hypot(partU-u, part.v-v); distance < bestDistance && !crosses(u, v, partU, part.v) {
+					best, reverse, bestDistance = i, end == 1, distance
+				}
+			}
+		}
+		return best, reverse
+	}
+
+	route := Route{Name: "coverage of " + z.label()}
+	var driven [][2]float64
+	drive := func(u, v float64) {
+		route.Waypoints = append(route.Waypoints, toWaypoint(u, v))
+		driven = append(driven, [2]float64{u, v})
+	}
+	for range parts {
+		next, reverse := 0, false
+		if len(driven) > 0 {
+			next = -1
+			for back := len(driven) - 1; back >= 0 && next < 0; back-- {
+				if next, reverse = nearest(driven[back][0], driven[back][1]); next >= 0 {
+					for i := len(driven) - 2; i >= back; i-- {
+						drive(driven[i][0], driven[i][1])
+					}
+				}
+			}
+			if next < 0 {
+				break
+			}
+		}
+		swept[next] = true
+		part := parts[next]
+		for i := range part.us {
+			if reverse {
+				i = len(part.us) - 1 - i
+			}
+			drive(part.us[i], part.v)
+		}
+	}
+	return route, nil
+}
+
+// A coveragePolygon is a zone on the plane CoverageRoute works on.
+type coveragePolygon struct {
+	us, vs []float64
+}
+
+// intervals returns the intervals of u, in order, where the line at v is inside the polygon.
+func (p coveragePolygon) intervals(v float64) [][2]float64 {
+	var crossings []float64
+	for i, j := 0, len(p.us)-1; i < len(p.us); j, i = i, i+1 {
+		if (p.vs[i] > v) != (p.vs[j] > v) {
+			crossings = append(crossings, p.us[i]+(v-p.vs[i])*(p.us[j]-p.us[i])/(p.vs[j]-p.vs[i]))
+		}
+	}
+	sort.Float64s(crossings)
+	var intervals [][2]float64
+	for i := 0; i+1 < len(crossings); i += 2 {
+		intervals = append(intervals, [2]float64{crossings[i], crossings[i+1]})
+	}
+	return intervals
+}
+
+// crosses returns whether the straight line between the two points crosses an edge of the polygon.
+func (p coveragePolygon) crosses(u1, v1, u2, v2 float64) bool {
+	for i, j := 0, len(p.us)-1; i < len(p.us); j, i = i, i+1 {
+		if segmentsIntersect(u1, v1, u2, v2, p.us[j], p.vs[j], p.us[i], p.vs[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// A coveragePart is the stretch of a sweep line at v driven from one u to the next.
+type coveragePart struct {
+	v  float64
+	us []float64
+}
+
+// subtractIntervals returns the parts of the ordered intervals in from that are outside every interval in cut.
+func subtractIntervals(from, cut [][2]float64) [][2]float64 {
+	for _, c := range cut {
+		var rest [][2]float64
+		for _, f := range from {
+			if c[1] <= f[0] || c[0] >= f[1] {
+				rest = append(rest, f)
+				continue
+			}
+			if c[0] > f[0] {
+				rest = append(rest, [2]float64{f[0], c[0]})
+			}
+			if c[1] < f[1] {
+				rest = append(rest, [2]float64{c[1], f[1]})
+			}
+		}
+		from = rest
+	}
+	return from
+}
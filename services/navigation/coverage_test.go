@@ -0,0 +1,91 @@
+package navigation_test
+
+import (
+	"math"
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/navigation"
+)
+
+func TestCoverageRoute(t *testing.T) {
+	// a field about 100m from west to east and 50m from south to north
+	const lat, long = 40., -74.
+	north := 50 / 111195.
+	east := 100 / (111195. * math.Cos(lat*math.Pi/180))
+	field := navigation.Zone{
+		Name: "field",
+		Kind: navigation.ZoneKindGeofence,
+		Vertices: []navigation.ZoneVertex{
+			{Lat: lat, Long: long},
+			{Lat: lat, Long: long + east},
+			{Lat: lat + north, Long: long + east},
+			{Lat: lat + north, Long: long},
+		},
+	}
+
+	_, err := field.CoverageRoute(0, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	route, err := field.CoverageRoute(10, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, route.Validate(), test.ShouldBeNil)
+	// five lines running east and west, along the longest edges, each stopping 5m short of the edges
+	test.That(t, len(route.Waypoints), test.ShouldEqual, 10)
+	for i, wp := range route.Waypoints {
+		point := geo.NewPoint(wp.Lat, wp.Long)
+		test.That(t, field.Contains(point), test.ShouldBeTrue)
+		test.That(t, point.GreatCircleDistance(geo.NewPoint(lat+float64(i/2*10+5)/111195., wp.Long)), test.ShouldBeLessThan, 0.001)
+		if i%2 == 1 {
+			previous := geo.NewPoint(route.Waypoints[i-1].Lat, route.Waypoints[i-1].Long)
+			test.That(t, previous.GreatCircleDistance(point), test.ShouldAlmostEqual, 0.09, 0.001)
+		}
+	}
+	// the lines alternate direction
+	test.That(t, route.Waypoints[0].Long, test.ShouldBeLessThan, route.Waypoints[1].Long)
+	test.That(t, route.Waypoints[2].Long, test.ShouldBeGreaterThan, route.Waypoints[3].Long)
+	test.That(t, route.Waypoints[1].Long, test.ShouldAlmostEqual, route.Waypoints[2].Long, 1e-9)
+
+	// a zone narrower than the spacing is swept once down the middle
+	route, err = field.CoverageRoute(200, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(route.Waypoints), test.ShouldEqual, 1)
+	test.That(t, route.Waypoints[0].Lat, test.ShouldAlmostEqual, lat+north/2, 1e-9)
+	test.That(t, route.Waypoints[0].Long, test.ShouldAlmostEqual, long+east/2, 1e-6)
+
+	// a keep-out zone across the middle of the field is swept around, never entered or crossed
+	pond := navigation.Zone{
+		Name: "pond",
+		Kind: navigation.ZoneKindKeepOut,
+		Vertices: []navigation.ZoneVertex{
+			{Lat: lat + north/4, Long: long + east*2/5},
+			{Lat: lat + north/4, Long: long + east*3/5},
+			{Lat: lat + north*3/4, Long: long + east*3/5},
+			{Lat: lat + north*3/4, Long: long + east*2/5},
+		},
+	}
+	route, err = field.CoverageRoute(10, []navigation.Zone{field, pond})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, route.Validate(), test.ShouldBeNil)
+	test.That(t, len(route.Waypoints), test.ShouldBeGreaterThan, 10)
+	var westOfPond, eastOfPond int
+	for i, wp := range route.Waypoints {
+		point := geo.NewPoint(wp.Lat, wp.Long)
+		test.That(t, field.Contains(point), test.ShouldBeTrue)
+		test.That(t, pond.Contains(point), test.ShouldBeFalse)
+		if wp.Long < long+east*2/5 {
+			westOfPond++
+		} else if wp.Long > long+east*3/5 {
+			eastOfPond++
+		}
+		if i > 0 {
+			previous := geo.NewPoint(route.Waypoints[i-1].Lat, route.Waypoints[i-1].Long)
+			test.That(t, pond.Crosses(previous, point), test.ShouldBeFalse)
+		}
+	}
+	// both sides of the keep-out zone are swept
+	test.That(t, westOfPond, test.ShouldBeGreaterThanOrEqualTo, 5)
+	test.That(t, eastOfPond, test.ShouldBeGreaterThanOrEqualTo, 5)
+}
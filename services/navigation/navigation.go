@@ -3,8 +3,10 @@ package navigation
 
 import (
 	"context"
+	"fmt"
 
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	servicepb "go.viam.com/api/service/navigation/v1"
 
@@ -28,12 +30,53 @@ type Mode uint8
 const (
 	ModeManual = Mode(iota)
 	ModeWaypoint
+	// ModeReturnHome drives back to where the robot was when it last started navigating, retracing the way it came.
+	ModeReturnHome
+	// ModeLoiter holds the robot where it was when the mode was set, driving back whenever it drifts away.
+	ModeLoiter
+	// ModeExplore sweeps the robot back and forth across a geofence, covering all of it outside the keep-out zones.
+	ModeExplore
+)
+
+var modeNames = map[Mode]string{
+	ModeManual:     "manual",
+	ModeWaypoint:   "waypoint",
+	ModeReturnHome: "return_home",
+	ModeLoiter:     "loiter",
+	ModeExplore:    "explore",
+}
+
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("Mode(%d)", uint8(m))
+}
+
+// modeFromString returns the mode with the given name.
+func modeFromString(name string) (Mode, error) {
+	for mode, modeName := range modeNames {
+		if modeName == name {
+			return mode, nil
+		}
+	}
+	return 0, errors.Errorf("unknown mode %q", name)
+}
+
+// The DoCommand commands clients send to get and set the modes that the GetMode and SetMode RPCs cannot express, which
+// are reported by GetMode as unspecified. The name of the mode is sent under the "mode" key and extra under the "extra"
+// key. Responses to ModeCommand hold the name of the mode under the "mode" key.
+const (
+	ModeCommand    = "mode"
+	SetModeCommand = "set_mode"
 )
 
 // A Service controls the navigation for a robot.
 type Service interface {
 	resource.Resource
 	Mode(ctx context.Context, extra map[string]interface{}) (Mode, error)
+	// SetMode switches the mode of the service. In explore mode, the geofence to sweep is given by its ID under the "zone"
+	// key of extra, or is the first geofence if there is none.
 	SetMode(ctx context.Context, mode Mode, extra map[string]interface{}) error
 
 	Location(ctx context.Context, extra map[string]interface{}) (*geo.Point, error)
@@ -44,6 +87,34 @@ type Service interface {
 	RemoveWaypoint(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error
 }
 
+// ZoneNavigator is implemented by navigation services that keep the robot within geofences and out of keep-out zones.
+type ZoneNavigator interface {
+	Zones(ctx context.Context, extra map[string]interface{}) ([]Zone, error)
+	AddZone(ctx context.Context, zone Zone, extra map[string]interface{}) error
+	RemoveZone(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error
+	// ZoneViolation returns the violation that stopped navigation, or nil if the robot has stayed within its zones since
+	// the mode was last set.
+	ZoneViolation(ctx context.Context, extra map[string]interface{}) (*ZoneViolation, error)
+}
+
+// RouteNavigator is implemented by navigation services that keep missions as routes of waypoints with their own behavior.
+type RouteNavigator interface {
+	Routes(ctx context.Context, extra map[string]interface{}) ([]Route, error)
+	AddRoute(ctx context.Context, route Route, extra map[string]interface{}) error
+	RemoveRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error
+	// LoadRoute replaces the waypoints still to be visited with those of the route, which are then navigated in waypoint
+	// mode.
+	LoadRoute(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error
+}
+
+// ProgressReporter is implemented by navigation services that report how navigation is going while it happens.
+type ProgressReporter interface {
+	Progress(ctx context.Context, extra map[string]interface{}) (Progress, error)
+	// Events returns the events after the given sequence number. If there are none, it waits for the next one, or until
+	// ctx is done. Use StreamEvents to follow them.
+	Events(ctx context.Context, after uint64, extra map[string]interface{}) ([]Event, error)
+}
+
 // SubtypeName is the name of the type of service.
 const SubtypeName = "navigation"
 
@@ -0,0 +1,296 @@
+package navigation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/utils"
+)
+
+// Progress describes what the robot is currently doing.
+type Progress struct {
+	Mode Mode
+	// Waypoint is the waypoint being navigated to, or nil if there is none.
+	Waypoint *Waypoint
+	// DistanceToGoM and BearingToGo are the distance in meters and the compass bearing in degrees from the current location
+	// to the waypoint.
+	DistanceToGoM float64
+	BearingToGo   float64
+	// ETA is when the robot is expected to arrive at the waypoint, or the zero time if it is not known.
+	ETA time.Time
+	// Leg is the leg being driven, or nil if there is none.
+	Leg *Leg
+	// Home is where the robot returns to in return-home mode, or nil if it has not started navigating.
+	Home *geo.Point
+}
+
+// A Leg is the plan for driving from one location to the next waypoint.
+type Leg struct {
+	From         *geo.Point
+	To           *geo.Point
+	MetersPerSec float64
+	Started      time.Time
+}
+
+// EventKind describes what happened during navigation.
+type EventKind string
+
+// The set of known event kinds.
+const (
+	EventKindWaypointReached = EventKind("waypoint_reached")
+	EventKindLegFailed       = EventKind("leg_failed")
+	// EventKindObstacleStop is a leg that failed because the robot stopped for an obstacle.
+	EventKindObstacleStop  = EventKind("obstacle_stop")
+	EventKindModeChange    = EventKind("mode_change")
+	EventKindZoneViolation = EventKind("zone_violation")
+	// EventKindActionFailed is a waypoint action that was skipped because it kept failing.
+	EventKindActionFailed = EventKind("action_failed")
+)
+
+// An Event is something that happened during navigation. Events are numbered in the order they happen.
+type Event struct {
+	Sequence uint64
+	Time     time.Time
+	Kind     EventKind
+	// WaypointID is the waypoint the event is about, if any.
+	WaypointID primitive.ObjectID
+	Message    string
+}
+
+// eventLogSize is the number of recent events an EventLog keeps.
+const eventLogSize = 256
+
+// EventLog keeps the most recent navigation events so that clients can follow them.
+type EventLog struct {
+	mu      sync.Mutex
+	events  []Event
+	next    uint64
+	newData chan struct{}
+}
+
+// NewEventLog returns an empty EventLog. Its events are numbered on from the time it is made in microseconds since the Unix
+// epoch, so that the events of a log made after a restart are numbered after those of the one before, and clients that
+// follow the events across the restart miss none of the new ones. Microseconds keep the numbers exact when they are sent
+// as JSON numbers.
+func NewEventLog() *EventLog {
+	return &EventLog{next: uint64(time.Now().UnixMicro()), newData: make(chan struct{})}
+}
+
+// Publish adds an event to the log and wakes up everyone waiting for it.
+func (l *EventLog) Publish(kind EventKind, waypointID primitive.ObjectID, message string) Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	event := Event{Sequence: l.next, Time: time.Now(), Kind: kind, WaypointID: waypointID, Message: message}
+	l.next++
+	l.events = append(l.events, event)
+	if len(l.events) > eventLogSize {
+		l.events = append([]Event(nil), l.events[len(l.events)-eventLogSize:]...)
+	}
+	close(l.newData)
+	l.newData = make(chan struct{})
+	return event
+}
+
+// Events returns the events after the given sequence number that are still in the log. If there are none, it waits for
+// the next one, or until ctx is done, in which case it returns ctx's error.
+func (l *EventLog) Events(ctx context.Context, after uint64) ([]Event, error) {
+	for {
+		l.mu.Lock()
+		var events []Event
+		for _, event := range l.events {
+			if event.Sequence > after {
+				events = append(events, event)
+			}
+		}
+		newData := l.newData
+		l.mu.Unlock()
+		if len(events) > 0 {
+			return events, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-newData:
+		}
+	}
+}
+
+// eventsPollTimeout bounds how long StreamEvents waits on each call to Events, so that a lost connection is noticed.
+const eventsPollTimeout = 30 * time.Second
+
+// StreamEvents follows the events of a navigation service, starting with those after the given sequence number, by
+// repeatedly calling Events, which waits for new events. Events are sent on the returned channel, which is closed once
+// ctx is done.
+func StreamEvents(ctx context.Context, svc ProgressReporter, after uint64, extra map[string]interface{}) <-chan Event {
+	stream := make(chan Event)
+	utils.PanicCapturingGo(func() {
+		defer close(stream)
+		for ctx.Err() == nil {
+			pollCtx, cancel := context.WithTimeout(ctx, eventsPollTimeout)
+			events, err := svc.Events(pollCtx, after, extra)
+			timedOut := pollCtx.Err() != nil
+			cancel()
+			if err != nil {
+				// a timed out poll just means there were no events, but anything else deserves a pause before retrying
+				if !timedOut && !utils.SelectContextOrWait(ctx, time.Second) {
+					return
+				}
+				continue
+			}
+			for _, event := range events {
+				select {
+				case <-ctx.Done():
+					return
+				case stream <- event:
+				}
+				after = event.Sequence
+			}
+		}
+	})
+	return stream
+}
+
+// The DoCommand commands clients send to call the methods of a ProgressReporter. Events are polled for by sending the
+// sequence number of the last one seen under the "after" key, or 0 for all those still kept, and extra under the "extra"
+// key. Responses hold the progress under the "progress" key and the events under the "events" key.
+const (
+	ProgressCommand = "progress"
+	EventsCommand   = "events"
+)
+
+func pointToMap(point *geo.Point) map[string]interface{} {
+	return map[string]interface{}{"latitude": point.Lat(), "longitude": point.Lng()}
+}
+
+func pointFromMap(m interface{}) *geo.Point {
+	pointMap, _ := m.(map[string]interface{})
+	lat, _ := pointMap["latitude"].(float64)
+	long, _ := pointMap["longitude"].(float64)
+	return geo.NewPoint(lat, long)
+}
+
+func timeToMap(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func timeFromMap(m interface{}) (time.Time, error) {
+	timeString, _ := m.(string)
+	if timeString == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, timeString)
+}
+
+func progressToMap(progress Progress) map[string]interface{} {
+	m := map[string]interface{}{
+		"mode":             float64(progress.Mode),
+		"distance_to_go_m": progress.DistanceToGoM,
+		"bearing_to_go":    progress.BearingToGo,
+		"eta":              timeToMap(progress.ETA),
+	}
+	if progress.Waypoint != nil {
+		wpMap := behaviorToMap(progress.Waypoint.WaypointBehavior)
+		wpMap["id"] = progress.Waypoint.ID.Hex()
+		wpMap["route_id"] = progress.Waypoint.RouteID.Hex()
+		wpMap["latitude"] = progress.Waypoint.Lat
+		wpMap["longitude"] = progress.Waypoint.Long
+		m["waypoint"] = wpMap
+	}
+	if progress.Leg != nil {
+		m["leg"] = map[string]interface{}{
+			"from":           pointToMap(progress.Leg.From),
+			"to":             pointToMap(progress.Leg.To),
+			"meters_per_sec": progress.Leg.MetersPerSec,
+			"started":        timeToMap(progress.Leg.Started),
+		}
+	}
+	if progress.Home != nil {
+		m["home"] = pointToMap(progress.Home)
+	}
+	return m
+}
+
+func progressFromMap(m interface{}) (Progress, error) {
+	progressMap, ok := m.(map[string]interface{})
+	if !ok {
+		return Progress{}, errors.New("progress is not an object")
+	}
+	var progress Progress
+	mode, _ := progressMap["mode"].(float64)
+	progress.Mode = Mode(mode)
+	progress.DistanceToGoM, _ = progressMap["distance_to_go_m"].(float64)
+	progress.BearingToGo, _ = progressMap["bearing_to_go"].(float64)
+	var err error
+	if progress.ETA, err = timeFromMap(progressMap["eta"]); err != nil {
+		return Progress{}, err
+	}
+	if wpMap, ok := progressMap["waypoint"].(map[string]interface{}); ok {
+		behavior, err := behaviorFromMap(wpMap)
+		if err != nil {
+			return Progress{}, err
+		}
+		wp := Waypoint{WaypointBehavior: behavior}
+		idString, _ := wpMap["id"].(string)
+		if wp.ID, err = primitive.ObjectIDFromHex(idString); err != nil {
+			return Progress{}, err
+		}
+		routeIDString, _ := wpMap["route_id"].(string)
+		if wp.RouteID, err = primitive.ObjectIDFromHex(routeIDString); err != nil {
+			return Progress{}, err
+		}
+		wp.Lat, _ = wpMap["latitude"].(float64)
+		wp.Long, _ = wpMap["longitude"].(float64)
+		progress.Waypoint = &wp
+	}
+	if legMap, ok := progressMap["leg"].(map[string]interface{}); ok {
+		leg := Leg{From: pointFromMap(legMap["from"]), To: pointFromMap(legMap["to"])}
+		leg.MetersPerSec, _ = legMap["meters_per_sec"].(float64)
+		if leg.Started, err = timeFromMap(legMap["started"]); err != nil {
+			return Progress{}, err
+		}
+		progress.Leg = &leg
+	}
+	if progressMap["home"] != nil {
+		progress.Home = pointFromMap(progressMap["home"])
+	}
+	return progress, nil
+}
+
+func eventToMap(event Event) map[string]interface{} {
+	return map[string]interface{}{
+		"sequence":    float64(event.Sequence),
+		"time":        timeToMap(event.Time),
+		"kind":        string(event.Kind),
+		"waypoint_id": event.WaypointID.Hex(),
+		"message":     event.Message,
+	}
+}
+
+func eventFromMap(m interface{}) (Event, error) {
+	eventMap, ok := m.(map[string]interface{})
+	if !ok {
+		return Event{}, errors.New("event is not an object")
+	}
+	var event Event
+	sequence, _ := eventMap["sequence"].(float64)
+	event.Sequence = uint64(sequence)
+	var err error
+	if event.Time, err = timeFromMap(eventMap["time"]); err != nil {
+		return Event{}, err
+	}
+	kind, _ := eventMap["kind"].(string)
+	event.Kind = EventKind(kind)
+	idString, _ := eventMap["waypoint_id"].(string)
+	if event.WaypointID, err = primitive.ObjectIDFromHex(idString); err != nil {
+		return Event{}, err
+	}
+	event.Message, _ = eventMap["message"].(string)
+	return event, nil
+}
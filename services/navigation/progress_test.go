@@ -0,0 +1,82 @@
+package navigation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestEventLog(t *testing.T) {
+	ctx := context.Background()
+	log := navigation.NewEventLog()
+
+	// waiting for events gives up with the context
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := log.Events(timeoutCtx, 0)
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+
+	wpID := primitive.NewObjectID()
+	first := log.Publish(navigation.EventKindModeChange, primitive.NilObjectID, "waypoint")
+	second := log.Publish(navigation.EventKindWaypointReached, wpID, "")
+	test.That(t, first.Sequence, test.ShouldBeGreaterThan, 0)
+	test.That(t, second.Sequence, test.ShouldEqual, first.Sequence+1)
+	events, err := log.Events(ctx, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events, test.ShouldResemble, []navigation.Event{first, second})
+	events, err = log.Events(ctx, first.Sequence)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events, test.ShouldResemble, []navigation.Event{second})
+
+	// waiting for events wakes up on the next one
+	published := make(chan navigation.Event)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		published <- log.Publish(navigation.EventKindLegFailed, wpID, "error moving")
+	}()
+	events, err = log.Events(ctx, second.Sequence)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events, test.ShouldResemble, []navigation.Event{<-published})
+
+	// only the most recent events are kept
+	for i := 0; i < 1000; i++ {
+		log.Publish(navigation.EventKindLegFailed, wpID, "error moving")
+	}
+	events, err = log.Events(ctx, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(events), test.ShouldBeLessThan, 1000)
+	last := events[len(events)-1]
+	test.That(t, last.Sequence, test.ShouldEqual, first.Sequence+1002)
+
+	// a log made later, e.g. after a restart, numbers its events after those of the old one
+	restarted := navigation.NewEventLog().Publish(navigation.EventKindModeChange, primitive.NilObjectID, "manual")
+	test.That(t, restarted.Sequence, test.ShouldBeGreaterThan, last.Sequence)
+}
+
+func TestStreamEvents(t *testing.T) {
+	log := navigation.NewEventLog()
+	svc := &extendedNavigationService{NavigationService: inject.NewNavigationService("nav")}
+	svc.eventsFunc = func(ctx context.Context, after uint64, extra map[string]interface{}) ([]navigation.Event, error) {
+		return log.Events(ctx, after)
+	}
+
+	log.Publish(navigation.EventKindModeChange, primitive.NilObjectID, "waypoint")
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := navigation.StreamEvents(ctx, svc, 0, nil)
+	first := <-stream
+	test.That(t, first.Kind, test.ShouldEqual, navigation.EventKindModeChange)
+	log.Publish(navigation.EventKindObstacleStop, primitive.NewObjectID(), "stopped executing plan")
+	event := <-stream
+	test.That(t, event.Kind, test.ShouldEqual, navigation.EventKindObstacleStop)
+	test.That(t, event.Sequence, test.ShouldEqual, first.Sequence+1)
+
+	cancel()
+	for range stream {
+	}
+}
@@ -0,0 +1,225 @@
+package navigation
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go.viam.com/rdk/resource"
+)
+
+// DefaultArrivalRadiusM is how close, in meters, the robot must get to a waypoint that does not set its own arrival radius.
+const DefaultArrivalRadiusM = 5.
+
+// A Route is a named mission: an ordered list of waypoints, each with its own behavior.
+type Route struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Name      string             `bson:"name"`
+	Waypoints []RouteWaypoint    `bson:"waypoints"`
+}
+
+// A RouteWaypoint is a location on a route along with what to do there.
+type RouteWaypoint struct {
+	Lat              float64 `bson:"latitude"`
+	Long             float64 `bson:"longitude"`
+	WaypointBehavior `bson:",inline"`
+}
+
+// WaypointBehavior describes how the robot approaches and what it does at a waypoint. The zero value drives to the
+// waypoint at the configured speed and moves on as soon as it arrives.
+type WaypointBehavior struct {
+	// ArrivalRadiusM is how close, in meters, the robot must get to the waypoint. Zero means DefaultArrivalRadiusM.
+	ArrivalRadiusM float64 `bson:"arrival_radius_m,omitempty"`
+	// Heading is the compass heading, in degrees, to face on arrival, if any.
+	Heading *float64 `bson:"heading,omitempty"`
+	// Dwell is how long to wait at the waypoint after arriving.
+	Dwell time.Duration `bson:"dwell,omitempty"`
+	// MetersPerSec is the speed to drive to the waypoint at. Zero means the configured speed of the service.
+	MetersPerSec float64 `bson:"meters_per_sec,omitempty"`
+	// Action is run at the waypoint once the robot has arrived and dwelled, if any.
+	Action *WaypointAction `bson:"action,omitempty"`
+}
+
+// A WaypointAction sends a DoCommand to a resource, such as asking a camera to capture an image or a sensor to take a
+// reading.
+type WaypointAction struct {
+	// Resource is the full name of the resource, e.g. rdk:component:camera/cam1.
+	Resource string                 `bson:"resource"`
+	Command  map[string]interface{} `bson:"command"`
+}
+
+// ArrivalRadius returns how close, in meters, the robot must get to the waypoint.
+func (b *WaypointBehavior) ArrivalRadius() float64 {
+	if b.ArrivalRadiusM > 0 {
+		return b.ArrivalRadiusM
+	}
+	return DefaultArrivalRadiusM
+}
+
+// Validate ensures the behavior is within range.
+func (b *WaypointBehavior) Validate() error {
+	if b.ArrivalRadiusM < 0 {
+		return errors.Errorf("arrival radius must not be negative, is %v", b.ArrivalRadiusM)
+	}
+	if b.Heading != nil && (*b.Heading < 0 || *b.Heading >= 360) {
+		return errors.Errorf("heading must be in [0, 360), is %v", *b.Heading)
+	}
+	if b.Dwell < 0 {
+		return errors.Errorf("dwell must not be negative, is %v", b.Dwell)
+	}
+	if b.MetersPerSec < 0 {
+		return errors.Errorf("speed must not be negative, is %v", b.MetersPerSec)
+	}
+	if b.Action != nil {
+		if _, err := resource.NewFromString(b.Action.Resource); err != nil {
+			return errors.Wrap(err, "action")
+		}
+	}
+	return nil
+}
+
+// Validate ensures the route is named, has waypoints, and that every waypoint is valid.
+func (r *Route) Validate() error {
+	if r.Name == "" {
+		return errors.New("route must have a name")
+	}
+	if len(r.Waypoints) == 0 {
+		return errors.Errorf("route %q has no waypoints", r.Name)
+	}
+	for i := range r.Waypoints {
+		if err := r.Waypoints[i].Validate(); err != nil {
+			return errors.Wrapf(err, "route %q waypoint %d", r.Name, i)
+		}
+	}
+	return nil
+}
+
+// The DoCommand commands clients send to call the methods of a RouteNavigator. Routes are sent under the "route" key, the
+// ID of a route to remove, load or export under the "id" key, and extra under the "extra" key. Responses hold the routes
+// under the "routes" key, and otherwise true under the "success" key.
+//
+// ImportRouteCommand adds the route read from the contents of a route file under the "data" key, in the RouteFormat under
+// the "format" key, optionally naming it by the "name" key. ExportRouteCommand responds with the contents of a route file
+// of the route under the "data" key.
+const (
+	RoutesCommand      = "routes"
+	AddRouteCommand    = "add_route"
+	RemoveRouteCommand = "remove_route"
+	LoadRouteCommand   = "load_route"
+	ImportRouteCommand = "import_route"
+	ExportRouteCommand = "export_route"
+)
+
+func routeToMap(route Route) map[string]interface{} {
+	waypoints := make([]interface{}, 0, len(route.Waypoints))
+	for _, wp := range route.Waypoints {
+		wpMap := behaviorToMap(wp.WaypointBehavior)
+		wpMap["latitude"] = wp.Lat
+		wpMap["longitude"] = wp.Long
+		waypoints = append(waypoints, wpMap)
+	}
+	return map[string]interface{}{
+		"id":        route.ID.Hex(),
+		"name":      route.Name,
+		"waypoints": waypoints,
+	}
+}
+
+func routeFromMap(m interface{}) (Route, error) {
+	routeMap, ok := m.(map[string]interface{})
+	if !ok {
+		return Route{}, errors.New("route is not an object")
+	}
+	var route Route
+	if id, _ := routeMap["id"].(string); id != "" && id != primitive.NilObjectID.Hex() {
+		var err error
+		if route.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return Route{}, err
+		}
+	}
+	route.Name, _ = routeMap["name"].(string)
+	waypoints, _ := routeMap["waypoints"].([]interface{})
+	for i, w := range waypoints {
+		wpMap, ok := w.(map[string]interface{})
+		if !ok {
+			return Route{}, errors.Errorf("route waypoint %d is not an object", i)
+		}
+		behavior, err := behaviorFromMap(wpMap)
+		if err != nil {
+			return Route{}, errors.Wrapf(err, "route waypoint %d", i)
+		}
+		lat, _ := wpMap["latitude"].(float64)
+		long, _ := wpMap["longitude"].(float64)
+		route.Waypoints = append(route.Waypoints, RouteWaypoint{Lat: lat, Long: long, WaypointBehavior: behavior})
+	}
+	return route, nil
+}
+
+// behaviorToMap converts a behavior to the properties used both by DoCommand and in route files. Unset fields are left out.
+func behaviorToMap(b WaypointBehavior) map[string]interface{} {
+	m := map[string]interface{}{}
+	if b.ArrivalRadiusM != 0 {
+		m["arrival_radius_m"] = b.ArrivalRadiusM
+	}
+	if b.Heading != nil {
+		m["heading"] = *b.Heading
+	}
+	if b.Dwell != 0 {
+		m["dwell_sec"] = b.Dwell.Seconds()
+	}
+	if b.MetersPerSec != 0 {
+		m["meters_per_sec"] = b.MetersPerSec
+	}
+	if b.Action != nil {
+		m["action"] = map[string]interface{}{"resource": b.Action.Resource, "command": b.Action.Command}
+	}
+	return m
+}
+
+// behaviorFromMap converts the properties used both by DoCommand and in route files to a behavior. Unknown properties
+// are ignored.
+func behaviorFromMap(m map[string]interface{}) (WaypointBehavior, error) {
+	var b WaypointBehavior
+	number := func(key string) (float64, bool, error) {
+		v, ok := m[key]
+		if !ok || v == nil {
+			return 0, false, nil
+		}
+		f, ok := v.(float64)
+		if !ok || math.IsNaN(f) {
+			return 0, false, errors.Errorf("%s must be a number", key)
+		}
+		return f, true, nil
+	}
+	var err error
+	if b.ArrivalRadiusM, _, err = number("arrival_radius_m"); err != nil {
+		return WaypointBehavior{}, err
+	}
+	heading, ok, err := number("heading")
+	if err != nil {
+		return WaypointBehavior{}, err
+	}
+	if ok {
+		b.Heading = &heading
+	}
+	dwell, _, err := number("dwell_sec")
+	if err != nil {
+		return WaypointBehavior{}, err
+	}
+	b.Dwell = time.Duration(dwell * float64(time.Second))
+	if b.MetersPerSec, _, err = number("meters_per_sec"); err != nil {
+		return WaypointBehavior{}, err
+	}
+	if m["action"] != nil {
+		actionMap, ok := m["action"].(map[string]interface{})
+		if !ok {
+			return WaypointBehavior{}, errors.New("action must be an object")
+		}
+		b.Action = &WaypointAction{}
+		b.Action.Resource, _ = actionMap["resource"].(string)
+		b.Action.Command, _ = actionMap["command"].(map[string]interface{})
+	}
+	return b, nil
+}
@@ -0,0 +1,324 @@
+package navigation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+)
+
+// RouteFormat is the format of a route file.
+type RouteFormat string
+
+// The set of known route file formats.
+const (
+	RouteFormatGeoJSON = RouteFormat("geojson")
+	RouteFormatKML     = RouteFormat("kml")
+)
+
+// ReadRoute reads a route from a route file of the given format.
+func ReadRoute(r io.Reader, format RouteFormat) (Route, error) {
+	switch format {
+	case RouteFormatGeoJSON:
+		return ReadGeoJSONRoute(r)
+	case RouteFormatKML:
+		return ReadKMLRoute(r)
+	default:
+		return Route{}, errors.Errorf("unknown route format %q", format)
+	}
+}
+
+// WriteRoute writes a route as a route file of the given format.
+func WriteRoute(w io.Writer, route Route, format RouteFormat) error {
+	switch format {
+	case RouteFormatGeoJSON:
+		return WriteGeoJSONRoute(w, route)
+	case RouteFormatKML:
+		return WriteKMLRoute(w, route)
+	default:
+		return errors.Errorf("unknown route format %q", format)
+	}
+}
+
+// geoJSONObject is the subset of a GeoJSON object needed to read and write routes. Coordinates are kept raw as their shape
+// depends on the type of geometry.
+type geoJSONObject struct {
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name,omitempty"`
+	Features    []*geoJSONObject       `json:"features,omitempty"`
+	Geometry    *geoJSONObject         `json:"geometry,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	Coordinates json.RawMessage        `json:"coordinates,omitempty"`
+}
+
+// ReadGeoJSONRoute reads a route from a GeoJSON FeatureCollection or Feature. Every Point, MultiPoint and LineString becomes
+// waypoints in the order they appear, with the properties of its feature as their behavior, using the same keys as
+// DoCommand, e.g. "arrival_radius_m" and "dwell_sec". The route is named by the "name" member of the collection, if any.
+func ReadGeoJSONRoute(r io.Reader) (Route, error) {
+	var obj geoJSONObject
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return Route{}, err
+	}
+	features := []*geoJSONObject{&obj}
+	if obj.Type == "FeatureCollection" {
+		features = obj.Features
+	}
+	route := Route{Name: obj.Name}
+	for i, feature := range features {
+		if feature.Type != "Feature" || feature.Geometry == nil {
+			return Route{}, errors.Errorf("GeoJSON object %d is a %q, not a feature with a geometry", i, feature.Type)
+		}
+		behavior, err := behaviorFromMap(feature.Properties)
+		if err != nil {
+			return Route{}, errors.Wrapf(err, "GeoJSON feature %d", i)
+		}
+		var positions [][]float64
+		switch feature.Geometry.Type {
+		case "Point":
+			var position []float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &position); err != nil {
+				return Route{}, errors.Wrapf(err, "GeoJSON feature %d", i)
+			}
+			positions = append(positions, position)
+		case "MultiPoint", "LineString":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &positions); err != nil {
+				return Route{}, errors.Wrapf(err, "GeoJSON feature %d", i)
+			}
+		default:
+			return Route{}, errors.Errorf("GeoJSON feature %d has unsupported geometry %q", i, feature.Geometry.Type)
+		}
+		for _, position := range positions {
+			if len(position) < 2 {
+				return Route{}, errors.Errorf("GeoJSON feature %d has a position without a longitude and latitude", i)
+			}
+			route.Waypoints = append(route.Waypoints, RouteWaypoint{Lat: position[1], Long: position[0], WaypointBehavior: behavior})
+		}
+	}
+	return route, nil
+}
+
+// WriteGeoJSONRoute writes a route as a GeoJSON FeatureCollection with a Point feature for each waypoint, in order.
+func WriteGeoJSONRoute(w io.Writer, route Route) error {
+	collection := geoJSONObject{Type: "FeatureCollection", Name: route.Name, Features: []*geoJSONObject{}}
+	for _, wp := range route.Waypoints {
+		coordinates, err := json.Marshal([]float64{wp.Long, wp.Lat})
+		if err != nil {
+			return err
+		}
+		collection.Features = append(collection.Features, &geoJSONObject{
+			Type:       "Feature",
+			Geometry:   &geoJSONObject{Type: "Point", Coordinates: coordinates},
+			Properties: behaviorToMap(wp.WaypointBehavior),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(collection)
+}
+
+// routeFileNumberKeys are the behavior properties that hold numbers.
+var routeFileNumberKeys = []string{"arrival_radius_m", "heading", "dwell_sec", "meters_per_sec"}
+
+// kmlNamespace is the namespace of the KML documents written. Documents without it, or with that of an older version of
+// KML, are read too.
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+// The subset of KML needed to read and write routes.
+type (
+	kmlFile struct {
+		XMLName  xml.Name  `xml:"kml"`
+		Xmlns    string    `xml:"xmlns,attr,omitempty"`
+		Document kmlFolder `xml:"Document"`
+	}
+
+	// kmlFolder is a Document or Folder. Its placemarks are written in order, and its placemarks and subfolders are read
+	// into features in the order they appear.
+	kmlFolder struct {
+		Name       string         `xml:"name,omitempty"`
+		Placemarks []kmlPlacemark `xml:"Placemark"`
+		features   []kmlFeature
+	}
+
+	// kmlFeature is either a placemark or a folder.
+	kmlFeature struct {
+		placemark *kmlPlacemark
+		folder    *kmlFolder
+	}
+
+	kmlPlacemark struct {
+		Name         string       `xml:"name,omitempty"`
+		ExtendedData []kmlData    `xml:"ExtendedData>Data"`
+		Point        *kmlGeometry `xml:"Point"`
+		LineString   *kmlGeometry `xml:"LineString"`
+	}
+
+	kmlData struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value"`
+	}
+
+	kmlGeometry struct {
+		Coordinates string `xml:"coordinates"`
+	}
+)
+
+// UnmarshalXML reads the name of the folder, and its placemarks and subfolders in the order they appear.
+func (f *kmlFolder) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "name":
+				if err := d.DecodeElement(&f.Name, &t); err != nil {
+					return err
+				}
+			case "Placemark":
+				var placemark kmlPlacemark
+				if err := d.DecodeElement(&placemark, &t); err != nil {
+					return err
+				}
+				f.features = append(f.features, kmlFeature{placemark: &placemark})
+			case "Folder", "Document":
+				var folder kmlFolder
+				if err := d.DecodeElement(&folder, &t); err != nil {
+					return err
+				}
+				f.features = append(f.features, kmlFeature{folder: &folder})
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// ReadKMLRoute reads a route from a KML document. Every Point and LineString placemark, including those in folders, becomes
+// waypoints in the order they appear, with the ExtendedData of its placemark as their behavior, using the same keys as
+// DoCommand. An action is given as JSON. The route is named by the document.
+func ReadKMLRoute(r io.Reader) (Route, error) {
+	var file kmlFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return Route{}, err
+	}
+	route := Route{Name: file.Document.Name}
+	var readFolder func(folder *kmlFolder) error
+	readFolder = func(folder *kmlFolder) error {
+		for _, feature := range folder.features {
+			if feature.folder != nil {
+				if err := readFolder(feature.folder); err != nil {
+					return err
+				}
+				continue
+			}
+			wps, err := feature.placemark.waypoints()
+			if err != nil {
+				return errors.Wrapf(err, "KML placemark %q", feature.placemark.Name)
+			}
+			route.Waypoints = append(route.Waypoints, wps...)
+		}
+		return nil
+	}
+	if err := readFolder(&file.Document); err != nil {
+		return Route{}, err
+	}
+	return route, nil
+}
+
+func (p *kmlPlacemark) waypoints() ([]RouteWaypoint, error) {
+	properties := map[string]interface{}{}
+	for _, data := range p.ExtendedData {
+		switch {
+		case data.Name == "action":
+			var action map[string]interface{}
+			if err := json.Unmarshal([]byte(data.Value), &action); err != nil {
+				return nil, errors.Wrap(err, "action")
+			}
+			properties[data.Name] = action
+		case slices.Contains(routeFileNumberKeys, data.Name):
+			value, err := strconv.ParseFloat(strings.TrimSpace(data.Value), 64)
+			if err != nil {
+				return nil, errors.Wrap(err, data.Name)
+			}
+			properties[data.Name] = value
+		}
+	}
+	behavior, err := behaviorFromMap(properties)
+	if err != nil {
+		return nil, err
+	}
+	var coordinates string
+	switch {
+	case p.Point != nil:
+		coordinates = p.Point.Coordinates
+	case p.LineString != nil:
+		coordinates = p.LineString.Coordinates
+	default:
+		return nil, nil
+	}
+	var wps []RouteWaypoint
+	for _, tuple := range strings.Fields(coordinates) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 {
+			return nil, errors.Errorf("coordinates %q do not have a longitude and latitude", tuple)
+		}
+		long, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		lat, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		wps = append(wps, RouteWaypoint{Lat: lat, Long: long, WaypointBehavior: behavior})
+	}
+	return wps, nil
+}
+
+// WriteKMLRoute writes a route as a KML document with a Point placemark for each waypoint, in order.
+func WriteKMLRoute(w io.Writer, route Route) error {
+	file := kmlFile{Xmlns: kmlNamespace, Document: kmlFolder{Name: route.Name}}
+	for i, wp := range route.Waypoints {
+		var data []kmlData
+		properties := behaviorToMap(wp.WaypointBehavior)
+		for _, key := range routeFileNumberKeys {
+			if value, ok := properties[key]; ok {
+				data = append(data, kmlData{Name: key, Value: strconv.FormatFloat(value.(float64), 'f', -1, 64)})
+			}
+		}
+		if action, ok := properties["action"]; ok {
+			actionJSON, err := json.Marshal(action)
+			if err != nil {
+				return err
+			}
+			data = append(data, kmlData{Name: "action", Value: string(actionJSON)})
+		}
+		file.Document.Placemarks = append(file.Document.Placemarks, kmlPlacemark{
+			Name:         strconv.Itoa(i + 1),
+			ExtendedData: data,
+			Point: &kmlGeometry{
+				Coordinates: strconv.FormatFloat(wp.Long, 'f', -1, 64) + "," + strconv.FormatFloat(wp.Lat, 'f', -1, 64),
+			},
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
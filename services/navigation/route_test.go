@@ -0,0 +1,157 @@
+package navigation_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/navigation"
+)
+
+func testRoute() navigation.Route {
+	heading := 90.
+	return navigation.Route{
+		Name: "survey",
+		Waypoints: []navigation.RouteWaypoint{
+			{Lat: 40.7, Long: -73.98},
+			{
+				Lat:  40.701,
+				Long: -73.981,
+				WaypointBehavior: navigation.WaypointBehavior{
+					ArrivalRadiusM: 0.5,
+					Heading:        &heading,
+					Dwell:          1500 * time.Millisecond,
+					MetersPerSec:   0.3,
+					Action: &navigation.WaypointAction{
+						Resource: "rdk:component:camera/cam1",
+						Command:  map[string]interface{}{"capture": true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRouteValidate(t *testing.T) {
+	route := testRoute()
+	test.That(t, route.Validate(), test.ShouldBeNil)
+
+	test.That(t, (&navigation.Route{Waypoints: route.Waypoints}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&navigation.Route{Name: "empty"}).Validate(), test.ShouldNotBeNil)
+
+	heading := 360.
+	route.Waypoints[1].Heading = &heading
+	err := route.Validate()
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "waypoint 1")
+
+	route = testRoute()
+	route.Waypoints[1].Action.Resource = "cam1"
+	test.That(t, route.Validate(), test.ShouldNotBeNil)
+}
+
+func TestRouteFiles(t *testing.T) {
+	t.Run("GeoJSON round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		test.That(t, navigation.WriteGeoJSONRoute(&buf, testRoute()), test.ShouldBeNil)
+		route, err := navigation.ReadGeoJSONRoute(&buf)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, route, test.ShouldResemble, testRoute())
+	})
+
+	t.Run("GeoJSON line string", func(t *testing.T) {
+		route, err := navigation.ReadGeoJSONRoute(strings.NewReader(`{
+			"type": "Feature",
+			"properties": {"name": "transect", "arrival_radius_m": 2},
+			"geometry": {"type": "LineString", "coordinates": [[-73.98, 40.7, 10], [-73.981, 40.701, 10]]}
+		}`))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(route.Waypoints), test.ShouldEqual, 2)
+		test.That(t, route.Waypoints[1].Lat, test.ShouldEqual, 40.701)
+		test.That(t, route.Waypoints[1].Long, test.ShouldEqual, -73.981)
+		test.That(t, route.Waypoints[1].ArrivalRadiusM, test.ShouldEqual, 2)
+
+		_, err = navigation.ReadGeoJSONRoute(strings.NewReader(`{"type": "Feature", "geometry": {"type": "Polygon"}}`))
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("KML round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		test.That(t, navigation.WriteKMLRoute(&buf, testRoute()), test.ShouldBeNil)
+		test.That(t, buf.String(), test.ShouldContainSubstring, `<kml xmlns="http://www.opengis.net/kml/2.2">`)
+		route, err := navigation.ReadKMLRoute(&buf)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, route, test.ShouldResemble, testRoute())
+	})
+
+	t.Run("KML from a GIS tool", func(t *testing.T) {
+		f, err := os.Open("data/survey_route.kml")
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		route, err := navigation.ReadKMLRoute(f)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, route.Name, test.ShouldEqual, "survey")
+		test.That(t, len(route.Waypoints), test.ShouldEqual, 3)
+		test.That(t, route.Waypoints[0].Dwell, test.ShouldEqual, 2500*time.Millisecond)
+		test.That(t, route.Waypoints[2].Lat, test.ShouldEqual, 40.702)
+		test.That(t, route.Waypoints[2].MetersPerSec, test.ShouldEqual, 0.3)
+		test.That(t, route.Waypoints[2].Action.Resource, test.ShouldEqual, "rdk:component:camera/cam1")
+		test.That(t, route.Waypoints[2].Action.Command, test.ShouldResemble, map[string]interface{}{"capture": true})
+	})
+
+	t.Run("KML placemarks and folders in document order", func(t *testing.T) {
+		route, err := navigation.ReadKMLRoute(strings.NewReader(`<kml>
+  <Document>
+    <name>ordered</name>
+    <Folder>
+      <Placemark><Point><coordinates>1,10</coordinates></Point></Placemark>
+      <Folder><Placemark><Point><coordinates>2,20</coordinates></Point></Placemark></Folder>
+    </Folder>
+    <Placemark><Point><coordinates>3,30</coordinates></Point></Placemark>
+    <Folder><Placemark><Point><coordinates>4,40</coordinates></Point></Placemark></Folder>
+  </Document>
+</kml>`))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, route.Name, test.ShouldEqual, "ordered")
+		test.That(t, len(route.Waypoints), test.ShouldEqual, 4)
+		for i, wp := range route.Waypoints {
+			test.That(t, wp.Long, test.ShouldEqual, float64(i+1))
+			test.That(t, wp.Lat, test.ShouldEqual, float64(10*(i+1)))
+		}
+	})
+}
+
+func TestMemoryStoreRoutes(t *testing.T) {
+	ctx := context.Background()
+	store := navigation.NewMemoryNavigationStore()
+
+	_, err := store.AddWaypoint(ctx, geo.NewPoint(40.7, -73.98))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = store.AddRoute(ctx, navigation.Route{Name: "empty"})
+	test.That(t, err, test.ShouldNotBeNil)
+	route, err := store.AddRoute(ctx, testRoute())
+	test.That(t, err, test.ShouldBeNil)
+	routes, err := store.Routes(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, routes, test.ShouldResemble, []navigation.Route{route})
+
+	// loading a route replaces the waypoints still to be visited
+	test.That(t, store.LoadRoute(ctx, route.ID), test.ShouldBeNil)
+	wps, err := store.Waypoints(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(wps), test.ShouldEqual, 2)
+	test.That(t, wps[1].RouteID, test.ShouldEqual, route.ID)
+	test.That(t, wps[1].WaypointBehavior, test.ShouldResemble, route.Waypoints[1].WaypointBehavior)
+
+	test.That(t, store.RemoveRoute(ctx, route.ID), test.ShouldBeNil)
+	routes, err = store.Routes(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, routes, test.ShouldBeEmpty)
+	test.That(t, store.LoadRoute(ctx, route.ID), test.ShouldNotBeNil)
+}
@@ -2,12 +2,14 @@ package navigation
 
 import (
 	"context"
+	"strings"
 
 	geo "github.com/kellydunn/golang-geo"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/navigation/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -150,5 +152,208 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	return protoutils.DoFromResourceServer(ctx, svc, req)
+	cmd := req.GetCommand().AsMap()
+	extra, _ := cmd["extra"].(map[string]interface{})
+	var result map[string]interface{}
+	switch cmd["command"] {
+	case ZonesCommand, AddZoneCommand, RemoveZoneCommand, ZoneViolationCommand:
+		zoner, ok := svc.(ZoneNavigator)
+		if !ok {
+			return protoutils.DoFromResourceServer(ctx, svc, req)
+		}
+		if result, err = server.zoneCommand(ctx, zoner, cmd, extra); err != nil {
+			return nil, err
+		}
+	case RoutesCommand, AddRouteCommand, RemoveRouteCommand, LoadRouteCommand, ImportRouteCommand, ExportRouteCommand:
+		router, ok := svc.(RouteNavigator)
+		if !ok {
+			return protoutils.DoFromResourceServer(ctx, svc, req)
+		}
+		if result, err = server.routeCommand(ctx, router, cmd, extra); err != nil {
+			return nil, err
+		}
+	case ModeCommand:
+		mode, err := svc.Mode(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		result = map[string]interface{}{"mode": mode.String()}
+	case SetModeCommand:
+		name, _ := cmd["mode"].(string)
+		mode, err := modeFromString(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := svc.SetMode(ctx, mode, extra); err != nil {
+			return nil, err
+		}
+		result = map[string]interface{}{}
+	case ProgressCommand, EventsCommand:
+		reporter, ok := svc.(ProgressReporter)
+		if !ok {
+			return protoutils.DoFromResourceServer(ctx, svc, req)
+		}
+		if result, err = server.progressCommand(ctx, reporter, cmd, extra); err != nil {
+			return nil, err
+		}
+	default:
+		return protoutils.DoFromResourceServer(ctx, svc, req)
+	}
+	resp, err := structpb.NewStruct(result)
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: resp}, nil
+}
+
+// zoneCommand serves the zone commands, which is how clients call the methods of services that keep to zones.
+func (server *serviceServer) zoneCommand(
+	ctx context.Context,
+	zoner ZoneNavigator,
+	cmd, extra map[string]interface{},
+) (map[string]interface{}, error) {
+	switch cmd["command"] {
+	case ZonesCommand:
+		zones, err := zoner.Zones(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		zoneMaps := make([]interface{}, 0, len(zones))
+		for _, zone := range zones {
+			zoneMaps = append(zoneMaps, zoneToMap(zone))
+		}
+		return map[string]interface{}{"zones": zoneMaps}, nil
+	case AddZoneCommand:
+		zone, err := zoneFromMap(cmd["zone"])
+		if err != nil {
+			return nil, err
+		}
+		if err := zoner.AddZone(ctx, zone, extra); err != nil {
+			return nil, err
+		}
+	case RemoveZoneCommand:
+		idString, _ := cmd["id"].(string)
+		id, err := primitive.ObjectIDFromHex(idString)
+		if err != nil {
+			return nil, err
+		}
+		if err := zoner.RemoveZone(ctx, id, extra); err != nil {
+			return nil, err
+		}
+	case ZoneViolationCommand:
+		violation, err := zoner.ZoneViolation(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		result := map[string]interface{}{"violation": nil}
+		if violation != nil {
+			result["violation"] = zoneViolationToMap(violation)
+		}
+		return result, nil
+	}
+	return map[string]interface{}{"success": true}, nil
+}
+
+// routeCommand serves the route commands, which is how clients call the methods of services that follow routes and move
+// routes in and out of route files.
+func (server *serviceServer) routeCommand(
+	ctx context.Context,
+	router RouteNavigator,
+	cmd, extra map[string]interface{},
+) (map[string]interface{}, error) {
+	switch cmd["command"] {
+	case RoutesCommand:
+		routes, err := router.Routes(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		routeMaps := make([]interface{}, 0, len(routes))
+		for _, route := range routes {
+			routeMaps = append(routeMaps, routeToMap(route))
+		}
+		return map[string]interface{}{"routes": routeMaps}, nil
+	case AddRouteCommand:
+		route, err := routeFromMap(cmd["route"])
+		if err != nil {
+			return nil, err
+		}
+		if err := router.AddRoute(ctx, route, extra); err != nil {
+			return nil, err
+		}
+	case ImportRouteCommand:
+		format, _ := cmd["format"].(string)
+		data, _ := cmd["data"].(string)
+		route, err := ReadRoute(strings.NewReader(data), RouteFormat(format))
+		if err != nil {
+			return nil, err
+		}
+		if name, _ := cmd["name"].(string); name != "" {
+			route.Name = name
+		}
+		if err := router.AddRoute(ctx, route, extra); err != nil {
+			return nil, err
+		}
+	case ExportRouteCommand:
+		idString, _ := cmd["id"].(string)
+		id, err := primitive.ObjectIDFromHex(idString)
+		if err != nil {
+			return nil, err
+		}
+		routes, err := router.Routes(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routes {
+			if route.ID != id {
+				continue
+			}
+			format, _ := cmd["format"].(string)
+			var data strings.Builder
+			if err := WriteRoute(&data, route, RouteFormat(format)); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"data": data.String()}, nil
+		}
+		return nil, errors.Errorf("no route with id %s", idString)
+	case RemoveRouteCommand, LoadRouteCommand:
+		idString, _ := cmd["id"].(string)
+		id, err := primitive.ObjectIDFromHex(idString)
+		if err != nil {
+			return nil, err
+		}
+		if cmd["command"] == RemoveRouteCommand {
+			err = router.RemoveRoute(ctx, id, extra)
+		} else {
+			err = router.LoadRoute(ctx, id, extra)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{"success": true}, nil
+}
+
+// progressCommand serves the progress commands, which is how clients follow services that report their progress.
+func (server *serviceServer) progressCommand(
+	ctx context.Context,
+	reporter ProgressReporter,
+	cmd, extra map[string]interface{},
+) (map[string]interface{}, error) {
+	if cmd["command"] == ProgressCommand {
+		progress, err := reporter.Progress(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"progress": progressToMap(progress)}, nil
+	}
+	after, _ := cmd["after"].(float64)
+	events, err := reporter.Events(ctx, uint64(after), extra)
+	if err != nil {
+		return nil, err
+	}
+	eventMaps := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		eventMaps = append(eventMaps, eventToMap(event))
+	}
+	return map[string]interface{}{"events": eventMaps}, nil
 }
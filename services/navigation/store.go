@@ -19,6 +19,21 @@ import (
 
 var errNoMoreWaypoints = errors.New("no more waypoints")
 
+// routeWaypoints returns the waypoints to visit for the route, in order.
+func routeWaypoints(route Route) []Waypoint {
+	wps := make([]Waypoint, 0, len(route.Waypoints))
+	for _, routeWp := range route.Waypoints {
+		wps = append(wps, Waypoint{
+			ID:               primitive.NewObjectID(),
+			Lat:              routeWp.Lat,
+			Long:             routeWp.Long,
+			RouteID:          route.ID,
+			WaypointBehavior: routeWp.WaypointBehavior,
+		})
+	}
+	return wps
+}
+
 // NavStore handles the waypoints for a navigation service.
 type NavStore interface {
 	Waypoints(ctx context.Context) ([]Waypoint, error)
@@ -26,6 +41,14 @@ type NavStore interface {
 	RemoveWaypoint(ctx context.Context, id primitive.ObjectID) error
 	NextWaypoint(ctx context.Context) (Waypoint, error)
 	WaypointVisited(ctx context.Context, id primitive.ObjectID) error
+	Zones(ctx context.Context) ([]Zone, error)
+	AddZone(ctx context.Context, zone Zone) (Zone, error)
+	RemoveZone(ctx context.Context, id primitive.ObjectID) error
+	Routes(ctx context.Context) ([]Route, error)
+	AddRoute(ctx context.Context, route Route) (Route, error)
+	RemoveRoute(ctx context.Context, id primitive.ObjectID) error
+	// LoadRoute replaces the waypoints still to be visited with those of the route.
+	LoadRoute(ctx context.Context, id primitive.ObjectID) error
 	Close(ctx context.Context) error
 }
 
@@ -36,6 +59,8 @@ const (
 	StoreTypeMemory = "memory"
 	// StoreTypeMongoDB is the constant for the mongodb store type.
 	StoreTypeMongoDB = "mongodb"
+	// StoreTypeBoltDB is the constant for the boltdb store type, which keeps the store in a local file.
+	StoreTypeBoltDB = "boltdb"
 )
 
 // StoreConfig describes how to configure data storage.
@@ -47,7 +72,7 @@ type StoreConfig struct {
 // Validate ensures all parts of the config are valid.
 func (config *StoreConfig) Validate(path string) error {
 	switch config.Type {
-	case StoreTypeMemory, StoreTypeMongoDB:
+	case StoreTypeMemory, StoreTypeMongoDB, StoreTypeBoltDB:
 	default:
 		return errors.Errorf("unknown store type %q", config.Type)
 	}
@@ -61,6 +86,9 @@ type Waypoint struct {
 	Order   int                `bson:"order"`
 	Lat     float64            `bson:"latitude"`
 	Long    float64            `bson:"longitude"`
+	// RouteID is the route the waypoint was loaded from, if any. Waypoints added one at a time have the zero behavior.
+	RouteID          primitive.ObjectID `bson:"route_id,omitempty"`
+	WaypointBehavior `bson:",inline"`
 }
 
 // ToPoint converts the waypoint to a geo.Point.
@@ -77,6 +105,8 @@ func NewMemoryNavigationStore() *MemoryNavigationStore {
 type MemoryNavigationStore struct {
 	mu        sync.RWMutex
 	waypoints []*Waypoint
+	zones     []Zone
+	routes    []Route
 }
 
 // Waypoints returns a copy of all of the waypoints in the MemoryNavigationStore.
@@ -147,6 +177,110 @@ func (store *MemoryNavigationStore) WaypointVisited(ctx context.Context, id prim
 	return nil
 }
 
+// Zones returns a copy of all of the zones in the MemoryNavigationStore.
+func (store *MemoryNavigationStore) Zones(ctx context.Context) ([]Zone, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	zones := make([]Zone, 0, len(store.zones))
+	for _, zone := range store.zones {
+		zone.Vertices = append([]ZoneVertex(nil), zone.Vertices...)
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+// AddZone adds a zone to the MemoryNavigationStore.
+func (store *MemoryNavigationStore) AddZone(ctx context.Context, zone Zone) (Zone, error) {
+	if err := zone.Validate(); err != nil {
+		return Zone{}, err
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	zone.ID = primitive.NewObjectID()
+	zone.Vertices = append([]ZoneVertex(nil), zone.Vertices...)
+	store.zones = append(store.zones, zone)
+	return zone, nil
+}
+
+// RemoveZone removes a zone from the MemoryNavigationStore.
+func (store *MemoryNavigationStore) RemoveZone(ctx context.Context, id primitive.ObjectID) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	newZones := make([]Zone, 0, len(store.zones))
+	for _, zone := range store.zones {
+		if zone.ID == id {
+			continue
+		}
+		newZones = append(newZones, zone)
+	}
+	store.zones = newZones
+	return nil
+}
+
+// Routes returns a copy of all of the routes in the MemoryNavigationStore.
+func (store *MemoryNavigationStore) Routes(ctx context.Context) ([]Route, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	routes := make([]Route, 0, len(store.routes))
+	for _, route := range store.routes {
+		route.Waypoints = append([]RouteWaypoint(nil), route.Waypoints...)
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// AddRoute adds a route to the MemoryNavigationStore.
+func (store *MemoryNavigationStore) AddRoute(ctx context.Context, route Route) (Route, error) {
+	if err := route.Validate(); err != nil {
+		return Route{}, err
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	route.ID = primitive.NewObjectID()
+	route.Waypoints = append([]RouteWaypoint(nil), route.Waypoints...)
+	store.routes = append(store.routes, route)
+	return route, nil
+}
+
+// RemoveRoute removes a route from the MemoryNavigationStore. Waypoints already loaded from it are kept.
+func (store *MemoryNavigationStore) RemoveRoute(ctx context.Context, id primitive.ObjectID) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	newRoutes := make([]Route, 0, len(store.routes))
+	for _, route := range store.routes {
+		if route.ID == id {
+			continue
+		}
+		newRoutes = append(newRoutes, route)
+	}
+	store.routes = newRoutes
+	return nil
+}
+
+// LoadRoute replaces the waypoints still to be visited in the MemoryNavigationStore with those of the route.
+func (store *MemoryNavigationStore) LoadRoute(ctx context.Context, id primitive.ObjectID) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for _, route := range store.routes {
+		if route.ID != id {
+			continue
+		}
+		newWps := make([]*Waypoint, 0, len(store.waypoints)+len(route.Waypoints))
+		for _, wp := range store.waypoints {
+			if wp.Visited {
+				newWps = append(newWps, wp)
+			}
+		}
+		for _, wp := range routeWaypoints(route) {
+			wp := wp
+			newWps = append(newWps, &wp)
+		}
+		store.waypoints = newWps
+		return nil
+	}
+	return errors.Errorf("no route with id %s", id.Hex())
+}
+
 // Close does nothing.
 func (store *MemoryNavigationStore) Close(ctx context.Context) error {
 	return nil
@@ -157,6 +291,8 @@ var (
 	defaultMongoDBURI                = "mongodb://127.0.0.1:27017"
 	MongoDBNavStoreDBName            = "navigation"
 	MongoDBNavStoreWaypointsCollName = "waypoints"
+	MongoDBNavStoreZonesCollName     = "zones"
+	MongoDBNavStoreRoutesCollName    = "routes"
 	mongoDBNavStoreIndexes           = []mongo.IndexModel{
 		{
 			Keys: bson.D{
@@ -193,13 +329,17 @@ func NewMongoDBNavigationStore(ctx context.Context, config map[string]interface{
 	return &MongoDBNavigationStore{
 		mongoClient:   mongoClient,
 		waypointsColl: waypoints,
+		zonesColl:     mongoClient.Database(MongoDBNavStoreDBName).Collection(MongoDBNavStoreZonesCollName),
+		routesColl:    mongoClient.Database(MongoDBNavStoreDBName).Collection(MongoDBNavStoreRoutesCollName),
 	}, nil
 }
 
-// MongoDBNavigationStore holds the mongodb client and waypoints collection.
+// MongoDBNavigationStore holds the mongodb client and the waypoints, zones and routes collections.
 type MongoDBNavigationStore struct {
 	mongoClient   *mongo.Client
 	waypointsColl *mongo.Collection
+	zonesColl     *mongo.Collection
+	routesColl    *mongo.Collection
 }
 
 // Close closes the connection with the mongodb client.
@@ -269,3 +409,113 @@ func (store *MongoDBNavigationStore) WaypointVisited(ctx context.Context, id pri
 	_, err := store.waypointsColl.UpdateOne(ctx, bson.D{{"_id", id}}, bson.D{{"$set", bson.D{{"visited", true}}}})
 	return err
 }
+
+// Zones returns a copy of all the zones in the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) Zones(ctx context.Context) ([]Zone, error) {
+	cursor, err := store.zonesColl.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Zone
+	if err := cursor.All(ctx, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// AddZone adds a zone to the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) AddZone(ctx context.Context, zone Zone) (Zone, error) {
+	if err := zone.Validate(); err != nil {
+		return Zone{}, err
+	}
+	zone.ID = primitive.NewObjectID()
+	if _, err := store.zonesColl.InsertOne(ctx, zone); err != nil {
+		return Zone{}, err
+	}
+	return zone, nil
+}
+
+// RemoveZone removes a zone from the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) RemoveZone(ctx context.Context, id primitive.ObjectID) error {
+	_, err := store.zonesColl.DeleteOne(ctx, bson.D{{"_id", id}})
+	return err
+}
+
+// Routes returns a copy of all the routes in the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) Routes(ctx context.Context) ([]Route, error) {
+	cursor, err := store.routesColl.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Route
+	if err := cursor.All(ctx, &all); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// AddRoute adds a route to the MongoDBNavigationStore.
+func (store *MongoDBNavigationStore) AddRoute(ctx context.Context, route Route) (Route, error) {
+	if err := route.Validate(); err != nil {
+		return Route{}, err
+	}
+	route.ID = primitive.NewObjectID()
+	if _, err := store.routesColl.InsertOne(ctx, route); err != nil {
+		return Route{}, err
+	}
+	return route, nil
+}
+
+// RemoveRoute removes a route from the MongoDBNavigationStore. Waypoints already loaded from it are kept.
+func (store *MongoDBNavigationStore) RemoveRoute(ctx context.Context, id primitive.ObjectID) error {
+	_, err := store.routesColl.DeleteOne(ctx, bson.D{{"_id", id}})
+	return err
+}
+
+// LoadRoute replaces the waypoints still to be visited in the MongoDBNavigationStore with those of the route.
+func (store *MongoDBNavigationStore) LoadRoute(ctx context.Context, id primitive.ObjectID) error {
+	var route Route
+	if err := store.routesColl.FindOne(ctx, bson.D{{"_id", id}}).Decode(&route); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.Errorf("no route with id %s", id.Hex())
+		}
+		return err
+	}
+	// the waypoints being replaced are found before the route's are inserted, so that waypoints added meanwhile are kept
+	cursor, err := store.waypointsColl.Find(ctx, bson.D{{"visited", false}}, options.Find().SetProjection(bson.D{{"_id", 1}}))
+	if err != nil {
+		return err
+	}
+	var old []Waypoint
+	if err := cursor.All(ctx, &old); err != nil {
+		return err
+	}
+	oldIDs := make([]primitive.ObjectID, 0, len(old))
+	for _, wp := range old {
+		oldIDs = append(oldIDs, wp.ID)
+	}
+
+	// Transactions need a replica set, so the route's waypoints are inserted before the old ones are removed, and a failed
+	// insert leaves the waypoints as they were.
+	wps := routeWaypoints(route)
+	docs := make([]interface{}, 0, len(wps))
+	newIDs := make([]primitive.ObjectID, 0, len(wps))
+	for _, wp := range wps {
+		docs = append(docs, wp)
+		newIDs = append(newIDs, wp.ID)
+	}
+	if len(docs) > 0 {
+		if _, err := store.waypointsColl.InsertMany(ctx, docs); err != nil {
+			_, delErr := store.waypointsColl.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$in", newIDs}}}})
+			return multierr.Combine(err, delErr)
+		}
+	}
+	if len(oldIDs) > 0 {
+		if _, err := store.waypointsColl.DeleteMany(ctx, bson.D{{"_id", bson.D{{"$in", oldIDs}}}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
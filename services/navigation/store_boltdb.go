@@ -0,0 +1,303 @@
+package navigation
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/multierr"
+)
+
+// The bucket names used by the BoltDBNavigationStore.
+var (
+	boltDBNavStoreWaypointsBucket = []byte("waypoints")
+	boltDBNavStoreZonesBucket     = []byte("zones")
+	boltDBNavStoreRoutesBucket    = []byte("routes")
+)
+
+// NewBoltDBNavigationStore creates a new navigation store kept in a single BoltDB file, so that it works without a database
+// server. The file is at the "path" of the config, or by default at ~/.viam/navigation/<name>.db, where name is that of
+// the navigation service using the store, as only one process can have the file open at a time.
+func NewBoltDBNavigationStore(name string, config map[string]interface{}) (*BoltDBNavigationStore, error) {
+	path, ok := config["path"].(string)
+	if !ok {
+		path = filepath.Join(os.Getenv("HOME"), ".viam", "navigation", name+".db")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	// the file is locked while open, so give up rather than wait forever on another process holding it
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot open navigation store %q", path)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{boltDBNavStoreWaypointsBucket, boltDBNavStoreZonesBucket, boltDBNavStoreRoutesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, multierr.Combine(err, db.Close())
+	}
+
+	return &BoltDBNavigationStore{db: db}, nil
+}
+
+// BoltDBNavigationStore holds the waypoints, zones and routes in a BoltDB file. Every change is a transaction that is
+// synced to disk before it returns, so after a crash or loss of power the store holds exactly the changes that returned,
+// and an interrupted mission resumes at the first waypoint that was not yet marked visited.
+type BoltDBNavigationStore struct {
+	db *bolt.DB
+}
+
+// Close closes the BoltDB file.
+func (store *BoltDBNavigationStore) Close(ctx context.Context) error {
+	return store.db.Close()
+}
+
+// Waypoints returns a copy of all the waypoints in the BoltDBNavigationStore that have not been visited.
+func (store *BoltDBNavigationStore) Waypoints(ctx context.Context) ([]Waypoint, error) {
+	var wps []Waypoint
+	err := store.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, boltDBNavStoreWaypointsBucket, func(key []byte, wp Waypoint) (bool, error) {
+			if !wp.Visited {
+				wps = append(wps, wp)
+			}
+			return true, nil
+		})
+	})
+	return wps, err
+}
+
+// AddWaypoint adds a waypoint to the BoltDBNavigationStore.
+func (store *BoltDBNavigationStore) AddWaypoint(ctx context.Context, point *geo.Point) (Waypoint, error) {
+	newPoint := Waypoint{
+		ID:   primitive.NewObjectID(),
+		Lat:  point.Lat(),
+		Long: point.Lng(),
+	}
+	if err := store.db.Update(func(tx *bolt.Tx) error {
+		return boltInsert(tx, boltDBNavStoreWaypointsBucket, newPoint.ID, newPoint)
+	}); err != nil {
+		return Waypoint{}, err
+	}
+	return newPoint, nil
+}
+
+// RemoveWaypoint removes a waypoint from the BoltDBNavigationStore.
+func (store *BoltDBNavigationStore) RemoveWaypoint(ctx context.Context, id primitive.ObjectID) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return boltDelete(tx, boltDBNavStoreWaypointsBucket, id)
+	})
+}
+
+// NextWaypoint gets the next waypoint that has not been visited.
+func (store *BoltDBNavigationStore) NextWaypoint(ctx context.Context) (Waypoint, error) {
+	var next *Waypoint
+	if err := store.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, boltDBNavStoreWaypointsBucket, func(key []byte, wp Waypoint) (bool, error) {
+			if wp.Visited {
+				return true, nil
+			}
+			next = &wp
+			return false, nil
+		})
+	}); err != nil {
+		return Waypoint{}, err
+	}
+	if next == nil {
+		return Waypoint{}, errNoMoreWaypoints
+	}
+	return *next, nil
+}
+
+// WaypointVisited sets that a waypoint has been visited.
+func (store *BoltDBNavigationStore) WaypointVisited(ctx context.Context, id primitive.ObjectID) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return boltForEach(tx, boltDBNavStoreWaypointsBucket, func(key []byte, wp Waypoint) (bool, error) {
+			if wp.ID != id {
+				return true, nil
+			}
+			wp.Visited = true
+			value, err := json.Marshal(wp)
+			if err != nil {
+				return false, err
+			}
+			return false, tx.Bucket(boltDBNavStoreWaypointsBucket).Put(key, value)
+		})
+	})
+}
+
+// Zones returns a copy of all the zones in the BoltDBNavigationStore.
+func (store *BoltDBNavigationStore) Zones(ctx context.Context) ([]Zone, error) {
+	var zones []Zone
+	err := store.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, boltDBNavStoreZonesBucket, func(key []byte, zone Zone) (bool, error) {
+			zones = append(zones, zone)
+			return true, nil
+		})
+	})
+	return zones, err
+}
+
+// AddZone adds a zone to the BoltDBNavigationStore.
+func (store *BoltDBNavigationStore) AddZone(ctx context.Context, zone Zone) (Zone, error) {
+	if err := zone.Validate(); err != nil {
+		return Zone{}, err
+	}
+	zone.ID = primitive.NewObjectID()
+	if err := store.db.Update(func(tx *bolt.Tx) error {
+		return boltInsert(tx, boltDBNavStoreZonesBucket, zone.ID, zone)
+	}); err != nil {
+		return Zone{}, err
+	}
+	return zone, nil
+}
+
+// RemoveZone removes a zone from the BoltDBNavigationStore.
+func (store *BoltDBNavigationStore) RemoveZone(ctx context.Context, id primitive.ObjectID) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return boltDelete(tx, boltDBNavStoreZonesBucket, id)
+	})
+}
+
+// Routes returns a copy of all the routes in the BoltDBNavigationStore.
+func (store *BoltDBNavigationStore) Routes(ctx context.Context) ([]Route, error) {
+	var routes []Route
+	err := store.db.View(func(tx *bolt.Tx) error {
+		return boltForEach(tx, boltDBNavStoreRoutesBucket, func(key []byte, route Route) (bool, error) {
+			routes = append(routes, route)
+			return true, nil
+		})
+	})
+	return routes, err
+}
+
+// AddRoute adds a route to the BoltDBNavigationStore.
+func (store *BoltDBNavigationStore) AddRoute(ctx context.Context, route Route) (Route, error) {
+	if err := route.Validate(); err != nil {
+		return Route{}, err
+	}
+	route.ID = primitive.NewObjectID()
+	if err := store.db.Update(func(tx *bolt.Tx) error {
+		return boltInsert(tx, boltDBNavStoreRoutesBucket, route.ID, route)
+	}); err != nil {
+		return Route{}, err
+	}
+	return route, nil
+}
+
+// RemoveRoute removes a route from the BoltDBNavigationStore. Waypoints already loaded from it are kept.
+func (store *BoltDBNavigationStore) RemoveRoute(ctx context.Context, id primitive.ObjectID) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		return boltDelete(tx, boltDBNavStoreRoutesBucket, id)
+	})
+}
+
+// LoadRoute replaces the waypoints still to be visited in the BoltDBNavigationStore with those of the route. This happens
+// in one transaction, so a crash part way through leaves the previous waypoints in place.
+func (store *BoltDBNavigationStore) LoadRoute(ctx context.Context, id primitive.ObjectID) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		var route *Route
+		if err := boltForEach(tx, boltDBNavStoreRoutesBucket, func(key []byte, r Route) (bool, error) {
+			if r.ID != id {
+				return true, nil
+			}
+			route = &r
+			return false, nil
+		}); err != nil {
+			return err
+		}
+		if route == nil {
+			return errors.Errorf("no route with id %s", id.Hex())
+		}
+
+		var unvisited [][]byte
+		if err := boltForEach(tx, boltDBNavStoreWaypointsBucket, func(key []byte, wp Waypoint) (bool, error) {
+			if !wp.Visited {
+				unvisited = append(unvisited, key)
+			}
+			return true, nil
+		}); err != nil {
+			return err
+		}
+		bucket := tx.Bucket(boltDBNavStoreWaypointsBucket)
+		for _, key := range unvisited {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		for _, wp := range routeWaypoints(*route) {
+			if err := boltInsert(tx, boltDBNavStoreWaypointsBucket, wp.ID, wp); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// boltKey returns the key to store a document under: the next sequence number of the bucket, so that iterating the bucket
+// returns documents in the order they were added, followed by the ID of the document.
+func boltKey(bucket *bolt.Bucket, id primitive.ObjectID) ([]byte, error) {
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 8, 8+len(id))
+	binary.BigEndian.PutUint64(key, seq)
+	return append(key, id[:]...), nil
+}
+
+// boltInsert adds the document to the end of the bucket.
+func boltInsert(tx *bolt.Tx, bucketName []byte, id primitive.ObjectID, doc interface{}) error {
+	bucket := tx.Bucket(bucketName)
+	key, err := boltKey(bucket, id)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, value)
+}
+
+// boltDelete removes the document with the ID from the bucket, if it is there.
+func boltDelete(tx *bolt.Tx, bucketName []byte, id primitive.ObjectID) error {
+	bucket := tx.Bucket(bucketName)
+	cursor := bucket.Cursor()
+	for key, _ := cursor.First(); key != nil; key, _ = cursor.Next() {
+		if bytes.Equal(key[8:], id[:]) {
+			return bucket.Delete(key)
+		}
+	}
+	return nil
+}
+
+// boltForEach decodes each document in the bucket, in the order they were added, and calls fn with it until fn returns
+// false or an error.
+func boltForEach[T any](tx *bolt.Tx, bucketName []byte, fn func(key []byte, doc T) (bool, error)) error {
+	cursor := tx.Bucket(bucketName).Cursor()
+	for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+		var doc T
+		if err := json.Unmarshal(value, &doc); err != nil {
+			return err
+		}
+		more, err := fn(key, doc)
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package navigation_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/navigation"
+)
+
+func TestBoltDBStore(t *testing.T) {
+	ctx := context.Background()
+	config := map[string]interface{}{"path": filepath.Join(t.TempDir(), "nested", "navigation.db")}
+
+	store, err := navigation.NewBoltDBNavigationStore("test", config)
+	test.That(t, err, test.ShouldBeNil)
+	first, err := store.AddWaypoint(ctx, geo.NewPoint(1, 1))
+	test.That(t, err, test.ShouldBeNil)
+	second, err := store.AddWaypoint(ctx, geo.NewPoint(2, 2))
+	test.That(t, err, test.ShouldBeNil)
+	third, err := store.AddWaypoint(ctx, geo.NewPoint(3, 3))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, store.RemoveWaypoint(ctx, third.ID), test.ShouldBeNil)
+	test.That(t, store.WaypointVisited(ctx, first.ID), test.ShouldBeNil)
+	zone, err := store.AddZone(ctx, navigation.Zone{
+		Name:     "pond",
+		Kind:     navigation.ZoneKindKeepOut,
+		Vertices: []navigation.ZoneVertex{{Lat: 0, Long: 0}, {Lat: 0, Long: 1}, {Lat: 1, Long: 0}},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	route, err := store.AddRoute(ctx, testRoute())
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, store.Close(ctx), test.ShouldBeNil)
+
+	// after a restart, navigation resumes at the first waypoint that was not visited
+	store, err = navigation.NewBoltDBNavigationStore("test", config)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, store.Close(ctx), test.ShouldBeNil)
+	}()
+	next, err := store.NextWaypoint(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, next, test.ShouldResemble, second)
+	wps, err := store.Waypoints(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, wps, test.ShouldResemble, []navigation.Waypoint{second})
+	zones, err := store.Zones(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, zones, test.ShouldResemble, []navigation.Zone{zone})
+	routes, err := store.Routes(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, routes, test.ShouldResemble, []navigation.Route{route})
+
+	test.That(t, store.LoadRoute(ctx, route.ID), test.ShouldBeNil)
+	wps, err = store.Waypoints(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(wps), test.ShouldEqual, 2)
+	test.That(t, wps[0].RouteID, test.ShouldEqual, route.ID)
+	test.That(t, wps[1].WaypointBehavior, test.ShouldResemble, route.Waypoints[1].WaypointBehavior)
+	test.That(t, store.WaypointVisited(ctx, wps[0].ID), test.ShouldBeNil)
+	test.That(t, store.WaypointVisited(ctx, wps[1].ID), test.ShouldBeNil)
+	_, err = store.NextWaypoint(ctx)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, store.RemoveZone(ctx, zone.ID), test.ShouldBeNil)
+	test.That(t, store.RemoveRoute(ctx, route.ID), test.ShouldBeNil)
+	zones, err = store.Zones(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, zones, test.ShouldBeEmpty)
+	test.That(t, store.LoadRoute(ctx, route.ID), test.ShouldNotBeNil)
+}
+
+func TestBoltDBStoreDefaultPath(t *testing.T) {
+	ctx := context.Background()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// navigation services each have a file of their own, so they can all have their stores open at once
+	first, err := navigation.NewBoltDBNavigationStore("nav1", map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, first.Close(ctx), test.ShouldBeNil)
+	}()
+	second, err := navigation.NewBoltDBNavigationStore("nav2", map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, second.Close(ctx), test.ShouldBeNil)
+	}()
+	for _, name := range []string{"nav1.db", "nav2.db"} {
+		_, err := os.Stat(filepath.Join(home, ".viam", "navigation", name))
+		test.That(t, err, test.ShouldBeNil)
+	}
+}
@@ -0,0 +1,277 @@
+package navigation
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ZoneKind describes how a zone restricts where the robot may go.
+type ZoneKind string
+
+// The set of known zone kinds.
+const (
+	// ZoneKindGeofence is an allowed operating area. Once any geofence exists, the robot must stay within one of them.
+	ZoneKindGeofence = ZoneKind("geofence")
+	// ZoneKindKeepOut is an area the robot must never enter.
+	ZoneKindKeepOut = ZoneKind("keep_out")
+)
+
+// zoneWallHeightMm is how far above and below the ground the walls of a zone reach when it is an obstacle for planning.
+const zoneWallHeightMm = 10000.
+
+// zoneWallThicknessMm is how thick the walls of a zone are when it is an obstacle for planning.
+const zoneWallThicknessMm = 100.
+
+// A Zone is a polygon on the globe that restricts where the robot may go.
+type Zone struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Name     string             `bson:"name"`
+	Kind     ZoneKind           `bson:"kind"`
+	Vertices []ZoneVertex       `bson:"vertices"`
+}
+
+// A ZoneVertex is a corner of the polygon of a zone.
+type ZoneVertex struct {
+	Lat  float64 `bson:"latitude"`
+	Long float64 `bson:"longitude"`
+}
+
+// Validate ensures the zone is of a known kind and is a polygon.
+func (z *Zone) Validate() error {
+	switch z.Kind {
+	case ZoneKindGeofence, ZoneKindKeepOut:
+	default:
+		return errors.Errorf("unknown zone kind %q", z.Kind)
+	}
+	if len(z.Vertices) < 3 {
+		return errors.Errorf("zone must have at least 3 vertices, has %d", len(z.Vertices))
+	}
+	return nil
+}
+
+// Contains returns whether the point is within the polygon of the zone. Zones are small enough that their edges are
+// treated as straight lines of latitude and longitude.
+func (z *Zone) Contains(point *geo.Point) bool {
+	inside := false
+	for i, j := 0, len(z.Vertices)-1; i < len(z.Vertices); j, i = i, i+1 {
+		vi, vj := z.Vertices[i], z.Vertices[j]
+		if (vi.Lat > point.Lat()) != (vj.Lat > point.Lat()) &&
+			point.Lng() < (vj.Long-vi.Long)*(point.Lat()-vi.Lat)/(vj.Lat-vi.Lat)+vi.Long {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Crosses returns whether the straight line between the two points crosses an edge of the polygon of the zone.
+func (z *Zone) Crosses(from, to *geo.Point) bool {
+	for i, j := 0, len(z.Vertices)-1; i < len(z.Vertices); j, i = i, i+1 {
+		if segmentsIntersect(
+			from.Lat(), from.Lng(), to.Lat(), to.Lng(),
+			z.Vertices[j].Lat, z.Vertices[j].Long, z.Vertices[i].Lat, z.Vertices[i].Long,
+		) {
+			return true
+		}
+	}
+	return false
+}
+
+// GeoObstacle returns the walls of the zone as an obstacle, a thin box along each edge of its polygon, so that plans never
+// cross the edges.
+func (z *Zone) GeoObstacle() (*spatialmath.GeoObstacle, error) {
+	location := geo.NewPoint(z.Vertices[0].Lat, z.Vertices[0].Long)
+	origin := spatialmath.GeoPointToPose(location).Point()
+	corners := make([]r3.Vector, 0, len(z.Vertices))
+	for _, v := range z.Vertices {
+		corner := spatialmath.GeoPointToPose(geo.NewPoint(v.Lat, v.Long)).Point().Sub(origin)
+		corners = append(corners, r3.Vector{X: corner.X, Y: corner.Y})
+	}
+	walls := make([]spatialmath.Geometry, 0, len(corners))
+	for i, from := range corners {
+		edge := corners[(i+1)%len(corners)].Sub(from)
+		center := spatialmath.NewPose(from.Add(edge.Mul(0.5)), &spatialmath.EulerAngles{Yaw: math.Atan2(edge.Y, edge.X)})
+		dims := r3.Vector{X: edge.Norm(), Y: zoneWallThicknessMm, Z: 2 * zoneWallHeightMm}
+		wall, err := spatialmath.NewBox(center, dims, fmt.Sprintf("%s_wall_%d", z.label(), i))
+		if err != nil {
+			return nil, err
+		}
+		walls = append(walls, wall)
+	}
+	return spatialmath.NewGeoObstacle(location, walls), nil
+}
+
+func (z *Zone) label() string {
+	if z.Name != "" {
+		return z.Name
+	}
+	return z.ID.Hex()
+}
+
+// A ZoneViolation reports that the robot was found outside every geofence or inside a keep-out zone.
+type ZoneViolation struct {
+	Time     time.Time
+	Location *geo.Point
+	// Zone is the keep-out zone the robot was inside, or nil if it was outside every geofence.
+	Zone *Zone
+}
+
+func (v *ZoneViolation) Error() string {
+	if v.Zone != nil {
+		return fmt.Sprintf("location %v, %v is inside keep-out zone %q", v.Location.Lat(), v.Location.Lng(), v.Zone.label())
+	}
+	return fmt.Sprintf("location %v, %v is outside every geofence", v.Location.Lat(), v.Location.Lng())
+}
+
+// CheckZones returns a violation if the point is inside any of the keep-out zones, or there are geofences and the point is
+// outside all of them.
+func CheckZones(zones []Zone, point *geo.Point) *ZoneViolation {
+	hasGeofence, inGeofence := false, false
+	for i := range zones {
+		zone := &zones[i]
+		switch zone.Kind {
+		case ZoneKindKeepOut:
+			if zone.Contains(point) {
+				return &ZoneViolation{Time: time.Now(), Location: point, Zone: zone}
+			}
+		case ZoneKindGeofence:
+			hasGeofence = true
+			inGeofence = inGeofence || zone.Contains(point)
+		}
+	}
+	if hasGeofence && !inGeofence {
+		return &ZoneViolation{Time: time.Now(), Location: point}
+	}
+	return nil
+}
+
+// CheckLeg returns an error if driving straight between the two points would enter a keep-out zone or leave the geofences.
+// A leg that starts within a geofence must lie wholly within one, while a leg that starts outside every geofence may drive
+// back into one, so that a robot which has strayed can recover.
+func CheckLeg(zones []Zone, from, to *geo.Point) error {
+	if violation := CheckZones(zones, to); violation != nil {
+		return violation
+	}
+	startsInGeofence, inGeofence := false, false
+	for i := range zones {
+		zone := &zones[i]
+		switch zone.Kind {
+		case ZoneKindKeepOut:
+			if zone.Crosses(from, to) {
+				return errors.Errorf("leg to %v, %v crosses keep-out zone %q", to.Lat(), to.Lng(), zone.label())
+			}
+		case ZoneKindGeofence:
+			startsInGeofence = startsInGeofence || zone.Contains(from)
+			inGeofence = inGeofence || (zone.Contains(from) && !zone.Crosses(from, to))
+		}
+	}
+	if startsInGeofence && !inGeofence {
+		return errors.Errorf("leg to %v, %v leaves the geofences", to.Lat(), to.Lng())
+	}
+	return nil
+}
+
+// segmentsIntersect returns whether the segment from (x1, y1) to (x2, y2) intersects the segment from (x3, y3) to (x4, y4).
+func segmentsIntersect(x1, y1, x2, y2, x3, y3, x4, y4 float64) bool {
+	orientation := func(ax, ay, bx, by, cx, cy float64) float64 {
+		return (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+	}
+	d1 := orientation(x3, y3, x4, y4, x1, y1)
+	d2 := orientation(x3, y3, x4, y4, x2, y2)
+	d3 := orientation(x1, y1, x2, y2, x3, y3)
+	d4 := orientation(x1, y1, x2, y2, x4, y4)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+// The DoCommand commands clients send to call the methods of a ZoneNavigator. Zones are sent under the "zone" key, the ID of
+// a zone to remove under the "id" key, and extra under the "extra" key. Responses hold the zones under the "zones" key, the
+// violation, if any, under the "violation" key, and otherwise true under the "success" key.
+const (
+	ZonesCommand         = "zones"
+	AddZoneCommand       = "add_zone"
+	RemoveZoneCommand    = "remove_zone"
+	ZoneViolationCommand = "zone_violation"
+)
+
+func zoneToMap(zone Zone) map[string]interface{} {
+	vertices := make([]interface{}, 0, len(zone.Vertices))
+	for _, v := range zone.Vertices {
+		vertices = append(vertices, map[string]interface{}{"latitude": v.Lat, "longitude": v.Long})
+	}
+	return map[string]interface{}{
+		"id":       zone.ID.Hex(),
+		"name":     zone.Name,
+		"kind":     string(zone.Kind),
+		"vertices": vertices,
+	}
+}
+
+func zoneFromMap(m interface{}) (Zone, error) {
+	zoneMap, ok := m.(map[string]interface{})
+	if !ok {
+		return Zone{}, errors.New("zone is not an object")
+	}
+	var zone Zone
+	if id, _ := zoneMap["id"].(string); id != "" && id != primitive.NilObjectID.Hex() {
+		var err error
+		if zone.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return Zone{}, err
+		}
+	}
+	zone.Name, _ = zoneMap["name"].(string)
+	kind, _ := zoneMap["kind"].(string)
+	zone.Kind = ZoneKind(kind)
+	vertices, _ := zoneMap["vertices"].([]interface{})
+	for i, v := range vertices {
+		vertex, ok := v.(map[string]interface{})
+		if !ok {
+			return Zone{}, errors.Errorf("zone vertex %d is not an object", i)
+		}
+		lat, _ := vertex["latitude"].(float64)
+		long, _ := vertex["longitude"].(float64)
+		zone.Vertices = append(zone.Vertices, ZoneVertex{Lat: lat, Long: long})
+	}
+	return zone, nil
+}
+
+func zoneViolationToMap(violation *ZoneViolation) map[string]interface{} {
+	m := map[string]interface{}{
+		"time":      violation.Time.Format(time.RFC3339Nano),
+		"latitude":  violation.Location.Lat(),
+		"longitude": violation.Location.Lng(),
+	}
+	if violation.Zone != nil {
+		m["zone"] = zoneToMap(*violation.Zone)
+	}
+	return m
+}
+
+func zoneViolationFromMap(m interface{}) (*ZoneViolation, error) {
+	violationMap, ok := m.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("zone violation is not an object")
+	}
+	timeString, _ := violationMap["time"].(string)
+	violationTime, err := time.Parse(time.RFC3339Nano, timeString)
+	if err != nil {
+		return nil, err
+	}
+	lat, _ := violationMap["latitude"].(float64)
+	long, _ := violationMap["longitude"].(float64)
+	violation := &ZoneViolation{Time: violationTime, Location: geo.NewPoint(lat, long)}
+	if violationMap["zone"] != nil {
+		zone, err := zoneFromMap(violationMap["zone"])
+		if err != nil {
+			return nil, err
+		}
+		violation.Zone = &zone
+	}
+	return violation, nil
+}
@@ -0,0 +1,94 @@
+package navigation_test
+
+import (
+	"testing"
+
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/services/navigation"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestZones(t *testing.T) {
+	square := []navigation.ZoneVertex{{Lat: 0, Long: 0}, {Lat: 0, Long: 0.01}, {Lat: 0.01, Long: 0.01}, {Lat: 0.01, Long: 0}}
+	geofence := navigation.Zone{Name: "yard", Kind: navigation.ZoneKindGeofence, Vertices: square}
+	keepOut := navigation.Zone{
+		Name:     "pond",
+		Kind:     navigation.ZoneKindKeepOut,
+		Vertices: []navigation.ZoneVertex{{Lat: 0.004, Long: 0.004}, {Lat: 0.004, Long: 0.006}, {Lat: 0.006, Long: 0.005}},
+	}
+	zones := []navigation.Zone{geofence, keepOut}
+
+	t.Run("validate", func(t *testing.T) {
+		test.That(t, geofence.Validate(), test.ShouldBeNil)
+		test.That(t, (&navigation.Zone{Kind: "moat", Vertices: square}).Validate(), test.ShouldNotBeNil)
+		test.That(t, (&navigation.Zone{Kind: navigation.ZoneKindKeepOut, Vertices: square[:2]}).Validate(), test.ShouldNotBeNil)
+	})
+
+	t.Run("contains and crosses", func(t *testing.T) {
+		test.That(t, geofence.Contains(geo.NewPoint(0.005, 0.005)), test.ShouldBeTrue)
+		test.That(t, geofence.Contains(geo.NewPoint(0.02, 0.005)), test.ShouldBeFalse)
+		test.That(t, geofence.Crosses(geo.NewPoint(0.005, 0.005), geo.NewPoint(0.02, 0.005)), test.ShouldBeTrue)
+		test.That(t, geofence.Crosses(geo.NewPoint(0.002, 0.002), geo.NewPoint(0.008, 0.002)), test.ShouldBeFalse)
+	})
+
+	t.Run("check zones", func(t *testing.T) {
+		test.That(t, navigation.CheckZones(nil, geo.NewPoint(1, 1)), test.ShouldBeNil)
+		test.That(t, navigation.CheckZones(zones, geo.NewPoint(0.002, 0.002)), test.ShouldBeNil)
+
+		violation := navigation.CheckZones(zones, geo.NewPoint(0.02, 0.002))
+		test.That(t, violation, test.ShouldNotBeNil)
+		test.That(t, violation.Zone, test.ShouldBeNil)
+		test.That(t, violation.Error(), test.ShouldContainSubstring, "outside every geofence")
+
+		violation = navigation.CheckZones(zones, geo.NewPoint(0.0045, 0.005))
+		test.That(t, violation, test.ShouldNotBeNil)
+		test.That(t, violation.Zone.Name, test.ShouldEqual, "pond")
+	})
+
+	t.Run("check leg", func(t *testing.T) {
+		start := geo.NewPoint(0.002, 0.005)
+		test.That(t, navigation.CheckLeg(zones, start, geo.NewPoint(0.002, 0.008)), test.ShouldBeNil)
+		// the pond lies between the start and the destination
+		err := navigation.CheckLeg(zones, start, geo.NewPoint(0.008, 0.005))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "pond")
+		test.That(t, navigation.CheckLeg(zones, start, geo.NewPoint(0.02, 0.005)), test.ShouldNotBeNil)
+
+		// a robot outside the yard may drive back into it, but not through the pond
+		outside := geo.NewPoint(0.005, -0.002)
+		test.That(t, navigation.CheckLeg(zones, outside, geo.NewPoint(0.005, 0.002)), test.ShouldBeNil)
+		test.That(t, navigation.CheckLeg(zones, outside, geo.NewPoint(0.005, 0.008)), test.ShouldNotBeNil)
+		test.That(t, navigation.CheckLeg(zones, outside, geo.NewPoint(0.005, -0.004)), test.ShouldNotBeNil)
+
+		// a leg between two geofences must not leave them on the way
+		field := navigation.Zone{
+			Name:     "field",
+			Kind:     navigation.ZoneKindGeofence,
+			Vertices: []navigation.ZoneVertex{{Lat: 0, Long: 0.02}, {Lat: 0, Long: 0.03}, {Lat: 0.01, Long: 0.03}, {Lat: 0.01, Long: 0.02}},
+		}
+		err = navigation.CheckLeg(append(zones, field), start, geo.NewPoint(0.002, 0.025))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "leaves the geofences")
+	})
+
+	t.Run("geo obstacle", func(t *testing.T) {
+		obstacle, err := geofence.GeoObstacle()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, obstacle.Location(), test.ShouldResemble, geo.NewPoint(0, 0))
+		test.That(t, len(obstacle.Geometries()), test.ShouldEqual, 4)
+		test.That(t, obstacle.Geometries()[0].Label(), test.ShouldEqual, "yard_wall_0")
+		// the walls stand on the edges of the yard and are thin, so that the inside of the yard stays free
+		inside := spatialmath.NewPoint(spatialmath.GeoPointToPose(geo.NewPoint(0.005, 0.005)).Point(), "")
+		onEdge := spatialmath.NewPoint(spatialmath.GeoPointToPose(geo.NewPoint(0, 0.005)).Point(), "")
+		collides, err := obstacle.Geometries()[0].CollidesWith(onEdge)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+		for _, wall := range obstacle.Geometries() {
+			collides, err := wall.CollidesWith(inside)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldBeFalse)
+		}
+	})
+}
@@ -157,6 +157,12 @@ func (b *box) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxCollision(other.position, b), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return other.CollidesWith(b)
+	}
+	if other, ok := g.(*mesh); ok {
+		return other.CollidesWith(b)
+	}
 	return true, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -173,6 +179,12 @@ func (b *box) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxDistance(other.position, b), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return convexDistance(boxShape(b), cylinderShape(other)), nil
+	}
+	if other, ok := g.(*mesh); ok {
+		return other.DistanceFrom(b)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -186,9 +198,15 @@ func (b *box) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*capsule); ok {
 		return boxInCapsule(b, other), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return boxInCylinder(b, other), nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return false, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -378,6 +396,16 @@ func boxInCapsule(b *box, c *capsule) bool {
 	return true
 }
 
+// boxInCylinder returns a bool describing if the given box is fully encompassed by the given cylinder.
+func boxInCylinder(b *box, c *cylinder) bool {
+	for _, vertex := range b.vertices() {
+		if cylinderVsPointDistance(c, vertex) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // separatingAxisTest projects two boxes onto the given plane and compute how much distance is between them along
 // this plane.  Per the separating hyperplane theorem, if such a plane exists (and a positive number is returned)
 // this proves that there is no collision between the boxes
@@ -149,6 +149,9 @@ func (c *capsule) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*box); ok {
 		return capsuleVsBoxCollision(c, other), nil
 	}
+	if other, ok := g.(*mesh); ok {
+		return other.CollidesWith(c)
+	}
 	dist, err := c.DistanceFrom(g)
 	if err != nil {
 		return true, err
@@ -170,6 +173,12 @@ func (c *capsule) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleVsSphereDistance(c, other), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return convexDistance(segmentShape(c.segA, c.segB), cylinderShape(other)) - c.radius, nil
+	}
+	if other, ok := g.(*mesh); ok {
+		return other.DistanceFrom(c)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
 }
 
@@ -183,9 +192,15 @@ func (c *capsule) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleInSphere(c, other), nil
 	}
+	if other, ok := g.(*cylinder); ok {
+		return cylinderVsPointDistance(other, c.segA) <= -c.radius && cylinderVsPointDistance(other, c.segB) <= -c.radius, nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
 	return true, newCollisionTypeUnsupportedError(c, g)
 }
 
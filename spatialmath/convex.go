@@ -0,0 +1,210 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+const (
+	gjkMaxIterations = 64
+	gjkTolerance     = 1e-9
+)
+
+// convexShape describes a convex collision geometry by its support function, which returns the point of the shape that is
+// furthest in a given direction. Its center and axes are used to estimate penetration depth.
+type convexShape struct {
+	support func(dir r3.Vector) r3.Vector
+	center  r3.Vector
+	axes    []r3.Vector
+}
+
+func boxShape(b *box) convexShape {
+	rm := b.rotationMatrix()
+	axes := []r3.Vector{rm.Row(0), rm.Row(1), rm.Row(2)}
+	center := b.pose.Point()
+	return convexShape{
+		support: func(dir r3.Vector) r3.Vector {
+			pt := center
+			for i, axis := range axes {
+				if dir.Dot(axis) >= 0 {
+					pt = pt.Add(axis.Mul(b.halfSize[i]))
+				} else {
+					pt = pt.Sub(axis.Mul(b.halfSize[i]))
+				}
+			}
+			return pt
+		},
+		center: center,
+		axes:   axes,
+	}
+}
+
+func cylinderShape(c *cylinder) convexShape {
+	halfLength := c.axis.Mul(c.length / 2)
+	return convexShape{
+		support: func(dir r3.Vector) r3.Vector {
+			pt := c.center.Add(halfLength)
+			along := dir.Dot(c.axis)
+			if along < 0 {
+				pt = c.center.Sub(halfLength)
+			}
+			if radial := dir.Sub(c.axis.Mul(along)); radial.Norm() > gjkTolerance {
+				pt = pt.Add(radial.Normalize().Mul(c.radius))
+			}
+			return pt
+		},
+		center: c.center,
+		axes:   []r3.Vector{c.axis},
+	}
+}
+
+// segmentShape describes the line segment at the core of a capsule, whose radius must be accounted for separately.
+func segmentShape(p0, p1 r3.Vector) convexShape {
+	return convexShape{
+		support: func(dir r3.Vector) r3.Vector {
+			if dir.Dot(p1) > dir.Dot(p0) {
+				return p1
+			}
+			return p0
+		},
+		center: p0.Add(p1).Mul(0.5),
+		axes:   []r3.Vector{p1.Sub(p0)},
+	}
+}
+
+func triangleShape(t *triangle) convexShape {
+	return convexShape{
+		support: func(dir r3.Vector) r3.Vector {
+			best := t.p0
+			if dir.Dot(t.p1) > dir.Dot(best) {
+				best = t.p1
+			}
+			if dir.Dot(t.p2) > dir.Dot(best) {
+				best = t.p2
+			}
+			return best
+		},
+		center: t.p0.Add(t.p1).Add(t.p2).Mul(1. / 3),
+		axes:   []r3.Vector{t.normal, t.p1.Sub(t.p0), t.p2.Sub(t.p1), t.p0.Sub(t.p2)},
+	}
+}
+
+// convexDistance returns the separation distance between two convex shapes using the Gilbert-Johnson-Keerthi algorithm.
+// If the shapes intersect, the negative of their penetration depth is returned instead. Penetration depth is estimated by
+// projecting the shapes onto a set of candidate axes, so it may overestimate the true depth.
+func convexDistance(a, b convexShape) float64 {
+	minkowski := func(dir r3.Vector) r3.Vector {
+		return a.support(dir).Sub(b.support(dir.Mul(-1)))
+	}
+	v := minkowski(b.center.Sub(a.center))
+	simplex := []r3.Vector{v}
+	for i := 0; i < gjkMaxIterations; i++ {
+		vv := v.Norm2()
+		if vv <= gjkTolerance*gjkTolerance {
+			break
+		}
+		w := minkowski(v.Mul(-1))
+		// stop once the new support point does not bring the simplex meaningfully closer to the origin
+		if vv-v.Dot(w) <= gjkTolerance*math.Max(vv, 1) {
+			return math.Sqrt(vv)
+		}
+		simplex = append(simplex, w)
+		var inside bool
+		v, simplex, inside = closestSimplexPoint(simplex)
+		if inside {
+			break
+		}
+	}
+	if dist := v.Norm(); dist > gjkTolerance {
+		return dist
+	}
+	return -penetrationDepth(a, b)
+}
+
+// closestSimplexPoint returns the point of the simplex closest to the origin and the smallest subset of the simplex that
+// contains it. If the simplex is a tetrahedron containing the origin, the origin is returned along with true.
+func closestSimplexPoint(simplex []r3.Vector) (r3.Vector, []r3.Vector, bool) {
+	switch len(simplex) {
+	case 1:
+		return simplex[0], simplex, false
+	case 2:
+		return ClosestPointSegmentPoint(simplex[0], simplex[1], r3.Vector{}), simplex, false
+	case 3:
+		t := &triangle{p0: simplex[0], p1: simplex[1], p2: simplex[2]}
+		return t.closestPointToPoint(r3.Vector{}), simplex, false
+	default:
+		if tetrahedronContainsOrigin(simplex) {
+			return r3.Vector{}, simplex, true
+		}
+		var best r3.Vector
+		var bestFace []r3.Vector
+		bestDist := math.Inf(1)
+		for skip := range simplex {
+			face := make([]r3.Vector, 0, 3)
+			for i, pt := range simplex {
+				if i != skip {
+					face = append(face, pt)
+				}
+			}
+			t := &triangle{p0: face[0], p1: face[1], p2: face[2]}
+			if pt := t.closestPointToPoint(r3.Vector{}); pt.Norm2() < bestDist {
+				best, bestFace, bestDist = pt, face, pt.Norm2()
+			}
+		}
+		return best, bestFace, false
+	}
+}
+
+// tetrahedronContainsOrigin returns whether the origin is within the tetrahedron with the four given vertices.
+func tetrahedronContainsOrigin(pts []r3.Vector) bool {
+	faces := [4][4]int{{0, 1, 2, 3}, {0, 1, 3, 2}, {0, 2, 3, 1}, {1, 2, 3, 0}}
+	for _, f := range faces {
+		normal := pts[f[1]].Sub(pts[f[0]]).Cross(pts[f[2]].Sub(pts[f[0]]))
+		opposite := normal.Dot(pts[f[3]].Sub(pts[f[0]]))
+		if math.Abs(opposite) <= gjkTolerance {
+			// degenerate tetrahedron
+			return false
+		}
+		if origin := normal.Dot(pts[f[0]].Mul(-1)); origin*opposite < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// penetrationDepth returns the smallest overlap of the projections of two intersecting convex shapes onto their axes, the
+// cross products of their axes, and the direction between their centers.
+func penetrationDepth(a, b convexShape) float64 {
+	centers := b.center.Sub(a.center)
+	candidates := []r3.Vector{centers}
+	candidates = append(candidates, a.axes...)
+	candidates = append(candidates, b.axes...)
+	for _, axisA := range a.axes {
+		// the component of the direction between centers which is perpendicular to an axis is the best direction in which
+		// to separate a shape from a curved surface around that axis, such as a cylinder's
+		candidates = append(candidates, centers.Sub(axisA.Mul(centers.Dot(axisA)/math.Max(axisA.Norm2(), gjkTolerance))))
+		for _, axisB := range b.axes {
+			candidates = append(candidates, axisA.Cross(axisB))
+		}
+	}
+	for _, axisB := range b.axes {
+		candidates = append(candidates, centers.Sub(axisB.Mul(centers.Dot(axisB)/math.Max(axisB.Norm2(), gjkTolerance))))
+	}
+
+	depth := math.Inf(1)
+	for _, axis := range candidates {
+		norm := axis.Norm()
+		if norm < floatEpsilon {
+			continue
+		}
+		axis = axis.Mul(1 / norm)
+		forward := a.support(axis).Dot(axis) - b.support(axis.Mul(-1)).Dot(axis)
+		backward := b.support(axis).Dot(axis) - a.support(axis.Mul(-1)).Dot(axis)
+		depth = math.Min(depth, math.Min(forward, backward))
+	}
+	if math.IsInf(depth, 1) || depth < 0 {
+		return 0
+	}
+	return depth
+}
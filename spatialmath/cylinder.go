@@ -0,0 +1,267 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/utils"
+)
+
+// Number of points sampled around each rim of a cylinder when checking whether it is encompassed by a curved geometry.
+const cylinderRimSamples = 32
+
+// cylinder is a collision geometry that represents a right circular cylinder. Its pose is at its center, and it extends
+// length/2 mm in either direction along the Z axis of that pose.
+type cylinder struct {
+	pose   Pose
+	radius float64
+	length float64
+	label  string
+
+	// These values are generated at geometry creation time and should not be altered by hand
+	center r3.Vector // Centerpoint of the cylinder
+	axis   r3.Vector // Unit vector along the cylinder's axis
+}
+
+// NewCylinder instantiates a new cylinder Geometry.
+func NewCylinder(pose Pose, radius, length float64, label string) (Geometry, error) {
+	if radius <= 0 || length <= 0 {
+		return nil, newBadGeometryDimensionsError(&cylinder{})
+	}
+	return newCylinder(pose, radius, length, label), nil
+}
+
+func newCylinder(pose Pose, radius, length float64, label string) *cylinder {
+	center := pose.Point()
+	return &cylinder{
+		pose:   pose,
+		radius: radius,
+		length: length,
+		label:  label,
+		center: center,
+		axis:   Compose(pose, NewPoseFromPoint(r3.Vector{Z: 1})).Point().Sub(center).Normalize(),
+	}
+}
+
+func (c *cylinder) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// String returns a human readable string that represents the cylinder.
+func (c *cylinder) String() string {
+	return fmt.Sprintf("Type: Cylinder, Radius: %.0f, Length: %.0f", c.radius, c.length)
+}
+
+// Label returns the label of this cylinder.
+func (c *cylinder) Label() string {
+	return c.label
+}
+
+// SetLabel sets the label of this cylinder.
+func (c *cylinder) SetLabel(label string) {
+	c.label = label
+}
+
+// Pose returns the pose of the cylinder.
+func (c *cylinder) Pose() Pose {
+	return c.pose
+}
+
+// AlmostEqual compares the cylinder with another geometry and checks if they are equivalent.
+func (c *cylinder) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*cylinder)
+	if !ok {
+		return false
+	}
+	return PoseAlmostEqual(c.pose, other.pose) &&
+		utils.Float64AlmostEqual(c.radius, other.radius, 1e-8) &&
+		utils.Float64AlmostEqual(c.length, other.length, 1e-8)
+}
+
+// Transform premultiplies the cylinder pose with a transform, allowing the cylinder to be moved in space.
+func (c *cylinder) Transform(toPremultiply Pose) Geometry {
+	return newCylinder(Compose(toPremultiply, c.pose), c.radius, c.length, c.label)
+}
+
+// ToProtobuf converts the cylinder to a Geometry proto message. The API has no cylinder message, so the cylinder is
+// represented by its bounding box, which callers sending it can detect with ApproximatedInProtobuf.
+func (c *cylinder) ToProtobuf() *commonpb.Geometry {
+	return &commonpb.Geometry{
+		Center: PoseToProtobuf(c.pose),
+		GeometryType: &commonpb.Geometry_Box{
+			Box: &commonpb.RectangularPrism{DimsMm: &commonpb.Vector3{
+				X: 2 * c.radius,
+				Y: 2 * c.radius,
+				Z: c.length,
+			}},
+		},
+		Label: c.label,
+	}
+}
+
+// CollidesWith checks if the given cylinder collides with the given geometry and returns true if it does.
+func (c *cylinder) CollidesWith(g Geometry) (bool, error) {
+	if other, ok := g.(*mesh); ok {
+		return other.CollidesWith(c)
+	}
+	dist, err := c.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return dist <= CollisionBuffer, nil
+}
+
+// DistanceFrom returns the distance between the cylinder and the given geometry.
+func (c *cylinder) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*box); ok {
+		return convexDistance(cylinderShape(c), boxShape(other)), nil
+	}
+	if other, ok := g.(*sphere); ok {
+		return cylinderVsPointDistance(c, other.pose.Point()) - other.radius, nil
+	}
+	if other, ok := g.(*capsule); ok {
+		return convexDistance(cylinderShape(c), segmentShape(other.segA, other.segB)) - other.radius, nil
+	}
+	if other, ok := g.(*point); ok {
+		return cylinderVsPointDistance(c, other.position), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return convexDistance(cylinderShape(c), cylinderShape(other)), nil
+	}
+	if other, ok := g.(*mesh); ok {
+		return other.DistanceFrom(c)
+	}
+	return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
+}
+
+// EncompassedBy returns a bool describing if the given cylinder is completely encompassed by the given geometry.
+// Encompassment by a capsule or another cylinder is checked at points sampled around the rims of the cylinder.
+func (c *cylinder) EncompassedBy(g Geometry) (bool, error) {
+	if other, ok := g.(*box); ok {
+		return cylinderInBox(c, other), nil
+	}
+	if other, ok := g.(*sphere); ok {
+		return cylinderInSphere(c, other), nil
+	}
+	if other, ok := g.(*capsule); ok {
+		for _, pt := range c.rimPoints() {
+			if capsuleVsPointDistance(other, pt) > 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		for _, pt := range c.rimPoints() {
+			if cylinderVsPointDistance(other, pt) > 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	if _, ok := g.(*point); ok {
+		return false, nil
+	}
+	if _, ok := g.(*mesh); ok {
+		return false, nil
+	}
+	return false, newCollisionTypeUnsupportedError(c, g)
+}
+
+// ToPoints converts a cylinder geometry into []r3.Vector. This method takes one argument which determines how many points
+// per sqmm should be on the cylinder's surface. If the argument is set to 0 we automatically substitute the value with
+// defaultPointDensity.
+func (c *cylinder) ToPoints(resolution float64) []r3.Vector {
+	if resolution <= 0 {
+		resolution = defaultPointDensity
+	}
+	spacing := 1 / math.Sqrt(resolution)
+	perRing := math.Max(math.Ceil(2*math.Pi*c.radius/spacing), 3)
+	rings := math.Max(math.Ceil(c.length/spacing), 1)
+
+	var vecList []r3.Vector
+	// the curved surface
+	for ring := 0.; ring <= rings; ring++ {
+		z := -c.length/2 + c.length*ring/rings
+		for i := 0.; i < perRing; i++ {
+			theta := 2 * math.Pi * i / perRing
+			vecList = append(vecList, r3.Vector{X: c.radius * math.Cos(theta), Y: c.radius * math.Sin(theta), Z: z})
+		}
+	}
+	// the end caps, in concentric circles
+	circles := math.Ceil(c.radius / spacing)
+	for circle := 0.; circle < circles; circle++ {
+		r := c.radius * circle / circles
+		count := math.Max(math.Ceil(2*math.Pi*r/spacing), 1)
+		for i := 0.; i < count; i++ {
+			theta := 2 * math.Pi * i / count
+			for _, z := range []float64{-c.length / 2, c.length / 2} {
+				vecList = append(vecList, r3.Vector{X: r * math.Cos(theta), Y: r * math.Sin(theta), Z: z})
+			}
+		}
+	}
+	return transformPointsToPose(vecList, c.pose)
+}
+
+// rimPoints returns points evenly spaced around both rims of the cylinder.
+func (c *cylinder) rimPoints() []r3.Vector {
+	vecList := make([]r3.Vector, 0, 2*cylinderRimSamples)
+	for i := 0; i < cylinderRimSamples; i++ {
+		theta := 2 * math.Pi * float64(i) / cylinderRimSamples
+		for _, z := range []float64{-c.length / 2, c.length / 2} {
+			vecList = append(vecList, r3.Vector{X: c.radius * math.Cos(theta), Y: c.radius * math.Sin(theta), Z: z})
+		}
+	}
+	return transformPointsToPose(vecList, c.pose)
+}
+
+// cylinderVsPointDistance returns the distance from the point to the surface of the cylinder, which is negative if the point
+// is inside the cylinder.
+func cylinderVsPointDistance(c *cylinder, pt r3.Vector) float64 {
+	delta := pt.Sub(c.center)
+	along := delta.Dot(c.axis)
+	axial := math.Abs(along) - c.length/2
+	radial := delta.Sub(c.axis.Mul(along)).Norm() - c.radius
+	if axial <= 0 && radial <= 0 {
+		return math.Max(axial, radial)
+	}
+	return math.Hypot(math.Max(axial, 0), math.Max(radial, 0))
+}
+
+// cylinderInBox returns a bool describing if the given cylinder is fully encompassed by the given box.
+func cylinderInBox(c *cylinder, b *box) bool {
+	rm := b.rotationMatrix()
+	delta := c.center.Sub(b.pose.Point())
+	for i := 0; i < 3; i++ {
+		axis := rm.Row(i)
+		cos := c.axis.Dot(axis)
+		// the extent of the cylinder along a box axis is due to both its length and the radius of its rims
+		extent := math.Abs(delta.Dot(axis)) + c.length/2*math.Abs(cos) + c.radius*math.Sqrt(math.Max(0, 1-cos*cos))
+		if extent > b.halfSize[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cylinderInSphere returns a bool describing if the given cylinder is fully encompassed by the given sphere.
+func cylinderInSphere(c *cylinder, s *sphere) bool {
+	for _, sign := range []float64{-1, 1} {
+		// the furthest point of a rim from the sphere's center is on the opposite side of the rim
+		delta := s.pose.Point().Sub(c.center.Add(c.axis.Mul(sign * c.length / 2)))
+		along := delta.Dot(c.axis)
+		radial := delta.Sub(c.axis.Mul(along)).Norm()
+		if math.Hypot(along, radial+c.radius) > s.radius {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package spatialmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func makeTestCylinder(o Orientation, pt r3.Vector, radius, length float64) Geometry {
+	c, _ := NewCylinder(NewPose(pt, o), radius, length, "")
+	return c
+}
+
+func TestCylinderConstruction(t *testing.T) {
+	_, err := NewCylinder(NewZeroPose(), 0, 1, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewCylinder(NewZeroPose(), 1, -1, "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	c := makeTestCylinder(&OrientationVector{OX: 1}, r3.Vector{1, 2, 3}, 1, 4).(*cylinder)
+	test.That(t, R3VectorAlmostEqual(c.axis, r3.Vector{1, 0, 0}, 1e-8), test.ShouldBeTrue)
+	moved := c.Transform(NewPoseFromPoint(r3.Vector{1, 0, 0})).(*cylinder)
+	test.That(t, R3VectorAlmostEqual(moved.center, r3.Vector{2, 2, 3}, 1e-8), test.ShouldBeTrue)
+
+	// the API has no cylinder message, so cylinders are sent as their bounding box
+	box := c.ToProtobuf().GetBox().GetDimsMm()
+	test.That(t, box.X, test.ShouldEqual, 2)
+	test.That(t, box.Y, test.ShouldEqual, 2)
+	test.That(t, box.Z, test.ShouldEqual, 4)
+
+	for _, pt := range c.ToPoints(1) {
+		test.That(t, cylinderVsPointDistance(c, pt), test.ShouldAlmostEqual, 0, 1e-6)
+	}
+}
+
+func TestCylinderCollision(t *testing.T) {
+	alongX := &OrientationVector{OX: 1}
+	cyl := makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 4)
+	cases := []geometryComparisonTestCase{
+		{"point radial separation", [2]Geometry{cyl, NewPoint(r3.Vector{3, 0, 0}, "")}, 2},
+		{"point axial separation", [2]Geometry{cyl, NewPoint(r3.Vector{0, 0, 3}, "")}, 1},
+		{"point rim separation", [2]Geometry{cyl, NewPoint(r3.Vector{2, 0, 3}, "")}, math.Sqrt2},
+		{"point inside", [2]Geometry{cyl, NewPoint(r3.Vector{0.5, 0, 0}, "")}, -0.5},
+		{"sphere separation", [2]Geometry{cyl, makeTestSphere(r3.Vector{4, 0, 0}, 1, "")}, 2},
+		{"box separation", [2]Geometry{cyl, makeTestBox(NewZeroOrientation(), r3.Vector{3, 0, 0}, r3.Vector{2, 2, 2}, "")}, 1},
+		{
+			"rotated box separation",
+			[2]Geometry{cyl, makeTestBox(&EulerAngles{0, 0, math.Pi / 4}, r3.Vector{2 + math.Sqrt2, 0, 0}, r3.Vector{2, 2, 2}, "")},
+			1,
+		},
+		{"box penetration", [2]Geometry{cyl, makeTestBox(NewZeroOrientation(), r3.Vector{1.5, 0, 0}, r3.Vector{2, 2, 2}, "")}, -0.5},
+		{"parallel capsule separation", [2]Geometry{cyl, makeTestCapsule(NewZeroOrientation(), r3.Vector{4, 0, 0}, 1, 4)}, 2},
+		{"perpendicular capsule separation", [2]Geometry{cyl, makeTestCapsule(alongX, r3.Vector{5, 0, 0}, 1, 4)}, 2},
+		{"axial cylinder separation", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{0, 0, 5}, 1, 4)}, 1},
+		{"perpendicular cylinder separation", [2]Geometry{cyl, makeTestCylinder(alongX, r3.Vector{0, 0, 3.5}, 1, 4)}, 0.5},
+		{"parallel cylinder separation", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{2.5, 0, 0}, 1, 4)}, 0.5},
+		{"parallel cylinder penetration", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{1.5, 0, 0}, 1, 4)}, -0.5},
+	}
+	testGeometryCollision(t, cases)
+}
+
+func TestCylinderEncompassed(t *testing.T) {
+	cyl := makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 4)
+	cases := []geometryComparisonTestCase{
+		{"in box", [2]Geometry{cyl, makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{2.2, 2.2, 4.2}, "")}, 0},
+		{"not in box", [2]Geometry{cyl, makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{1.9, 2.2, 4.2}, "")}, 1},
+		{"in sphere", [2]Geometry{cyl, makeTestSphere(r3.Vector{}, 2.3, "")}, 0},
+		{"not in sphere", [2]Geometry{cyl, makeTestSphere(r3.Vector{}, 2.2, "")}, 1},
+		{"in capsule", [2]Geometry{cyl, makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 1.1, 6.2)}, 0},
+		{"not in capsule", [2]Geometry{cyl, makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 1.1, 4.2)}, 1},
+		{"in cylinder", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1.1, 4.2)}, 0},
+		{"not in cylinder", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{0.2, 0, 0}, 1.1, 4.2)}, 1},
+		{"not in point", [2]Geometry{cyl, NewPoint(r3.Vector{}, "")}, 1},
+		{"box in cylinder", [2]Geometry{makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{1.4, 1.4, 4}, ""), cyl}, 0},
+		{"box not in cylinder", [2]Geometry{makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{1.5, 1.5, 4}, ""), cyl}, 1},
+		{"sphere in cylinder", [2]Geometry{makeTestSphere(r3.Vector{0, 0, 1}, 1, ""), cyl}, 0},
+		{"sphere not in cylinder", [2]Geometry{makeTestSphere(r3.Vector{0, 0, 1.5}, 1, ""), cyl}, 1},
+		{"capsule in cylinder", [2]Geometry{makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 0.5, 4), cyl}, 0},
+		{"capsule not in cylinder", [2]Geometry{makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 0.5, 4.2), cyl}, 1},
+		{"point in cylinder", [2]Geometry{NewPoint(r3.Vector{0, 0, 2}, ""), cyl}, 0},
+		{"point not in cylinder", [2]Geometry{NewPoint(r3.Vector{0, 0, 2.1}, ""), cyl}, 1},
+	}
+	testGeometryEncompassed(t, cases)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 )
 
@@ -37,6 +38,8 @@ const (
 	SphereType      = GeometryType("sphere")
 	CapsuleType     = GeometryType("capsule")
 	PointType       = GeometryType("point")
+	CylinderType    = GeometryType("cylinder")
+	MeshType        = GeometryType("mesh")
 	CollisionBuffer = 1e-8 // objects must be separated by this many mm to not be in collision
 
 	// Point density corresponding to how many points per square mm.
@@ -55,9 +58,13 @@ type GeometryConfig struct {
 	// parameter used for defining a sphere's radius'
 	R float64 `json:"r"`
 
-	// parameter used for defining a capsule's length
+	// parameter used for defining a capsule's or cylinder's length
 	L float64 `json:"l"`
 
+	// parameters used for defining a mesh, loaded from a STL, PLY or OBJ file whose vertices are multiplied by the scale
+	MeshFile  string    `json:"mesh_file,omitempty"`
+	MeshScale r3.Vector `json:"mesh_scale,omitempty"`
+
 	// define an offset to position the geometry
 	TranslationOffset r3.Vector         `json:"translation,omitempty"`
 	OrientationOffset OrientationConfig `json:"orientation,omitempty"`
@@ -87,6 +94,19 @@ func NewGeometryConfig(gc Geometry) (*GeometryConfig, error) {
 	case *point:
 		config.Type = PointType
 		config.Label = gc.(*point).label
+	case *cylinder:
+		config.Type = CylinderType
+		config.R = gc.(*cylinder).radius
+		config.L = gc.(*cylinder).length
+		config.Label = gc.(*cylinder).label
+	case *mesh:
+		if gc.(*mesh).file == "" {
+			return nil, errors.New("only meshes loaded from a file can be converted to a config")
+		}
+		config.Type = MeshType
+		config.MeshFile = gc.(*mesh).file
+		config.MeshScale = gc.(*mesh).scale
+		config.Label = gc.(*mesh).label
 	default:
 		return nil, fmt.Errorf("%w %s", ErrGeometryTypeUnsupported, fmt.Sprintf("%T", gcType))
 	}
@@ -120,6 +140,10 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 		return NewCapsule(offset, config.R, config.L, config.Label)
 	case PointType:
 		return NewPoint(offset.Point(), config.Label), nil
+	case CylinderType:
+		return NewCylinder(offset, config.R, config.L, config.Label)
+	case MeshType:
+		return NewMeshFromFile(offset, config.MeshFile, config.MeshScale, config.Label)
 	case UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		boxDims := r3.Vector{X: config.X, Y: config.Y, Z: config.Z}
@@ -179,11 +203,28 @@ func NewGeometriesToProto(geometries []Geometry) []*commonpb.Geometry {
 	return proto
 }
 
-// ToProtobuf converts a GeometryConfig to Protobuf.
+// ToProtobuf converts a GeometryConfig to Protobuf. Configs of geometries the API has no message for are not converted,
+// as the config they would be read back as would describe a different geometry.
 func (config *GeometryConfig) ToProtobuf() (*commonpb.Geometry, error) {
 	creator, err := config.ParseConfig()
 	if err != nil {
 		return nil, err
 	}
+	if len(ApproximatedInProtobuf([]Geometry{creator})) > 0 {
+		return nil, errors.Errorf("cannot convert %s geometry config to protobuf, which has no message for it", config.Type)
+	}
 	return creator.ToProtobuf(), nil
 }
+
+// ApproximatedInProtobuf returns the labels of the given geometries that the API has no message for, whose ToProtobuf
+// messages describe only the boxes bounding them.
+func ApproximatedInProtobuf(geometries []Geometry) []string {
+	var labels []string
+	for _, geometry := range geometries {
+		switch geometry.(type) {
+		case *cylinder, *mesh:
+			labels = append(labels, geometry.Label())
+		}
+	}
+	return labels
+}
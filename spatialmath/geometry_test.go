@@ -39,6 +39,25 @@ func TestGeometrySerialization(t *testing.T) {
 		{"bad type", GeometryConfig{Type: "bad"}, false},
 		{"c", GeometryConfig{Type: "capsule", L: 4, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "c"}, true},
 		{"infer c", GeometryConfig{L: 4, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "infer c"}, true},
+		{
+			"cylinder",
+			GeometryConfig{Type: "cylinder", L: 4, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "cylinder"},
+			true,
+		},
+		{"cylinder bad dims", GeometryConfig{Type: "cylinder", R: 1}, false},
+		{
+			"mesh",
+			GeometryConfig{
+				Type:              "mesh",
+				MeshFile:          "data/cube.stl",
+				MeshScale:         r3.Vector{X: 2, Y: 2, Z: 2},
+				TranslationOffset: translation,
+				OrientationOffset: orientation,
+				Label:             "mesh",
+			},
+			true,
+		},
+		{"mesh bad file", GeometryConfig{Type: "mesh", MeshFile: "data/missing.stl"}, false},
 	}
 
 	pose := NewPoseFromPoint(r3.Vector{X: 1, Y: 1, Z: 1})
@@ -85,6 +104,20 @@ func TestGeometryToFromProtobuf(t *testing.T) {
 	// test that bad message does not generate error
 	_, err := NewGeometryFromProto(&commonpb.Geometry{Center: PoseToProtobuf(NewZeroPose())})
 	test.That(t, err.Error(), test.ShouldContainSubstring, ErrGeometryTypeUnsupported.Error())
+
+	// cylinders and meshes have no message, so they are only sent as approximations and their configs are not sent at all
+	cylinder := makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1, 2)
+	cylinder.SetLabel("cylinder")
+	approximated := ApproximatedInProtobuf([]Geometry{testCases[0].geometry, cylinder, testCases[1].geometry})
+	test.That(t, approximated, test.ShouldResemble, []string{"cylinder"})
+	config, err := NewGeometryConfig(cylinder)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = config.ToProtobuf()
+	test.That(t, err, test.ShouldNotBeNil)
+	config, err = NewGeometryConfig(testCases[0].geometry)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = config.ToProtobuf()
+	test.That(t, err, test.ShouldBeNil)
 }
 
 type geometryComparisonTestCase struct {
@@ -1,7 +1,13 @@
 package spatialmath
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
 )
 
 // This file incorporates work covered by the Brax project -- https://github.com/google/brax/blob/main/LICENSE.
@@ -9,9 +15,379 @@ import (
 // You may obtain a copy of the license at http://www.apache.org/licenses/LICENSE-2.0.
 
 // mesh is a collision geometry that represents a set of triangles that represent a mesh.
+// IMPORTANT: only closed meshes, in which every edge is shared by exactly two triangles, are considered solid. Distances
+// to a mesh are measured to its closest triangle, and are negative, the depth of the penetration, for geometries that are
+// enclosed by a closed mesh. An open mesh does not enclose a volume, so no geometry is considered to be within it.
 type mesh struct {
 	pose      Pose
 	triangles []*triangle
+	label     string
+	closed    bool
+
+	// the file the mesh was loaded from and the scale applied to it, if any, which are needed to serialize the mesh
+	file  string
+	scale r3.Vector
+
+	// bounding sphere of the mesh, used to rule out collisions without checking every triangle
+	boundingCenter r3.Vector
+	boundingR      float64
+}
+
+// NewMesh instantiates a new mesh Geometry from a set of vertices, specified relative to the pose, and the faces that
+// connect them. Each face is the indices of the three vertices of a triangle.
+func NewMesh(pose Pose, vertices []r3.Vector, faces [][3]int, label string) (Geometry, error) {
+	return newMesh(pose, vertices, faces, label)
+}
+
+// NewMeshFromFile instantiates a new mesh Geometry from a STL, PLY or OBJ file. The vertices in the file are multiplied by
+// scale, which defaults to 1 in each dimension if zero, and then interpreted in mm relative to the pose.
+func NewMeshFromFile(pose Pose, path string, scale r3.Vector, label string) (Geometry, error) {
+	vertices, faces, err := readMeshFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if scale.Norm2() == 0 {
+		scale = r3.Vector{X: 1, Y: 1, Z: 1}
+	}
+	for i, v := range vertices {
+		vertices[i] = r3.Vector{X: v.X * scale.X, Y: v.Y * scale.Y, Z: v.Z * scale.Z}
+	}
+	m, err := newMesh(pose, vertices, faces, label)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid mesh file %s", path)
+	}
+	m.file = path
+	m.scale = scale
+	return m, nil
+}
+
+func newMesh(pose Pose, vertices []r3.Vector, faces [][3]int, label string) (*mesh, error) {
+	if len(faces) == 0 {
+		return nil, newBadGeometryDimensionsError(&mesh{})
+	}
+	transformed := transformPointsToPose(vertices, pose)
+	triangles := make([]*triangle, 0, len(faces))
+	for _, face := range faces {
+		for _, idx := range face {
+			if idx < 0 || idx >= len(vertices) {
+				return nil, errors.Errorf("mesh face references vertex %d, but there are only %d vertices", idx, len(vertices))
+			}
+		}
+		triangles = append(triangles, newTriangle(transformed[face[0]], transformed[face[1]], transformed[face[2]]))
+	}
+	m := &mesh{pose: pose, triangles: triangles, label: label, closed: facesClosed(faces)}
+	m.computeBoundingSphere()
+	return m, nil
+}
+
+// facesClosed returns whether every edge of the faces is shared by exactly two of them.
+func facesClosed(faces [][3]int) bool {
+	edges := map[[2]int]int{}
+	for _, face := range faces {
+		for i := range face {
+			a, b := face[i], face[(i+1)%3]
+			if a > b {
+				a, b = b, a
+			}
+			edges[[2]int{a, b}]++
+		}
+	}
+	for _, count := range edges {
+		if count != 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// insideRayDirection is the direction of the ray cast from a point to check whether a closed mesh encloses it. It is
+// chosen to be unlikely to pass exactly through the edges or vertices of a mesh, which would be counted twice.
+var insideRayDirection = r3.Vector{X: 0.5773, Y: 0.5774, Z: 0.5775}.Normalize()
+
+// encloses returns whether the point is within the volume enclosed by the mesh, which is the case if a ray cast from it
+// crosses the mesh's triangles an odd number of times.
+func (m *mesh) encloses(pt r3.Vector) bool {
+	if !m.closed || pt.Sub(m.boundingCenter).Norm() > m.boundingR {
+		return false
+	}
+	end := pt.Add(insideRayDirection.Mul(2*m.boundingR + 1))
+	crossings := 0
+	for _, t := range m.triangles {
+		if segmentIntersectsTriangle(pt, end, t) {
+			crossings++
+		}
+	}
+	return crossings%2 == 1
+}
+
+func (m *mesh) computeBoundingSphere() {
+	var center r3.Vector
+	for _, t := range m.triangles {
+		center = center.Add(t.p0).Add(t.p1).Add(t.p2)
+	}
+	center = center.Mul(1 / float64(3*len(m.triangles)))
+	var radius float64
+	for _, t := range m.triangles {
+		for _, pt := range []r3.Vector{t.p0, t.p1, t.p2} {
+			radius = math.Max(radius, pt.Sub(center).Norm())
+		}
+	}
+	m.boundingCenter, m.boundingR = center, radius
+}
+
+func (m *mesh) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// String returns a human readable string that represents the mesh.
+func (m *mesh) String() string {
+	return fmt.Sprintf("Type: Mesh, Triangles: %d", len(m.triangles))
+}
+
+// Label returns the label of this mesh.
+func (m *mesh) Label() string {
+	return m.label
+}
+
+// SetLabel sets the label of this mesh.
+func (m *mesh) SetLabel(label string) {
+	m.label = label
+}
+
+// Pose returns the pose of the mesh.
+func (m *mesh) Pose() Pose {
+	return m.pose
+}
+
+// AlmostEqual compares the mesh with another geometry and checks if they are equivalent.
+func (m *mesh) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*mesh)
+	if !ok || len(m.triangles) != len(other.triangles) || !PoseAlmostEqual(m.pose, other.pose) {
+		return false
+	}
+	for i, t := range m.triangles {
+		o := other.triangles[i]
+		if !R3VectorAlmostEqual(t.p0, o.p0, 1e-8) || !R3VectorAlmostEqual(t.p1, o.p1, 1e-8) || !R3VectorAlmostEqual(t.p2, o.p2, 1e-8) {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform premultiplies the mesh pose with a transform, allowing the mesh to be moved in space.
+func (m *mesh) Transform(toPremultiply Pose) Geometry {
+	// the rows of the rotation matrix are the axes of the rotated frame
+	rm := toPremultiply.Orientation().RotationMatrix()
+	x, y, z := rm.Row(0), rm.Row(1), rm.Row(2)
+	rotate := func(pt r3.Vector) r3.Vector {
+		return x.Mul(pt.X).Add(y.Mul(pt.Y)).Add(z.Mul(pt.Z))
+	}
+	translation := toPremultiply.Point()
+	transform := func(pt r3.Vector) r3.Vector {
+		return rotate(pt).Add(translation)
+	}
+	triangles := make([]*triangle, 0, len(m.triangles))
+	for _, t := range m.triangles {
+		triangles = append(triangles, &triangle{
+			p0:     transform(t.p0),
+			p1:     transform(t.p1),
+			p2:     transform(t.p2),
+			normal: rotate(t.normal),
+		})
+	}
+	return &mesh{
+		pose:           Compose(toPremultiply, m.pose),
+		triangles:      triangles,
+		label:          m.label,
+		closed:         m.closed,
+		file:           m.file,
+		scale:          m.scale,
+		boundingCenter: transform(m.boundingCenter),
+		boundingR:      m.boundingR,
+	}
+}
+
+// ToProtobuf converts the mesh to a Geometry proto message. The API has no mesh message, so the mesh is represented by its
+// bounding box, which callers sending it can detect with ApproximatedInProtobuf.
+func (m *mesh) ToProtobuf() *commonpb.Geometry {
+	toLocal := PoseInverse(m.pose)
+	minPt := r3.Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxPt := minPt.Mul(-1)
+	for _, t := range m.triangles {
+		for _, pt := range []r3.Vector{t.p0, t.p1, t.p2} {
+			local := Compose(toLocal, NewPoseFromPoint(pt)).Point()
+			minPt = r3.Vector{X: math.Min(minPt.X, local.X), Y: math.Min(minPt.Y, local.Y), Z: math.Min(minPt.Z, local.Z)}
+			maxPt = r3.Vector{X: math.Max(maxPt.X, local.X), Y: math.Max(maxPt.Y, local.Y), Z: math.Max(maxPt.Z, local.Z)}
+		}
+	}
+	center := Compose(m.pose, NewPoseFromPoint(minPt.Add(maxPt).Mul(0.5)))
+	dims := maxPt.Sub(minPt)
+	return &commonpb.Geometry{
+		Center: PoseToProtobuf(center),
+		GeometryType: &commonpb.Geometry_Box{
+			Box: &commonpb.RectangularPrism{DimsMm: &commonpb.Vector3{X: dims.X, Y: dims.Y, Z: dims.Z}},
+		},
+		Label: m.label,
+	}
+}
+
+// CollidesWith checks if the given mesh collides with the given geometry and returns true if it does.
+func (m *mesh) CollidesWith(g Geometry) (bool, error) {
+	// if the bounding sphere of the mesh does not collide with the geometry, no triangle of the mesh can
+	bound := &sphere{pose: NewPoseFromPoint(m.boundingCenter), radius: m.boundingR}
+	if other, ok := g.(*mesh); ok {
+		bound.radius += other.boundingR
+		if sphereVsPointDistance(bound, other.boundingCenter) > CollisionBuffer {
+			return false, nil
+		}
+	} else if m.boundingR > 0 {
+		if dist, err := bound.DistanceFrom(g); err == nil && dist > CollisionBuffer {
+			return false, nil
+		}
+	}
+	dist, err := m.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return dist <= CollisionBuffer, nil
+}
+
+// DistanceFrom returns the distance between the closest triangle of the mesh and the given geometry. A geometry that does
+// not touch any triangle but is enclosed by a closed mesh, or that encloses the mesh, is at the negated distance.
+func (m *mesh) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*sphere); ok {
+		return meshVsPointDistance(m, other.pose.Point()) - other.radius, nil
+	}
+	if other, ok := g.(*point); ok {
+		return meshVsPointDistance(m, other.position), nil
+	}
+
+	var dist float64
+	var within bool
+	switch other := g.(type) {
+	case *box:
+		dist, within = meshVsConvexDistance(m, boxShape(other)), m.encloses(other.pose.Point())
+	case *capsule:
+		dist, within = capsuleVsMeshDistance(other, m), m.encloses(other.pose.Point())
+	case *cylinder:
+		dist, within = meshVsCylinderDistance(m, other), m.encloses(other.pose.Point())
+	case *mesh:
+		dist = meshVsMeshDistance(m, other)
+		within = m.encloses(other.triangles[0].p0) || other.encloses(m.triangles[0].p0)
+	default:
+		return math.Inf(-1), newCollisionTypeUnsupportedError(m, g)
+	}
+	if dist > 0 && within {
+		return -dist, nil
+	}
+	return dist, nil
+}
+
+// EncompassedBy returns a bool describing if the given mesh is completely encompassed by the given geometry. Since all
+// other geometries are convex, this is the case if every vertex of the mesh is encompassed by the geometry.
+func (m *mesh) EncompassedBy(g Geometry) (bool, error) {
+	switch g.(type) {
+	case *point, *mesh:
+		return false, nil
+	case *box, *sphere, *capsule, *cylinder:
+	default:
+		return false, newCollisionTypeUnsupportedError(m, g)
+	}
+	for _, t := range m.triangles {
+		for _, pt := range []r3.Vector{t.p0, t.p1, t.p2} {
+			if inside, err := NewPoint(pt, "").EncompassedBy(g); err != nil || !inside {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// ToPoints converts a mesh geometry into []r3.Vector. This method takes one argument which determines how many points per
+// sqmm should be on the surface of each triangle, in addition to its vertices. If the argument is set to 0 we automatically
+// substitute the value with defaultPointDensity.
+func (m *mesh) ToPoints(resolution float64) []r3.Vector {
+	if resolution <= 0 {
+		resolution = defaultPointDensity
+	}
+	var vecList []r3.Vector
+	for _, t := range m.triangles {
+		vecList = append(vecList, t.p0, t.p1, t.p2)
+		e0, e1 := t.p1.Sub(t.p0), t.p2.Sub(t.p0)
+		// sample the triangle on a grid of barycentric coordinates
+		steps := math.Ceil(math.Sqrt(e0.Cross(e1).Norm() / 2 * resolution))
+		for i := 1.; i < steps; i++ {
+			for j := 1.; i+j < steps; j++ {
+				vecList = append(vecList, t.p0.Add(e0.Mul(i/steps)).Add(e1.Mul(j/steps)))
+			}
+		}
+	}
+	return vecList
+}
+
+// meshVsPointDistance returns the distance from the closest triangle in the mesh to the point, which is negative if the
+// mesh is closed and encloses the point.
+func meshVsPointDistance(m *mesh, pt r3.Vector) float64 {
+	lowDist := math.Inf(1)
+	for _, t := range m.triangles {
+		if dist := t.closestPointToPoint(pt).Sub(pt).Norm(); dist < lowDist {
+			lowDist = dist
+		}
+	}
+	if m.encloses(pt) {
+		return -lowDist
+	}
+	return lowDist
+}
+
+func meshVsCylinderDistance(m *mesh, c *cylinder) float64 {
+	return meshVsConvexDistance(m, cylinderShape(c))
+}
+
+// meshVsConvexDistance returns the distance from the closest triangle in the mesh to a convex shape.
+func meshVsConvexDistance(m *mesh, shape convexShape) float64 {
+	lowDist := math.Inf(1)
+	for _, t := range m.triangles {
+		if dist := convexDistance(triangleShape(t), shape); dist < lowDist {
+			lowDist = dist
+		}
+	}
+	return lowDist
+}
+
+func meshVsMeshDistance(m, other *mesh) float64 {
+	lowDist := math.Inf(1)
+	for _, t := range m.triangles {
+		for _, o := range other.triangles {
+			if dist := triangleVsTriangleDistance(t, o); dist < lowDist {
+				lowDist = dist
+			}
+		}
+	}
+	return lowDist
+}
+
+// triangleVsTriangleDistance returns the distance between two triangles. If they are not intersecting, the closest points
+// lie on an edge of at least one of them, and if they are, an edge of one intersects the other.
+func triangleVsTriangleDistance(a, b *triangle) float64 {
+	lowDist := math.Inf(1)
+	for _, pair := range [2][2]*triangle{{a, b}, {b, a}} {
+		t, other := pair[0], pair[1]
+		for _, edge := range [3][2]r3.Vector{{t.p0, t.p1}, {t.p1, t.p2}, {t.p2, t.p0}} {
+			if segmentIntersectsTriangle(edge[0], edge[1], other) {
+				return 0
+			}
+			segPt, triPt := closestPointsSegmentTriangle(edge[0], edge[1], other)
+			if dist := segPt.Sub(triPt).Norm(); dist < lowDist {
+				lowDist = dist
+			}
+		}
+	}
+	return lowDist
 }
 
 type triangle struct {
@@ -113,3 +489,27 @@ func (t *triangle) closestInsidePoint(point r3.Vector) (r3.Vector, bool) {
 	inside := (0 <= u) && (u <= 1) && (0 <= v) && (v <= 1) && (u+v <= 1)
 	return t.p0.Add(e0.Mul(u)).Add(e1.Mul(v)), inside
 }
+
+// segmentIntersectsTriangle returns whether a line segment passes through a triangle, using the Moller-Trumbore algorithm.
+func segmentIntersectsTriangle(p0, p1 r3.Vector, t *triangle) bool {
+	dir := p1.Sub(p0)
+	e0, e1 := t.p1.Sub(t.p0), t.p2.Sub(t.p0)
+	h := dir.Cross(e1)
+	det := e0.Dot(h)
+	if math.Abs(det) < gjkTolerance {
+		// the segment is parallel to the triangle
+		return false
+	}
+	s := p0.Sub(t.p0)
+	u := s.Dot(h) / det
+	if u < 0 || u > 1 {
+		return false
+	}
+	q := s.Cross(e0)
+	v := dir.Dot(q) / det
+	if v < 0 || u+v > 1 {
+		return false
+	}
+	along := e1.Dot(q) / det
+	return along >= 0 && along <= 1
+}
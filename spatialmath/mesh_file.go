@@ -0,0 +1,339 @@
+package spatialmath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// readMeshFile reads the vertices and triangular faces of a mesh from a STL, PLY or OBJ file, as determined by its extension.
+// Polygonal faces are split into triangles.
+func readMeshFile(path string) ([]r3.Vector, [][3]int, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read mesh file")
+	}
+	var vertices []r3.Vector
+	var faces [][3]int
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".stl":
+		vertices, faces, err = readSTL(data)
+	case ".ply":
+		vertices, faces, err = readPLY(data)
+	case ".obj":
+		vertices, faces, err = readOBJ(data)
+	default:
+		return nil, nil, errors.Errorf("unsupported mesh file extension %q, must be one of .stl, .ply or .obj", ext)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse mesh file %s", path)
+	}
+	return vertices, faces, nil
+}
+
+// triangulate splits a polygon into a fan of triangles around its first vertex.
+func triangulate(polygon []int) [][3]int {
+	faces := make([][3]int, 0, len(polygon))
+	for i := 2; i < len(polygon); i++ {
+		faces = append(faces, [3]int{polygon[0], polygon[i-1], polygon[i]})
+	}
+	return faces
+}
+
+// readSTL parses binary and ASCII STL files. Binary files are identified by their size matching the triangle count in
+// their header, since some binary files also begin with "solid".
+func readSTL(data []byte) ([]r3.Vector, [][3]int, error) {
+	const (
+		headerSize   = 80
+		triangleSize = 50
+	)
+	if len(data) >= headerSize+4 {
+		count := int(binary.LittleEndian.Uint32(data[headerSize:]))
+		if len(data) == headerSize+4+count*triangleSize {
+			vertices := make([]r3.Vector, 0, 3*count)
+			faces := make([][3]int, 0, count)
+			for i := 0; i < count; i++ {
+				// each triangle is a normal, three vertices and a two byte attribute count
+				offset := headerSize + 4 + i*triangleSize + 12
+				for v := 0; v < 3; v++ {
+					vertices = append(vertices, r3.Vector{
+						X: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset:]))),
+						Y: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+4:]))),
+						Z: float64(math.Float32frombits(binary.LittleEndian.Uint32(data[offset+8:]))),
+					})
+					offset += 12
+				}
+				faces = append(faces, [3]int{3 * i, 3*i + 1, 3*i + 2})
+			}
+			return vertices, faces, nil
+		}
+	}
+
+	var vertices []r3.Vector
+	var faces [][3]int
+	var polygon []int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "outer":
+			polygon = polygon[:0]
+		case "vertex":
+			v, err := parseVector(fields[1:])
+			if err != nil {
+				return nil, nil, err
+			}
+			polygon = append(polygon, len(vertices))
+			vertices = append(vertices, v)
+		case "endloop":
+			faces = append(faces, triangulate(polygon)...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(faces) == 0 {
+		return nil, nil, errors.New("no facets found in STL data")
+	}
+	return vertices, faces, nil
+}
+
+// readOBJ parses the vertices and faces of Wavefront OBJ files, ignoring texture coordinates, normals and materials.
+func readOBJ(data []byte) ([]r3.Vector, [][3]int, error) {
+	var vertices []r3.Vector
+	var faces [][3]int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			v, err := parseVector(fields[1:])
+			if err != nil {
+				return nil, nil, err
+			}
+			vertices = append(vertices, v)
+		case "f":
+			polygon := make([]int, 0, len(fields)-1)
+			for _, field := range fields[1:] {
+				// vertices of a face may be given as index/texture/normal
+				idx, err := strconv.Atoi(strings.Split(field, "/")[0])
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "invalid face vertex %q", field)
+				}
+				// indices are 1-based, or relative to the end of the vertices read so far if negative
+				if idx < 0 {
+					idx += len(vertices)
+				} else {
+					idx--
+				}
+				polygon = append(polygon, idx)
+			}
+			faces = append(faces, triangulate(polygon)...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return vertices, faces, nil
+}
+
+type plyProperty struct {
+	name      string
+	valueType string
+	// countType is set for list properties, which are a count followed by that many values
+	countType string
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// readPLY parses ASCII and binary PLY files, using the x, y and z properties of the vertex element and the first list
+// property of the face element.
+func readPLY(data []byte) ([]r3.Vector, [][3]int, error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	var format string
+	var elements []*plyElement
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, nil, errors.New("PLY header has no end_header")
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				return nil, nil, errors.New("invalid PLY format line")
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) < 3 {
+				return nil, nil, errors.Errorf("invalid PLY element line %q", strings.TrimSpace(line))
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid PLY element count %q", fields[2])
+			}
+			elements = append(elements, &plyElement{name: fields[1], count: count})
+		case "property":
+			if len(elements) == 0 {
+				return nil, nil, errors.New("PLY property defined before any element")
+			}
+			element := elements[len(elements)-1]
+			switch {
+			case len(fields) == 5 && fields[1] == "list":
+				element.properties = append(element.properties, plyProperty{name: fields[4], valueType: fields[3], countType: fields[2]})
+			case len(fields) == 3:
+				element.properties = append(element.properties, plyProperty{name: fields[2], valueType: fields[1]})
+			default:
+				return nil, nil, errors.Errorf("invalid PLY property line %q", strings.TrimSpace(line))
+			}
+		}
+		if fields[0] == "end_header" {
+			break
+		}
+	}
+
+	var read func(valueType string) (float64, error)
+	switch format {
+	case "ascii":
+		scanner := bufio.NewScanner(reader)
+		scanner.Split(bufio.ScanWords)
+		read = func(string) (float64, error) {
+			if !scanner.Scan() {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return strconv.ParseFloat(scanner.Text(), 64)
+		}
+	case "binary_little_endian":
+		read = func(valueType string) (float64, error) { return readPLYBinary(reader, binary.LittleEndian, valueType) }
+	case "binary_big_endian":
+		read = func(valueType string) (float64, error) { return readPLYBinary(reader, binary.BigEndian, valueType) }
+	default:
+		return nil, nil, errors.Errorf("unsupported PLY format %q", format)
+	}
+
+	var vertices []r3.Vector
+	var faces [][3]int
+	for _, element := range elements {
+		for i := 0; i < element.count; i++ {
+			var vertex r3.Vector
+			var polygon []int
+			for _, property := range element.properties {
+				if property.countType == "" {
+					value, err := read(property.valueType)
+					if err != nil {
+						return nil, nil, errors.Wrapf(err, "failed to read PLY %s %s", element.name, property.name)
+					}
+					switch property.name {
+					case "x":
+						vertex.X = value
+					case "y":
+						vertex.Y = value
+					case "z":
+						vertex.Z = value
+					}
+					continue
+				}
+				count, err := read(property.countType)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "failed to read PLY %s %s", element.name, property.name)
+				}
+				values := make([]int, 0, int(count))
+				for j := 0; j < int(count); j++ {
+					value, err := read(property.valueType)
+					if err != nil {
+						return nil, nil, errors.Wrapf(err, "failed to read PLY %s %s", element.name, property.name)
+					}
+					values = append(values, int(value))
+				}
+				if polygon == nil {
+					polygon = values
+				}
+			}
+			switch element.name {
+			case "vertex":
+				vertices = append(vertices, vertex)
+			case "face":
+				faces = append(faces, triangulate(polygon)...)
+			}
+		}
+	}
+	return vertices, faces, nil
+}
+
+func readPLYBinary(reader io.Reader, order binary.ByteOrder, valueType string) (float64, error) {
+	var err error
+	switch valueType {
+	case "char", "int8":
+		var v int8
+		err = binary.Read(reader, order, &v)
+		return float64(v), err
+	case "uchar", "uint8":
+		var v uint8
+		err = binary.Read(reader, order, &v)
+		return float64(v), err
+	case "short", "int16":
+		var v int16
+		err = binary.Read(reader, order, &v)
+		return float64(v), err
+	case "ushort", "uint16":
+		var v uint16
+		err = binary.Read(reader, order, &v)
+		return float64(v), err
+	case "int", "int32":
+		var v int32
+		err = binary.Read(reader, order, &v)
+		return float64(v), err
+	case "uint", "uint32":
+		var v uint32
+		err = binary.Read(reader, order, &v)
+		return float64(v), err
+	case "float", "float32":
+		var v float32
+		err = binary.Read(reader, order, &v)
+		return float64(v), err
+	case "double", "float64":
+		var v float64
+		err = binary.Read(reader, order, &v)
+		return v, err
+	default:
+		return 0, errors.Errorf("unsupported PLY property type %q", valueType)
+	}
+}
+
+func parseVector(fields []string) (r3.Vector, error) {
+	if len(fields) < 3 {
+		return r3.Vector{}, errors.Errorf("expected 3 coordinates, got %d", len(fields))
+	}
+	var coords [3]float64
+	for i := range coords {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return r3.Vector{}, errors.Wrapf(err, "invalid coordinate %q", fields[i])
+		}
+		coords[i] = value
+	}
+	return r3.Vector{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+}
@@ -1,6 +1,11 @@
 package spatialmath
 
 import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -21,3 +26,167 @@ func TestClosestPoint(t *testing.T) {
 	test.That(t, cp3.ApproxEqual(qp1), test.ShouldBeTrue)
 	test.That(t, cp1.ApproxEqual(cp2), test.ShouldBeTrue)
 }
+
+// makeTestMesh returns a mesh of a cube with sides of length 2.
+func makeTestMesh(t *testing.T, pt r3.Vector) Geometry {
+	t.Helper()
+	m, err := NewMeshFromFile(NewPoseFromPoint(pt), "data/cube.obj", r3.Vector{}, "")
+	test.That(t, err, test.ShouldBeNil)
+	return m
+}
+
+// makeOpenTestMesh returns the mesh of a cube with sides of length 2 that is missing its top face.
+func makeOpenTestMesh(t *testing.T) Geometry {
+	t.Helper()
+	vertices, faces, err := readMeshFile("data/cube.obj")
+	test.That(t, err, test.ShouldBeNil)
+	var open [][3]int
+	for _, face := range faces {
+		if vertices[face[0]].Z < 1 || vertices[face[1]].Z < 1 || vertices[face[2]].Z < 1 {
+			open = append(open, face)
+		}
+	}
+	test.That(t, open, test.ShouldHaveLength, len(faces)-2)
+	m, err := NewMesh(NewZeroPose(), vertices, open, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m.(*mesh).closed, test.ShouldBeFalse)
+	return m
+}
+
+func TestMeshFiles(t *testing.T) {
+	expected := makeTestMesh(t, r3.Vector{})
+	// the API has no mesh message, so meshes are sent as their bounding box
+	proto := makeTestMesh(t, r3.Vector{1, 2, 3}).ToProtobuf()
+	test.That(t, proto.GetBox().GetDimsMm().X, test.ShouldAlmostEqual, 2)
+	test.That(t, proto.GetBox().GetDimsMm().Z, test.ShouldAlmostEqual, 2)
+	test.That(t, proto.GetCenter().GetZ(), test.ShouldAlmostEqual, 3)
+
+	for _, file := range []string{"data/cube.stl", "data/cube.ply"} {
+		t.Run(file, func(t *testing.T) {
+			m, err := NewMeshFromFile(NewZeroPose(), file, r3.Vector{}, "")
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(m.(*mesh).triangles), test.ShouldEqual, 12)
+			test.That(t, m.(*mesh).boundingR, test.ShouldAlmostEqual, math.Sqrt(3))
+			for _, pt := range m.ToPoints(0) {
+				test.That(t, meshVsPointDistance(expected.(*mesh), pt), test.ShouldAlmostEqual, 0)
+			}
+		})
+	}
+
+	t.Run("binary", func(t *testing.T) {
+		vertices, faces, err := readMeshFile("data/cube.obj")
+		test.That(t, err, test.ShouldBeNil)
+		dir := t.TempDir()
+
+		stl := make([]byte, 84, 84+50*len(faces))
+		binary.LittleEndian.PutUint32(stl[80:], uint32(len(faces)))
+		for _, face := range faces {
+			tri := make([]byte, 50)
+			for i, idx := range face {
+				for j, v := range []float64{vertices[idx].X, vertices[idx].Y, vertices[idx].Z} {
+					binary.LittleEndian.PutUint32(tri[12+12*i+4*j:], math.Float32bits(float32(v)))
+				}
+			}
+			stl = append(stl, tri...)
+		}
+		test.That(t, os.WriteFile(filepath.Join(dir, "cube.stl"), stl, 0o600), test.ShouldBeNil)
+
+		var ply bytes.Buffer
+		ply.WriteString("ply\nformat binary_little_endian 1.0\nelement vertex 8\nproperty double x\nproperty double y\n" +
+			"property double z\nelement face 12\nproperty list uchar uint vertex_indices\nend_header\n")
+		for _, v := range vertices {
+			test.That(t, binary.Write(&ply, binary.LittleEndian, []float64{v.X, v.Y, v.Z}), test.ShouldBeNil)
+		}
+		for _, face := range faces {
+			ply.WriteByte(3)
+			test.That(t, binary.Write(&ply, binary.LittleEndian, []uint32{uint32(face[0]), uint32(face[1]), uint32(face[2])}),
+				test.ShouldBeNil)
+		}
+		test.That(t, os.WriteFile(filepath.Join(dir, "cube.ply"), ply.Bytes(), 0o600), test.ShouldBeNil)
+
+		for _, file := range []string{"cube.stl", "cube.ply"} {
+			m, err := NewMeshFromFile(NewZeroPose(), filepath.Join(dir, file), r3.Vector{}, "")
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(m.(*mesh).triangles), test.ShouldEqual, 12)
+			test.That(t, m.(*mesh).boundingR, test.ShouldAlmostEqual, math.Sqrt(3))
+		}
+	})
+
+	t.Run("scale", func(t *testing.T) {
+		m, err := NewMeshFromFile(NewZeroPose(), "data/cube.stl", r3.Vector{X: 1, Y: 1, Z: 2}, "")
+		test.That(t, err, test.ShouldBeNil)
+		dist, err := m.DistanceFrom(NewPoint(r3.Vector{Z: 3}, ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, 1)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewMeshFromFile(NewZeroPose(), "data/orientations.json", r3.Vector{}, "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewMeshFromFile(NewZeroPose(), "data/missing.stl", r3.Vector{}, "")
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = NewMesh(NewZeroPose(), []r3.Vector{{}, {X: 1}, {Y: 1}}, [][3]int{{0, 1, 3}}, "")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestMeshCollision(t *testing.T) {
+	m := makeTestMesh(t, r3.Vector{})
+	small, err := NewMeshFromFile(NewZeroPose(), "data/cube.obj", r3.Vector{X: 0.5, Y: 0.5, Z: 0.5}, "")
+	test.That(t, err, test.ShouldBeNil)
+	cases := []geometryComparisonTestCase{
+		{"point separation", [2]Geometry{m, NewPoint(r3.Vector{3, 0, 0}, "")}, 2},
+		{"sphere separation", [2]Geometry{m, makeTestSphere(r3.Vector{3, 1, 1}, 1, "")}, 1},
+		{"box separation", [2]Geometry{m, makeTestBox(NewZeroOrientation(), r3.Vector{4, 0, 0}, r3.Vector{2, 2, 2}, "")}, 2},
+		{"box touching", [2]Geometry{m, makeTestBox(NewZeroOrientation(), r3.Vector{2, 2, 0}, r3.Vector{2, 2, 2}, "")}, 0},
+		{"capsule separation", [2]Geometry{m, makeTestCapsule(NewZeroOrientation(), r3.Vector{3, 0, 0}, 1, 4)}, 1},
+		{"cylinder separation", [2]Geometry{m, makeTestCylinder(NewZeroOrientation(), r3.Vector{0, 0, 4}, 1, 2)}, 2},
+		{"mesh separation", [2]Geometry{m, makeTestMesh(t, r3.Vector{3, 3, 0})}, math.Sqrt2},
+		{"mesh intersection", [2]Geometry{m, makeTestMesh(t, r3.Vector{1, 1, 1})}, 0},
+		// the cube is closed, so geometries within it are in collision with it, at the depth of their penetration
+		{"point within", [2]Geometry{m, NewPoint(r3.Vector{0.5, 0, 0}, "")}, -0.5},
+		{"sphere within", [2]Geometry{m, makeTestSphere(r3.Vector{}, 0.5, "")}, -1.5},
+		{"box within", [2]Geometry{m, makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{1, 1, 1}, "")}, -0.5},
+		{"capsule within", [2]Geometry{m, makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 0.25, 1)}, -0.5},
+		{"cylinder within", [2]Geometry{m, makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 0.5, 1)}, -0.5},
+		{"mesh within", [2]Geometry{m, small}, -0.5},
+		// an open mesh does not enclose a volume, so a geometry within it is not in collision with it
+		{"point within open mesh", [2]Geometry{makeOpenTestMesh(t), NewPoint(r3.Vector{}, "")}, 1},
+		{"sphere within open mesh", [2]Geometry{makeOpenTestMesh(t), makeTestSphere(r3.Vector{}, 0.5, "")}, 0.5},
+	}
+	testGeometryCollision(t, cases)
+
+	// points just inside and outside of each face are on either side of the mesh
+	for _, dir := range []r3.Vector{{X: 1}, {X: -1}, {Y: 1}, {Y: -1}, {Z: 1}, {Z: -1}} {
+		inside, err := m.DistanceFrom(NewPoint(dir.Mul(0.99), ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, inside, test.ShouldAlmostEqual, -0.01)
+		outside, err := m.DistanceFrom(NewPoint(dir.Mul(1.01), ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, outside, test.ShouldAlmostEqual, 0.01)
+	}
+
+	moved := m.Transform(NewPose(r3.Vector{10, 0, 0}, &EulerAngles{0, 0, math.Pi / 4}))
+	dist, err := moved.DistanceFrom(NewPoint(r3.Vector{10 + math.Sqrt2 + 1, 0, 0}, ""))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dist, test.ShouldAlmostEqual, 1)
+	test.That(t, moved.Pose().Point().X, test.ShouldAlmostEqual, 10)
+}
+
+func TestMeshEncompassed(t *testing.T) {
+	m := makeTestMesh(t, r3.Vector{})
+	cases := []geometryComparisonTestCase{
+		{"in box", [2]Geometry{m, makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{2, 2, 2}, "")}, 0},
+		{"not in box", [2]Geometry{m, makeTestBox(NewZeroOrientation(), r3.Vector{0.1, 0, 0}, r3.Vector{2, 2, 2}, "")}, 1},
+		{"in sphere", [2]Geometry{m, makeTestSphere(r3.Vector{}, 1.8, "")}, 0},
+		{"not in sphere", [2]Geometry{m, makeTestSphere(r3.Vector{}, 1.7, "")}, 1},
+		{"in cylinder", [2]Geometry{m, makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 1.5, 2)}, 0},
+		{"not in mesh", [2]Geometry{m, makeTestMesh(t, r3.Vector{})}, 1},
+		{"sphere in mesh", [2]Geometry{makeTestSphere(r3.Vector{}, 0.5, ""), m}, 0},
+		{"sphere not in mesh", [2]Geometry{makeTestSphere(r3.Vector{}, 1.5, ""), m}, 1},
+		{"point in mesh", [2]Geometry{NewPoint(r3.Vector{0.5, 0.5, 0.5}, ""), m}, 0},
+		{"point not in mesh", [2]Geometry{NewPoint(r3.Vector{1.5, 0, 0}, ""), m}, 1},
+		{"nothing in open mesh", [2]Geometry{makeTestSphere(r3.Vector{}, 0.5, ""), makeOpenTestMesh(t)}, 1},
+	}
+	testGeometryEncompassed(t, cases)
+}
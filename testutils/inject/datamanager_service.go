@@ -3,6 +3,8 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/datamanager"
 )
@@ -13,6 +15,7 @@ type DataManagerService struct {
 	datamanager.Service
 	name          resource.Name
 	SyncFunc      func(ctx context.Context, extra map[string]interface{}) error
+	GetStatusFunc func(ctx context.Context, extra map[string]interface{}) (datamanager.Status, error)
 	DoCommandFunc func(ctx context.Context,
 		cmd map[string]interface{}) (map[string]interface{}, error)
 	CloseFunc func(ctx context.Context) error
@@ -36,6 +39,18 @@ func (svc *DataManagerService) Sync(ctx context.Context, extra map[string]interf
 	return svc.SyncFunc(ctx, extra)
 }
 
+// GetStatus calls the injected GetStatus or the real variant, if it reports its status.
+func (svc *DataManagerService) GetStatus(ctx context.Context, extra map[string]interface{}) (datamanager.Status, error) {
+	if svc.GetStatusFunc == nil {
+		reporter, ok := svc.Service.(datamanager.StatusReporter)
+		if !ok {
+			return datamanager.Status{}, errors.New("data manager service does not report its status")
+		}
+		return reporter.GetStatus(ctx, extra)
+	}
+	return svc.GetStatusFunc(ctx, extra)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (svc *DataManagerService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},